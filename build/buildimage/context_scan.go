@@ -0,0 +1,91 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buildimage
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	reference2 "github.com/docker/distribution/reference"
+
+	"github.com/sealerio/sealer/build/layerutils/charts"
+	manifest "github.com/sealerio/sealer/build/layerutils/manifests"
+	strUtils "github.com/sealerio/sealer/utils/strings"
+)
+
+// ScanContextImages statically walks the whole build context and collects
+// every `image:` reference found in YAML manifests and helm charts, wherever
+// they live in the context. It returns the normalized references alongside
+// the ones that do not parse as an image reference, so the caller can report
+// them instead of discovering a missing container image at cluster launch.
+func ScanContextImages(contextDir string) (valid []string, invalid []string, err error) {
+	var images []string
+
+	chartSearcher, err := charts.NewCharts()
+	if err != nil {
+		return nil, nil, err
+	}
+	yamlSearcher, err := manifest.NewManifests()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = filepath.Walk(contextDir, func(path string, f fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if f.IsDir() {
+			// a complete chart is rendered as one unit, do not scan its
+			// templates as plain YAML on top of that
+			if isChartArtifactEnough(path) {
+				imgs, err := chartSearcher.ListImages(path)
+				if err != nil {
+					return err
+				}
+				images = append(images, imgs...)
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		ext := strings.ToLower(filepath.Ext(f.Name()))
+		if ext != ".yaml" && ext != ".yml" && ext != ".tmpl" {
+			return nil
+		}
+
+		imgs, err := yamlSearcher.ListImages(path)
+		if err != nil {
+			return err
+		}
+		images = append(images, imgs...)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, image := range FormatImages(images) {
+		named, err := reference2.ParseNormalizedNamed(image)
+		if err != nil {
+			invalid = append(invalid, image)
+			continue
+		}
+		valid = append(valid, named.String())
+	}
+
+	return strUtils.RemoveDuplicate(valid), strUtils.RemoveDuplicate(invalid), nil
+}
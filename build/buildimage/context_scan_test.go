@@ -0,0 +1,55 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buildimage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScanContextImages(t *testing.T) {
+	contextDir := t.TempDir()
+
+	deployment := `apiVersion: apps/v1
+kind: Deployment
+spec:
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:1.21
+      - name: sidecar
+        image: "registry.example.com/team/sidecar:v2"
+`
+	broken := `kind: Pod
+spec:
+  containers:
+  - name: bad
+    image: Not_A_Valid/Reference!
+`
+	assert.NoError(t, os.MkdirAll(filepath.Join(contextDir, "deploy", "nested"), 0750))
+	assert.NoError(t, os.WriteFile(filepath.Join(contextDir, "deploy", "app.yaml"), []byte(deployment), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(contextDir, "deploy", "nested", "broken.yml"), []byte(broken), 0600))
+	// non yaml files are ignored entirely
+	assert.NoError(t, os.WriteFile(filepath.Join(contextDir, "README.md"), []byte("image: not-scanned"), 0600))
+
+	valid, invalid, err := ScanContextImages(contextDir)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"docker.io/library/nginx:1.21", "registry.example.com/team/sidecar:v2"}, valid)
+	assert.ElementsMatch(t, []string{"Not_A_Valid/Reference!"}, invalid)
+}
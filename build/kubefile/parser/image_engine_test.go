@@ -103,6 +103,16 @@ func (testImageEngine) RemoveContainer(opts *options.RemoveContainerOptions) err
 	panic("implement me")
 }
 
+func (testImageEngine) Prune(opts *options.PruneOptions) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (testImageEngine) DiskUsage(opts *options.DiskUsageOptions) error {
+	//TODO implement me
+	panic("implement me")
+}
+
 func (testImageEngine) Tag(opts *options.TagOptions) error {
 	//TODO implement me
 	panic("implement me")
@@ -28,6 +28,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sealerio/sealer/build/kubefile/command"
 	"github.com/sealerio/sealer/pkg/define/application/version"
+	imagev1 "github.com/sealerio/sealer/pkg/define/image/v1"
 	"github.com/sealerio/sealer/pkg/define/options"
 	"github.com/sealerio/sealer/pkg/imageengine"
 	"github.com/sirupsen/logrus"
@@ -61,6 +62,10 @@ type KubefileResult struct {
 	// user could overwrite it through v2.ClusterSpec at run stage.
 	GlobalEnv map[string]string
 
+	// GlobalEnvDocs is the metadata of the declared global env keys:
+	// default value, description and whether the key is required.
+	GlobalEnvDocs []imagev1.EnvDoc
+
 	// AppEnv is a set of key value pair.
 	// it is app level, only this app will be aware of its existence,
 	// it is used to render app files, or as an environment variable for app startup and deletion commands
@@ -214,8 +219,8 @@ func (kp *KubefileParser) processOnCmd(result *KubefileResult, node *Node) error
 		result.Dockerfile = mergeLines(result.Dockerfile, node.Original)
 		return nil
 	case command.Env:
-		// update global env to dockerfile at the same,	for using it at build stage.
-		result.Dockerfile = mergeLines(result.Dockerfile, node.Original)
+		// processGlobalEnv mirrors the key=value pairs back into the
+		// dockerfile (without the metadata flags) for the build stage.
 		return kp.processGlobalEnv(node, result)
 	case command.AppEnv:
 		return kp.processAppEnv(node, result)
@@ -382,23 +387,93 @@ func (kp *KubefileParser) processGlobalEnv(node *Node, result *KubefileResult) e
 	if len(valueList) != 2 {
 		return fmt.Errorf("line %d: invalid ENV instruction: %s", node.StartLine, node.Original)
 	}
-	envs := valueList[1]
 
-	for _, elem := range strings.Split(envs, " ") {
-		if elem == "" {
-			continue
-		}
+	docs, err := parseEnvLine(valueList[1])
+	if err != nil {
+		return fmt.Errorf("line %d: %v", node.StartLine, err)
+	}
 
-		var kv []string
-		if kv = strings.SplitN(elem, "=", 2); len(kv) != 2 {
-			continue
+	var pairs []string
+	for _, doc := range docs {
+		result.GlobalEnvDocs = append(result.GlobalEnvDocs, doc)
+		if doc.Default != "" {
+			result.GlobalEnv[doc.Key] = doc.Default
+			pairs = append(pairs, doc.Key+"="+doc.Default)
 		}
-		result.GlobalEnv[kv[0]] = kv[1]
+	}
+
+	// mirror the key=value pairs into the dockerfile for the build stage.
+	// bare keys and the metadata flags are sealer only and stay out of it.
+	if len(pairs) > 0 {
+		result.Dockerfile = mergeLines(result.Dockerfile, "ENV "+strings.Join(pairs, " "))
 	}
 
 	return nil
 }
 
+// parseEnvLine parses the arguments of one ENV instruction. Besides the
+// historical `key=value` pairs it accepts bare keys (declared without a
+// default) and the metadata flags `--required` and `--description="..."`,
+// each applying to the key they follow:
+//
+//	ENV PodCIDR=100.64.0.0/10 --description="pod network cidr"
+//	ENV VaultToken --required --description="token to fetch secrets"
+func parseEnvLine(envs string) ([]imagev1.EnvDoc, error) {
+	var docs []imagev1.EnvDoc
+	for _, elem := range splitEnvTokens(envs) {
+		switch {
+		case elem == "--required":
+			if len(docs) == 0 {
+				return nil, fmt.Errorf("invalid ENV instruction: --required must follow a key")
+			}
+			docs[len(docs)-1].Required = true
+		case strings.HasPrefix(elem, "--description="):
+			if len(docs) == 0 {
+				return nil, fmt.Errorf("invalid ENV instruction: --description must follow a key")
+			}
+			docs[len(docs)-1].Description = strings.Trim(strings.TrimPrefix(elem, "--description="), `"`)
+		case strings.HasPrefix(elem, "--"):
+			return nil, fmt.Errorf("invalid ENV instruction: unknown flag %s", elem)
+		default:
+			kv := strings.SplitN(elem, "=", 2)
+			doc := imagev1.EnvDoc{Key: kv[0]}
+			if len(kv) == 2 {
+				doc.Default = strings.Trim(kv[1], `"`)
+			}
+			docs = append(docs, doc)
+		}
+	}
+	return docs, nil
+}
+
+// splitEnvTokens splits the ENV arguments on spaces while keeping
+// double-quoted segments, e.g. --description="pod network cidr", together.
+func splitEnvTokens(envs string) []string {
+	var (
+		tokens  []string
+		current strings.Builder
+		quoted  bool
+	)
+	for _, r := range envs {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			current.WriteRune(r)
+		case r == ' ' && !quoted:
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+	return tokens
+}
+
 func (kp *KubefileParser) processCmd(node *Node, result *KubefileResult) error {
 	original := node.Original
 	cmd := strings.Split(original, "CMD ")
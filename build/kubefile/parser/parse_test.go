@@ -27,6 +27,7 @@ import (
 	"github.com/sealerio/sealer/pkg/define/application"
 	v1 "github.com/sealerio/sealer/pkg/define/application/v1"
 	"github.com/sealerio/sealer/pkg/define/application/version"
+	imagev1 "github.com/sealerio/sealer/pkg/define/image/v1"
 	"github.com/sealerio/sealer/pkg/define/options"
 )
 
@@ -270,3 +271,33 @@ func setupTempContext() (string, error) {
 
 	return tmpDir, nil
 }
+
+func TestParseEnvLine(t *testing.T) {
+	docs, err := parseEnvLine(`PodCIDR=100.64.0.0/10 --description="pod network cidr" VaultToken --required --description="token to fetch secrets" Mode=ha`)
+	if err != nil {
+		t.Fatalf("failed to parse env line: %s", err)
+	}
+
+	assert.Equal(t, []imagev1.EnvDoc{
+		{
+			Key:         "PodCIDR",
+			Default:     "100.64.0.0/10",
+			Description: "pod network cidr",
+		},
+		{
+			Key:         "VaultToken",
+			Required:    true,
+			Description: "token to fetch secrets",
+		},
+		{
+			Key:     "Mode",
+			Default: "ha",
+		},
+	}, docs)
+
+	_, err = parseEnvLine("--required key=value")
+	assert.NotNil(t, err)
+
+	_, err = parseEnvLine("key=value --unknown")
+	assert.NotNil(t, err)
+}
@@ -147,6 +147,10 @@ func getApplicationType(sources []string) (string, error) {
 			return application.KubeApp
 		}
 
+		if ext == ".service" || ext == ".timer" {
+			return application.HostApp
+		}
+
 		return application.UnknownApp
 	}
 
@@ -182,23 +186,32 @@ func getApplicationType(sources []string) (string, error) {
 	}
 
 	//matches the file suffix strictly
-	var isShell, isKube bool
+	var isShell, isKube, isHost, hasUnknown bool
 	for _, appType := range appTypeList {
-		if appType == application.UnknownApp {
-			logrus.Debugf("application type not detected in %s,%s,%s",
-				application.KubeApp, application.HelmApp, application.ShellApp)
-			return "", nil
-		}
-
-		if appType == application.ShellApp {
+		switch appType {
+		case application.ShellApp:
 			isShell = true
-		}
-
-		if appType == application.KubeApp {
+		case application.KubeApp:
 			isKube = true
+		case application.HostApp:
+			isHost = true
+		case application.UnknownApp:
+			hasUnknown = true
 		}
 	}
 
+	// systemd units mark a host app, files without a known suffix are part
+	// of its payload (static binaries) and do not make the type unknown.
+	if isHost && !isKube {
+		return application.HostApp, nil
+	}
+
+	if hasUnknown {
+		logrus.Debugf("application type not detected in %s,%s,%s,%s",
+			application.KubeApp, application.HelmApp, application.ShellApp, application.HostApp)
+		return "", nil
+	}
+
 	if isShell && !isKube {
 		return application.ShellApp, nil
 	}
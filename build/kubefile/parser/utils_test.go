@@ -211,6 +211,29 @@ func TestGetApplicationType(t *testing.T) {
 				wanted: "",
 			},
 		},
+		{
+			name: "it is host application",
+			args: args{
+				preparedFunc: func() (string, error) {
+					dir, err := os.MkdirTemp("/tmp/", "sealer-test")
+					if err != nil {
+						t.Error(err)
+					}
+					targets := []string{
+						filepath.Join(dir, "node-exporter"),
+						filepath.Join(dir, "node-exporter.service"),
+					}
+					for _, tar := range targets {
+						if _, err := os.Create(tar); err != nil {
+							t.Error(err)
+						}
+					}
+
+					return dir, nil
+				},
+				wanted: application.HostApp,
+			},
+		},
 	}
 
 	for _, tt := range tests {
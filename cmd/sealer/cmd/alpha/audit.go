@@ -0,0 +1,88 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alpha
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sealerio/sealer/common"
+	"github.com/sealerio/sealer/pkg/clusterfile"
+	"github.com/sealerio/sealer/pkg/infradriver"
+	"github.com/sealerio/sealer/pkg/runtime/kubernetes"
+)
+
+var longAuditCISDescription = `Run the CIS benchmark checks sealer can verify over ssh against the current
+cluster: control plane flags in the static pod manifests, the kubelet
+configuration and the file permissions on manifests, pki and kubeconfigs.
+Clusters installed with "--profile cis" should pass every check; the command
+exits non-zero when any check fails.`
+
+var exampleForAuditCISCmd = `
+  sealer alpha audit cis
+`
+
+// NewAuditCmd implements the sealer alpha audit command.
+func NewAuditCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "audit the current cluster against a hardening benchmark",
+	}
+	cmd.AddCommand(NewAuditCISCmd())
+	return cmd
+}
+
+// NewAuditCISCmd implements the sealer alpha audit cis command.
+func NewAuditCISCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "cis",
+		Short:   "Check the current cluster against the CIS benchmark",
+		Long:    longAuditCISDescription,
+		Example: exampleForAuditCISCmd,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cluster, err := clusterfile.GetClusterFromFile(common.GetDefaultClusterfile())
+			if err != nil {
+				return err
+			}
+
+			infraDriver, err := infradriver.NewInfraDriver(cluster)
+			if err != nil {
+				return err
+			}
+
+			results, err := kubernetes.AuditCIS(infraDriver)
+			if err != nil {
+				return err
+			}
+
+			var failed int
+			for _, result := range results {
+				if result.Findings == "" {
+					fmt.Printf("[PASS] %s: %s\n", result.Host, result.Name)
+					continue
+				}
+				failed++
+				fmt.Printf("[FAIL] %s: %s: %s\n", result.Host, result.Name, result.Findings)
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d of %d CIS checks failed", failed, len(results))
+			}
+			fmt.Printf("all %d CIS checks passed\n", len(results))
+			return nil
+		},
+	}
+}
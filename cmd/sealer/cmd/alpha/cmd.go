@@ -34,11 +34,20 @@ func NewCmdAlpha() *cobra.Command {
 	cmd.AddCommand(NewExecCmd())
 	cmd.AddCommand(NewMergeCmd())
 	cmd.AddCommand(NewGenCmd())
+	cmd.AddCommand(NewMigrateCmd())
 	cmd.AddCommand(NewCheckCmd())
 	cmd.AddCommand(NewSearchCmd())
 	cmd.AddCommand(NewManifestCmd())
 	cmd.AddCommand(NewHostAliasCmd())
 	cmd.AddCommand(NewMountCmd())
 	cmd.AddCommand(NewUmountCmd())
+	cmd.AddCommand(NewRolloutCmd())
+	cmd.AddCommand(NewCpCmd())
+	cmd.AddCommand(NewRegistryCmd())
+	cmd.AddCommand(NewReplaceNodeCmd())
+	cmd.AddCommand(NewRotateEncryptionKeyCmd())
+	cmd.AddCommand(NewAuditCmd())
+	cmd.AddCommand(NewKubeconfigCmd())
+	cmd.AddCommand(NewTestCmd())
 	return cmd
 }
@@ -0,0 +1,172 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alpha
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/sealerio/sealer/common"
+	"github.com/sealerio/sealer/pkg/clusterfile"
+	"github.com/sealerio/sealer/pkg/infradriver"
+	netutils "github.com/sealerio/sealer/utils/net"
+)
+
+var longCpCmdDescription = `Copy a local file or directory to all or selected cluster hosts in parallel
+through the SSH configuration of the saved Clusterfile, or fetch a remote
+path from every host with -R. Fetched files land in a per-host subdirectory
+of the local target. Failures are summarized per host at the end.`
+
+var exampleForCpCmd = `
+push a config to every node:
+  sealer alpha cp ./daemon.json /etc/docker/daemon.json
+
+push only to the masters:
+  sealer alpha cp -r master ./audit-policy.yaml /etc/kubernetes/audit-policy.yaml
+
+fetch a log file from two hosts into ./logs/<ip>/:
+  sealer alpha cp -R --hosts 192.168.0.2,192.168.0.3 /var/log/kubelet.log ./logs
+`
+
+// NewCpCmd returns the "sealer alpha cp" command.
+func NewCpCmd() *cobra.Command {
+	var (
+		cpRoles []string
+		cpHosts []string
+		reverse bool
+	)
+
+	cpCmd := &cobra.Command{
+		Use:     "cp <local> <remote>",
+		Short:   "Copy files to or from the cluster hosts in parallel",
+		Long:    longCpCmdDescription,
+		Example: exampleForCpCmd,
+		Args:    cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cluster, err := clusterfile.GetClusterFromFile(common.GetDefaultClusterfile())
+			if err != nil {
+				return err
+			}
+
+			var ipList []net.IP
+			switch {
+			case len(cpHosts) != 0:
+				all := cluster.GetAllIPList()
+				for _, host := range cpHosts {
+					ip := net.ParseIP(host)
+					if ip == nil {
+						return fmt.Errorf("invalid host IP: %s", host)
+					}
+					if !netutils.IsInIPList(ip, all) {
+						return fmt.Errorf("host %s is not part of cluster %s", ip, cluster.Name)
+					}
+					ipList = append(ipList, ip)
+				}
+			case len(cpRoles) != 0:
+				for _, role := range cpRoles {
+					ipList = append(ipList, cluster.GetIPSByRole(role)...)
+				}
+				if len(ipList) == 0 {
+					return fmt.Errorf("no host matches roles %v", cpRoles)
+				}
+			default:
+				ipList = cluster.GetAllIPList()
+			}
+
+			infraDriver, err := infradriver.NewInfraDriver(cluster)
+			if err != nil {
+				return err
+			}
+
+			if reverse {
+				return fetchFromHosts(infraDriver, ipList, args[0], args[1])
+			}
+			return pushToHosts(infraDriver, ipList, args[0], args[1])
+		},
+	}
+
+	cpCmd.Flags().StringSliceVarP(&cpRoles, "roles", "r", []string{}, "set role label to filter node")
+	cpCmd.Flags().StringSliceVar(&cpHosts, "hosts", []string{}, "comma separated host IPs to copy to or from, must be part of the cluster")
+	cpCmd.Flags().BoolVarP(&reverse, "reverse", "R", false, "fetch the remote path from the hosts instead of pushing to them")
+
+	return cpCmd
+}
+
+// pushToHosts distributes localPath to remotePath on every host and returns
+// an error summarizing the hosts the copy failed on.
+func pushToHosts(infraDriver infradriver.InfraDriver, ipList []net.IP, localPath, remotePath string) error {
+	if _, err := os.Stat(localPath); err != nil {
+		return fmt.Errorf("failed to access local path %s: %v", localPath, err)
+	}
+
+	return summarizeCopy(infraDriver, ipList, func(host net.IP) error {
+		logrus.Infof("copying %s to %s:%s", localPath, host, remotePath)
+		return infraDriver.Copy(host, localPath, remotePath)
+	})
+}
+
+// fetchFromHosts pulls remotePath from every host into a per-host
+// subdirectory of localDir, so files of different hosts never collide.
+func fetchFromHosts(infraDriver infradriver.InfraDriver, ipList []net.IP, remotePath, localDir string) error {
+	return summarizeCopy(infraDriver, ipList, func(host net.IP) error {
+		target := filepath.Join(localDir, host.String(), filepath.Base(remotePath))
+		if err := os.MkdirAll(filepath.Dir(target), 0750); err != nil {
+			return err
+		}
+		logrus.Infof("fetching %s:%s to %s", host, remotePath, target)
+		return infraDriver.CopyR(host, remotePath, target)
+	})
+}
+
+// summarizeCopy runs f on every host through the infradriver, which caps
+// the concurrency, and reports the outcome as one summary instead of
+// stopping at the first failure.
+func summarizeCopy(infraDriver infradriver.InfraDriver, ipList []net.IP, f func(host net.IP) error) error {
+	var (
+		mu       sync.Mutex
+		failures = map[string]error{}
+	)
+
+	// errors are collected instead of returned, so every host gets its try
+	_ = infraDriver.Execute(ipList, func(host net.IP) error {
+		if err := f(host); err != nil {
+			mu.Lock()
+			failures[host.String()] = err
+			mu.Unlock()
+		}
+		return nil
+	})
+
+	if len(failures) == 0 {
+		logrus.Infof("copy succeeded on all %d hosts", len(ipList))
+		return nil
+	}
+
+	var lines []string
+	for host, err := range failures {
+		lines = append(lines, fmt.Sprintf("%s: %v", host, err))
+	}
+	sort.Strings(lines)
+	return fmt.Errorf("copy succeeded on %d hosts, failed on %d:\n%s",
+		len(ipList)-len(failures), len(failures), strings.Join(lines, "\n"))
+}
@@ -0,0 +1,58 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alpha
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/sealerio/sealer/common"
+	"github.com/sealerio/sealer/pkg/clusterfile"
+	"github.com/sealerio/sealer/pkg/infradriver"
+	"github.com/sealerio/sealer/pkg/runtime/kubernetes"
+)
+
+var longRotateEncryptionKeyDescription = `Rotate the aescbc key encrypting kubernetes secrets at rest on a cluster
+created with the SecretEncryption env: a fresh key is generated and rolled
+out to every master with a kube-apiserver restart, then all secrets are
+rewritten so they use the new key. The previous key is kept as a fallback
+until the next rotation.`
+
+var exampleForRotateEncryptionKeyCmd = `
+  sealer alpha rotate-encryption-key
+`
+
+// NewRotateEncryptionKeyCmd implements the sealer alpha rotate-encryption-key command.
+func NewRotateEncryptionKeyCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "rotate-encryption-key",
+		Short:   "Rotate the secret encryption key of the current cluster",
+		Long:    longRotateEncryptionKeyDescription,
+		Example: exampleForRotateEncryptionKeyCmd,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cluster, err := clusterfile.GetClusterFromFile(common.GetDefaultClusterfile())
+			if err != nil {
+				return err
+			}
+
+			infraDriver, err := infradriver.NewInfraDriver(cluster)
+			if err != nil {
+				return err
+			}
+
+			return kubernetes.RotateEncryptionKey(infraDriver)
+		},
+	}
+}
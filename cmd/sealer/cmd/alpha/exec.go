@@ -17,20 +17,27 @@ package alpha
 import (
 	"fmt"
 	"net"
+	"os"
+	"sync"
 
 	"github.com/spf13/cobra"
 
 	"github.com/sealerio/sealer/common"
 	"github.com/sealerio/sealer/pkg/clusterfile"
-	"github.com/sealerio/sealer/pkg/exec"
+	"github.com/sealerio/sealer/pkg/infradriver"
+	netutils "github.com/sealerio/sealer/utils/net"
 )
 
 var (
 	clusterName string
 	roles       []string
+	execHosts   []string
 )
 
-var longExecCmdDescription = `Using ssh client which is built in sealer to run shell command on the nodes filtered by cluster and cluster roles. It is convenient for cluster administrator to do quick investigation.`
+var longExecCmdDescription = `Run a shell command on the cluster nodes selected by role or by IP, reusing
+the SSH configuration of the saved Clusterfile and the infradriver's
+concurrency limit. The output of every node is collected and printed per
+host, so there is no need for hand-written pssh loops.`
 
 var exampleForExecCmd = `
 Exec the default cluster node:
@@ -40,7 +47,10 @@ specify the cluster name:
   sealer alpha exec -c my-cluster "cat /etc/hosts"
 
 using role label to filter node and run exec cmd:
-  sealer alpha exec -c my-cluster -r master,slave,node1 "cat /etc/hosts"		
+  sealer alpha exec -c my-cluster -r master,slave,node1 "cat /etc/hosts"
+
+run on two specific hosts only:
+  sealer alpha exec --hosts 192.168.0.2,192.168.0.3 "uptime"
 `
 
 // NewExecCmd implement the sealer exec command
@@ -56,6 +66,7 @@ func NewExecCmd() *cobra.Command {
 
 	execCmd.Flags().StringVarP(&clusterName, "cluster-name", "c", "", "specify the name of cluster")
 	execCmd.Flags().StringSliceVarP(&roles, "roles", "r", []string{}, "set role label to filter node")
+	execCmd.Flags().StringSliceVar(&execHosts, "hosts", []string{}, "comma separated host IPs to run the command on, must be part of the cluster")
 
 	return execCmd
 }
@@ -68,17 +79,68 @@ func execActionFunc(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	if len(roles) == 0 {
-		ipList = cluster.GetAllIPList()
-	} else {
+	switch {
+	case len(execHosts) != 0:
+		all := cluster.GetAllIPList()
+		for _, host := range execHosts {
+			ip := net.ParseIP(host)
+			if ip == nil {
+				return fmt.Errorf("invalid host IP: %s", host)
+			}
+			if !netutils.IsInIPList(ip, all) {
+				return fmt.Errorf("host %s is not part of cluster %s", ip, cluster.Name)
+			}
+			ipList = append(ipList, ip)
+		}
+	case len(roles) != 0:
 		for _, role := range roles {
 			ipList = append(ipList, cluster.GetIPSByRole(role)...)
 		}
 		if len(ipList) == 0 {
 			return fmt.Errorf("failed to get target ipList: no IP gotten by role(%s)", roles)
 		}
+	default:
+		ipList = cluster.GetAllIPList()
 	}
 
-	execCmd := exec.NewExecCmd(cluster, ipList)
-	return execCmd.RunCmd(args[0])
+	infraDriver, err := infradriver.NewInfraDriver(cluster)
+	if err != nil {
+		return err
+	}
+
+	return execOnHosts(infraDriver, ipList, args[0])
+}
+
+// execOnHosts runs cmd on every host through the infradriver, which caps the
+// concurrency, and prints the collected output grouped per host in the order
+// the hosts were selected.
+func execOnHosts(infraDriver infradriver.InfraDriver, ipList []net.IP, cmd string) error {
+	var (
+		mu      sync.Mutex
+		outputs = map[string][]byte{}
+	)
+
+	execErr := infraDriver.Execute(ipList, func(host net.IP) error {
+		out, err := infraDriver.Cmd(host, nil, cmd)
+		mu.Lock()
+		outputs[host.String()] = out
+		mu.Unlock()
+		return err
+	})
+
+	for _, ip := range ipList {
+		out, ok := outputs[ip.String()]
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(os.Stdout, "----- %s -----\n%s", ip, out)
+		if len(out) > 0 && out[len(out)-1] != '\n' {
+			fmt.Fprintln(os.Stdout)
+		}
+	}
+
+	if execErr != nil {
+		return fmt.Errorf("failed to exec command (%s): %v", cmd, execErr)
+	}
+	return nil
 }
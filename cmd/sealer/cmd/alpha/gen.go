@@ -16,11 +16,24 @@ package alpha
 
 import (
 	"fmt"
+	"net"
+	"strconv"
 
-	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
 
+	"github.com/sealerio/sealer/cmd/sealer/cmd/utils"
 	"github.com/sealerio/sealer/common"
+	"github.com/sealerio/sealer/pkg/client/k8s"
+	clusterruntime "github.com/sealerio/sealer/pkg/cluster-runtime"
+	"github.com/sealerio/sealer/pkg/clusterfile"
+	"github.com/sealerio/sealer/pkg/define/options"
+	"github.com/sealerio/sealer/pkg/imageengine"
+	"github.com/sealerio/sealer/pkg/infradriver"
+	"github.com/sealerio/sealer/types/api/constants"
+	v1 "github.com/sealerio/sealer/types/api/v1"
+	v2 "github.com/sealerio/sealer/types/api/v2"
 )
 
 type ParserArg struct {
@@ -57,7 +70,7 @@ func NewGenCmd() *cobra.Command {
 			if flag.Passwd == "" || flag.Image == "" {
 				return fmt.Errorf("password and image name cannot be empty")
 			}
-			return errors.New("gen is not implemented yet")
+			return genClusterfile(flag)
 		},
 	}
 
@@ -71,3 +84,106 @@ func NewGenCmd() *cobra.Command {
 
 	return genCmd
 }
+
+// genClusterfile inspects the existing kubeadm cluster through the local
+// kubeconfig, checks every node over SSH, and registers the cluster in
+// sealer: the generated Clusterfile is saved locally and committed to the
+// cluster, so scale, upgrade and app operations can be driven by sealer
+// afterwards.
+func genClusterfile(arg *ParserArg) error {
+	client, err := k8s.NewK8sClient()
+	if err != nil {
+		return fmt.Errorf("failed to connect the cluster, make sure the kubeconfig exists under %s: %v",
+			common.DefaultKubeConfigDir(), err)
+	}
+
+	currentCluster, err := utils.GetCurrentCluster(client)
+	if err != nil {
+		return fmt.Errorf("failed to get master and node IP info from the cluster: %v", err)
+	}
+
+	masterIPList := currentCluster.GetMasterIPList()
+	nodeIPList := currentCluster.GetNodeIPList()
+	if len(masterIPList) == 0 {
+		return fmt.Errorf("no master node found in the cluster")
+	}
+
+	logrus.Infof("found %d masters %v and %d nodes %v", len(masterIPList), masterIPList, len(nodeIPList), nodeIPList)
+
+	// pull the matching sealer image, later operations such as scale and
+	// upgrade mount it from the local image store.
+	imageEngine, err := imageengine.NewImageEngine(options.EngineGlobalConfigurations{})
+	if err != nil {
+		return err
+	}
+
+	id, err := imageEngine.Pull(&options.PullOptions{
+		Quiet:      false,
+		PullPolicy: "missing",
+		Image:      arg.Image,
+		Platform:   "local",
+	})
+	if err != nil {
+		return err
+	}
+
+	imageSpec, err := imageEngine.Inspect(&options.InspectOptions{ImageNameOrID: id})
+	if err != nil {
+		return fmt.Errorf("failed to get sealer image extension: %s", err)
+	}
+
+	cluster := v2.Cluster{
+		Spec: v2.ClusterSpec{
+			SSH: v1.SSH{
+				User:     common.ROOT,
+				Passwd:   arg.Passwd,
+				Pk:       arg.Pk,
+				PkPasswd: arg.PkPassword,
+				Port:     strconv.Itoa(int(arg.Port)),
+			},
+			Image: arg.Image,
+			Hosts: []v2.Host{
+				{IPS: masterIPList, Roles: []string{common.MASTER}},
+				{IPS: nodeIPList, Roles: []string{common.NODE}},
+			},
+		},
+	}
+	cluster.APIVersion = v2.GroupVersion.String()
+	cluster.Kind = constants.ClusterKind
+	cluster.Name = arg.Name
+
+	// make sure every node is reachable with the given credentials before
+	// anything is persisted.
+	infraDriver, err := infradriver.NewInfraDriver(&cluster)
+	if err != nil {
+		return err
+	}
+
+	if err := infraDriver.Execute(infraDriver.GetHostIPList(), func(host net.IP) error {
+		if err := infraDriver.Ping(host); err != nil {
+			return fmt.Errorf("failed to ssh node %s, check the --passwd or --pk flags: %v", host.String(), err)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	clusterData, err := yaml.Marshal(cluster)
+	if err != nil {
+		return err
+	}
+
+	cf, err := clusterfile.NewClusterFile(clusterData)
+	if err != nil {
+		return err
+	}
+
+	confPath := clusterruntime.GetClusterConfPath(imageSpec.ImageExtension.Labels)
+	if err := cf.SaveAll(clusterfile.SaveOptions{CommitToCluster: true, ConfPath: confPath}); err != nil {
+		return err
+	}
+
+	logrus.Infof("succeeded in taking over cluster %s, the Clusterfile is saved under %s", arg.Name, common.GetSealerWorkDir())
+
+	return nil
+}
@@ -0,0 +1,86 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alpha
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sealerio/sealer/common"
+	"github.com/sealerio/sealer/pkg/clusterfile"
+	"github.com/sealerio/sealer/pkg/infradriver"
+	"github.com/sealerio/sealer/pkg/runtime/kubernetes"
+)
+
+var longKubeconfigExportDescription = `Export a kubeconfig for the current cluster and merge it into a local
+kubeconfig under a context named after the cluster. The server endpoint is
+rewritten to master0 so the context works from outside the cluster. With
+--user admin the cluster-admin credential of admin.conf is exported; with
+--user view a fresh read-only client certificate is minted and bound to the
+built-in view ClusterRole.`
+
+var exampleForKubeconfigExportCmd = `
+  sealer alpha kubeconfig export
+  sealer alpha kubeconfig export --user view --merge ~/.kube/config
+`
+
+// NewKubeconfigCmd implements the sealer alpha kubeconfig command.
+func NewKubeconfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "kubeconfig",
+		Short: "manage kubeconfig access to the current cluster",
+	}
+	cmd.AddCommand(NewKubeconfigExportCmd())
+	return cmd
+}
+
+// NewKubeconfigExportCmd implements the sealer alpha kubeconfig export command.
+func NewKubeconfigExportCmd() *cobra.Command {
+	var (
+		mergePath string
+		user      string
+	)
+	cmd := &cobra.Command{
+		Use:     "export",
+		Short:   "Export a kubeconfig context for the current cluster",
+		Long:    longKubeconfigExportDescription,
+		Example: exampleForKubeconfigExportCmd,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cluster, err := clusterfile.GetClusterFromFile(common.GetDefaultClusterfile())
+			if err != nil {
+				return err
+			}
+
+			infraDriver, err := infradriver.NewInfraDriver(cluster)
+			if err != nil {
+				return err
+			}
+
+			contextName, err := kubernetes.ExportKubeConfig(infraDriver, user, mergePath)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("exported context %q into %s and made it current\n", contextName, mergePath)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&mergePath, "merge", filepath.Join(common.DefaultKubeConfigDir(), "config"), "kubeconfig file the new context is merged into")
+	cmd.Flags().StringVar(&user, "user", kubernetes.ExportUserAdmin, "credential to export, \"admin\" or \"view\"")
+	return cmd
+}
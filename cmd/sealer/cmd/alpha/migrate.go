@@ -0,0 +1,121 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alpha
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/sealerio/sealer/common"
+	"github.com/sealerio/sealer/pkg/clusterfile"
+	"github.com/sealerio/sealer/pkg/migration"
+)
+
+var migrateClusterfilePath string
+
+var longMigrateCmdDescription = `Migrate converts cluster state saved by an older sealer version into the current format: v1 Cluster specs are rewritten as v2 Clusterfiles and Clusterfiles found in the pre-0.9 per-cluster work dirs (~/.sealer/<cluster-name>/Clusterfile) are moved into the current layout. The old files are kept untouched as a backup, so running migrate is safe to retry.`
+
+var exampleForMigrateCmd = `migrate the cluster found in the old sealer work dir:
+
+  sealer alpha migrate
+
+migrate a specific Clusterfile:
+
+  sealer alpha migrate -f /root/.sealer/my-cluster/Clusterfile
+`
+
+// NewMigrateCmd returns the sealer migrate Cobra command
+func NewMigrateCmd() *cobra.Command {
+	migrateCmd := &cobra.Command{
+		Use:     "migrate",
+		Short:   "Migrate a cluster created by an older sealer version to the current format",
+		Long:    longMigrateCmdDescription,
+		Example: exampleForMigrateCmd,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return migrateCluster(migrateClusterfilePath)
+		},
+	}
+
+	migrateCmd.Flags().StringVarP(&migrateClusterfilePath, "Clusterfile", "f", "", "Clusterfile path to migrate, default is the one found in the old sealer work dir")
+
+	return migrateCmd
+}
+
+// migrateCluster reads the old Clusterfile, lets the clusterfile decoder
+// convert the v1 spec, and saves the result in the current storage format.
+func migrateCluster(path string) error {
+	if path == "" {
+		located, err := locateLegacyClusterfile()
+		if err != nil {
+			return err
+		}
+		path = located
+	}
+
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return fmt.Errorf("failed to read Clusterfile %s: %v", path, err)
+	}
+
+	// the decoder converts legacy v1 cluster specs transparently, so the
+	// loaded Clusterfile is already in the v2 shape here.
+	cf, err := clusterfile.NewClusterFile(data)
+	if err != nil {
+		return fmt.Errorf("failed to load Clusterfile %s: %v", path, err)
+	}
+
+	cluster := cf.GetCluster()
+	if err := cf.SaveAll(clusterfile.SaveOptions{}); err != nil {
+		return fmt.Errorf("failed to save migrated Clusterfile: %v", err)
+	}
+
+	logrus.Infof("succeeded in migrating cluster %s, the Clusterfile is saved under %s, the old files under %s are kept as a backup",
+		cluster.Name, common.GetDefaultClusterfile(), filepath.Dir(path))
+
+	return nil
+}
+
+// locateLegacyClusterfile falls back to the current default Clusterfile
+// first (it may still carry a v1 spec written by an old sealer), then
+// scans the pre-0.9 per-cluster work dirs.
+func locateLegacyClusterfile() (string, error) {
+	if _, err := os.Stat(common.GetDefaultClusterfile()); err == nil {
+		return common.GetDefaultClusterfile(), nil
+	}
+
+	found, err := migration.FindLegacyClusterfiles(common.GetSealerWorkDir())
+	if err != nil {
+		return "", err
+	}
+
+	switch len(found) {
+	case 0:
+		return "", fmt.Errorf("no Clusterfile found under %s, use -f to point at the Clusterfile to migrate", common.GetSealerWorkDir())
+	case 1:
+		logrus.Infof("found legacy Clusterfile of cluster %s at %s", found[0].ClusterName, found[0].Path)
+		return found[0].Path, nil
+	default:
+		var names []string
+		for _, f := range found {
+			names = append(names, f.ClusterName)
+		}
+		return "", fmt.Errorf("found Clusterfiles of multiple clusters %v under %s, use -f to select one", names, common.GetSealerWorkDir())
+	}
+}
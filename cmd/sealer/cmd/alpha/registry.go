@@ -0,0 +1,78 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alpha
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/sealerio/sealer/common"
+	"github.com/sealerio/sealer/pkg/clusterfile"
+	containerruntime "github.com/sealerio/sealer/pkg/container-runtime"
+	"github.com/sealerio/sealer/pkg/infradriver"
+)
+
+var longRegistrySyncConfigDescription = `Re-render the registry mirrors and insecure registries from the saved
+Clusterfile into the container runtime configuration of every node, so the
+settings can be changed on a live cluster without reinstalling the runtime.`
+
+var exampleForRegistrySyncConfigCmd = `
+  sealer alpha registry sync-config
+`
+
+// NewRegistryCmd groups registry related helpers.
+func NewRegistryCmd() *cobra.Command {
+	registryCmd := &cobra.Command{
+		Use:   "registry",
+		Short: "registry configuration helpers",
+	}
+	registryCmd.AddCommand(newRegistrySyncConfigCmd())
+	return registryCmd
+}
+
+func newRegistrySyncConfigCmd() *cobra.Command {
+	syncConfigCmd := &cobra.Command{
+		Use:     "sync-config",
+		Short:   "apply registry mirrors and insecure registries to all nodes",
+		Long:    longRegistrySyncConfigDescription,
+		Example: exampleForRegistrySyncConfigCmd,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cluster, err := clusterfile.GetClusterFromFile(common.GetDefaultClusterfile())
+			if err != nil {
+				return err
+			}
+
+			infraDriver, err := infradriver.NewInfraDriver(cluster)
+			if err != nil {
+				return err
+			}
+
+			installer, err := containerruntime.NewInstaller(cluster.Spec.ContainerRuntime, infraDriver)
+			if err != nil {
+				return err
+			}
+
+			hosts := infraDriver.GetHostIPList()
+			if err := installer.SyncRegistryConfig(hosts); err != nil {
+				return err
+			}
+
+			logrus.Infof("successfully synced registry config to %d hosts", len(hosts))
+			return nil
+		},
+	}
+	return syncConfigCmd
+}
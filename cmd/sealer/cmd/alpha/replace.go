@@ -0,0 +1,168 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alpha
+
+import (
+	"fmt"
+	"net"
+
+	sealer "github.com/sealerio/sealer/pkg/client/sealer"
+	"github.com/sealerio/sealer/pkg/clusterfile"
+	"github.com/sealerio/sealer/pkg/define/options"
+	"github.com/sealerio/sealer/pkg/imageengine"
+	netutils "github.com/sealerio/sealer/utils/net"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var longReplaceNodeCmdDescription = `replace-node swaps one cluster host for a fresh machine in a single run:
+the replacement is joined first with the same roles, labels, taints and env
+as the host it replaces, then the old host is drained and removed through
+the scale-down pipeline, which also updates the registry and lvscare
+configuration the old host was part of.`
+
+var exampleForReplaceNodeCmd = `
+replace a failed worker with a new machine:
+  sealer alpha replace-node --old 192.168.0.5 --new 192.168.0.6
+replace and reclaim the old machine for reuse:
+  sealer alpha replace-node --old 192.168.0.5 --new 192.168.0.6 --prune
+`
+
+// NewReplaceNodeCmd returns the "sealer alpha replace-node" command.
+func NewReplaceNodeCmd() *cobra.Command {
+	var (
+		oldIPStr string
+		newIPStr string
+		prune    bool
+	)
+
+	replaceNodeCmd := &cobra.Command{
+		Use:     "replace-node",
+		Short:   "replace a cluster host with a new machine in one operation",
+		Long:    longReplaceNodeCmdDescription,
+		Example: exampleForReplaceNodeCmd,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			oldIP := net.ParseIP(oldIPStr)
+			if oldIP == nil {
+				return fmt.Errorf("invalid --old IP: %s", oldIPStr)
+			}
+			newIP := net.ParseIP(newIPStr)
+			if newIP == nil {
+				return fmt.Errorf("invalid --new IP: %s", newIPStr)
+			}
+
+			return replaceNode(oldIP, newIP, prune)
+		},
+	}
+
+	replaceNodeCmd.Flags().StringVar(&oldIPStr, "old", "", "IP of the host to remove from the cluster")
+	replaceNodeCmd.Flags().StringVar(&newIPStr, "new", "", "IP of the machine joining in its place")
+	replaceNodeCmd.Flags().BoolVar(&prune, "prune", false, "remove the sealer data dir from the old host so the machine can be reused")
+	_ = replaceNodeCmd.MarkFlagRequired("old")
+	_ = replaceNodeCmd.MarkFlagRequired("new")
+
+	return replaceNodeCmd
+}
+
+func replaceNode(oldIP, newIP net.IP, prune bool) error {
+	cf, _, err := clusterfile.GetActualClusterFile()
+	if err != nil {
+		return err
+	}
+
+	cluster := cf.GetCluster()
+
+	if !netutils.IsInIPList(oldIP, cluster.GetAllIPList()) {
+		return fmt.Errorf("%s is not part of cluster %s", oldIP, cluster.Name)
+	}
+	if netutils.IsInIPList(newIP, cluster.GetAllIPList()) {
+		return fmt.Errorf("%s is already part of cluster %s", newIP, cluster.Name)
+	}
+	if oldIP.Equal(cluster.GetMaster0IP()) {
+		return fmt.Errorf("%s is master0, promote another master first via sealer promote-master", oldIP)
+	}
+
+	isMaster := netutils.IsInIPList(oldIP, cluster.GetMasterIPList())
+
+	// join the replacement through the host entry of the old IP, so it
+	// inherits the same roles, ssh config, env, labels and taints
+	var entryFound bool
+	for i := range cluster.Spec.Hosts {
+		if netutils.IsInIPList(oldIP, cluster.Spec.Hosts[i].IPS) {
+			cluster.Spec.Hosts[i].IPS = append(cluster.Spec.Hosts[i].IPS, newIP)
+			entryFound = true
+			break
+		}
+	}
+	if !entryFound {
+		return fmt.Errorf("failed to find the host entry of %s in the Clusterfile", oldIP)
+	}
+
+	imageEngine, err := imageengine.NewImageEngine(options.EngineGlobalConfigurations{})
+	if err != nil {
+		return err
+	}
+
+	id, err := imageEngine.Pull(&options.PullOptions{
+		Quiet:      false,
+		PullPolicy: "missing",
+		Image:      cluster.Spec.Image,
+		Platform:   "local",
+	})
+	if err != nil {
+		return err
+	}
+
+	imageSpec, err := imageEngine.Inspect(&options.InspectOptions{ImageNameOrID: id})
+	if err != nil {
+		return fmt.Errorf("failed to get sealer image extension: %s", err)
+	}
+
+	mergedWithExt := sealer.MergeClusterWithImageExtension(&cluster, imageSpec.ImageExtension)
+	cf.SetCluster(*mergedWithExt)
+
+	kubeInstaller, err := sealer.NewKubeInstaller(cf, imageEngine, imageSpec)
+	if err != nil {
+		return err
+	}
+
+	var joinMasters, joinNodes []net.IP
+	if isMaster {
+		joinMasters = []net.IP{newIP}
+	} else {
+		joinNodes = []net.IP{newIP}
+	}
+
+	logrus.Infof("start to join the replacement host %s", newIP)
+	if err := kubeInstaller.ScaleUp(joinMasters, joinNodes, sealer.KubeScaleUpOptions{}); err != nil {
+		return fmt.Errorf("failed to join the replacement host %s, the cluster is unchanged: %v", newIP, err)
+	}
+
+	logrus.Infof("start to remove the old host %s", oldIP)
+	var deleteMasters, deleteNodes []net.IP
+	if isMaster {
+		deleteMasters = []net.IP{oldIP}
+	} else {
+		deleteNodes = []net.IP{oldIP}
+	}
+
+	if err := kubeInstaller.ScaleDown(deleteMasters, deleteNodes, sealer.KubeScaleDownOptions{Prune: prune}); err != nil {
+		return fmt.Errorf("the replacement %s joined but removing %s failed, remove it via sealer delete: %v", newIP, oldIP, err)
+	}
+
+	logrus.Infof("succeeded in replacing %s with %s", oldIP, newIP)
+	return nil
+}
@@ -0,0 +1,188 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alpha
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/sealerio/sealer/common"
+	"github.com/sealerio/sealer/pkg/client/k8s"
+	"github.com/sealerio/sealer/pkg/clusterfile"
+	"github.com/sealerio/sealer/pkg/infradriver"
+)
+
+var longRolloutRebootCmdDescription = `Reboot the cluster nodes one batch at a time: each node is cordoned and
+drained through the apiserver, rebooted over ssh, waited for Ready and
+uncordoned before the next batch starts. Use it after kernel or OS patching
+on sealer-managed clusters.`
+
+var exampleForRolloutRebootCmd = `
+reboot all nodes one by one:
+  sealer alpha rollout reboot
+
+reboot only the workers, two at a time:
+  sealer alpha rollout reboot -r node --batch 2
+`
+
+// rebootCmd issues the reboot detached from the ssh session, so the command
+// returns cleanly before the connection drops.
+const rebootCmd = "nohup sh -c 'sleep 2 && reboot' >/dev/null 2>&1 &"
+
+// NewRolloutCmd returns "sealer alpha rollout" command.
+func NewRolloutCmd() *cobra.Command {
+	rolloutCmd := &cobra.Command{
+		Use:   "rollout",
+		Short: "rolling operations over the cluster nodes",
+	}
+	rolloutCmd.AddCommand(NewRolloutRebootCmd())
+	return rolloutCmd
+}
+
+func NewRolloutRebootCmd() *cobra.Command {
+	var (
+		rolloutRoles []string
+		batchSize    int
+		readyTimeout time.Duration
+	)
+
+	rebootCmd := &cobra.Command{
+		Use:     "reboot",
+		Short:   "cordon, drain and reboot the cluster nodes in batches",
+		Long:    longRolloutRebootCmdDescription,
+		Example: exampleForRolloutRebootCmd,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if batchSize < 1 {
+				return fmt.Errorf("batch must be at least 1, got %d", batchSize)
+			}
+
+			cluster, err := clusterfile.GetClusterFromFile(common.GetDefaultClusterfile())
+			if err != nil {
+				return err
+			}
+
+			infraDriver, err := infradriver.NewInfraDriver(cluster)
+			if err != nil {
+				return err
+			}
+
+			var ipList []net.IP
+			if len(rolloutRoles) == 0 {
+				ipList = infraDriver.GetHostIPList()
+			} else {
+				for _, role := range rolloutRoles {
+					ipList = append(ipList, infraDriver.GetHostIPListByRole(role)...)
+				}
+				if len(ipList) == 0 {
+					return fmt.Errorf("no host matches roles %v", rolloutRoles)
+				}
+			}
+
+			client, err := k8s.NewK8sClient()
+			if err != nil {
+				return err
+			}
+
+			return rolloutReboot(client, infraDriver, ipList, batchSize, readyTimeout)
+		},
+	}
+
+	rebootCmd.Flags().StringSliceVarP(&rolloutRoles, "roles", "r", []string{}, "set role label to filter node")
+	rebootCmd.Flags().IntVar(&batchSize, "batch", 1, "number of nodes rebooted in parallel per batch")
+	rebootCmd.Flags().DurationVar(&readyTimeout, "ready-timeout", 10*time.Minute, "how long to wait for a rebooted node to become Ready again")
+
+	return rebootCmd
+}
+
+func rolloutReboot(client *k8s.Client, infraDriver infradriver.InfraDriver, ipList []net.IP, batchSize int, readyTimeout time.Duration) error {
+	nodeNames, err := mapIPsToNodeNames(client, ipList)
+	if err != nil {
+		return err
+	}
+
+	for start := 0; start < len(ipList); start += batchSize {
+		end := start + batchSize
+		if end > len(ipList) {
+			end = len(ipList)
+		}
+		batch := ipList[start:end]
+		logrus.Infof("start to reboot batch %v", batch)
+
+		rebootedAt := metav1.Now()
+		for _, ip := range batch {
+			nodeName := nodeNames[ip.String()]
+			logrus.Infof("cordon and drain node %s(%s)", nodeName, ip)
+			if err := client.CordonNode(nodeName, true); err != nil {
+				return err
+			}
+			if err := client.DrainNode(nodeName); err != nil {
+				return err
+			}
+			if err := infraDriver.CmdAsync(ip, nil, rebootCmd); err != nil {
+				return fmt.Errorf("failed to reboot host %s: %v", ip, err)
+			}
+		}
+
+		for _, ip := range batch {
+			nodeName := nodeNames[ip.String()]
+			logrus.Infof("waiting for node %s(%s) to become ready", nodeName, ip)
+			if err := client.WaitNodeReady(nodeName, rebootedAt, readyTimeout); err != nil {
+				return err
+			}
+			if err := client.CordonNode(nodeName, false); err != nil {
+				return err
+			}
+			logrus.Infof("node %s(%s) is back and schedulable", nodeName, ip)
+		}
+	}
+
+	logrus.Info("rolling reboot completed")
+	return nil
+}
+
+// mapIPsToNodeNames resolves every host IP to its node name through the node
+// InternalIP addresses, failing early when a host is not part of the cluster.
+func mapIPsToNodeNames(client *k8s.Client, ipList []net.IP) (map[string]string, error) {
+	nodes, err := client.ListNodes()
+	if err != nil {
+		return nil, err
+	}
+
+	byIP := make(map[string]string)
+	for _, node := range nodes.Items {
+		for _, addr := range node.Status.Addresses {
+			if addr.Type == corev1.NodeInternalIP {
+				byIP[addr.Address] = node.Name
+			}
+		}
+	}
+
+	ret := make(map[string]string, len(ipList))
+	for _, ip := range ipList {
+		name, ok := byIP[ip.String()]
+		if !ok {
+			return nil, fmt.Errorf("host %s is not a node of the current cluster", ip)
+		}
+		ret[ip.String()] = name
+	}
+	return ret, nil
+}
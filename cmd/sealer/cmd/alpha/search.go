@@ -25,8 +25,15 @@ import (
 	"github.com/sealerio/sealer/common"
 	"github.com/sealerio/sealer/pkg/image/reference"
 	save2 "github.com/sealerio/sealer/pkg/image/save"
+	"github.com/sealerio/sealer/pkg/printer"
 )
 
+// searchResult is one image/tag pair found in the remote registry.
+type searchResult struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
 const (
 	imageName = "IMAGE NAME"
 )
@@ -49,8 +56,7 @@ func NewSearchCmd() *cobra.Command {
 		Example: exampleForSearchCmd,
 		Args:    cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			table := tablewriter.NewWriter(common.StdOut)
-			table.SetHeader([]string{imageName, "version"})
+			var results []searchResult
 			for _, imgName := range args {
 				named, err := reference.ParseToNamed(imgName)
 				if err != nil {
@@ -73,9 +79,19 @@ func NewSearchCmd() *cobra.Command {
 					return err
 				}
 				for _, tag := range tags {
-					table.Append([]string{named.String(), tag})
+					results = append(results, searchResult{Name: named.String(), Version: tag})
 				}
 			}
+
+			if p := printer.Default(); p.IsStructured() {
+				return p.PrintObj(results)
+			}
+
+			table := tablewriter.NewWriter(common.StdOut)
+			table.SetHeader([]string{imageName, "version"})
+			for _, result := range results {
+				table.Append([]string{result.Name, result.Version})
+			}
 			table.Render()
 			return nil
 		},
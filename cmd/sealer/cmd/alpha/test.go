@@ -0,0 +1,196 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alpha
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/sealerio/sealer/common"
+	sealer "github.com/sealerio/sealer/pkg/client/sealer"
+	"github.com/sealerio/sealer/pkg/clusterfile"
+	"github.com/sealerio/sealer/pkg/define/options"
+	"github.com/sealerio/sealer/pkg/imageengine"
+	"github.com/sealerio/sealer/pkg/infra"
+	"github.com/sealerio/sealer/pkg/infra/container"
+	"github.com/sealerio/sealer/pkg/infradriver"
+	v1 "github.com/sealerio/sealer/types/api/v1"
+)
+
+// testClusterName keeps the disposable environment apart from the clusters
+// the regular commands manage.
+const testClusterName = "sealer-test"
+
+var longTestCmdDescription = `test gives sealer image authors a local CI loop: it spins up disposable
+container based hosts through the docker infra provider, applies the given
+sealer image against them, runs the check scripts the image ships under
+check/ in its rootfs on the first master, and tears everything down again.
+The hosts survive with --keep for debugging a failed check; delete them by
+rerunning without it or with docker directly.`
+
+var exampleForTestCmd = `
+  sealer alpha test docker.io/sealerio/kubernetes:v1.22.15
+  sealer alpha test my-image:latest --masters 3 --nodes 1 --keep
+`
+
+// NewTestCmd returns the "sealer alpha test" command.
+func NewTestCmd() *cobra.Command {
+	var (
+		masters   int
+		nodes     int
+		keep      bool
+		customEnv []string
+	)
+
+	testCmd := &cobra.Command{
+		Use:     "test",
+		Short:   "apply a sealer image onto disposable container hosts and run its check scripts",
+		Long:    longTestCmdDescription,
+		Example: exampleForTestCmd,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if masters < 1 {
+				return fmt.Errorf("at least one master is required, got %d", masters)
+			}
+			return runImageTest(args[0], masters, nodes, customEnv, keep)
+		},
+	}
+
+	testCmd.Flags().IntVar(&masters, "masters", 1, "number of container hosts joined as masters")
+	testCmd.Flags().IntVar(&nodes, "nodes", 1, "number of container hosts joined as nodes")
+	testCmd.Flags().BoolVar(&keep, "keep", false, "keep the container hosts after the test for debugging")
+	testCmd.Flags().StringSliceVarP(&customEnv, "env", "e", []string{}, "set custom environment variables for the test cluster")
+	return testCmd
+}
+
+func runImageTest(imageName string, masters, nodes int, customEnv []string, keep bool) (err error) {
+	testCluster := &v1.Cluster{
+		Spec: v1.ClusterSpec{
+			Image:    imageName,
+			Provider: common.CONTAINER,
+			Env:      customEnv,
+			SSH: v1.SSH{
+				User:   common.ROOT,
+				Passwd: container.DefaultPassword,
+				Port:   "22",
+			},
+			Masters: v1.Hosts{Count: strconv.Itoa(masters)},
+			Nodes:   v1.Hosts{Count: strconv.Itoa(nodes)},
+		},
+	}
+	testCluster.Kind = "Cluster"
+	testCluster.APIVersion = v1.GroupVersion.String()
+	testCluster.Name = testClusterName
+
+	provider, err := infra.NewDefaultProvider(testCluster)
+	if err != nil {
+		return err
+	}
+
+	logrus.Infof("start to create %d master and %d node containers", masters, nodes)
+	if err := provider.Apply(); err != nil {
+		return fmt.Errorf("failed to create container hosts: %v", err)
+	}
+
+	defer func() {
+		if keep {
+			logrus.Infof("keeping the container hosts of cluster %s as requested", testClusterName)
+			return
+		}
+		now := metav1.Now()
+		testCluster.DeletionTimestamp = &now
+		if cleanErr := provider.Apply(); cleanErr != nil {
+			logrus.Errorf("failed to clean up the container hosts: %v", cleanErr)
+			if err == nil {
+				err = cleanErr
+			}
+		}
+	}()
+
+	if err := applyAndCheck(imageName, testCluster); err != nil {
+		return err
+	}
+
+	logrus.Infof("succeeded in testing sealer image %s", imageName)
+	return nil
+}
+
+func applyAndCheck(imageName string, testCluster *v1.Cluster) error {
+	imageEngine, err := imageengine.NewImageEngine(options.EngineGlobalConfigurations{})
+	if err != nil {
+		return err
+	}
+
+	id, err := imageEngine.Pull(&options.PullOptions{
+		Quiet:      false,
+		PullPolicy: "missing",
+		Image:      imageName,
+		Platform:   "local",
+	})
+	if err != nil {
+		return err
+	}
+
+	imageSpec, err := imageEngine.Inspect(&options.InspectOptions{ImageNameOrID: id})
+	if err != nil {
+		return fmt.Errorf("failed to get sealer image extension: %s", err)
+	}
+
+	clusterData, err := yaml.Marshal(infra.ToV2Cluster(testCluster))
+	if err != nil {
+		return err
+	}
+
+	cf, err := clusterfile.NewClusterFile(clusterData)
+	if err != nil {
+		return err
+	}
+
+	kubeInstaller, err := sealer.NewKubeInstaller(cf, imageEngine, imageSpec)
+	if err != nil {
+		return err
+	}
+
+	if err := kubeInstaller.Install(imageName, sealer.KubeInstallOptions{}); err != nil {
+		return fmt.Errorf("failed to apply %s onto the container hosts: %v", imageName, err)
+	}
+
+	return runCheckScripts(cf)
+}
+
+// runCheckScripts executes every check/*.sh of the distributed rootfs on
+// the first master; images without a check directory pass trivially.
+func runCheckScripts(cf clusterfile.Interface) error {
+	cluster := cf.GetCluster()
+	infraDriver, err := infradriver.NewInfraDriver(&cluster)
+	if err != nil {
+		return err
+	}
+
+	master0 := infraDriver.GetHostIPListByRole(common.MASTER)[0]
+	checkDir := infraDriver.GetClusterRootfsPath() + "/check"
+	checkCmd := fmt.Sprintf(`if [ -d %[1]s ]; then for script in %[1]s/*.sh; do [ -e "$script" ] || continue; echo "running check script $script"; bash "$script" || exit 1; done; fi`, checkDir)
+
+	logrus.Infof("start to run the check scripts of the image on master(%s)", master0)
+	if err := infraDriver.CmdAsync(master0, infraDriver.GetHostEnv(master0), checkCmd); err != nil {
+		return fmt.Errorf("check scripts failed: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"fmt"
+
+	"github.com/sealerio/sealer/common"
+	"github.com/sealerio/sealer/pkg/application"
+	sealer "github.com/sealerio/sealer/pkg/client/sealer"
+	"github.com/sealerio/sealer/pkg/define/options"
+	"github.com/sealerio/sealer/pkg/imageengine"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exampleForRollbackCmd = `
+  sealer app rollback dashboard
+`
+	longDescriptionForRollbackCmd = `rollback command re-renders and launches the previous image version of an
+application recorded in the app install history, complementing the upgrade path.`
+)
+
+type rollbackFlags struct {
+	customEnv   []string
+	ignoreCache bool
+}
+
+func NewRollbackCmd() *cobra.Command {
+	flags := &rollbackFlags{}
+	rollbackCmd := &cobra.Command{
+		Use:     "rollback",
+		Short:   "rollback an application to its previous image version",
+		Long:    longDescriptionForRollbackCmd,
+		Example: exampleForRollbackCmd,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			appName := args[0]
+
+			previous, err := application.PreviousVersion(appName)
+			if err != nil {
+				return err
+			}
+
+			logrus.Infof("start to rollback app %s to image %s installed at %s",
+				appName, previous.ImageName, previous.InstalledAt.Format("2006-01-02 15:04:05"))
+
+			imageEngine, err := imageengine.NewImageEngine(options.EngineGlobalConfigurations{})
+			if err != nil {
+				return err
+			}
+
+			id, err := imageEngine.Pull(&options.PullOptions{
+				Quiet:      false,
+				PullPolicy: "missing",
+				Image:      previous.ImageName,
+				Platform:   "local",
+			})
+			if err != nil {
+				return err
+			}
+
+			imageSpec, err := imageEngine.Inspect(&options.InspectOptions{ImageNameOrID: id})
+			if err != nil {
+				return fmt.Errorf("failed to get sealer image extension: %s", err)
+			}
+
+			appSpec := sealer.ConstructApplication(nil, nil, []string{appName}, flags.customEnv)
+			appInstaller, err := sealer.NewApplicationInstaller(appSpec, imageSpec.ImageExtension, imageEngine)
+			if err != nil {
+				return err
+			}
+
+			if err := appInstaller.Install(previous.ImageName, sealer.AppInstallOptions{
+				Envs:        flags.customEnv,
+				RunMode:     common.ApplyModeApply,
+				IgnoreCache: flags.ignoreCache,
+			}); err != nil {
+				return err
+			}
+
+			logrus.Infof("succeeded in rolling back app %s to image %s", appName, previous.ImageName)
+			return nil
+		},
+	}
+
+	rollbackCmd.Flags().StringSliceVarP(&flags.customEnv, "env", "e", []string{}, "set custom environment variables")
+	rollbackCmd.Flags().BoolVar(&flags.ignoreCache, "ignore-cache", false, "whether ignore cache when distribute sealer image, default is false.")
+
+	return rollbackCmd
+}
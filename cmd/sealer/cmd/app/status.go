@@ -0,0 +1,193 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/sealerio/sealer/common"
+	"github.com/sealerio/sealer/pkg/application"
+	"github.com/sealerio/sealer/pkg/clusterfile"
+	appdefinition "github.com/sealerio/sealer/pkg/define/application"
+	"github.com/sealerio/sealer/pkg/define/options"
+	"github.com/sealerio/sealer/pkg/imageengine"
+	"github.com/sealerio/sealer/pkg/infradriver"
+	"github.com/sealerio/sealer/pkg/printer"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exampleForStatusCmd = `
+  sealer app status
+  sealer app status dashboard
+`
+	longDescriptionForStatusCmd = `status command reads the app install history, and for helm type apps queries
+the release health from the cluster via helm status, so the state of the
+launched applications can be checked without logging into the hosts.`
+)
+
+// appStatus is the per-app summary printed by `sealer app status`.
+type appStatus struct {
+	App         string `json:"app"`
+	Type        string `json:"type"`
+	Image       string `json:"image"`
+	InstalledAt string `json:"installedAt"`
+	Status      string `json:"status"`
+}
+
+func NewStatusCmd() *cobra.Command {
+	statusCmd := &cobra.Command{
+		Use:     "status [app]",
+		Short:   "report the status of installed applications",
+		Long:    longDescriptionForStatusCmd,
+		Example: exampleForStatusCmd,
+		Args:    cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			records, err := application.LoadHistory()
+			if err != nil {
+				return err
+			}
+			if len(records) == 0 {
+				return fmt.Errorf("no app install history found, no application was installed yet")
+			}
+
+			cf, _, err := clusterfile.GetActualClusterFile()
+			if err != nil {
+				return err
+			}
+
+			cluster := cf.GetCluster()
+			infraDriver, err := infradriver.NewInfraDriver(&cluster)
+			if err != nil {
+				return err
+			}
+			master0 := infraDriver.GetHostIPListByRole(common.MASTER)[0]
+
+			statusList, err := collectAppStatus(records, infraDriver, master0, args)
+			if err != nil {
+				return err
+			}
+
+			if p := printer.Default(); p.IsStructured() {
+				return p.PrintObj(statusList)
+			}
+
+			table := tablewriter.NewWriter(common.StdOut)
+			table.SetHeader([]string{"app", "type", "image", "installed-at", "status"})
+			for _, status := range statusList {
+				table.Append([]string{status.App, status.Type, status.Image, status.InstalledAt, status.Status})
+			}
+			table.Render()
+			return nil
+		},
+	}
+	return statusCmd
+}
+
+// collectAppStatus merges the latest history record of each app with the
+// release health queried from the cluster. Only helm type apps own a release,
+// other types report the launch record only.
+func collectAppStatus(records []application.VersionRecord, infraDriver infradriver.InfraDriver, master0 net.IP, args []string) ([]appStatus, error) {
+	// the latest record of each app is its current version.
+	latest := map[string]application.VersionRecord{}
+	var order []string
+	for _, record := range records {
+		for _, appName := range record.AppNames {
+			if _, ok := latest[appName]; !ok {
+				order = append(order, appName)
+			}
+			latest[appName] = record
+		}
+	}
+
+	if len(args) == 1 {
+		if _, ok := latest[args[0]]; !ok {
+			return nil, fmt.Errorf("app %s is not found in the install history", args[0])
+		}
+		order = []string{args[0]}
+	}
+
+	imageEngine, err := imageengine.NewImageEngine(options.EngineGlobalConfigurations{})
+	if err != nil {
+		return nil, err
+	}
+
+	var statusList []appStatus
+	for _, appName := range order {
+		record := latest[appName]
+		status := appStatus{
+			App:         appName,
+			Image:       record.ImageName,
+			InstalledAt: record.InstalledAt.Format("2006-01-02 15:04:05"),
+		}
+
+		status.Type = lookupAppType(imageEngine, record.ImageName, appName)
+		if status.Type == appdefinition.HelmApp {
+			status.Status = queryHelmReleaseStatus(infraDriver, master0, appName)
+		} else {
+			// only helm type apps own a queryable release.
+			status.Status = "installed"
+		}
+
+		statusList = append(statusList, status)
+	}
+
+	return statusList, nil
+}
+
+// lookupAppType resolves the app type from the image extension of the image
+// the app was installed from. An image which is not available locally any
+// more degrades to an empty type.
+func lookupAppType(imageEngine imageengine.Interface, imageName, appName string) string {
+	imageSpec, err := imageEngine.Inspect(&options.InspectOptions{ImageNameOrID: imageName})
+	if err != nil {
+		logrus.Debugf("failed to inspect image %s for app type: %v", imageName, err)
+		return ""
+	}
+
+	for _, registeredApp := range imageSpec.ImageExtension.Applications {
+		if registeredApp.Name() == appName {
+			return registeredApp.Type()
+		}
+	}
+
+	return ""
+}
+
+// queryHelmReleaseStatus asks helm on master0 for the release health of the
+// app, relying on the secrets-backed release storage written at launch time.
+func queryHelmReleaseStatus(infraDriver infradriver.InfraDriver, master0 net.IP, appName string) string {
+	output, err := infraDriver.Cmd(master0, nil, fmt.Sprintf("helm status %s -o json", appName))
+	if err != nil {
+		logrus.Debugf("failed to query helm release %s: %v", appName, err)
+		return "release not found"
+	}
+
+	var release struct {
+		Info struct {
+			Status string `json:"status"`
+		} `json:"info"`
+	}
+	if err := json.Unmarshal(output, &release); err != nil {
+		logrus.Debugf("failed to parse helm status output of release %s: %v", appName, err)
+		return "unknown"
+	}
+
+	return release.Info.Status
+}
@@ -17,7 +17,10 @@ package cluster
 import (
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"syscall"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/sealerio/sealer/cmd/sealer/cmd/types"
@@ -51,90 +54,17 @@ func NewApplyCmd() *cobra.Command {
 		Example: exampleForApplyCmd,
 		Args:    cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			var (
-				cf               clusterfile.Interface
-				clusterFileData  []byte
-				err              error
-				applyClusterFile = applyFlags.ClusterFile
-				applyMode        = applyFlags.Mode
-			)
 			logrus.Warn("sealer apply command will be deprecated in the future, please use sealer run instead.")
 
-			if applyClusterFile == "" {
+			if applyFlags.ClusterFile == "" {
 				return fmt.Errorf("you must input Clusterfile")
 			}
 
-			clusterFileData, err = os.ReadFile(filepath.Clean(applyClusterFile))
-			if err != nil {
-				return err
+			if applyFlags.Watch {
+				return watchApply(applyFlags)
 			}
 
-			cf, err = clusterfile.NewClusterFile(clusterFileData)
-			if err != nil {
-				return err
-			}
-
-			desiredCluster := cf.GetCluster()
-
-			// use image extension to determine apply type:
-			// scale up cluster, install applications, maybe support upgrade later
-			imageName := desiredCluster.Spec.Image
-			imageEngine, err := imageengine.NewImageEngine(options.EngineGlobalConfigurations{})
-			if err != nil {
-				return err
-			}
-
-			id, err := imageEngine.Pull(&options.PullOptions{
-				Quiet:      false,
-				PullPolicy: "missing",
-				Image:      imageName,
-				Platform:   "local",
-			})
-			if err != nil {
-				return err
-			}
-
-			imageSpec, err := imageEngine.Inspect(&options.InspectOptions{ImageNameOrID: id})
-			if err != nil {
-				return fmt.Errorf("failed to get sealer image extension: %s", err)
-			}
-
-			// NOTE: in some scenarios, we do not need to prepare the app file repeatedly,
-			// such as the cluster and the apps in the same image
-			var skipPrepareAppMaterials bool
-			// ensure that the cluster reaches the final state firstly
-			if imageSpec.ImageExtension.Type == imagev1.KubeInstaller {
-				client := utils.GetClusterClient()
-				if client == nil {
-					// the application will also been installed for a new cluster
-					// TODO: decouple the cluster installation and application installation
-					return applyClusterWithNew(cf, applyMode, imageEngine, imageSpec, applyFlags)
-				}
-
-				clusterUpdated, err := applyClusterWithExisted(cf, client, imageEngine, imageSpec, applyFlags)
-				if err != nil {
-					return err
-				}
-				// NOTE: we should continue to apply application after the cluster is applied successfully
-				// And it's not needed to prepare the app file repeatedly
-				skipPrepareAppMaterials = clusterUpdated
-			}
-
-			// install application
-			//TODO use flag env to construct application directly. at present ,sealer use cluster.env to construct application
-			appSpec := utils.ConstructApplication(cf.GetApplication(), desiredCluster.Spec.CMD,
-				desiredCluster.Spec.APPNames, desiredCluster.Spec.Env)
-			appInstaller, err := NewApplicationInstaller(appSpec, imageSpec.ImageExtension, imageEngine)
-			if err != nil {
-				return err
-			}
-
-			return appInstaller.Install(imageName, AppInstallOptions{
-				Envs:                    desiredCluster.Spec.Env,
-				RunMode:                 applyMode,
-				SkipPrepareAppMaterials: skipPrepareAppMaterials,
-				IgnoreCache:             applyFlags.IgnoreCache,
-			})
+			return runApply(applyFlags)
 		},
 	}
 
@@ -151,10 +81,136 @@ func NewApplyCmd() *cobra.Command {
 	applyCmd.Flags().StringVar(&applyFlags.Pk, "pk", filepath.Join(common.GetHomeDir(), ".ssh", "id_rsa"), "set baremetal server private key")
 	applyCmd.Flags().StringVar(&applyFlags.PkPassword, "pk-passwd", "", "set baremetal server private key password")
 	applyCmd.Flags().BoolVar(&applyFlags.IgnoreCache, "ignore-cache", false, "whether ignore cache when distribute sealer image, default is false.")
+	applyCmd.Flags().BoolVar(&applyFlags.RollbackOnFailure, "rollback-on-failure", false, "reset the touched hosts and restore the previous Clusterfile when the install fails or is interrupted")
+	applyCmd.Flags().BoolVar(&applyFlags.Watch, "watch", false, "run as a reconcile daemon: re-apply the Clusterfile periodically until the process is stopped")
+	applyCmd.Flags().DurationVar(&applyFlags.WatchInterval, "watch-interval", 5*time.Minute, "interval between two reconcile rounds, only valid with --watch")
 
 	return applyCmd
 }
 
+// watchApply reconciles the Clusterfile against the actual cluster until
+// the process is stopped: removed nodes are rejoined and the plugins of the
+// Clusterfile, e.g. labels and taints, are reapplied every round.
+func watchApply(applyFlags *types.ApplyFlags) error {
+	signalCh := make(chan os.Signal, 1)
+	signal.Notify(signalCh, syscall.SIGINT, syscall.SIGTERM)
+
+	logrus.Infof("start to reconcile the cluster every %s", applyFlags.WatchInterval)
+	ticker := time.NewTicker(applyFlags.WatchInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := runApply(applyFlags); err != nil {
+			// a failed round is retried on the next tick, the daemon
+			// itself keeps running
+			logrus.Errorf("reconcile round failed: %v", err)
+		}
+
+		select {
+		case sig := <-signalCh:
+			logrus.Infof("received signal %s, stop reconciling", sig)
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func runApply(applyFlags *types.ApplyFlags) error {
+	var (
+		cf              clusterfile.Interface
+		clusterFileData []byte
+		err             error
+		clusterFilePath = applyFlags.ClusterFile
+	)
+
+	clusterFileData, err = os.ReadFile(filepath.Clean(clusterFilePath))
+	if err != nil {
+		return err
+	}
+
+	// provision cloud hosts first when the Clusterfile asks for a
+	// cloud infra provider such as ALI_CLOUD
+	clusterFileData, err = utils.ProvisionCloudCluster(clusterFileData)
+	if err != nil {
+		return err
+	}
+
+	cf, err = clusterfile.NewClusterFile(clusterFileData)
+	if err != nil {
+		return err
+	}
+
+	return applyClusterFile(cf, applyFlags)
+}
+
+// applyClusterFile drives the apply pipeline for an already decoded
+// Clusterfile: create or scale the cluster first, then install the
+// applications. It is shared by apply and the controller command.
+func applyClusterFile(cf clusterfile.Interface, applyFlags *types.ApplyFlags) error {
+	applyMode := applyFlags.Mode
+	desiredCluster := cf.GetCluster()
+
+	// use image extension to determine apply type:
+	// scale up cluster, install applications, maybe support upgrade later
+	imageName := desiredCluster.Spec.Image
+	imageEngine, err := imageengine.NewImageEngine(options.EngineGlobalConfigurations{})
+	if err != nil {
+		return err
+	}
+
+	id, err := imageEngine.Pull(&options.PullOptions{
+		Quiet:      false,
+		PullPolicy: "missing",
+		Image:      imageName,
+		Platform:   "local",
+	})
+	if err != nil {
+		return err
+	}
+
+	imageSpec, err := imageEngine.Inspect(&options.InspectOptions{ImageNameOrID: id})
+	if err != nil {
+		return fmt.Errorf("failed to get sealer image extension: %s", err)
+	}
+
+	// NOTE: in some scenarios, we do not need to prepare the app file repeatedly,
+	// such as the cluster and the apps in the same image
+	var skipPrepareAppMaterials bool
+	// ensure that the cluster reaches the final state firstly
+	if imageSpec.ImageExtension.Type == imagev1.KubeInstaller {
+		client := utils.GetClusterClient()
+		if client == nil {
+			// the application will also been installed for a new cluster
+			// TODO: decouple the cluster installation and application installation
+			return applyClusterWithNew(cf, applyMode, imageEngine, imageSpec, applyFlags)
+		}
+
+		clusterUpdated, err := applyClusterWithExisted(cf, client, imageEngine, imageSpec, applyFlags)
+		if err != nil {
+			return err
+		}
+		// NOTE: we should continue to apply application after the cluster is applied successfully
+		// And it's not needed to prepare the app file repeatedly
+		skipPrepareAppMaterials = clusterUpdated
+	}
+
+	// install application
+	//TODO use flag env to construct application directly. at present ,sealer use cluster.env to construct application
+	appSpec := utils.ConstructApplication(cf.GetApplication(), desiredCluster.Spec.CMD,
+		desiredCluster.Spec.APPNames, desiredCluster.Spec.Env)
+	appInstaller, err := NewApplicationInstaller(appSpec, imageSpec.ImageExtension, imageEngine)
+	if err != nil {
+		return err
+	}
+
+	return appInstaller.Install(imageName, AppInstallOptions{
+		Envs:                    desiredCluster.Spec.Env,
+		RunMode:                 applyMode,
+		SkipPrepareAppMaterials: skipPrepareAppMaterials,
+		IgnoreCache:             applyFlags.IgnoreCache,
+	})
+}
+
 func applyClusterWithNew(cf clusterfile.Interface, applyMode string,
 	imageEngine imageengine.Interface, imageSpec *imagev1.ImageSpec, applyFlags *types.ApplyFlags) error {
 	desiredCluster := cf.GetCluster()
@@ -186,8 +242,9 @@ func applyClusterWithNew(cf clusterfile.Interface, applyMode string,
 	}
 
 	return kubeInstaller.Install(mergedWithExt.Spec.Image, KubeInstallOptions{
-		RunMode:     applyMode,
-		IgnoreCache: applyFlags.IgnoreCache,
+		RunMode:           applyMode,
+		IgnoreCache:       applyFlags.IgnoreCache,
+		RollbackOnFailure: applyFlags.RollbackOnFailure,
 	})
 }
 
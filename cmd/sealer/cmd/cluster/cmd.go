@@ -20,6 +20,6 @@ import (
 
 func NewClusterCommands() []*cobra.Command {
 	var clusterCommands []*cobra.Command
-	clusterCommands = append(clusterCommands, NewDeleteCmd(), NewJoinCmd(), NewRunCmd(), NewCertCmd(), NewScaleUpCmd(), NewApplyCmd(), NewUpgradeCmd(), NewRollbackCmd())
+	clusterCommands = append(clusterCommands, NewDeleteCmd(), NewJoinCmd(), NewRunCmd(), NewCertCmd(), NewScaleUpCmd(), NewApplyCmd(), NewUpgradeCmd(), NewRollbackCmd(), NewStatusCmd(), NewInitCmd(), NewClusterContextCmd(), NewDiffCmd(), NewControllerCmd(), NewServeCmd(), NewPromoteCmd())
 	return clusterCommands
 }
@@ -0,0 +1,109 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/sealerio/sealer/common"
+)
+
+var longDescriptionForClusterCmd = `cluster command manages the cluster contexts on this machine. Each context
+namespaces the saved Clusterfile, the application state and the fetched
+kubeconfig under ~/.sealer/clusters/<name>, so several clusters can be
+managed from one machine. Select the context to operate on with
+"sealer cluster use <name>" or per invocation with the -c flag.`
+
+var exampleForClusterCmd = `
+  sealer cluster list
+  sealer cluster use my-cluster
+  sealer cluster delete my-cluster
+  sealer -c my-cluster status
+`
+
+func NewClusterContextCmd() *cobra.Command {
+	clusterCmd := &cobra.Command{
+		Use:     "cluster",
+		Short:   "manage the cluster contexts on this machine",
+		Long:    longDescriptionForClusterCmd,
+		Example: exampleForClusterCmd,
+	}
+	clusterCmd.AddCommand(newClusterListCmd(), newClusterUseCmd(), newClusterDeleteCmd())
+	return clusterCmd
+}
+
+func newClusterListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "list the known cluster contexts",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			names, err := common.ListClusterContexts()
+			if err != nil {
+				return err
+			}
+
+			current := common.GetCurrentClusterContext()
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetHeader([]string{"current", "name"})
+			for _, name := range names {
+				marker := ""
+				if name == current {
+					marker = "*"
+				}
+				table.Append([]string{marker, name})
+			}
+			table.Render()
+			return nil
+		},
+	}
+}
+
+func newClusterUseCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "use <name>",
+		Short: "set the current cluster context",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := common.UseClusterContext(args[0]); err != nil {
+				return err
+			}
+			fmt.Printf("switched to cluster context %s\n", args[0])
+			return nil
+		},
+	}
+}
+
+func newClusterDeleteCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete <name>",
+		Short: "delete the local state of a cluster context",
+		Long: `delete removes the saved Clusterfile, application state and kubeconfig of
+the named context from this machine. The cluster itself is not touched; use
+"sealer delete" to tear down a cluster.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := common.DeleteClusterContext(args[0]); err != nil {
+				return err
+			}
+			fmt.Printf("deleted cluster context %s\n", args[0])
+			return nil
+		},
+	}
+}
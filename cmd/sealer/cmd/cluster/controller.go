@@ -0,0 +1,191 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	runtimeClient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/sealerio/sealer/cmd/sealer/cmd/types"
+	"github.com/sealerio/sealer/common"
+	"github.com/sealerio/sealer/pkg/clusterfile"
+	v2 "github.com/sealerio/sealer/types/api/v2"
+)
+
+// lastAppliedGenerationAnnotation records the generation of a Cluster CR
+// that was last applied successfully, so unchanged CRs are skipped.
+const lastAppliedGenerationAnnotation = "sealer.io/last-applied-generation"
+
+type controllerFlags struct {
+	kubeConfig string
+	namespace  string
+	interval   time.Duration
+}
+
+var longDescriptionForControllerCmd = `controller watches the Cluster objects of a management cluster and drives
+the apply pipeline for each of them: a new Cluster object creates a member
+cluster, a changed one is scaled to its desired spec. Run it as a long-lived
+process, either on an admin machine or as a deployment inside the management
+cluster (in-cluster config is used when no kubeconfig is given).`
+
+var exampleForControllerCmd = `
+  sealer controller --kubeconfig ~/.kube/mgmt-config --interval 2m
+`
+
+func NewControllerCmd() *cobra.Command {
+	flags := &controllerFlags{}
+	controllerCmd := &cobra.Command{
+		Use:     "controller",
+		Short:   "reconcile the Cluster objects of a management cluster",
+		Long:    longDescriptionForControllerCmd,
+		Example: exampleForControllerCmd,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cli, err := newManagementClusterClient(flags.kubeConfig)
+			if err != nil {
+				return err
+			}
+			return runController(cli, flags)
+		},
+	}
+
+	controllerCmd.Flags().StringVar(&flags.kubeConfig, "kubeconfig", "", "kubeconfig of the management cluster, defaults to the in-cluster config")
+	controllerCmd.Flags().StringVarP(&flags.namespace, "namespace", "n", "", "only watch Cluster objects in this namespace, defaults to all namespaces")
+	controllerCmd.Flags().DurationVar(&flags.interval, "interval", 2*time.Minute, "interval between two reconcile rounds")
+
+	return controllerCmd
+}
+
+func newManagementClusterClient(kubeConfig string) (runtimeClient.Client, error) {
+	config, err := rest.InClusterConfig()
+	if kubeConfig != "" || err != nil {
+		if kubeConfig == "" {
+			kubeConfig = filepath.Join(common.DefaultKubeConfigDir(), "config")
+		}
+		config, err = clientcmd.BuildConfigFromFlags("", kubeConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load management cluster config: %v", err)
+		}
+	}
+
+	scheme := runtime.NewScheme()
+	if err := v2.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+
+	return runtimeClient.New(config, runtimeClient.Options{Scheme: scheme})
+}
+
+func runController(cli runtimeClient.Client, flags *controllerFlags) error {
+	signalCh := make(chan os.Signal, 1)
+	signal.Notify(signalCh, syscall.SIGINT, syscall.SIGTERM)
+
+	logrus.Infof("start to watch Cluster objects every %s", flags.interval)
+	ticker := time.NewTicker(flags.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := reconcileClusterObjects(cli, flags.namespace); err != nil {
+			logrus.Errorf("reconcile round failed: %v", err)
+		}
+
+		select {
+		case sig := <-signalCh:
+			logrus.Infof("received signal %s, stop watching", sig)
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func reconcileClusterObjects(cli runtimeClient.Client, namespace string) error {
+	ctx := context.Background()
+	clusterList := &v2.ClusterList{}
+	if err := cli.List(ctx, clusterList, &runtimeClient.ListOptions{Namespace: namespace}); err != nil {
+		return fmt.Errorf("failed to list Cluster objects: %v", err)
+	}
+
+	for i := range clusterList.Items {
+		clusterCR := &clusterList.Items[i]
+		if clusterCR.Annotations[lastAppliedGenerationAnnotation] == strconv.FormatInt(clusterCR.Generation, 10) {
+			continue
+		}
+
+		logrus.Infof("start to reconcile cluster %s (generation %d)", clusterCR.Name, clusterCR.Generation)
+		if err := applyClusterObject(clusterCR); err != nil {
+			logrus.Errorf("failed to reconcile cluster %s: %v", clusterCR.Name, err)
+			continue
+		}
+
+		if err := recordAppliedGeneration(ctx, cli, clusterCR); err != nil {
+			logrus.Errorf("failed to record applied generation of cluster %s: %v", clusterCR.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// recordAppliedGeneration annotates the Cluster object with the generation
+// that was just applied. v2.Cluster carries a legacy SetAnnotations helper
+// that hides the metav1.Object one, so the annotation is patched through an
+// unstructured object instead of a typed update.
+func recordAppliedGeneration(ctx context.Context, cli runtimeClient.Client, clusterCR *v2.Cluster) error {
+	patch := []byte(fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`,
+		lastAppliedGenerationAnnotation, strconv.FormatInt(clusterCR.Generation, 10)))
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(v2.GroupVersion.WithKind("Cluster"))
+	obj.SetNamespace(clusterCR.Namespace)
+	obj.SetName(clusterCR.Name)
+
+	return cli.Patch(ctx, obj, runtimeClient.RawPatch(k8stypes.MergePatchType, patch))
+}
+
+// applyClusterObject turns one Cluster object into a Clusterfile and drives
+// the same pipeline as apply.
+func applyClusterObject(clusterCR *v2.Cluster) error {
+	cluster := &v2.Cluster{Spec: clusterCR.Spec}
+	cluster.APIVersion = v2.GroupVersion.String()
+	cluster.Kind = "Cluster"
+	cluster.Name = clusterCR.Name
+
+	data, err := yaml.Marshal(cluster)
+	if err != nil {
+		return err
+	}
+
+	cf, err := clusterfile.NewClusterFile(data)
+	if err != nil {
+		return err
+	}
+
+	return applyClusterFile(cf, &types.ApplyFlags{Mode: common.ApplyModeApply})
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/sealerio/sealer/pkg/clusterfile"
 	"github.com/sealerio/sealer/pkg/define/options"
 	"github.com/sealerio/sealer/pkg/imageengine"
+	"github.com/sealerio/sealer/pkg/infra"
 	"github.com/sealerio/sealer/utils"
 	netutils "github.com/sealerio/sealer/utils/net"
 	"github.com/sirupsen/logrus"
@@ -38,6 +39,8 @@ delete cluster node:
   sealer delete --nodes 192.168.0.1 [--force]
   sealer delete --masters 192.168.0.1 --nodes 192.168.0.2 [--force]
   sealer delete --masters 192.168.0.1-192.168.0.3 --nodes 192.168.0.4-192.168.0.6 [--force]
+delete cluster node and fully reclaim the machine:
+  sealer delete --nodes 192.168.0.1 --prune [--force]
 delete all:
   sealer delete --all [--force]
   sealer delete -a -f Clusterfile [--force]
@@ -83,7 +86,7 @@ func NewDeleteCmd() *cobra.Command {
 	deleteCmd.Flags().StringSliceVarP(&deleteFlags.CustomEnv, "env", "e", []string{}, "set custom environment variables")
 	deleteCmd.Flags().BoolVar(&deleteFlags.ForceDelete, "force", false, "We also can input an --force flag to delete cluster by force")
 	deleteCmd.Flags().BoolVarP(&deleteFlags.DeleteAll, "all", "a", false, "this flags is for delete the entire cluster, default is false")
-	deleteCmd.Flags().BoolVarP(&deleteFlags.Prune, "prune", "p", false, "this flags is for delete all cluster rootfs, default is false")
+	deleteCmd.Flags().BoolVarP(&deleteFlags.Prune, "prune", "p", false, "remove the sealer data dir from the deleted hosts so the machines can be reused, default is false")
 
 	return deleteCmd
 }
@@ -150,9 +153,23 @@ func deleteCluster(workClusterfile string, forceDelete bool, deleteFlags *types.
 		return err
 	}
 
-	return kubeInstaller.Delete(KubeDeleteOptions{
+	if err := kubeInstaller.Delete(KubeDeleteOptions{
 		Prune: deleteFlags.Prune,
-	})
+	}); err != nil {
+		return err
+	}
+
+	// release IaaS resources when the hosts were provisioned by a cloud
+	// infra provider
+	cloudCluster, err := infra.LoadCloudCluster()
+	if err != nil {
+		return err
+	}
+	if cloudCluster != nil {
+		logrus.Infof("start to tear down cloud resources with provider %s", cloudCluster.Spec.Provider)
+		return infra.NewCloudApplier(cloudCluster).Teardown()
+	}
+	return nil
 }
 
 func scaleDownCluster(workClusterfile, masters, workers string, forceDelete bool, deleteFlags *types.DeleteFlags) error {
@@ -0,0 +1,113 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sealerio/sealer/cmd/sealer/cmd/utils"
+	"github.com/sealerio/sealer/pkg/clusterfile"
+	v2 "github.com/sealerio/sealer/types/api/v2"
+)
+
+var diffClusterFile string
+
+var longDescriptionForDiffCmd = `diff compares the desired Clusterfile against the live cluster and prints a
+human readable plan of the adds ("+"), removes ("-") and changes ("~") an
+apply would perform. It is the read-only counterpart to apply and never
+touches the cluster.`
+
+var exampleForDiffCmd = `
+  sealer diff -f Clusterfile
+`
+
+func NewDiffCmd() *cobra.Command {
+	diffCmd := &cobra.Command{
+		Use:     "diff",
+		Short:   "show what apply would change on the cluster",
+		Long:    longDescriptionForDiffCmd,
+		Example: exampleForDiffCmd,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if diffClusterFile == "" {
+				return fmt.Errorf("you must input Clusterfile")
+			}
+
+			clusterFileData, err := os.ReadFile(filepath.Clean(diffClusterFile))
+			if err != nil {
+				return err
+			}
+
+			cf, err := clusterfile.NewClusterFile(clusterFileData)
+			if err != nil {
+				return err
+			}
+			desiredCluster := cf.GetCluster()
+
+			actualCluster, err := getActualClusterState()
+			if err != nil {
+				return err
+			}
+			if actualCluster == nil {
+				fmt.Println("cluster does not exist, apply would create it:")
+				actualCluster = &v2.Cluster{}
+			}
+
+			plan := utils.DiffClusters(actualCluster, &desiredCluster)
+			if len(plan) == 0 {
+				fmt.Println("no changes, the cluster matches the desired spec")
+				return nil
+			}
+			for _, line := range plan {
+				fmt.Println(line)
+			}
+			return nil
+		},
+	}
+
+	diffCmd.Flags().StringVarP(&diffClusterFile, "Clusterfile", "f", "", "Clusterfile path to compare against the live cluster")
+
+	return diffCmd
+}
+
+// getActualClusterState reconstructs the actual cluster state: image, env
+// and apps come from the Clusterfile committed to the cluster, the host
+// list from the live nodes. A nil cluster means there is no cluster yet.
+func getActualClusterState() (*v2.Cluster, error) {
+	client := utils.GetClusterClient()
+	if client == nil {
+		return nil, nil
+	}
+
+	actualCF, _, err := clusterfile.GetActualClusterFile()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get actual clusterfile: %v", err)
+	}
+	actualCluster := actualCF.GetCluster()
+
+	// the node list reported by the runtime is more truthful than the saved
+	// clusterfile, e.g. when a node was joined or removed out of band
+	liveCluster, err := utils.GetCurrentCluster(client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cluster nodes: %v", err)
+	}
+	actualCluster.Spec.Hosts = liveCluster.Spec.Hosts
+
+	return &actualCluster, nil
+}
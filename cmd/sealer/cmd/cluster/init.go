@@ -0,0 +1,182 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"github.com/sealerio/sealer/cmd/sealer/cmd/types"
+	"github.com/sealerio/sealer/cmd/sealer/cmd/utils"
+	"github.com/sealerio/sealer/common"
+	"github.com/sealerio/sealer/pkg/clusterfile"
+	"github.com/sealerio/sealer/pkg/infradriver"
+	v2 "github.com/sealerio/sealer/types/api/v2"
+)
+
+var longDescriptionForInitCmd = `init command generates a complete, validated Clusterfile skeleton. Values not
+given through flags are asked for interactively, and the result can be checked
+against the hosts with a connectivity preflight before it is written out.`
+
+var exampleForInitCmd = `
+generate a Clusterfile interactively:
+  sealer init
+
+generate a Clusterfile from flags only and check host connectivity:
+  sealer init --image docker.io/sealerio/kubernetes:v1.22.15 \
+    --masters 192.168.0.1 --nodes 192.168.0.2 -p 'Sealer123' --preflight
+`
+
+type initFlag struct {
+	Image       string
+	ClusterName string
+	Path        string
+	Preflight   bool
+	RunFlags    *types.RunFlags
+}
+
+func NewInitCmd() *cobra.Command {
+	initFlags := &initFlag{RunFlags: &types.RunFlags{}}
+	initCmd := &cobra.Command{
+		Use:     "init",
+		Short:   "generate a Clusterfile skeleton interactively or from flags",
+		Long:    longDescriptionForInitCmd,
+		Args:    cobra.NoArgs,
+		Example: exampleForInitCmd,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return initRunFunc(initFlags)
+		},
+	}
+
+	initCmd.Flags().StringVar(&initFlags.Image, "image", "", "set the sealer image to run")
+	initCmd.Flags().StringVar(&initFlags.ClusterName, "cluster-name", "my-cluster", "set the cluster name")
+	initCmd.Flags().StringVar(&initFlags.Path, "path", "Clusterfile", "set where to write the generated Clusterfile")
+	initCmd.Flags().BoolVar(&initFlags.Preflight, "preflight", false, "check ssh connectivity of all hosts before writing the Clusterfile")
+	initCmd.Flags().StringVarP(&initFlags.RunFlags.Masters, "masters", "m", "", "set count or IPList to masters")
+	initCmd.Flags().StringVarP(&initFlags.RunFlags.Nodes, "nodes", "n", "", "set count or IPList to nodes")
+	initCmd.Flags().StringVarP(&initFlags.RunFlags.User, "user", "u", "root", "set baremetal server username")
+	initCmd.Flags().StringVarP(&initFlags.RunFlags.Password, "passwd", "p", "", "set cloud provider or baremetal server password")
+	initCmd.Flags().Uint16Var(&initFlags.RunFlags.Port, "port", 22, "set the sshd service port number for the server (default port: 22)")
+	initCmd.Flags().StringVar(&initFlags.RunFlags.Pk, "pk", filepath.Join(common.GetHomeDir(), ".ssh", "id_rsa"), "set baremetal server private key")
+	initCmd.Flags().StringVar(&initFlags.RunFlags.PkPassword, "pk-passwd", "", "set baremetal server private key password")
+	initCmd.Flags().StringSliceVarP(&initFlags.RunFlags.CustomEnv, "env", "e", []string{}, "set custom environment variables")
+
+	return initCmd
+}
+
+func initRunFunc(initFlags *initFlag) error {
+	reader := bufio.NewReader(os.Stdin)
+
+	// ask for whatever the flags left empty
+	if initFlags.Image == "" {
+		initFlags.Image = promptValue(reader, "sealer image to run", "")
+		if initFlags.Image == "" {
+			return fmt.Errorf("sealer image is required")
+		}
+	}
+	if initFlags.RunFlags.Masters == "" {
+		initFlags.RunFlags.Masters = promptValue(reader, "master ip list (comma separated)", "")
+		if initFlags.RunFlags.Masters == "" {
+			return fmt.Errorf("at least one master is required")
+		}
+	}
+	if initFlags.RunFlags.Nodes == "" {
+		initFlags.RunFlags.Nodes = promptValue(reader, "node ip list (comma separated, empty for none)", "")
+	}
+	if initFlags.RunFlags.Password == "" {
+		initFlags.RunFlags.Password = promptValue(reader, fmt.Sprintf("ssh password (empty to use private key %s)", initFlags.RunFlags.Pk), "")
+	}
+
+	if err := utils.ValidateRunHosts(initFlags.RunFlags.Masters, initFlags.RunFlags.Nodes); err != nil {
+		return fmt.Errorf("failed to validate input hosts: %v", err)
+	}
+
+	cluster, err := utils.ConstructClusterForRun(initFlags.Image, initFlags.RunFlags)
+	if err != nil {
+		return err
+	}
+	cluster.Name = initFlags.ClusterName
+
+	content, err := yaml.Marshal(cluster)
+	if err != nil {
+		return err
+	}
+
+	// run the generated Clusterfile through the regular decoder so the
+	// user gets the same validation errors apply would produce
+	if _, err := clusterfile.NewClusterFile(content); err != nil {
+		return err
+	}
+
+	if initFlags.Preflight {
+		if err := preflightPing(cluster); err != nil {
+			return err
+		}
+	}
+
+	if err := os.WriteFile(initFlags.Path, content, 0600); err != nil {
+		return fmt.Errorf("failed to write Clusterfile to %s: %v", initFlags.Path, err)
+	}
+
+	logrus.Infof("succeeded in generating Clusterfile at %s, run `sealer apply -f %s` to bring the cluster up", initFlags.Path, initFlags.Path)
+	return nil
+}
+
+func promptValue(reader *bufio.Reader, prompt, defaultValue string) string {
+	if defaultValue != "" {
+		fmt.Printf("%s [%s]: ", prompt, defaultValue)
+	} else {
+		fmt.Printf("%s: ", prompt)
+	}
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return defaultValue
+	}
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return defaultValue
+	}
+	return line
+}
+
+// preflightPing checks that every host of the generated cluster is
+// reachable over ssh.
+func preflightPing(cluster *v2.Cluster) error {
+	infraDriver, err := infradriver.NewInfraDriver(cluster)
+	if err != nil {
+		return err
+	}
+
+	var unreachable []string
+	for _, host := range infraDriver.GetHostIPList() {
+		if err := infraDriver.Ping(host); err != nil {
+			logrus.Warnf("host %s is not reachable: %v", host, err)
+			unreachable = append(unreachable, host.String())
+			continue
+		}
+		logrus.Infof("host %s is reachable", host)
+	}
+	if len(unreachable) != 0 {
+		return fmt.Errorf("preflight failed, unreachable hosts: %s", strings.Join(unreachable, ","))
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/sealerio/sealer/cmd/sealer/cmd/types"
+	"github.com/sealerio/sealer/common"
+	"github.com/sealerio/sealer/pkg/clusterfile"
+	"github.com/sealerio/sealer/pkg/infradriver"
+	"github.com/sealerio/sealer/pkg/runtime/kubernetes"
+	v2 "github.com/sealerio/sealer/types/api/v2"
+	"github.com/sealerio/sealer/utils"
+	netutils "github.com/sealerio/sealer/utils/net"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exampleForPromoteCmd = `
+promote another master to master0:
+  sealer promote-master 192.168.0.3
+promote and remove the original master0 from the cluster:
+  sealer promote-master 192.168.0.3 --remove-old [--force]
+`
+	longDescriptionForPromoteCmd = `promote-master command moves the "master0" role to another existing master,
+the host where kubeadm certs and kubeconfig operations run. Use it when the
+first control-plane machine is lost or about to be retired, so scale and
+upgrade operations no longer depend on it.`
+)
+
+func NewPromoteCmd() *cobra.Command {
+	var (
+		removeOld   bool
+		forceDelete bool
+	)
+
+	promoteCmd := &cobra.Command{
+		Use:     "promote-master",
+		Short:   "promote another existing master to master0",
+		Long:    longDescriptionForPromoteCmd,
+		Example: exampleForPromoteCmd,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			newMaster0 := net.ParseIP(args[0])
+			if newMaster0 == nil {
+				return fmt.Errorf("invalid master IP: %s", args[0])
+			}
+
+			return promoteMaster0(newMaster0, removeOld, forceDelete)
+		},
+	}
+
+	promoteCmd.Flags().BoolVar(&removeOld, "remove-old", false, "remove the original master0 from the cluster after the promotion")
+	promoteCmd.Flags().BoolVar(&forceDelete, "force", false, "skip the confirmation when removing the original master0")
+
+	return promoteCmd
+}
+
+func promoteMaster0(newMaster0 net.IP, removeOld, forceDelete bool) error {
+	cf, _, err := clusterfile.GetActualClusterFile()
+	if err != nil {
+		return err
+	}
+
+	cluster := cf.GetCluster()
+	oldMaster0 := cluster.GetMaster0IP()
+
+	if newMaster0.Equal(oldMaster0) {
+		logrus.Infof("%s is already master0, nothing to do", newMaster0)
+		return nil
+	}
+
+	if !netutils.IsInIPList(newMaster0, cluster.GetMasterIPList()) {
+		return fmt.Errorf("%s is not a master of cluster %s, join it as master first", newMaster0, cluster.Name)
+	}
+
+	// in noHA mode the builtin registry and its data live on master0,
+	// moving them is not supported
+	if cluster.Spec.Registry.LocalRegistry != nil && !*cluster.Spec.Registry.LocalRegistry.HA {
+		return fmt.Errorf("cannot promote another master0 when the local registry is in noHA mode, it is served from master0(%s)", oldMaster0)
+	}
+
+	infraDriver, err := infradriver.NewInfraDriver(&cluster)
+	if err != nil {
+		return err
+	}
+
+	// the new master0 must already hold the admin kubeconfig, certs and
+	// kubeconfig operations will run there from now on
+	if exist, err := infraDriver.IsFileExist(newMaster0, kubernetes.AdminKubeConfPath); err != nil {
+		return fmt.Errorf("failed to check %s on the new master0 %s: %v", kubernetes.AdminKubeConfPath, newMaster0, err)
+	} else if !exist {
+		return fmt.Errorf("%s not found on %s, it does not look like a joined control-plane node", kubernetes.AdminKubeConfPath, newMaster0)
+	}
+
+	cluster.Spec.Hosts = moveMasterToFront(cluster.Spec.Hosts, newMaster0)
+	cf.SetCluster(cluster)
+
+	if err := cf.SaveAll(clusterfile.SaveOptions{CommitToCluster: true}); err != nil {
+		return err
+	}
+
+	logrus.Infof("succeeded in promoting %s to master0 of cluster %s", newMaster0, cluster.Name)
+
+	if !removeOld {
+		return nil
+	}
+
+	if !forceDelete {
+		if pass, err := utils.ConfirmOperation(fmt.Sprintf("Are you sure to remove the original master0 %s:", oldMaster0)); err != nil {
+			return err
+		} else if !pass {
+			return fmt.Errorf("exit the operation of removing the original master0")
+		}
+	}
+
+	return scaleDownCluster("", oldMaster0.String(), "", true, &types.DeleteFlags{})
+}
+
+// moveMasterToFront rebuilds the host list so that the given master IP is
+// the first master entry, keeping the host level SSH, env, labels and
+// taints it was declared with.
+func moveMasterToFront(hosts []v2.Host, master net.IP) []v2.Host {
+	var (
+		newHosts  []v2.Host
+		master0   v2.Host
+		master0OK bool
+	)
+
+	for _, host := range hosts {
+		if !netutils.IsInIPList(master, host.IPS) {
+			newHosts = append(newHosts, host)
+			continue
+		}
+
+		master0 = host
+		master0.IPS = []net.IP{master}
+		master0.Roles = append([]string{}, host.Roles...)
+		master0OK = true
+
+		remainder := host
+		remainder.IPS = netutils.RemoveIPs(host.IPS, []net.IP{master})
+		if len(remainder.IPS) > 0 {
+			newHosts = append(newHosts, remainder)
+		}
+	}
+
+	if !master0OK {
+		return hosts
+	}
+
+	// make sure the promoted entry carries the master role even when it
+	// was declared through a combined role list
+	var hasMasterRole bool
+	for _, role := range master0.Roles {
+		if role == common.MASTER {
+			hasMasterRole = true
+			break
+		}
+	}
+	if !hasMasterRole {
+		master0.Roles = append(master0.Roles, common.MASTER)
+	}
+
+	return append([]v2.Host{master0}, newHosts...)
+}
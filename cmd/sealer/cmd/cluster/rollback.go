@@ -201,7 +201,7 @@ func rollbackCluster(cf clusterfile.Interface, imageEngine imageengine.Interface
 	if err = v2App.Launch(infraDriver); err != nil {
 		return err
 	}
-	if err = v2App.Save(application.SaveOptions{}); err != nil {
+	if err = v2App.Save(application.SaveOptions{ImageName: imageName}); err != nil {
 		return err
 	}
 
@@ -26,6 +26,10 @@ import (
 	imagev1 "github.com/sealerio/sealer/pkg/define/image/v1"
 	"github.com/sealerio/sealer/pkg/define/options"
 	"github.com/sealerio/sealer/pkg/imageengine"
+	"github.com/sealerio/sealer/pkg/progress"
+	v2 "github.com/sealerio/sealer/types/api/v2"
+	"github.com/sealerio/sealer/utils/strings"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"sigs.k8s.io/yaml"
 )
@@ -71,8 +75,32 @@ func NewRunCmd() *cobra.Command {
 				return fmt.Errorf("you must input image name Or use Clusterfile")
 			}
 
-			if err = utils.ValidateRunHosts(runFlags.Masters, runFlags.Nodes); err != nil {
-				return fmt.Errorf("failed to validate input run master or node: %v", err)
+			// with an infra provider the masters/nodes flags carry counts
+			// instead of host IPs, the provider allocates the machines
+			if runFlags.Provider == "" || runFlags.Provider == common.BAREMETAL {
+				if err = utils.ValidateRunHosts(runFlags.Masters, runFlags.Nodes); err != nil {
+					return fmt.Errorf("failed to validate input run master or node: %v", err)
+				}
+			}
+
+			if runFlags.MaxJoinFailures > 0 {
+				runFlags.CustomEnv = append(runFlags.CustomEnv, fmt.Sprintf("%s=%d", common.EnvMaxJoinFailures, runFlags.MaxJoinFailures))
+			}
+
+			if runFlags.PodCIDR != "" {
+				runFlags.CustomEnv = append(runFlags.CustomEnv, fmt.Sprintf("%s=%s", common.EnvPodCIDR, runFlags.PodCIDR))
+			}
+			if runFlags.SvcCIDR != "" {
+				runFlags.CustomEnv = append(runFlags.CustomEnv, fmt.Sprintf("%s=%s", common.EnvSvcCIDR, runFlags.SvcCIDR))
+			}
+			if runFlags.DNSDomain != "" {
+				runFlags.CustomEnv = append(runFlags.CustomEnv, fmt.Sprintf("%s=%s", common.EnvDNSDomain, runFlags.DNSDomain))
+			}
+			if runFlags.Profile != "" {
+				if runFlags.Profile != common.HardeningProfileCIS {
+					return fmt.Errorf("unknown --profile %q, only %q is supported", runFlags.Profile, common.HardeningProfileCIS)
+				}
+				runFlags.CustomEnv = append(runFlags.CustomEnv, fmt.Sprintf("%s=%s", common.EnvHardeningProfile, runFlags.Profile))
 			}
 
 			if clusterFile != "" {
@@ -83,8 +111,7 @@ func NewRunCmd() *cobra.Command {
 		},
 	}
 
-	//todo remove provider Flag now, maybe we can support it later
-	//runCmd.Flags().StringVarP(&runFlags.Provider, "provider", "", "", "set infra provider, example `ALI_CLOUD`, the local server need ignore this")
+	runCmd.Flags().StringVarP(&runFlags.Provider, "provider", "", "", "set infra provider, example `ALI_CLOUD` or `AWS_CLOUD`, the local server need ignore this")
 	runCmd.Flags().StringVarP(&runFlags.Masters, "masters", "m", "", "set count or IPList to masters")
 	runCmd.Flags().StringVarP(&runFlags.Nodes, "nodes", "n", "", "set count or IPList to nodes")
 	runCmd.Flags().StringVarP(&runFlags.User, "user", "u", "root", "set baremetal server username")
@@ -92,31 +119,40 @@ func NewRunCmd() *cobra.Command {
 	runCmd.Flags().Uint16Var(&runFlags.Port, "port", 22, "set the sshd service port number for the server (default port: 22)")
 	runCmd.Flags().StringVar(&runFlags.Pk, "pk", filepath.Join(common.GetHomeDir(), ".ssh", "id_rsa"), "set baremetal server private key")
 	runCmd.Flags().StringVar(&runFlags.PkPassword, "pk-passwd", "", "set baremetal server private key password")
+	runCmd.Flags().StringVar(&runFlags.Profile, "profile", "", "apply a hardening profile during install, currently only `cis`")
 	runCmd.Flags().StringSliceVar(&runFlags.Cmds, "cmds", []string{}, "override default LaunchCmds of sealer image")
 	runCmd.Flags().StringSliceVar(&runFlags.AppNames, "apps", nil, "override default AppNames of sealer image")
 	runCmd.Flags().StringSliceVarP(&runFlags.CustomEnv, "env", "e", []string{}, "set custom environment variables")
 	runCmd.Flags().StringVarP(&runFlags.ClusterFile, "Clusterfile", "f", "", "Clusterfile path to run a Kubernetes cluster")
 	runCmd.Flags().StringVar(&runFlags.Mode, "mode", common.ApplyModeApply, "load images to the specified registry in advance")
 	runCmd.Flags().BoolVar(&runFlags.IgnoreCache, "ignore-cache", false, "whether ignore cache when distribute sealer image, default is false.")
-	runCmd.Flags().StringVar(&runFlags.Distributor, "distributor", "sftp", "distribution method to use (sftp, p2p), default is sftp.")
-
-	//err := runCmd.RegisterFlagCompletionFunc("provider", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-	//	return strings.ContainPartial([]string{common.BAREMETAL, common.AliCloud, common.CONTAINER}, toComplete), cobra.ShellCompDirectiveNoFileComp
-	//})
-	//if err != nil {
-	//	logrus.Errorf("provide completion for provider flag, err: %v", err)
-	//	os.Exit(1)
-	//}
+	runCmd.Flags().BoolVar(&runFlags.RollbackOnFailure, "rollback-on-failure", false, "reset the touched hosts and restore the previous Clusterfile when the install fails or is interrupted")
+	runCmd.Flags().IntVar(&runFlags.MaxJoinFailures, "max-join-failures", 0, "tolerate up to this many worker join failures instead of aborting the whole apply (default 0: fail fast)")
+	runCmd.Flags().StringVar(&runFlags.PodCIDR, "pod-cidr", "", "set the pod network CIDR, such as 10.233.0.0/18")
+	runCmd.Flags().StringVar(&runFlags.SvcCIDR, "svc-cidr", "", "set the service network CIDR, such as 10.96.0.0/22")
+	runCmd.Flags().StringVar(&runFlags.DNSDomain, "dns-domain", "", "set the cluster dns domain (default cluster.local)")
+	runCmd.Flags().StringVar(&runFlags.CNI, "cni", "", "launch only the chosen CNI app of the image, such as calico, cilium or flannel")
+	runCmd.Flags().StringVar(&runFlags.Distributor, "distributor", "sftp", "distribution method to use (sftp, p2p, registry), default is sftp.")
+
+	err := runCmd.RegisterFlagCompletionFunc("provider", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return strings.ContainPartial([]string{common.BAREMETAL, common.AliCloud, common.AWSCloud, common.LIBVIRT, common.TERRAFORM, common.CONTAINER}, toComplete), cobra.ShellCompDirectiveNoFileComp
+	})
+	if err != nil {
+		logrus.Errorf("provide completion for provider flag, err: %v", err)
+		os.Exit(1)
+	}
 	return runCmd
 }
 
 func runWithClusterfile(clusterFile string, runFlags *types.RunFlags) error {
-	var p2p bool
+	var distribution types.DistributionMethod
 	switch runFlags.Distributor {
 	case "sftp":
-		p2p = false
+		distribution = types.SFTPDistribution
 	case "p2p":
-		p2p = true
+		distribution = types.P2PDistribution
+	case "registry":
+		distribution = types.RegistryDistribution
 	default:
 		return fmt.Errorf("invalid distributor %s", runFlags.Distributor)
 	}
@@ -155,6 +191,8 @@ func runWithClusterfile(clusterFile string, runFlags *types.RunFlags) error {
 		return err
 	}
 
+	reporter := progress.Default()
+	reporter.PhaseStarted(progress.PhasePullImage, 0)
 	id, err := imageEngine.Pull(&options.PullOptions{
 		Quiet:      false,
 		PullPolicy: "missing",
@@ -162,8 +200,10 @@ func runWithClusterfile(clusterFile string, runFlags *types.RunFlags) error {
 		Platform:   "local",
 	})
 	if err != nil {
+		reporter.PhaseFinished(progress.PhasePullImage, err)
 		return err
 	}
+	reporter.PhaseFinished(progress.PhasePullImage, nil)
 
 	imageSpec, err := imageEngine.Inspect(&options.InspectOptions{ImageNameOrID: id})
 	if err != nil {
@@ -182,29 +222,45 @@ func runWithClusterfile(clusterFile string, runFlags *types.RunFlags) error {
 			Envs:         runFlags.CustomEnv,
 			RunMode:      runFlags.Mode,
 			IgnoreCache:  runFlags.IgnoreCache,
-			Distribution: types.P2PDistribution,
+			Distribution: distribution,
 		})
 	}
 
+	if runFlags.CNI != "" {
+		selected := cf.GetCluster()
+		if err := utils.SelectCNIApp(&selected, imageSpec.ImageExtension, runFlags.CNI); err != nil {
+			return err
+		}
+		cf.SetCluster(selected)
+	}
+
+	withGPUApp := cf.GetCluster()
+	utils.EnsureGPUApp(&withGPUApp, imageSpec.ImageExtension)
+	cf.SetCluster(withGPUApp)
+
 	kubeInstaller, err := NewKubeInstaller(cf, imageEngine, imageSpec)
 	if err != nil {
 		return err
 	}
 
 	return kubeInstaller.Install(imageName, KubeInstallOptions{
-		RunMode:         runFlags.Mode,
-		IgnoreCache:     runFlags.IgnoreCache,
-		P2PDistribution: p2p,
+		RunMode:              runFlags.Mode,
+		IgnoreCache:          runFlags.IgnoreCache,
+		P2PDistribution:      distribution == types.P2PDistribution,
+		RegistryDistribution: distribution == types.RegistryDistribution,
+		RollbackOnFailure:    runFlags.RollbackOnFailure,
 	})
 }
 
 func runWithArgs(imageName string, runFlags *types.RunFlags) error {
-	var p2p bool
+	var distribution types.DistributionMethod
 	switch runFlags.Distributor {
 	case "sftp":
-		p2p = false
+		distribution = types.SFTPDistribution
 	case "p2p":
-		p2p = true
+		distribution = types.P2PDistribution
+	case "registry":
+		distribution = types.RegistryDistribution
 	default:
 		return fmt.Errorf("invalid distributor %s", runFlags.Distributor)
 	}
@@ -214,6 +270,8 @@ func runWithArgs(imageName string, runFlags *types.RunFlags) error {
 		return err
 	}
 
+	reporter := progress.Default()
+	reporter.PhaseStarted(progress.PhasePullImage, 0)
 	id, err := imageEngine.Pull(&options.PullOptions{
 		Quiet:      false,
 		PullPolicy: "missing",
@@ -221,8 +279,10 @@ func runWithArgs(imageName string, runFlags *types.RunFlags) error {
 		Platform:   "local",
 	})
 	if err != nil {
+		reporter.PhaseFinished(progress.PhasePullImage, err)
 		return err
 	}
+	reporter.PhaseFinished(progress.PhasePullImage, nil)
 
 	imageSpec, err := imageEngine.Inspect(&options.InspectOptions{ImageNameOrID: id})
 	if err != nil {
@@ -241,11 +301,16 @@ func runWithArgs(imageName string, runFlags *types.RunFlags) error {
 			Envs:         runFlags.CustomEnv,
 			RunMode:      runFlags.Mode,
 			IgnoreCache:  runFlags.IgnoreCache,
-			Distribution: types.P2PDistribution,
+			Distribution: distribution,
 		})
 	}
 
-	cluster, err := utils.ConstructClusterForRun(imageName, runFlags)
+	var cluster *v2.Cluster
+	if runFlags.Provider != "" && runFlags.Provider != common.BAREMETAL {
+		cluster, err = utils.ProvisionClusterOnProvider(imageName, runFlags)
+	} else {
+		cluster, err = utils.ConstructClusterForRun(imageName, runFlags)
+	}
 	if err != nil {
 		return err
 	}
@@ -260,14 +325,28 @@ func runWithArgs(imageName string, runFlags *types.RunFlags) error {
 		return err
 	}
 
+	if runFlags.CNI != "" {
+		selected := cf.GetCluster()
+		if err := utils.SelectCNIApp(&selected, imageSpec.ImageExtension, runFlags.CNI); err != nil {
+			return err
+		}
+		cf.SetCluster(selected)
+	}
+
+	withGPUApp := cf.GetCluster()
+	utils.EnsureGPUApp(&withGPUApp, imageSpec.ImageExtension)
+	cf.SetCluster(withGPUApp)
+
 	kubeInstaller, err := NewKubeInstaller(cf, imageEngine, imageSpec)
 	if err != nil {
 		return err
 	}
 
 	return kubeInstaller.Install(imageName, KubeInstallOptions{
-		RunMode:         runFlags.Mode,
-		IgnoreCache:     runFlags.IgnoreCache,
-		P2PDistribution: p2p,
+		RunMode:              runFlags.Mode,
+		IgnoreCache:          runFlags.IgnoreCache,
+		P2PDistribution:      distribution == types.P2PDistribution,
+		RegistryDistribution: distribution == types.RegistryDistribution,
+		RollbackOnFailure:    runFlags.RollbackOnFailure,
 	})
 }
@@ -21,9 +21,11 @@ import (
 	"github.com/sealerio/sealer/cmd/sealer/cmd/types"
 	"github.com/sealerio/sealer/cmd/sealer/cmd/utils"
 	"github.com/sealerio/sealer/common"
+	"github.com/sealerio/sealer/pkg/checkpoint"
 	"github.com/sealerio/sealer/pkg/clusterfile"
 	"github.com/sealerio/sealer/pkg/define/options"
 	"github.com/sealerio/sealer/pkg/imageengine"
+	netutils "github.com/sealerio/sealer/utils/net"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
@@ -33,6 +35,8 @@ var (
 scale-up cluster:
   sealer scale-up --masters 192.168.0.1 --nodes 192.168.0.2 -p 'Sealer123'
   sealer scale-up --masters 192.168.0.1-192.168.0.3 --nodes 192.168.0.4-192.168.0.6 -p 'Sealer123'
+scale-up cluster with host level labels, taints and env:
+  sealer scale-up --nodes 192.168.0.2 --labels ssd=true --taints key=value:NoSchedule -e FOO=bar
 `
 	longDescriptionForScaleUpCmd = `scale-up command is used to scale-up master or node to the existing cluster.
 User can scale-up cluster by explicitly specifying host IP`
@@ -41,6 +45,8 @@ User can scale-up cluster by explicitly specifying host IP`
 join cluster:
   sealer join --masters 192.168.0.1 --nodes 192.168.0.2 -p 'Sealer123'
   sealer join --masters 192.168.0.1-192.168.0.3 --nodes 192.168.0.4-192.168.0.6 -p 'Sealer123'
+join cluster with host level labels, taints and env:
+  sealer join --nodes 192.168.0.2 --labels ssd=true --taints key=value:NoSchedule -e FOO=bar
 `
 	longDescriptionForJoinCmd = `join command is used to join master or node to the existing cluster.
 User can join cluster by explicitly specifying host IP`
@@ -70,6 +76,11 @@ func NewJoinCmd() *cobra.Command {
 	joinCmd.Flags().StringVarP(&joinFlags.Masters, "masters", "m", "", "set Count or IPList to masters")
 	joinCmd.Flags().StringVarP(&joinFlags.Nodes, "nodes", "n", "", "set Count or IPList to nodes")
 	joinCmd.Flags().BoolVar(&joinFlags.IgnoreCache, "ignore-cache", false, "whether ignore cache when distribute sealer image, default is false.")
+	joinCmd.Flags().IntVar(&joinFlags.MaxJoinFailures, "max-join-failures", 0, "tolerate up to this many worker join failures instead of aborting the whole join (default 0: fail fast)")
+	joinCmd.Flags().BoolVar(&joinFlags.RetryFailed, "retry-failed", false, "retry the hosts recorded as failed to join by a previous run")
+	joinCmd.Flags().StringSliceVar(&joinFlags.Labels, "labels", nil, "set node labels for the joined hosts, such as ssd=true")
+	joinCmd.Flags().StringSliceVar(&joinFlags.Taints, "taints", nil, "set node taints for the joined hosts, such as key=value:NoSchedule")
+	joinCmd.Flags().StringVar(&joinFlags.Distributor, "distributor", "sftp", "distribution method to use (sftp, registry), default is sftp.")
 
 	return joinCmd
 }
@@ -96,6 +107,11 @@ func NewScaleUpCmd() *cobra.Command {
 	scaleUpFlagsCmd.Flags().StringVarP(&scaleUpFlags.Masters, "masters", "m", "", "set Count or IPList to masters")
 	scaleUpFlagsCmd.Flags().StringVarP(&scaleUpFlags.Nodes, "nodes", "n", "", "set Count or IPList to nodes")
 	scaleUpFlagsCmd.Flags().BoolVar(&scaleUpFlags.IgnoreCache, "ignore-cache", false, "whether ignore cache when distribute sealer image, default is false.")
+	scaleUpFlagsCmd.Flags().IntVar(&scaleUpFlags.MaxJoinFailures, "max-join-failures", 0, "tolerate up to this many worker join failures instead of aborting the whole join (default 0: fail fast)")
+	scaleUpFlagsCmd.Flags().BoolVar(&scaleUpFlags.RetryFailed, "retry-failed", false, "retry the hosts recorded as failed to join by a previous run")
+	scaleUpFlagsCmd.Flags().StringSliceVar(&scaleUpFlags.Labels, "labels", nil, "set node labels for the joined hosts, such as ssd=true")
+	scaleUpFlagsCmd.Flags().StringSliceVar(&scaleUpFlags.Taints, "taints", nil, "set node taints for the joined hosts, such as key=value:NoSchedule")
+	scaleUpFlagsCmd.Flags().StringVar(&scaleUpFlags.Distributor, "distributor", "sftp", "distribution method to use (sftp, registry), default is sftp.")
 
 	return scaleUpFlagsCmd
 }
@@ -106,6 +122,20 @@ func scaleUpRunFunc(scaleUpFlags *types.ScaleUpFlags) error {
 		err error
 	)
 
+	var registryDistribution bool
+	switch scaleUpFlags.Distributor {
+	case "sftp":
+		registryDistribution = false
+	case "registry":
+		registryDistribution = true
+	default:
+		return fmt.Errorf("invalid distributor %s", scaleUpFlags.Distributor)
+	}
+
+	if scaleUpFlags.MaxJoinFailures > 0 {
+		scaleUpFlags.CustomEnv = append(scaleUpFlags.CustomEnv, fmt.Sprintf("%s=%d", common.EnvMaxJoinFailures, scaleUpFlags.MaxJoinFailures))
+	}
+
 	if err = utils.ValidateScaleIPStr(scaleUpFlags.Masters, scaleUpFlags.Nodes); err != nil {
 		return fmt.Errorf("failed to validate input run args: %v", err)
 	}
@@ -121,6 +151,24 @@ func scaleUpRunFunc(scaleUpFlags *types.ScaleUpFlags) error {
 	}
 
 	cluster := cf.GetCluster()
+
+	if scaleUpFlags.RetryFailed {
+		cp, err := checkpoint.NewManager(cluster.Name)
+		if err != nil {
+			return err
+		}
+		failedHosts := cp.FailedJoinHosts()
+		if len(failedHosts) == 0 {
+			logrus.Infof("no failed join hosts recorded for cluster %s", cluster.Name)
+		} else {
+			logrus.Infof("retrying previously failed join hosts: %v", failedHosts)
+			scaleUpNodeIPList = append(scaleUpNodeIPList, netutils.IPStrsToIPs(failedHosts)...)
+			if err := cp.ClearFailedJoinHosts(); err != nil {
+				return err
+			}
+		}
+	}
+
 	client := utils.GetClusterClient()
 	if client == nil {
 		return fmt.Errorf("failed to get cluster client")
@@ -168,6 +216,7 @@ func scaleUpRunFunc(scaleUpFlags *types.ScaleUpFlags) error {
 	}
 
 	return kubeInstaller.ScaleUp(mj, nj, KubeScaleUpOptions{
-		IgnoreCache: scaleUpFlags.IgnoreCache,
+		IgnoreCache:          scaleUpFlags.IgnoreCache,
+		RegistryDistribution: registryDistribution,
 	})
 }
@@ -0,0 +1,109 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sealerio/sealer/cmd/sealer/cmd/types"
+	"github.com/sealerio/sealer/cmd/sealer/cmd/utils"
+	"github.com/sealerio/sealer/common"
+	"github.com/sealerio/sealer/pkg/apiserver"
+	"github.com/sealerio/sealer/pkg/clusterfile"
+)
+
+type serveFlags struct {
+	addr  string
+	token string
+}
+
+var longDescriptionForServeCmd = `serve exposes the cluster lifecycle operations over an authenticated REST
+API with async job tracking, so platform portals can orchestrate clusters
+without shelling out to the CLI. Every operation returns a job which can be
+polled under /api/v1/jobs/<id>. Clients authenticate with
+"Authorization: Bearer <token>"; a random token is generated and logged
+when none is given.`
+
+var exampleForServeCmd = `
+  sealer serve --addr :8383 --token my-secret
+  curl -H "Authorization: Bearer my-secret" --data-binary @Clusterfile http://localhost:8383/api/v1/apply
+`
+
+func NewServeCmd() *cobra.Command {
+	flags := &serveFlags{}
+	serveCmd := &cobra.Command{
+		Use:     "serve",
+		Short:   "serve the cluster lifecycle operations over a REST API",
+		Long:    longDescriptionForServeCmd,
+		Example: exampleForServeCmd,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			server, err := apiserver.NewServer(apiserver.Options{
+				Addr:  flags.addr,
+				Token: flags.token,
+			}, apiserver.Operations{
+				Apply:  applyClusterFileData,
+				Delete: deleteWholeCluster,
+			})
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			signalCh := make(chan os.Signal, 1)
+			signal.Notify(signalCh, syscall.SIGINT, syscall.SIGTERM)
+			go func() {
+				<-signalCh
+				cancel()
+			}()
+
+			return server.Run(ctx)
+		},
+	}
+
+	serveCmd.Flags().StringVar(&flags.addr, "addr", ":8383", "address the API server listens on")
+	serveCmd.Flags().StringVar(&flags.token, "token", os.Getenv("SEALER_API_TOKEN"), "bearer token clients must present, defaults to $SEALER_API_TOKEN or a generated one")
+
+	return serveCmd
+}
+
+// applyClusterFileData applies raw Clusterfile data with the same semantics
+// as `sealer apply -f`.
+func applyClusterFileData(clusterFileData []byte) error {
+	clusterFileData, err := utils.ProvisionCloudCluster(clusterFileData)
+	if err != nil {
+		return err
+	}
+
+	cf, err := clusterfile.NewClusterFile(clusterFileData)
+	if err != nil {
+		return err
+	}
+
+	return applyClusterFile(cf, &types.ApplyFlags{Mode: common.ApplyModeApply})
+}
+
+// deleteWholeCluster tears down the cluster of the saved Clusterfile with
+// the same semantics as `sealer delete -a --force`.
+func deleteWholeCluster() error {
+	return deleteCluster("", true, &types.DeleteFlags{DeleteAll: true, ForceDelete: true})
+}
@@ -0,0 +1,148 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/sealerio/sealer/cmd/sealer/cmd/utils"
+	"github.com/sealerio/sealer/common"
+	"github.com/sealerio/sealer/pkg/clusterfile"
+	"github.com/sealerio/sealer/pkg/infradriver"
+	"github.com/sealerio/sealer/pkg/printer"
+)
+
+var longDescriptionForStatusCmd = `status command reads the saved Clusterfile, checks the SSH reachability and
+kubelet state of every node, queries the runtime for node readiness, and prints
+a concise per-node health summary.`
+
+var exampleForStatusCmd = `
+  sealer status
+  sealer status my-cluster
+`
+
+// nodeStatus is the per-node health summary printed by `sealer status`.
+type nodeStatus struct {
+	Node      string `json:"node"`
+	Role      string `json:"role"`
+	Reachable bool   `json:"reachable"`
+	Kubelet   string `json:"kubelet"`
+	Ready     bool   `json:"ready"`
+	Version   string `json:"version"`
+}
+
+func NewStatusCmd() *cobra.Command {
+	statusCmd := &cobra.Command{
+		Use:     "status [cluster]",
+		Short:   "print a health summary of the cluster nodes",
+		Long:    longDescriptionForStatusCmd,
+		Example: exampleForStatusCmd,
+		Args:    cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cf, _, err := clusterfile.GetActualClusterFile()
+			if err != nil {
+				return err
+			}
+
+			cluster := cf.GetCluster()
+			if len(args) == 1 && args[0] != cluster.Name {
+				return fmt.Errorf("cluster %s not found, current cluster is %s", args[0], cluster.Name)
+			}
+
+			infraDriver, err := infradriver.NewInfraDriver(&cluster)
+			if err != nil {
+				return err
+			}
+
+			statusList, err := collectNodeStatus(infraDriver)
+			if err != nil {
+				return err
+			}
+
+			if p := printer.Default(); p.IsStructured() {
+				return p.PrintObj(statusList)
+			}
+
+			table := tablewriter.NewWriter(common.StdOut)
+			table.SetHeader([]string{"node", "role", "reachable", "kubelet", "ready", "version"})
+			for _, status := range statusList {
+				table.Append([]string{
+					status.Node,
+					status.Role,
+					fmt.Sprintf("%t", status.Reachable),
+					status.Kubelet,
+					fmt.Sprintf("%t", status.Ready),
+					status.Version,
+				})
+			}
+			table.Render()
+			return nil
+		},
+	}
+	return statusCmd
+}
+
+// collectNodeStatus pings every cluster host through the infradriver and
+// merges the result with the node health known to the runtime.
+func collectNodeStatus(infraDriver infradriver.InfraDriver) ([]nodeStatus, error) {
+	// node health from the runtime, keyed by internal IP; a nil client is
+	// tolerated so status still reports reachability when the apiserver
+	// itself is down.
+	k8sNodes := map[string]corev1.Node{}
+	if client := utils.GetClusterClient(); client != nil {
+		if nodes, err := client.ListNodes(); err == nil {
+			for _, node := range nodes.Items {
+				for _, address := range node.Status.Addresses {
+					if address.Type == corev1.NodeInternalIP {
+						k8sNodes[address.Address] = node
+					}
+				}
+			}
+		}
+	}
+
+	var statusList []nodeStatus
+	for _, host := range infraDriver.GetHostIPList() {
+		status := nodeStatus{
+			Node:    host.String(),
+			Role:    strings.Join(infraDriver.GetRoleListByHostIP(host.String()), ","),
+			Kubelet: "unknown",
+		}
+
+		if err := infraDriver.Ping(host); err == nil {
+			status.Reachable = true
+			if out, err := infraDriver.CmdToString(host, nil, "systemctl is-active kubelet", ""); err == nil {
+				status.Kubelet = strings.TrimSpace(out)
+			}
+		}
+
+		if node, ok := k8sNodes[host.String()]; ok {
+			status.Version = node.Status.NodeInfo.KubeletVersion
+			for _, condition := range node.Status.Conditions {
+				if condition.Type == corev1.NodeReady {
+					status.Ready = condition.Status == corev1.ConditionTrue
+				}
+			}
+		}
+
+		statusList = append(statusList, status)
+	}
+	return statusList, nil
+}
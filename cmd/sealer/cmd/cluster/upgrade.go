@@ -127,6 +127,13 @@ func upgradeCluster(cf clusterfile.Interface, imageEngine imageengine.Interface,
 
 	imageName := infraDriver.GetClusterImageName()
 
+	if err := clusterruntime.CheckSealerVersionCompat(imageSpec.ImageExtension.Labels); err != nil {
+		return err
+	}
+	if err := clusterruntime.CheckKubeVersionSkew(imageSpec.ImageExtension.Labels, clusterruntime.GetClusterKubeVersion(infraDriver), clusterruntime.CompatOperationUpgrade); err != nil {
+		return err
+	}
+
 	clusterHosts := infraDriver.GetHostIPList()
 	clusterHostsPlatform, err := infraDriver.GetHostsPlatform(clusterHosts)
 	if err != nil {
@@ -204,7 +211,7 @@ func upgradeCluster(cf clusterfile.Interface, imageEngine imageengine.Interface,
 	if err = v2App.Launch(infraDriver); err != nil {
 		return err
 	}
-	if err = v2App.Save(application.SaveOptions{}); err != nil {
+	if err = v2App.Save(application.SaveOptions{ImageName: imageName}); err != nil {
 		return err
 	}
 
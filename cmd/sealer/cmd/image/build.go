@@ -66,6 +66,8 @@ build multi-platform image:
   sealer build -f Kubefile -t my-kubernetes:1.22.15 --platform linux/amd64,linux/arm64
 build manually ignore image:
   sealer build -f Kubefile -t my-kubernetes:1.22.15 --ignored-image-list ignoredListPath
+build an image from the running cluster:
+  sealer build --build-mode capture -t my-snapshot:v1
 `
 
 // NewBuildCmd buildCmd represents the build command
@@ -85,6 +87,27 @@ func NewBuildCmd() *cobra.Command {
 				buildFlags.ContextDir = args[0]
 			}
 
+			// capture mode generates its own context from the running
+			// cluster; the container images already sit in the captured
+			// registry storage, so the build itself runs lite.
+			if buildFlags.BuildMode == options.WithCaptureMode {
+				if len(args) > 0 {
+					return errors.New("capture mode builds from the running cluster, do not pass a context PATH")
+				}
+				contextDir, err := captureClusterContext()
+				if err != nil {
+					return err
+				}
+				defer func() {
+					if err := os.RemoveAll(contextDir); err != nil {
+						logrus.Warnf("failed to remove capture context %s: %v", contextDir, err)
+					}
+				}()
+				buildFlags.ContextDir = contextDir
+				buildFlags.Kubefile = filepath.Join(contextDir, "Kubefile")
+				buildFlags.BuildMode = options.WithLiteMode
+			}
+
 			engine, err := imageengine.NewImageEngine(options.EngineGlobalConfigurations{})
 			if err != nil {
 				return errors.Wrap(err, "failed to initiate image engine")
@@ -117,14 +140,20 @@ func NewBuildCmd() *cobra.Command {
 	buildCmd.Flags().StringSliceVar(&buildFlags.Annotations, "annotation", []string{}, "add annotations for image. Format like --annotation key=[value]")
 	buildCmd.Flags().StringSliceVar(&buildFlags.Labels, "label", []string{getSealerLabel()}, "add labels for image. Format like --label key=[value]")
 	buildCmd.Flags().BoolVar(&buildFlags.NoCache, "no-cache", false, "do not use existing cached images for building. Build from the start with a new set of cached layers.")
-	buildCmd.Flags().StringVar(&buildFlags.BuildMode, "build-mode", options.WithAllMode, "whether to download container image during the build process. default is `all`.")
+	buildCmd.Flags().BoolVar(&buildFlags.Squash, "squash", false, "squash all layers of the built image into one")
+	buildCmd.Flags().BoolVar(&buildFlags.Minimize, "minimize", false, "strip binaries of foreign architectures and hard link identical files before committing, with a report of what was removed")
+	buildCmd.Flags().StringVar(&buildFlags.CacheFrom, "cache-from", "", "remote repository to use as a potential layer cache source, e.g. registry.example.com/cache/app")
+	buildCmd.Flags().StringVar(&buildFlags.CacheTo, "cache-to", "", "remote repository to push intermediate layers to as a shared cache")
+	buildCmd.Flags().StringVar(&buildFlags.CacheTTL, "cache-ttl", "", "only consider remote cache layers younger than this duration, e.g. 24h")
+	buildCmd.Flags().BoolVar(&buildFlags.ScanContext, "scan-context", false, "statically scan the whole build context for image: references in YAML manifests and charts, merge them with the image list and report any that cannot be parsed")
+	buildCmd.Flags().StringVar(&buildFlags.BuildMode, "build-mode", options.WithAllMode, "whether to download container image during the build process. `capture` builds from the rootfs snapshot of the running cluster instead of a context. default is `all`.")
 
 	supportedImageType := map[string]struct{}{v12.KubeInstaller: {}, v12.AppInstaller: {}}
 	if _, ok := supportedImageType[buildFlags.ImageType]; !ok {
 		logrus.Fatalf("image type %s is not supported", buildFlags.ImageType)
 	}
 
-	supportedBuildModeType := map[string]struct{}{options.WithAllMode: {}, options.WithLiteMode: {}}
+	supportedBuildModeType := map[string]struct{}{options.WithAllMode: {}, options.WithLiteMode: {}, options.WithCaptureMode: {}}
 	if _, ok := supportedBuildModeType[buildFlags.BuildMode]; !ok {
 		logrus.Fatalf("build mode type %s is not supported in %s", buildFlags.BuildMode, options.SupportedBuildModes)
 	}
@@ -318,6 +347,34 @@ func applyRegistryToImage(engine imageengine.Interface, imageID string, platform
 		}
 	}
 
+	// statically scan the whole build context, so images referenced by
+	// manifests outside the conventional dirs are bundled as well
+	if buildFlags.ScanContext {
+		contextDir := buildFlags.ContextDir
+		if contextDir == "" {
+			contextDir, err = os.Getwd()
+			if err != nil {
+				return "", nil, err
+			}
+		}
+		scanned, invalid, err := buildimage.ScanContextImages(contextDir)
+		if err != nil {
+			return "", nil, errors.Wrap(err, "failed to scan build context for container images")
+		}
+		if len(invalid) > 0 {
+			logrus.Warnf("the following image references found in the build context cannot be parsed and will be skipped: %v", invalid)
+		}
+		for _, image := range scanned {
+			logrus.Debugf("get container image(%s) with platform(%s) from context scan",
+				image, platform.ToString())
+			containerImageList = append(containerImageList, &v12.ContainerImage{
+				Image:    image,
+				AppName:  "",
+				Platform: &platform,
+			})
+		}
+	}
+
 	// automatically parses container images and stores them
 	parsedContainerImageList, err := buildimage.ParseContainerImageList(tmpDirForLink)
 	if err != nil {
@@ -385,6 +442,12 @@ func applyRegistryToImage(engine imageengine.Interface, imageID string, platform
 		}
 	}
 
+	if buildFlags.Minimize {
+		if err := minimizeRootfs(tmpDirForLink, platform); err != nil {
+			return "", nil, errors.Wrap(err, "failed to minimize the image rootfs")
+		}
+	}
+
 	return containerID, containerImageList, nil
 }
 
@@ -423,6 +486,7 @@ func commitContainer(engine imageengine.Interface, opts *commitContainerOpts) er
 	id, err := engine.Commit(&options.CommitOptions{
 		Format:      cli.DefaultFormat(),
 		Rm:          true,
+		Squash:      buildFlags.Squash,
 		ContainerID: containerID,
 		Image:       tag,
 		Manifest:    manifest,
@@ -468,6 +532,7 @@ func buildImageExtensionOnResult(result *parser.KubefileResult, imageType string
 		Launch:        v12.Launch{},
 		SchemaVersion: v12.ImageSpecSchemaVersionV1Beta1,
 		Env:           result.GlobalEnv,
+		EnvDocs:       result.GlobalEnvDocs,
 		BuildClient: v12.BuildClient{
 			SealerVersion:  version.Get().GitVersion,
 			BuildahVersion: define.Version,
@@ -0,0 +1,131 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package image
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/json"
+
+	"github.com/sealerio/sealer/common"
+	"github.com/sealerio/sealer/pkg/clusterfile"
+	"github.com/sealerio/sealer/pkg/infradriver"
+	"github.com/sealerio/sealer/utils/archive"
+)
+
+// captureClusterContext snapshots the rootfs of the running cluster from its
+// first master into a fresh build context: the distributed files, the local
+// registry storage holding the running container images and the installed
+// charts all live below the rootfs. The pki directory stays behind since
+// certificates are regenerated per cluster, and a Kubefile replaying the
+// launch commands of the cluster is written into the context.
+func captureClusterContext() (string, error) {
+	cluster, err := clusterfile.GetClusterFromFile(common.GetDefaultClusterfile())
+	if err != nil {
+		return "", fmt.Errorf("capture mode needs a running cluster installed from this host: %v", err)
+	}
+
+	infraDriver, err := infradriver.NewInfraDriver(cluster)
+	if err != nil {
+		return "", err
+	}
+
+	masters := infraDriver.GetHostIPListByRole(common.MASTER)
+	if len(masters) == 0 {
+		return "", fmt.Errorf("no master found in cluster %s", infraDriver.GetClusterName())
+	}
+	master0 := masters[0]
+	rootfs := infraDriver.GetClusterRootfsPath()
+
+	remoteTar := filepath.Join("/tmp", fmt.Sprintf("sealer-capture-%d.tar", time.Now().Unix()))
+	logrus.Infof("start to capture the rootfs of cluster %s from master(%s)", infraDriver.GetClusterName(), master0)
+	if err := infraDriver.CmdAsync(master0, nil, fmt.Sprintf("tar -C %s --exclude=./pki -cf %s .", rootfs, remoteTar)); err != nil {
+		return "", fmt.Errorf("failed to archive the cluster rootfs on master(%s): %v", master0, err)
+	}
+	defer func() {
+		if err := infraDriver.CmdAsync(master0, nil, fmt.Sprintf("rm -f %s", remoteTar)); err != nil {
+			logrus.Warnf("failed to remove %s on master(%s): %v", remoteTar, master0, err)
+		}
+	}()
+
+	localTar := filepath.Join(os.TempDir(), filepath.Base(remoteTar))
+	if err := infraDriver.CopyR(master0, remoteTar, localTar); err != nil {
+		return "", fmt.Errorf("failed to fetch the rootfs archive from master(%s): %v", master0, err)
+	}
+	defer func() {
+		if err := os.Remove(localTar); err != nil {
+			logrus.Warnf("failed to remove %s: %v", localTar, err)
+		}
+	}()
+
+	contextDir, err := os.MkdirTemp("", "sealer-capture-context-")
+	if err != nil {
+		return "", err
+	}
+
+	tarFile, err := os.Open(filepath.Clean(localTar))
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err := tarFile.Close(); err != nil {
+			logrus.Warnf("failed to close %s: %v", localTar, err)
+		}
+	}()
+	if _, err := archive.Untar(tarFile, contextDir); err != nil {
+		return "", fmt.Errorf("failed to unpack the rootfs archive: %v", err)
+	}
+
+	kubefile, err := captureKubefile(infraDriver)
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(contextDir, "Kubefile"), []byte(kubefile), os.FileMode(0644)); err != nil {
+		return "", err
+	}
+
+	return contextDir, nil
+}
+
+// captureKubefile renders the Kubefile rebuilding the snapshot: everything
+// of the captured rootfs is copied back in, and the launch commands or apps
+// of the running cluster become the launch instruction of the new image.
+func captureKubefile(infraDriver infradriver.InfraDriver) (string, error) {
+	lines := []string{
+		"FROM scratch",
+		"COPY . .",
+	}
+
+	if cmds := infraDriver.GetClusterLaunchCmds(); len(cmds) > 0 {
+		rendered, err := json.Marshal(cmds)
+		if err != nil {
+			return "", err
+		}
+		lines = append(lines, fmt.Sprintf("CMDS %s", rendered))
+	} else if apps := infraDriver.GetClusterLaunchApps(); len(apps) > 0 {
+		rendered, err := json.Marshal(apps)
+		if err != nil {
+			return "", err
+		}
+		lines = append(lines, fmt.Sprintf("LAUNCH %s", rendered))
+	}
+
+	return strings.Join(lines, "\n") + "\n", nil
+}
@@ -19,6 +19,7 @@ import (
 
 	"github.com/sealerio/sealer/pkg/define/options"
 	"github.com/sealerio/sealer/pkg/imageengine"
+	"github.com/sealerio/sealer/pkg/printer"
 )
 
 var imagesOpts *options.ImagesOptions
@@ -42,6 +43,9 @@ func NewListCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			if p := printer.Default(); p.IsStructured() {
+				imagesOpts.Output = string(p.Format())
+			}
 			return engine.Images(imagesOpts)
 		},
 	}
@@ -27,6 +27,7 @@ import (
 
 	"github.com/sealerio/sealer/pkg/define/options"
 	"github.com/sealerio/sealer/pkg/imageengine"
+	"github.com/sealerio/sealer/pkg/printer"
 )
 
 var inspectOpts *options.InspectOptions
@@ -78,6 +79,10 @@ func NewInspectCmd() *cobra.Command {
 				return nil
 			}
 
+			if p := printer.Default(); p.IsStructured() {
+				return p.PrintObj(result)
+			}
+
 			enc := json.NewEncoder(os.Stdout)
 			enc.SetIndent("", "    ")
 			if term.IsTerminal(int(os.Stdout.Fd())) {
@@ -56,9 +56,11 @@ func NewLoadCmd() *cobra.Command {
 	}
 	loadOpts = &options.LoadOptions{}
 	flags := loadCmd.Flags()
-	flags.StringVarP(&loadOpts.Input, "input", "i", "", "Load image from file")
+	flags.StringVarP(&loadOpts.Input, "input", "i", "", "Load image from a tar file or an OCI image layout directory")
 	flags.BoolVarP(&loadOpts.Quiet, "quiet", "q", false, "Suppress the output")
 	flags.StringVar(&loadOpts.TmpDir, "tmp-dir", "", "set temporary directory when load image. if not set, use system`s temporary directory")
+	flags.StringVar(&loadOpts.Format, "format", "", "archive format of the input: sealer (default, auto-detected), docker-archive, oci-archive, oci, oci-dir or docker-dir. Set it to load archives written by docker or podman directly")
+	flags.StringSliceVar(&loadOpts.DecryptionKeys, "decryption-key", nil, "Key needed to decrypt the image (e.g. /path/to/key.pem or /path/to/key.pem:passphrase)")
 	if err := loadCmd.MarkFlagRequired("input"); err != nil {
 		logrus.Errorf("failed to init flag: %v", err)
 		os.Exit(1)
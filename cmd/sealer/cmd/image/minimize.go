@@ -0,0 +1,200 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package image
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"debug/elf"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/docker/go-units"
+	"github.com/sirupsen/logrus"
+
+	v1 "github.com/sealerio/sealer/types/api/v1"
+)
+
+var elfMagic = []byte{0x7f, 'E', 'L', 'F'}
+
+// elfMachineByArch maps the GOARCH spellings of the build platform onto the
+// ELF machine the binaries of that platform carry.
+var elfMachineByArch = map[string]elf.Machine{
+	"386":      elf.EM_386,
+	"amd64":    elf.EM_X86_64,
+	"arm":      elf.EM_ARM,
+	"arm64":    elf.EM_AARCH64,
+	"mips64le": elf.EM_MIPS,
+	"ppc64le":  elf.EM_PPC64,
+	"riscv64":  elf.EM_RISCV,
+	"s390x":    elf.EM_S390,
+}
+
+// minimizeRootfs shrinks the assembled rootfs before it is committed:
+// binaries built for a foreign architecture are removed and identical
+// regular files are collapsed into hard links, with a report of what was
+// removed and how much the image shrank.
+func minimizeRootfs(rootfsDir string, platform v1.Platform) error {
+	// the rootfs dir is a link into the working container mount
+	root, err := filepath.EvalSymlinks(rootfsDir)
+	if err != nil {
+		return err
+	}
+
+	strippedCount, strippedBytes, err := stripForeignArchBinaries(root, platform.Architecture)
+	if err != nil {
+		return err
+	}
+
+	dedupedCount, dedupedBytes, err := dedupeRootfsFiles(root)
+	if err != nil {
+		return err
+	}
+
+	logrus.Infof("minimize report: removed %d foreign architecture binaries (%s), hard linked %d duplicate files (%s), %s saved in total",
+		strippedCount, units.HumanSize(float64(strippedBytes)),
+		dedupedCount, units.HumanSize(float64(dedupedBytes)),
+		units.HumanSize(float64(strippedBytes+dedupedBytes)))
+	return nil
+}
+
+// stripForeignArchBinaries removes every ELF binary whose machine does not
+// match the build architecture; registry blobs and scripts carry no ELF
+// header and pass through untouched. Architectures this map does not know
+// keep their binaries rather than risk stripping wanted ones.
+func stripForeignArchBinaries(root, arch string) (int, int64, error) {
+	wantMachine, ok := elfMachineByArch[arch]
+	if !ok {
+		logrus.Warnf("unknown architecture %s, skipping foreign binary stripping", arch)
+		return 0, 0, nil
+	}
+
+	var (
+		count int
+		size  int64
+	)
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.Mode().IsRegular() || info.Size() < int64(len(elfMagic)) {
+			return err
+		}
+
+		machine, isELF := elfMachine(path)
+		if !isELF || machine == wantMachine {
+			return nil
+		}
+
+		logrus.Debugf("removing foreign architecture binary %s (%s)", path, machine)
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		count++
+		size += info.Size()
+		return nil
+	})
+	return count, size, err
+}
+
+func elfMachine(path string) (elf.Machine, bool) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return 0, false
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	magic := make([]byte, len(elfMagic))
+	if _, err := io.ReadFull(f, magic); err != nil || !bytes.Equal(magic, elfMagic) {
+		return 0, false
+	}
+
+	elfFile, err := elf.NewFile(f)
+	if err != nil {
+		return 0, false
+	}
+	return elfFile.Machine, true
+}
+
+// dedupeRootfsFiles hard links regular files with identical content onto a
+// single copy; tar keeps the links on commit, so every duplicate is stored
+// once in the final layer.
+func dedupeRootfsFiles(root string) (int, int64, error) {
+	var (
+		count int
+		size  int64
+		// content digest -> first path seen with that content
+		seen = map[string]string{}
+	)
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.Mode().IsRegular() || info.Size() == 0 {
+			return err
+		}
+
+		digest, err := fileDigest(path, info.Size())
+		if err != nil {
+			return err
+		}
+
+		first, ok := seen[digest]
+		if !ok {
+			seen[digest] = path
+			return nil
+		}
+
+		if sameInode(first, path) {
+			return nil
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		if err := os.Link(first, path); err != nil {
+			return err
+		}
+		count++
+		size += info.Size()
+		return nil
+	})
+	return count, size, err
+}
+
+func fileDigest(path string, size int64) (string, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d-%x", size, hash.Sum(nil)), nil
+}
+
+func sameInode(a, b string) bool {
+	infoA, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	infoB, err := os.Stat(b)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(infoA, infoB)
+}
@@ -62,5 +62,6 @@ func NewPullCmd() *cobra.Command {
 	pullCmd.Flags().StringVar(&pullOpts.PullPolicy, "policy", "always", "missing, always, ifnewer or never.")
 	pullCmd.Flags().BoolVarP(&pullOpts.Quiet, "quiet", "q", false, "don't output progress information when pulling images")
 	pullCmd.Flags().BoolVar(&pullOpts.SkipTLSVerify, "skip-tls-verify", false, "default is requiring HTTPS and verify certificates when accessing the registry.")
+	pullCmd.Flags().IntVar(&pullOpts.MaxParallelLayers, "max-parallel-layers", 0, "maximum number of image layers to download at the same time, 0 keeps the engine default")
 	return pullCmd
 }
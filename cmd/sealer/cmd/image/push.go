@@ -53,5 +53,6 @@ func NewPushCmd() *cobra.Command {
 	pushCmd.Flags().BoolVar(&pushOpts.SkipTLSVerify, "skip-tls-verify", false, "default is requiring HTTPS and verify certificates when accessing the registry.")
 	pushCmd.Flags().BoolVarP(&pushOpts.Quiet, "quiet", "q", false, "don't output progress information when pushing images")
 	pushCmd.Flags().BoolVar(&pushOpts.All, "all", true, "also push the images in the list")
+	pushCmd.Flags().IntVar(&pushOpts.MaxParallelLayers, "max-parallel-layers", 0, "maximum number of image layers to upload at the same time, 0 keeps the engine default")
 	return pushCmd
 }
@@ -28,6 +28,9 @@ var longNewRmiCmdDescription = ``
 var exampleForRmiCmd = `
   sealer rmi docker.io/sealerio/kubernetes:v1-22-15-sealerio-2
 
+remove every release candidate tag of an image (quote the pattern so the shell does not expand it):
+  sealer rmi 'myapp:*-rc*'
+
 prune dangling images:
   sealer rmi --prune/-p
 
@@ -32,7 +32,15 @@ var longNewSaveCmdDescription = `sealer save -o [output file name] [image name]`
 var exampleForSaveCmd = `
 save docker.io/sealerio/kubernetes:v1-22-15-sealerio-2 image to kubernetes.tar file:
 
-  sealer save -o kubernetes.tar docker.io/sealerio/kubernetes:v1-22-15-sealerio-2`
+  sealer save -o kubernetes.tar docker.io/sealerio/kubernetes:v1-22-15-sealerio-2
+
+save the same image as a plain OCI image layout directory:
+
+  sealer save --format oci -o kubernetes-oci docker.io/sealerio/kubernetes:v1-22-15-sealerio-2
+
+save the same image as a tar docker load understands:
+
+  sealer save --format docker-archive -o kubernetes-docker.tar docker.io/sealerio/kubernetes:v1-22-15-sealerio-2`
 
 // NewSaveCmd saveCmd represents the save command
 func NewSaveCmd() *cobra.Command {
@@ -59,11 +67,13 @@ func NewSaveCmd() *cobra.Command {
 	}
 	saveOpts = &options.SaveOptions{}
 	flags := saveCmd.Flags()
-	flags.StringVar(&saveOpts.Format, "format", buildah.OCIArchive, "Save image to oci-archive, oci-dir (directory with oci manifest type), docker-archive, docker-dir (directory with v2s2 manifest type)")
+	flags.StringVar(&saveOpts.Format, "format", buildah.OCIArchive, "Save image to sealer (sealer's multi-arch tar bundle), oci-archive, oci (plain OCI image layout directory, consumable by skopeo/oras), oci-dir (directory with oci manifest type), docker-archive (consumable by docker load), docker-dir (directory with v2s2 manifest type)")
 	flags.StringVarP(&saveOpts.Output, "output", "o", "", "Write image to a specified file")
 	flags.BoolVarP(&saveOpts.Quiet, "quiet", "q", false, "Suppress the output")
 	flags.StringVar(&saveOpts.TmpDir, "tmp-dir", "", "set temporary directory when save image. if not set, use system`s temporary directory")
 	flags.BoolVar(&saveOpts.Compress, "compress", false, "Compress tarball image layers when saving to a directory using the 'dir' transport. (default is same compression type as source)")
+	flags.StringSliceVar(&saveOpts.EncryptionKeys, "encryption-key", nil, "Key with the encryption protocol to use to encrypt the image (e.g. jwe:/path/to/key)")
+	flags.IntSliceVar(&saveOpts.EncryptLayers, "encrypt-layer", nil, "Layers to encrypt, 0-indexed layer indices with support for negative indexing (e.g. 0 is the first layer, -1 is the last layer). If not defined, encrypts all layers if encryption-key flag is specified")
 	if err := saveCmd.MarkFlagRequired("output"); err != nil {
 		logrus.Errorf("failed to init flag: %v", err)
 		os.Exit(1)
@@ -0,0 +1,49 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/sealerio/sealer/pkg/define/options"
+	"github.com/sealerio/sealer/pkg/imageengine"
+)
+
+var pruneOpts *options.PruneOptions
+
+var exampleForPruneCmd = `
+  sealer prune
+`
+
+// NewPruneCmd pruneCmd represents the prune command
+func NewPruneCmd() *cobra.Command {
+	pruneCmd := &cobra.Command{
+		Use:     "prune",
+		Short:   "remove dangling images, build containers and unreferenced layer directories",
+		Example: exampleForPruneCmd,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			engine, err := imageengine.NewImageEngine(options.EngineGlobalConfigurations{})
+			if err != nil {
+				return err
+			}
+
+			return engine.Prune(pruneOpts)
+		},
+	}
+	pruneOpts = &options.PruneOptions{}
+	pruneCmd.Flags().BoolVarP(&pruneOpts.Quiet, "quiet", "q", false, "don't print what gets removed")
+	return pruneCmd
+}
@@ -15,31 +15,43 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"syscall"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
 	"github.com/sealerio/sealer/cmd/sealer/cmd/alpha"
+	"github.com/sealerio/sealer/cmd/sealer/cmd/app"
 	"github.com/sealerio/sealer/cmd/sealer/cmd/cluster"
 	"github.com/sealerio/sealer/cmd/sealer/cmd/image"
 	"github.com/sealerio/sealer/common"
 	"github.com/sealerio/sealer/pkg/logger"
+	"github.com/sealerio/sealer/pkg/printer"
+	"github.com/sealerio/sealer/utils/ssh"
 	"github.com/sealerio/sealer/version"
 )
 
 type rootOpts struct {
 	cfgFile              string
+	outputFormat         string
 	debugModeOn          bool
+	logLevel             string
+	logFile              string
 	hideLogTime          bool
 	hideLogPath          bool
 	logToFile            bool
 	colorMode            string
 	remoteLoggerURL      string
 	remoteLoggerTaskName string
+	clusterContext       string
+	timeout              time.Duration
 }
 
 var rootOpt rootOpts
@@ -80,12 +92,15 @@ func Execute() {
 func init() {
 	cobra.OnInitialize(initConfig)
 
-	rootCmd.AddCommand(alpha.NewCmdAlpha(), NewCompletionCmd(), NewVersionCmd(), NewGenDocCommand())
+	rootCmd.AddCommand(alpha.NewCmdAlpha(), app.NewAppCmd(), NewCompletionCmd(), NewVersionCmd(), NewGenDocCommand(), NewPruneCmd(), NewSystemCmd())
 	rootCmd.AddCommand(cluster.NewClusterCommands()...)
 	rootCmd.AddCommand(image.NewImageCommands()...)
 
 	rootCmd.PersistentFlags().StringVar(&rootOpt.cfgFile, "config", "", "config file of sealer tool (default is $HOME/.sealer.json)")
+	rootCmd.PersistentFlags().StringVarP(&rootOpt.outputFormat, "output", "o", "", fmt.Sprintf("set the command output format, the possible values can be %v", printer.SupportedFormats))
 	rootCmd.PersistentFlags().BoolVarP(&rootOpt.debugModeOn, "debug", "d", false, "turn on debug mode")
+	rootCmd.PersistentFlags().StringVar(&rootOpt.logLevel, "log-level", "", "set the log level (trace/debug/info/warn/error), overrides --debug")
+	rootCmd.PersistentFlags().StringVar(&rootOpt.logFile, "log-file", "", fmt.Sprintf("directory to write the rotated JSON log files to (default %s)", common.DefaultLogDir))
 	rootCmd.PersistentFlags().BoolVarP(&rootCmd.SilenceUsage, "quiet", "q", false, "silence the usage when fail")
 	rootCmd.PersistentFlags().BoolVar(&rootOpt.hideLogTime, "hide-time", false, "hide the log time")
 	rootCmd.PersistentFlags().BoolVar(&rootOpt.hideLogPath, "hide-path", false, "hide the log path")
@@ -93,10 +108,44 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&rootOpt.colorMode, "color", colorModeAlways, fmt.Sprintf("set the log color mode, the possible values can be %v", supportedColorModes))
 	rootCmd.PersistentFlags().StringVar(&rootOpt.remoteLoggerURL, "remote-logger-url", "", "remote logger url, if not empty, will send log to this url")
 	rootCmd.PersistentFlags().StringVar(&rootOpt.remoteLoggerTaskName, "task-name", "", "task name which will embedded in the remote logger header, only valid when --remote-logger-url is set")
+	rootCmd.PersistentFlags().StringVarP(&rootOpt.clusterContext, "cluster", "c", "", "cluster context to operate on, overrides the one set by 'sealer cluster use'")
+	rootCmd.PersistentFlags().DurationVar(&rootOpt.timeout, "timeout", 0, "global timeout for the whole operation, such as 30m; 0 means no timeout")
 	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 	rootCmd.DisableAutoGenTag = true
 }
 
+// setupProcessContext installs the context governing all remote
+// operations: it is canceled on the first Ctrl-C or once the global
+// --timeout elapses, so in-flight ssh sessions are closed instead of
+// orphaning remote processes. A second Ctrl-C exits immediately.
+func setupProcessContext(timeout time.Duration) {
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+	)
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), timeout)
+	} else {
+		ctx, cancel = context.WithCancel(context.Background())
+	}
+
+	signalCh := make(chan os.Signal, 1)
+	signal.Notify(signalCh, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		defer cancel()
+		select {
+		case sig := <-signalCh:
+			logrus.Warnf("received signal %s, canceling in-flight operations; press Ctrl-C again to exit immediately", sig)
+			// restore the default handling so a second signal kills the
+			// process right away
+			signal.Stop(signalCh)
+		case <-ctx.Done():
+		}
+	}()
+
+	ssh.SetProcessContext(ctx)
+}
+
 // initConfig reads in config file and ENV variables if set.
 func initConfig() {
 	if rootOpt.cfgFile == "" {
@@ -110,8 +159,21 @@ func initConfig() {
 
 	viper.AutomaticEnv() // read in environment variables that match
 
+	if rootOpt.clusterContext != "" {
+		common.SetClusterContextOverride(rootOpt.clusterContext)
+	}
+
+	setupProcessContext(rootOpt.timeout)
+
+	if err := printer.SetOutputFormat(rootOpt.outputFormat); err != nil {
+		logrus.Error(err)
+		os.Exit(1)
+	}
+
 	if err := logger.Init(logger.LogOptions{
 		LogToFile:            rootOpt.logToFile,
+		OutputPath:           rootOpt.logFile,
+		Level:                rootOpt.logLevel,
 		Verbose:              rootOpt.debugModeOn,
 		RemoteLoggerURL:      rootOpt.remoteLoggerURL,
 		RemoteLoggerTaskName: rootOpt.remoteLoggerTaskName,
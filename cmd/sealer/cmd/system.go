@@ -0,0 +1,55 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/sealerio/sealer/pkg/define/options"
+	"github.com/sealerio/sealer/pkg/imageengine"
+)
+
+var exampleForSystemDfCmd = `
+  sealer system df
+`
+
+// NewSystemCmd systemCmd groups commands that inspect sealer itself rather
+// than a cluster or an image.
+func NewSystemCmd() *cobra.Command {
+	systemCmd := &cobra.Command{
+		Use:   "system",
+		Short: "manage the sealer installation on this machine",
+	}
+	systemCmd.AddCommand(newSystemDfCmd())
+	return systemCmd
+}
+
+func newSystemDfCmd() *cobra.Command {
+	dfCmd := &cobra.Command{
+		Use:     "df",
+		Short:   "show disk usage of images, layers and cluster data",
+		Example: exampleForSystemDfCmd,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			engine, err := imageengine.NewImageEngine(options.EngineGlobalConfigurations{})
+			if err != nil {
+				return err
+			}
+
+			return engine.DiskUsage(&options.DiskUsageOptions{})
+		},
+	}
+	return dfCmd
+}
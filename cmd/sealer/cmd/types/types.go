@@ -14,10 +14,33 @@
 
 package types
 
+import (
+	"time"
+
+	sealer "github.com/sealerio/sealer/pkg/client/sealer"
+)
+
 type RunFlags struct {
+	//MaxJoinFailures: the number of node join failures tolerated before
+	//the apply is aborted. 0 means fail fast.
+	MaxJoinFailures int
+	//PodCIDR/SvcCIDR/DNSDomain: basic networking parameters, rendered
+	//into the kubeadm ClusterConfiguration and exposed to apps as env.
+	PodCIDR   string
+	SvcCIDR   string
+	DNSDomain string
+	//CNI: of the CNI apps bundled in the image, launch only this one.
+	CNI string
+	//Profile: hardening profile applied during install, e.g. "cis".
+	Profile string
+
 	Masters string
 	Nodes   string
 
+	//Provider: infra provider provisioning the hosts before install,
+	//such as AWS_CLOUD. empty means running against baremetal host IPs.
+	Provider string
+
 	User        string
 	Password    string
 	Port        uint16
@@ -38,9 +61,13 @@ type RunFlags struct {
 	//default is false.
 	IgnoreCache bool
 
-	// Distributor: distribution method to use (sftp, p2p)
+	// Distributor: distribution method to use (sftp, p2p, registry)
 	// default is sftp
 	Distributor string
+
+	//RollbackOnFailure resets the touched hosts and restores the previous
+	//Clusterfile snapshot when the install fails or is interrupted.
+	RollbackOnFailure bool
 }
 
 type ApplyFlags struct {
@@ -62,12 +89,34 @@ type ApplyFlags struct {
 	//if not, will force sync sealer rootfs.
 	//default is false.
 	IgnoreCache bool
+
+	//Watch keeps apply running as a reconcile daemon: the Clusterfile is
+	//re-applied every WatchInterval until the process is stopped.
+	Watch         bool
+	WatchInterval time.Duration
+
+	//RollbackOnFailure resets the touched hosts and restores the previous
+	//Clusterfile snapshot when the install fails or is interrupted.
+	RollbackOnFailure bool
 }
 
 type ScaleUpFlags struct {
 	Masters string
 	Nodes   string
 
+	//MaxJoinFailures: the number of node join failures tolerated before
+	//the scale up is aborted. 0 means fail fast.
+	MaxJoinFailures int
+
+	//RetryFailed: retry the hosts recorded as failed by a previous
+	//run or scale up.
+	RetryFailed bool
+
+	//Labels/Taints: host level labels and taints applied to the joined
+	//nodes, same syntax as the v2.Host fields in the Clusterfile.
+	Labels []string
+	Taints []string
+
 	User       string
 	Password   string
 	Port       uint16
@@ -79,6 +128,10 @@ type ScaleUpFlags struct {
 	//if not, will force sync sealer rootfs.
 	//default is false.
 	IgnoreCache bool
+
+	// Distributor: distribution method to use (sftp, registry)
+	// default is sftp
+	Distributor string
 }
 
 type DeleteFlags struct {
@@ -127,9 +180,13 @@ type RollbackFlags struct {
 	IgnoreCache bool
 }
 
-type DistributionMethod uint64
+// DistributionMethod moved to the library package so that embedding Go
+// programs do not need to import the cmd tree; the aliases keep the old
+// references working.
+type DistributionMethod = sealer.DistributionMethod
 
 const (
-	SFTPDistribution DistributionMethod = iota
-	P2PDistribution
+	SFTPDistribution     = sealer.SFTPDistribution
+	P2PDistribution      = sealer.P2PDistribution
+	RegistryDistribution = sealer.RegistryDistribution
 )
@@ -15,42 +15,10 @@
 package utils
 
 import (
-	"github.com/sealerio/sealer/types/api/constants"
-	v2 "github.com/sealerio/sealer/types/api/v2"
+	sealer "github.com/sealerio/sealer/pkg/client/sealer"
 )
 
-// ConstructApplication merge flags to v2.Application
-func ConstructApplication(app *v2.Application, cmds, appNames, globalEnvs []string) *v2.Application {
-	var newApp *v2.Application
-
-	if app != nil {
-		newApp = app
-	} else {
-		newApp = &v2.Application{
-			Spec: v2.ApplicationSpec{},
-		}
-		newApp.Name = "my-application"
-		newApp.Kind = v2.GroupVersion.String()
-		newApp.APIVersion = constants.ApplicationKind
-	}
-
-	if len(cmds) > 0 {
-		newApp.Spec.Cmds = cmds
-	}
-
-	if appNames != nil {
-		newApp.Spec.LaunchApps = appNames
-	}
-
-	// add appEnvs from flag to application object.
-	if len(globalEnvs) > 0 {
-		var appConfigList []v2.ApplicationConfig
-		for _, appConfig := range newApp.Spec.Configs {
-			appConfig.Env = append(globalEnvs, appConfig.Env...)
-			appConfigList = append(appConfigList, appConfig)
-		}
-		newApp.Spec.Configs = appConfigList
-	}
-
-	return newApp
-}
+// ConstructApplication merge flags to v2.Application.
+// The implementation lives in the library package, the alias keeps the
+// existing cmd callers working.
+var ConstructApplication = sealer.ConstructApplication
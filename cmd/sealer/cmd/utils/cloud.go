@@ -0,0 +1,158 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/sealerio/sealer/cmd/sealer/cmd/types"
+	"github.com/sealerio/sealer/common"
+	"github.com/sealerio/sealer/pkg/infra"
+	v1 "github.com/sealerio/sealer/types/api/v1"
+	v2 "github.com/sealerio/sealer/types/api/v2"
+	yamlUtils "github.com/sealerio/sealer/utils/yaml"
+)
+
+// ProvisionCloudCluster scans the raw Clusterfile for a v1 Cluster document
+// whose spec.provider asks for a cloud infra provider, provisions the hosts
+// through pkg/infra and returns the Clusterfile with that document replaced
+// by the resulting v2 cluster. Clusterfiles without such a document come
+// back untouched.
+func ProvisionCloudCluster(data []byte) ([]byte, error) {
+	decoder := k8syaml.NewYAMLToJSONDecoder(bufio.NewReaderSize(bytes.NewReader(data), 4096))
+
+	var (
+		docs        []interface{}
+		provisioned bool
+	)
+	for {
+		ext := runtime.RawExtension{}
+		if err := decoder.Decode(&ext); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+
+		ext.Raw = bytes.TrimSpace(ext.Raw)
+		if len(ext.Raw) == 0 || bytes.Equal(ext.Raw, []byte("null")) {
+			continue
+		}
+		metaType := metav1.TypeMeta{}
+		if err := k8syaml.Unmarshal(ext.Raw, &metaType); err != nil {
+			return nil, fmt.Errorf("failed to decode TypeMeta: %v", err)
+		}
+
+		if metaType.Kind == "Cluster" && metaType.APIVersion == v1.GroupVersion.String() {
+			var cloudCluster v1.Cluster
+			if err := k8syaml.Unmarshal(ext.Raw, &cloudCluster); err != nil {
+				return nil, fmt.Errorf("failed to decode %s[%s]: %v", metaType.Kind, metaType.APIVersion, err)
+			}
+
+			if cloudCluster.Spec.Provider != "" && cloudCluster.Spec.Provider != common.BAREMETAL {
+				logrus.Infof("start to provision cluster hosts with provider %s", cloudCluster.Spec.Provider)
+				v2Cluster, err := infra.NewCloudApplier(&cloudCluster).Provision()
+				if err != nil {
+					return nil, err
+				}
+
+				docs = append(docs, v2Cluster)
+				provisioned = true
+				continue
+			}
+		}
+
+		var doc map[string]interface{}
+		if err := k8syaml.Unmarshal(ext.Raw, &doc); err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+
+	if !provisioned {
+		return data, nil
+	}
+	return yamlUtils.MarshalWithDelimiter(docs...)
+}
+
+// normalizeProviderName maps the user facing spellings of the --provider
+// flag onto the provider constants pkg/infra switches on.
+func normalizeProviderName(provider string) string {
+	switch strings.ToUpper(provider) {
+	case "ALI", "ALICLOUD", common.AliCloud:
+		return common.AliCloud
+	case "AWS", "AWSCLOUD", common.AWSCloud:
+		return common.AWSCloud
+	case common.LIBVIRT, "KVM":
+		return common.LIBVIRT
+	case common.TERRAFORM, "TF":
+		return common.TERRAFORM
+	case common.CONTAINER:
+		return common.CONTAINER
+	default:
+		return strings.ToUpper(provider)
+	}
+}
+
+// ProvisionClusterOnProvider builds a v1 cluster from the run flags, where
+// masters/nodes carry instance counts instead of host IPs, lets the infra
+// provider allocate the machines and returns the ready to install v2 cluster.
+func ProvisionClusterOnProvider(imageName string, runFlags *types.RunFlags) (*v2.Cluster, error) {
+	masterCount := runFlags.Masters
+	if masterCount == "" {
+		masterCount = "1"
+	}
+	for _, count := range []string{masterCount, runFlags.Nodes} {
+		if count == "" {
+			continue
+		}
+		if _, err := strconv.Atoi(count); err != nil {
+			return nil, fmt.Errorf("masters and nodes must be instance counts when --provider is set, got %q", count)
+		}
+	}
+
+	cloudCluster := &v1.Cluster{
+		Spec: v1.ClusterSpec{
+			Image:    imageName,
+			Provider: normalizeProviderName(runFlags.Provider),
+			Env:      runFlags.CustomEnv,
+			SSH: v1.SSH{
+				User:     runFlags.User,
+				Passwd:   runFlags.Password,
+				PkPasswd: runFlags.PkPassword,
+				Pk:       runFlags.Pk,
+				Port:     strconv.Itoa(int(runFlags.Port)),
+			},
+			Masters: v1.Hosts{Count: masterCount},
+			Nodes:   v1.Hosts{Count: runFlags.Nodes},
+		},
+	}
+	cloudCluster.Kind = "Cluster"
+	cloudCluster.APIVersion = v1.GroupVersion.String()
+	cloudCluster.Name = "my-cluster"
+
+	logrus.Infof("start to provision cluster hosts with provider %s", cloudCluster.Spec.Provider)
+	return infra.NewCloudApplier(cloudCluster).Provision()
+}
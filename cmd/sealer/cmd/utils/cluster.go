@@ -23,27 +23,21 @@ import (
 	"github.com/sealerio/sealer/cmd/sealer/cmd/types"
 	"github.com/sealerio/sealer/common"
 	"github.com/sealerio/sealer/pkg/client/k8s"
+	sealer "github.com/sealerio/sealer/pkg/client/sealer"
 	imagev1 "github.com/sealerio/sealer/pkg/define/image/v1"
 	"github.com/sealerio/sealer/types/api/constants"
 	v1 "github.com/sealerio/sealer/types/api/v1"
 	v2 "github.com/sealerio/sealer/types/api/v2"
-	"github.com/sealerio/sealer/utils/maps"
 	netutils "github.com/sealerio/sealer/utils/net"
 	strUtils "github.com/sealerio/sealer/utils/strings"
 	"github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
 )
 
-// MergeClusterWithImageExtension :set default value get from image extension,such as image global env
-func MergeClusterWithImageExtension(cluster *v2.Cluster, imageExt imagev1.ImageExtension) *v2.Cluster {
-	if len(imageExt.Env) > 0 {
-		envs := maps.ConvertToSlice(imageExt.Env)
-		envs = append(envs, cluster.Spec.Env...)
-		cluster.Spec.Env = envs
-	}
-
-	return cluster
-}
+// MergeClusterWithImageExtension :set default value get from image extension,such as image global env.
+// The implementation lives in the library package, the alias keeps the
+// existing cmd callers working.
+var MergeClusterWithImageExtension = sealer.MergeClusterWithImageExtension
 
 func MergeClusterWithFlags(cluster v2.Cluster, mergeFlags *types.MergeFlags) (*v2.Cluster, error) {
 	if len(mergeFlags.CustomEnv) > 0 {
@@ -163,45 +157,18 @@ func ConstructClusterForScaleUp(cluster *v2.Cluster, scaleFlags *types.ScaleUpFl
 	return mj, nj, nil
 }
 
-func ConstructClusterForScaleDown(cluster *v2.Cluster, mastersToDelete, workersToDelete []net.IP) error {
-	if len(mastersToDelete) != 0 {
-		for i := range cluster.Spec.Hosts {
-			if strUtils.IsInSlice(common.MASTER, cluster.Spec.Hosts[i].Roles) {
-				cluster.Spec.Hosts[i].IPS = netutils.RemoveIPs(cluster.Spec.Hosts[i].IPS, mastersToDelete)
-			}
-			continue
-		}
-	}
-
-	if len(workersToDelete) != 0 {
-		for i := range cluster.Spec.Hosts {
-			if strUtils.IsInSlice(common.NODE, cluster.Spec.Hosts[i].Roles) {
-				cluster.Spec.Hosts[i].IPS = netutils.RemoveIPs(cluster.Spec.Hosts[i].IPS, workersToDelete)
-			}
-			continue
-		}
-	}
-
-	// if hosts have no ip address exist,then delete this host.
-	var hosts []v2.Host
-	for _, host := range cluster.Spec.Hosts {
-		if len(host.IPS) == 0 {
-			continue
-		}
-		hosts = append(hosts, host)
-	}
-	cluster.Spec.Hosts = hosts
-
-	return nil
-}
+var ConstructClusterForScaleDown = sealer.ConstructClusterForScaleDown
 
 func constructHost(role string, joinIPs []net.IP, scaleFlags *types.ScaleUpFlags, clusterSSH v1.SSH) v2.Host {
-	//todo we could support host level env form cli later.
 	//todo we could support host level role form cli later.
 	host := v2.Host{
-		IPS:   joinIPs,
-		Roles: []string{role},
-		Env:   scaleFlags.CustomEnv,
+		IPS:    joinIPs,
+		Roles:  []string{role},
+		Env:    scaleFlags.CustomEnv,
+		Taints: scaleFlags.Taints,
+	}
+	if len(scaleFlags.Labels) != 0 {
+		host.Labels = strUtils.ConvertStringSliceToMap(scaleFlags.Labels)
 	}
 
 	scaleFlagSSH := v1.SSH{
@@ -220,6 +187,91 @@ func constructHost(role string, joinIPs []net.IP, scaleFlags *types.ScaleUpFlags
 	return host
 }
 
+// knownCNIApps are the app names recognized as CNI plugins when bundled
+// in a sealer image.
+var knownCNIApps = []string{"calico", "cilium", "flannel", "hybridnet"}
+
+func isCNIApp(name string) bool {
+	return strUtils.IsInSlice(name, knownCNIApps)
+}
+
+// SelectCNIApp narrows the apps launched from the image so that of all
+// bundled CNI apps only the chosen one is installed.
+func SelectCNIApp(cluster *v2.Cluster, extension imagev1.ImageExtension, cni string) error {
+	var bundledCNIs []string
+	for _, app := range extension.Applications {
+		if isCNIApp(app.Name()) {
+			bundledCNIs = append(bundledCNIs, app.Name())
+		}
+	}
+	if !strUtils.IsInSlice(cni, bundledCNIs) {
+		return fmt.Errorf("image does not bundle CNI app %s, available CNI apps: %v", cni, bundledCNIs)
+	}
+
+	// start from the launch list the image or the user declared, falling
+	// back to every bundled app
+	appNames := cluster.Spec.APPNames
+	if len(appNames) == 0 {
+		appNames = extension.Launch.AppNames
+	}
+	if len(appNames) == 0 {
+		for _, app := range extension.Applications {
+			appNames = append(appNames, app.Name())
+		}
+	}
+
+	var filtered []string
+	for _, name := range appNames {
+		if isCNIApp(name) && name != cni {
+			continue
+		}
+		filtered = append(filtered, name)
+	}
+	if !strUtils.IsInSlice(cni, filtered) {
+		filtered = append([]string{cni}, filtered...)
+	}
+
+	cluster.Spec.APPNames = filtered
+	return nil
+}
+
+// gpuDevicePluginApp is the app deployed for hosts carrying the gpu
+// role when the image bundles it.
+const gpuDevicePluginApp = "nvidia-device-plugin"
+
+// EnsureGPUApp adds the NVIDIA device plugin app to the launch list when
+// the cluster has gpu hosts and the image bundles it.
+func EnsureGPUApp(cluster *v2.Cluster, extension imagev1.ImageExtension) {
+	hasGPUHost := false
+	for _, host := range cluster.Spec.Hosts {
+		if strUtils.IsInSlice(common.GPU, host.Roles) {
+			hasGPUHost = true
+			break
+		}
+	}
+	if !hasGPUHost {
+		return
+	}
+
+	bundled := false
+	for _, app := range extension.Applications {
+		if app.Name() == gpuDevicePluginApp {
+			bundled = true
+			break
+		}
+	}
+	if !bundled {
+		logrus.Warnf("cluster has gpu hosts but the image does not bundle the %s app", gpuDevicePluginApp)
+		return
+	}
+
+	// an empty launch list already launches every bundled app
+	if len(cluster.Spec.APPNames) == 0 || strUtils.IsInSlice(gpuDevicePluginApp, cluster.Spec.APPNames) {
+		return
+	}
+	cluster.Spec.APPNames = append(cluster.Spec.APPNames, gpuDevicePluginApp)
+}
+
 func GetCurrentCluster(client *k8s.Client) (*v2.Cluster, error) {
 	nodes, err := client.ListNodes()
 	if err != nil {
@@ -20,6 +20,8 @@ import (
 
 	"github.com/sealerio/sealer/cmd/sealer/cmd/types"
 	"github.com/sealerio/sealer/pkg/clusterfile"
+	"github.com/sealerio/sealer/pkg/define/application"
+	applicationV1 "github.com/sealerio/sealer/pkg/define/application/v1"
 	imagev1 "github.com/sealerio/sealer/pkg/define/image/v1"
 	v1 "github.com/sealerio/sealer/types/api/v1"
 	v2 "github.com/sealerio/sealer/types/api/v2"
@@ -384,3 +386,29 @@ func Test_MergeClusterWithImageExtension(t *testing.T) {
 		})
 	}
 }
+
+func TestSelectCNIApp(t *testing.T) {
+	multiCNIExtension := imagev1.ImageExtension{}
+	for _, name := range []string{"calico", "cilium", "dashboard"} {
+		multiCNIExtension.Applications = append(multiCNIExtension.Applications,
+			applicationV1.NewV1Application(name, application.KubeApp, nil))
+	}
+
+	// only the chosen CNI survives, other apps stay
+	cluster := &v2.Cluster{}
+	err := SelectCNIApp(cluster, multiCNIExtension, "cilium")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"cilium", "dashboard"}, cluster.Spec.APPNames)
+
+	// a user supplied launch list is filtered, not replaced
+	cluster = &v2.Cluster{}
+	cluster.Spec.APPNames = []string{"calico", "dashboard"}
+	err = SelectCNIApp(cluster, multiCNIExtension, "calico")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"calico", "dashboard"}, cluster.Spec.APPNames)
+
+	// asking for a CNI the image does not bundle fails
+	cluster = &v2.Cluster{}
+	err = SelectCNIApp(cluster, multiCNIExtension, "flannel")
+	assert.Error(t, err)
+}
@@ -0,0 +1,115 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"fmt"
+	"sort"
+
+	v2 "github.com/sealerio/sealer/types/api/v2"
+	strUtils "github.com/sealerio/sealer/utils/strings"
+)
+
+// DiffClusters compares the actual cluster state with the desired spec and
+// returns a human readable plan: one line per difference, prefixed with "+"
+// for additions, "-" for removals and "~" for changes. An empty plan means
+// apply would be a no-op.
+func DiffClusters(actual, desired *v2.Cluster) []string {
+	var plan []string
+
+	if actual.Spec.Image != desired.Spec.Image {
+		plan = append(plan, fmt.Sprintf("~ image: %s -> %s", actual.Spec.Image, desired.Spec.Image))
+	}
+
+	mj, md := strUtils.Diff(actual.GetMasterIPList(), desired.GetMasterIPList())
+	for _, ip := range mj {
+		plan = append(plan, fmt.Sprintf("+ master: %s", ip))
+	}
+	for _, ip := range md {
+		plan = append(plan, fmt.Sprintf("- master: %s", ip))
+	}
+
+	nj, nd := strUtils.Diff(actual.GetNodeIPList(), desired.GetNodeIPList())
+	for _, ip := range nj {
+		plan = append(plan, fmt.Sprintf("+ node: %s", ip))
+	}
+	for _, ip := range nd {
+		plan = append(plan, fmt.Sprintf("- node: %s", ip))
+	}
+
+	plan = append(plan, diffEnv(actual.Spec.Env, desired.Spec.Env)...)
+	plan = append(plan, diffApps(actual.Spec.APPNames, desired.Spec.APPNames)...)
+
+	return plan
+}
+
+func diffEnv(actualEnv, desiredEnv []string) []string {
+	var (
+		plan    []string
+		actual  = strUtils.ConvertStringSliceToMap(actualEnv)
+		desired = strUtils.ConvertStringSliceToMap(desiredEnv)
+		keys    []string
+	)
+
+	for key := range actual {
+		keys = append(keys, key)
+	}
+	for key := range desired {
+		if _, ok := actual[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		actualValue, inActual := actual[key]
+		desiredValue, inDesired := desired[key]
+		switch {
+		case !inActual:
+			plan = append(plan, fmt.Sprintf("+ env: %s=%s", key, desiredValue))
+		case !inDesired:
+			plan = append(plan, fmt.Sprintf("- env: %s=%s", key, actualValue))
+		case actualValue != desiredValue:
+			plan = append(plan, fmt.Sprintf("~ env: %s: %s -> %s", key, actualValue, desiredValue))
+		}
+	}
+	return plan
+}
+
+func diffApps(actualApps, desiredApps []string) []string {
+	var (
+		plan    []string
+		actual  = make(map[string]bool)
+		desired = make(map[string]bool)
+	)
+	for _, name := range actualApps {
+		actual[name] = true
+	}
+	for _, name := range desiredApps {
+		desired[name] = true
+	}
+
+	for _, name := range desiredApps {
+		if !actual[name] {
+			plan = append(plan, fmt.Sprintf("+ app: %s", name))
+		}
+	}
+	for _, name := range actualApps {
+		if !desired[name] {
+			plan = append(plan, fmt.Sprintf("- app: %s", name))
+		}
+	}
+	return plan
+}
@@ -0,0 +1,60 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"net"
+	"testing"
+
+	v2 "github.com/sealerio/sealer/types/api/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_DiffClusters(t *testing.T) {
+	actual := &v2.Cluster{
+		Spec: v2.ClusterSpec{
+			Image:    "kubernetes:v1.22.15",
+			Env:      []string{"PodCIDR=100.64.0.0/10", "Mode=single"},
+			APPNames: []string{"calico", "dashboard"},
+			Hosts: []v2.Host{
+				{IPS: []net.IP{net.IPv4(192, 168, 0, 2)}, Roles: []string{"master"}},
+				{IPS: []net.IP{net.IPv4(192, 168, 0, 3)}, Roles: []string{"node"}},
+			},
+		},
+	}
+	desired := &v2.Cluster{
+		Spec: v2.ClusterSpec{
+			Image:    "kubernetes:v1.24.6",
+			Env:      []string{"PodCIDR=100.64.0.0/10", "Mode=ha", "SvcCIDR=10.96.0.0/16"},
+			APPNames: []string{"calico", "mysql"},
+			Hosts: []v2.Host{
+				{IPS: []net.IP{net.IPv4(192, 168, 0, 2)}, Roles: []string{"master"}},
+				{IPS: []net.IP{net.IPv4(192, 168, 0, 3), net.IPv4(192, 168, 0, 4)}, Roles: []string{"node"}},
+			},
+		},
+	}
+
+	plan := DiffClusters(actual, desired)
+	assert.Equal(t, []string{
+		"~ image: kubernetes:v1.22.15 -> kubernetes:v1.24.6",
+		"+ node: 192.168.0.4",
+		"~ env: Mode: single -> ha",
+		"+ env: SvcCIDR=10.96.0.0/16",
+		"+ app: mysql",
+		"- app: dashboard",
+	}, plan)
+
+	assert.Empty(t, DiffClusters(actual, actual))
+}
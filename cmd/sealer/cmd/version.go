@@ -15,15 +15,23 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 
 	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/sealerio/sealer/pkg/client/k8s"
+	"github.com/sealerio/sealer/pkg/runtime/kubernetes"
 	"github.com/sealerio/sealer/version"
 )
 
-var shortPrint bool
+var (
+	shortPrint     bool
+	clusterVersion bool
+)
 
 func NewVersionCmd() *cobra.Command {
 	versionCmd := &cobra.Command{
@@ -32,6 +40,9 @@ func NewVersionCmd() *cobra.Command {
 		Args:    cobra.NoArgs,
 		Example: `sealer version`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if clusterVersion {
+				return printClusterMetadata()
+			}
 			marshalled, err := json.Marshal(version.Get())
 			if err != nil {
 				return err
@@ -45,5 +56,30 @@ func NewVersionCmd() *cobra.Command {
 		},
 	}
 	versionCmd.Flags().BoolVar(&shortPrint, "short", false, "if true, print sealer's own version number.")
+	versionCmd.Flags().BoolVar(&clusterVersion, "cluster", false, "print the install metadata sealer recorded in the current cluster.")
 	return versionCmd
 }
+
+// printClusterMetadata reads the metadata configmap sealer wrote into the
+// sealer-system namespace at install time.
+func printClusterMetadata() error {
+	client, err := k8s.NewK8sClient()
+	if err != nil {
+		return err
+	}
+
+	configMap, err := client.ConfigMap(kubernetes.SealerSystemNamespace).Get(context.Background(), kubernetes.ClusterMetadataConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to read cluster metadata, was the cluster installed by an older sealer? %v", err)
+	}
+
+	keys := make([]string, 0, len(configMap.Data))
+	for key := range configMap.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Printf("%s: %s\n", key, configMap.Data[key])
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// A cluster context namespaces everything sealer keeps on the local machine
+// for one cluster: the saved Clusterfile, the application state and the
+// fetched kubeconfig all live under ~/.sealer/clusters/<name>. When no
+// context is set, the historical single-cluster layout (~/.sealer/Clusterfile)
+// keeps working unchanged.
+
+// clusterContextOverride is set by the -c/--cluster flag and wins over the
+// persisted current context for this process only.
+var clusterContextOverride string
+
+func clusterContextsDir() string {
+	return filepath.Join(GetSealerWorkDir(), "clusters")
+}
+
+func currentContextFile() string {
+	return filepath.Join(GetSealerWorkDir(), "context")
+}
+
+// SetClusterContextOverride selects the cluster context for this process
+// without touching the persisted current context.
+func SetClusterContextOverride(name string) {
+	clusterContextOverride = name
+}
+
+// GetCurrentClusterContext returns the active cluster context name, or ""
+// when sealer runs in the historical single-cluster layout.
+func GetCurrentClusterContext() string {
+	if clusterContextOverride != "" {
+		return clusterContextOverride
+	}
+	data, err := os.ReadFile(filepath.Clean(currentContextFile()))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// GetClusterContextDir returns the local state dir of the named cluster.
+func GetClusterContextDir(name string) string {
+	return filepath.Join(clusterContextsDir(), name)
+}
+
+// GetClusterContextKubeConfig returns the path of the kubeconfig fetched for
+// the active cluster context, or "" when no context is set.
+func GetClusterContextKubeConfig() string {
+	ctx := GetCurrentClusterContext()
+	if ctx == "" {
+		return ""
+	}
+	return filepath.Join(GetClusterContextDir(ctx), "kubeconfig")
+}
+
+// UseClusterContext persists name as the current cluster context.
+func UseClusterContext(name string) error {
+	if err := validateClusterContextName(name); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(GetClusterContextDir(name), os.ModePerm); err != nil {
+		return err
+	}
+	return os.WriteFile(currentContextFile(), []byte(name+"\n"), FileMode0644)
+}
+
+// ListClusterContexts returns the known cluster context names.
+func ListClusterContexts() ([]string, error) {
+	entries, err := os.ReadDir(clusterContextsDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	return names, nil
+}
+
+// DeleteClusterContext removes the local state of the named cluster and
+// unsets the current context when it pointed to it. The cluster itself is
+// not touched.
+func DeleteClusterContext(name string) error {
+	if err := validateClusterContextName(name); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(GetClusterContextDir(name)); err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(filepath.Clean(currentContextFile()))
+	if err == nil && strings.TrimSpace(string(data)) == name {
+		return os.Remove(currentContextFile())
+	}
+	return nil
+}
+
+func validateClusterContextName(name string) error {
+	if name == "" {
+		return fmt.Errorf("cluster context name cannot be empty")
+	}
+	if strings.ContainsAny(name, "/\\ ") || name == "." || name == ".." {
+		return fmt.Errorf("invalid cluster context name: %s", name)
+	}
+	return nil
+}
@@ -24,6 +24,13 @@ const (
 	MASTER = "master"
 	// TODO，警惕，不能通过此标志来获取worker，因为master也可以role=node
 	NODE = "node"
+	// GPU marks hosts that carry NVIDIA devices and should get the
+	// nvidia container runtime configured.
+	GPU = "gpu"
+	// ETCD marks dedicated hosts serving the cluster store as external etcd
+	// members instead of joining kubernetes; without any etcd role hosts the
+	// members run stacked on the masters as before.
+	ETCD = "etcd"
 )
 
 const (
@@ -47,12 +54,23 @@ const (
 	RegLvsCareStaticPodName  = "reg-lvscare"
 	StaticPodDir             = "/etc/kubernetes/manifests"
 	LvsCareRepoAndTag        = "sealerio/lvscare:v1.1.3-beta.8"
+
+	// VIP serving modes, see EnvVIPMode.
+	VIPModeLvscare = "lvscare"
+	VIPModeKubeVIP = "kube-vip"
 )
 
 // Envs
 const (
-	EnvHostIP                 = "HostIP"
-	EnvHostIPFamily           = "HostIPFamily"
+	EnvHostIP       = "HostIP"
+	EnvHostIPFamily = "HostIPFamily"
+	// EnvHostName is the hostname of the target host, resolved on the host
+	// itself when a wrapped shell command runs.
+	EnvHostName = "HostName"
+	// EnvHostRoles is the comma joined role list of the target host.
+	EnvHostRoles = "HostRoles"
+	// EnvHostLabels is the comma joined "key=value" label list of the target host.
+	EnvHostLabels             = "HostLabels"
 	EnvContainerRuntime       = "ContainerRuntime"
 	EnvIPv6DualStack          = "IPv6DualStack"
 	EnvRegistryDomain         = "RegistryDomain"
@@ -66,12 +84,133 @@ const (
 	EnvExternalRegistryURL    = "ExternalRegistryURL"
 	EnvCertSANs               = "CertSANs"
 	EnvIPvsVIPForIPv4         = "IPvsVIPv4"
-	EnvIPvsVIPForIPv6         = "IPvsVIPv6"
-	EnvSvcCIDR                = "SvcCIDR"
-	EnvPodCIDR                = "PodCIDR"
-	EnvDNSSvcIP               = "DNSSvcIP"
-	EnvKubeSvcIP              = "KubeSvcIP"
-	EnvUseIPasNodeName        = "UseIPasNodeName"
+	EnvMaxJoinFailures        = "MaxJoinFailures"
+	EnvSSHConcurrency         = "SSHConcurrency"
+	// EnvAuditSyslog mirrors the remote command audit log to syslog when "true".
+	EnvAuditSyslog = "SEALER_AUDIT_SYSLOG"
+	// EnvMetricsAddr makes sealer serve Prometheus metrics on the given
+	// address (e.g. ":9100") for the lifetime of the process.
+	EnvMetricsAddr     = "SEALER_METRICS_ADDR"
+	EnvIPvsVIPForIPv6  = "IPvsVIPv6"
+	EnvSvcCIDR         = "SvcCIDR"
+	EnvPodCIDR         = "PodCIDR"
+	EnvDNSDomain       = "DNSDomain"
+	EnvDNSSvcIP        = "DNSSvcIP"
+	EnvKubeSvcIP       = "KubeSvcIP"
+	EnvUseIPasNodeName = "UseIPasNodeName"
+	// EnvNodeIP is a per-host env entry naming the address kubelet registers
+	// as node ip and kubeadm advertises for the apiserver and etcd peer URLs.
+	// Set it on the host entry, not cluster wide: each host needs its own.
+	EnvNodeIP = "NodeIP"
+	// EnvVIPMode selects how the apiserver VIP is served: "lvscare" (the
+	// default, a local ipvs proxy on every node) or "kube-vip" (a real
+	// ARP/BGP announced VIP on the masters).
+	EnvVIPMode = "VIPMode"
+	// EnvKubeVIPImage overrides the kube-vip image reference; references
+	// without a registry host are pulled from the local registry.
+	EnvKubeVIPImage = "KubeVIPImage"
+	// EnvKubeVIPMode selects the kube-vip announcement mode, "arp" or "bgp".
+	EnvKubeVIPMode = "KubeVIPMode"
+	// EnvKubeVIPInterface names the NIC kube-vip binds the VIP to in ARP
+	// mode; NodeInterface is used when unset, autodetect when both are empty.
+	EnvKubeVIPInterface = "KubeVIPInterface"
+	// EnvKubeVIPEnableServices lets kube-vip also serve LoadBalancer
+	// Services when set to "true".
+	EnvKubeVIPEnableServices = "KubeVIPEnableServices"
+	// EnvKubeVIPBGPRouterID, EnvKubeVIPBGPAS and EnvKubeVIPBGPPeers carry
+	// the BGP session parameters when KubeVIPMode is "bgp"; peers are a
+	// comma separated "address:as" list.
+	EnvKubeVIPBGPRouterID = "KubeVIPBGPRouterID"
+	EnvKubeVIPBGPAS       = "KubeVIPBGPAS"
+	EnvKubeVIPBGPPeers    = "KubeVIPBGPPeers"
+	// EnvLvscareImage overrides the lvscare image reference used for the
+	// generated kube-lvscare and reg-lvscare static pods; references
+	// without a registry host are pulled from the local registry.
+	EnvLvscareImage = "LvscareImage"
+	// EnvNodeInterface names the network interface holding the cluster
+	// network on multi-NIC servers where the default route leaves through
+	// another interface; the address is resolved on the host itself. It may
+	// be set cluster wide when all hosts share the NIC naming.
+	EnvNodeInterface = "NodeInterface"
+	// EnvFirewallMode decides what sealer does about a running firewalld
+	// during install, see the FirewallMode* values.
+	EnvFirewallMode = "FirewallMode"
+	// EnvSELinuxMode decides what sealer does about SELinux during install,
+	// see the SELinuxMode* values.
+	EnvSELinuxMode = "SELinuxMode"
+	// EnvSecretEncryption enables encryption at rest for kubernetes secrets:
+	// "aescbc" generates a key during install, "kms" wires the apiserver to
+	// the KMS plugin socket named by KMSEndpoint.
+	EnvSecretEncryption = "SecretEncryption"
+	// EnvKMSEndpoint is the unix socket of the KMS provider plugin, e.g.
+	// "unix:///var/run/kmsplugin/socket.sock", required with
+	// SecretEncryption=kms.
+	EnvKMSEndpoint = "KMSEndpoint"
+	// EnvAuditLog set to "true" wires the audit policy shipped in the image
+	// statics dir into kube-apiserver with a rotated audit log; the
+	// AuditLogMax* envs tune the rotation.
+	EnvAuditLog          = "AuditLog"
+	EnvAuditLogMaxAge    = "AuditLogMaxAge"
+	EnvAuditLogMaxBackup = "AuditLogMaxBackup"
+	EnvAuditLogMaxSize   = "AuditLogMaxSize"
+	// EnvHardeningProfile applies a hardening profile during install, see the
+	// HardeningProfile* values; the `sealer run --profile` flag sets it.
+	EnvHardeningProfile = "HardeningProfile"
+	// EnvOIDCIssuerURL hooks the apiserver to an enterprise IdP at creation
+	// time: the https URL of the OIDC issuer. OIDCClientID is required with
+	// it, the other OIDC envs are optional.
+	EnvOIDCIssuerURL = "OIDCIssuerURL"
+	// EnvOIDCClientID is the client id all OIDC tokens must be issued for.
+	EnvOIDCClientID = "OIDCClientID"
+	// EnvOIDCUsernameClaim and EnvOIDCUsernamePrefix control how token
+	// claims map to kubernetes user names.
+	EnvOIDCUsernameClaim  = "OIDCUsernameClaim"
+	EnvOIDCUsernamePrefix = "OIDCUsernamePrefix"
+	// EnvOIDCGroupsClaim names the token claim carrying the group list used
+	// for RBAC.
+	EnvOIDCGroupsClaim = "OIDCGroupsClaim"
+	// EnvCustomCACert and EnvCustomCAKey are local file paths to a user
+	// supplied CA pair (a corporate root or an intermediate) the cluster and
+	// registry certificates are issued from, instead of generated self-signed
+	// CAs. Both must be set together.
+	EnvCustomCACert = "CustomCACert"
+	EnvCustomCAKey  = "CustomCAKey"
+)
+
+// HardeningProfile values for EnvHardeningProfile.
+const (
+	// HardeningProfileCIS applies the CIS kubernetes benchmark settings:
+	// kubelet read-only port and anonymous auth off, webhook authorization,
+	// strong TLS cipher suites on apiserver/kubelet/etcd, no profiling
+	// endpoints and tightened permissions on manifests, pki and kubeconfigs.
+	// `sealer alpha audit cis` verifies them on a running cluster.
+	HardeningProfileCIS = "cis"
+)
+
+// FirewallMode values for EnvFirewallMode.
+const (
+	// FirewallModeCheck (the default) only reports required ports a running
+	// firewalld does not allow, it changes nothing.
+	FirewallModeCheck = "check"
+	// FirewallModeOpenPorts permanently opens the required ports via
+	// firewall-cmd on every node.
+	FirewallModeOpenPorts = "open-ports"
+	// FirewallModeSkip disables the firewall handling entirely.
+	FirewallModeSkip = "skip"
+)
+
+// SELinuxMode values for EnvSELinuxMode.
+const (
+	// SELinuxModeKeep (the default) leaves SELinux as found; on enforcing
+	// hosts the sealer data dirs are labeled and the container runtime is
+	// configured with selinux support so the install works under enforcement.
+	SELinuxModeKeep = "keep"
+	// SELinuxModePermissive switches SELinux to permissive, now and in the
+	// persistent config.
+	SELinuxModePermissive = "permissive"
+	// SELinuxModeDisable disables SELinux in the persistent config and stops
+	// enforcement immediately.
+	SELinuxModeDisable = "disable"
 )
 
 const (
@@ -122,6 +261,9 @@ const (
 const (
 	BAREMETAL = "BAREMETAL"
 	AliCloud  = "ALI_CLOUD"
+	AWSCloud  = "AWS_CLOUD"
+	LIBVIRT   = "LIBVIRT"
+	TERRAFORM = "TERRAFORM"
 	CONTAINER = "CONTAINER"
 )
 
@@ -148,13 +290,26 @@ func GetSealerWorkDir() string {
 }
 
 func GetDefaultClusterfile() string {
+	if ctx := GetCurrentClusterContext(); ctx != "" {
+		return filepath.Join(GetClusterContextDir(ctx), "Clusterfile")
+	}
 	return filepath.Join(GetSealerWorkDir(), "Clusterfile")
 }
 
 func GetDefaultApplicationFile() string {
+	if ctx := GetCurrentClusterContext(); ctx != "" {
+		return filepath.Join(GetClusterContextDir(ctx), "application.json")
+	}
 	return filepath.Join(GetSealerWorkDir(), "application.json")
 }
 
+func GetDefaultApplicationHistoryFile() string {
+	if ctx := GetCurrentClusterContext(); ctx != "" {
+		return filepath.Join(GetClusterContextDir(ctx), "application-history.json")
+	}
+	return filepath.Join(GetSealerWorkDir(), "application-history.json")
+}
+
 func DefaultRegistryAuthConfigDir() string {
 	return filepath.Join(GetHomeDir(), ".docker/config.json")
 }
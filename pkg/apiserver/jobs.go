@@ -0,0 +1,128 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type JobStatus string
+
+const (
+	JobPending   JobStatus = "Pending"
+	JobRunning   JobStatus = "Running"
+	JobSucceeded JobStatus = "Succeeded"
+	JobFailed    JobStatus = "Failed"
+)
+
+// Job is the unit of async work the API server hands out: every cluster
+// operation is accepted immediately and tracked until it finishes.
+type Job struct {
+	ID         string     `json:"id"`
+	Operation  string     `json:"operation"`
+	Status     JobStatus  `json:"status"`
+	Error      string     `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+type jobTracker struct {
+	mu   sync.RWMutex
+	jobs map[string]*Job
+
+	// execMu serializes the cluster operations themselves: two concurrent
+	// applies against the same cluster would corrupt it.
+	execMu sync.Mutex
+}
+
+func newJobTracker() *jobTracker {
+	return &jobTracker{jobs: map[string]*Job{}}
+}
+
+// Submit registers a job and runs fn asynchronously. Jobs are executed one
+// at a time in submission order.
+func (t *jobTracker) Submit(operation string, fn func() error) *Job {
+	job := &Job{
+		ID:        uuid.New().String(),
+		Operation: operation,
+		Status:    JobPending,
+		CreatedAt: time.Now(),
+	}
+
+	t.mu.Lock()
+	t.jobs[job.ID] = job
+	t.mu.Unlock()
+
+	go func() {
+		t.execMu.Lock()
+		defer t.execMu.Unlock()
+
+		t.setStatus(job.ID, JobRunning, "")
+		if err := fn(); err != nil {
+			t.setStatus(job.ID, JobFailed, err.Error())
+			return
+		}
+		t.setStatus(job.ID, JobSucceeded, "")
+	}()
+
+	return t.snapshot(job.ID)
+}
+
+func (t *jobTracker) setStatus(id string, status JobStatus, errMsg string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	job := t.jobs[id]
+	job.Status = status
+	job.Error = errMsg
+	if status == JobSucceeded || status == JobFailed {
+		now := time.Now()
+		job.FinishedAt = &now
+	}
+}
+
+// Get returns a copy of the job, or nil when it is unknown.
+func (t *jobTracker) Get(id string) *Job {
+	return t.snapshot(id)
+}
+
+// List returns copies of all jobs, oldest first.
+func (t *jobTracker) List() []Job {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	jobs := make([]Job, 0, len(t.jobs))
+	for _, job := range t.jobs {
+		jobs = append(jobs, *job)
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreatedAt.Before(jobs[j].CreatedAt)
+	})
+	return jobs
+}
+
+func (t *jobTracker) snapshot(id string) *Job {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	job, ok := t.jobs[id]
+	if !ok {
+		return nil
+	}
+	copied := *job
+	return &copied
+}
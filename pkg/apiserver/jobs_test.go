@@ -0,0 +1,56 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func waitForJob(t *testing.T, tracker *jobTracker, id string) *Job {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		job := tracker.Get(id)
+		if job.Status == JobSucceeded || job.Status == JobFailed {
+			return job
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("job %s did not finish in time", id)
+	return nil
+}
+
+func TestJobTracker(t *testing.T) {
+	tracker := newJobTracker()
+
+	ok := tracker.Submit("apply", func() error { return nil })
+	failed := tracker.Submit("delete", func() error { return fmt.Errorf("boom") })
+
+	okDone := waitForJob(t, tracker, ok.ID)
+	assert.Equal(t, JobSucceeded, okDone.Status)
+	assert.Empty(t, okDone.Error)
+	assert.NotNil(t, okDone.FinishedAt)
+
+	failedDone := waitForJob(t, tracker, failed.ID)
+	assert.Equal(t, JobFailed, failedDone.Status)
+	assert.Equal(t, "boom", failedDone.Error)
+
+	jobs := tracker.List()
+	assert.Len(t, jobs, 2)
+	assert.Nil(t, tracker.Get("unknown"))
+}
@@ -0,0 +1,196 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maxRequestBody caps the request body size; a Clusterfile is small.
+const maxRequestBody = 4 << 20
+
+// Operations are the cluster lifecycle actions the API server exposes. The
+// cmd layer injects them so this package stays free of the CLI wiring.
+type Operations struct {
+	// Apply creates or scales a cluster from raw Clusterfile data, the
+	// same semantics as `sealer apply -f`.
+	Apply func(clusterfileData []byte) error
+	// Delete tears down the whole cluster, the same semantics as
+	// `sealer delete -a --force`.
+	Delete func() error
+}
+
+type Options struct {
+	// Addr to listen on, e.g. ":8383".
+	Addr string
+	// Token clients must present as `Authorization: Bearer <token>`.
+	// A random token is generated and logged when empty.
+	Token string
+}
+
+type Server struct {
+	addr  string
+	token string
+	ops   Operations
+	jobs  *jobTracker
+}
+
+func NewServer(opts Options, ops Operations) (*Server, error) {
+	token := opts.Token
+	if token == "" {
+		raw := make([]byte, 24)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, err
+		}
+		token = hex.EncodeToString(raw)
+		logrus.Infof("generated API token: %s", token)
+	}
+
+	return &Server{
+		addr:  opts.Addr,
+		token: token,
+		ops:   ops,
+		jobs:  newJobTracker(),
+	}, nil
+}
+
+// Run serves the API until ctx is cancelled, then shuts down gracefully.
+func (s *Server) Run(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "ok")
+	})
+	mux.HandleFunc("/api/v1/apply", s.authenticated(s.handleApply))
+	mux.HandleFunc("/api/v1/delete", s.authenticated(s.handleDelete))
+	mux.HandleFunc("/api/v1/build", s.authenticated(s.handleBuild))
+	mux.HandleFunc("/api/v1/jobs", s.authenticated(s.handleJobs))
+	mux.HandleFunc("/api/v1/jobs/", s.authenticated(s.handleJob))
+
+	server := &http.Server{
+		Addr:              s.addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		logrus.Infof("API server listening on %s", s.addr)
+		errCh <- server.ListenAndServe()
+	}()
+
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return server.Shutdown(shutdownCtx)
+	case err := <-errCh:
+		return err
+	}
+}
+
+func (s *Server) authenticated(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			writeError(w, http.StatusUnauthorized, "invalid or missing bearer token")
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (s *Server) handleApply(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "only POST is allowed")
+		return
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBody))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("failed to read request body: %v", err))
+		return
+	}
+	if len(data) == 0 {
+		writeError(w, http.StatusBadRequest, "request body must be a Clusterfile")
+		return
+	}
+
+	job := s.jobs.Submit("apply", func() error {
+		return s.ops.Apply(data)
+	})
+	writeJSON(w, http.StatusAccepted, job)
+}
+
+func (s *Server) handleDelete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "only POST is allowed")
+		return
+	}
+
+	job := s.jobs.Submit("delete", s.ops.Delete)
+	writeJSON(w, http.StatusAccepted, job)
+}
+
+func (s *Server) handleBuild(w http.ResponseWriter, r *http.Request) {
+	// a build needs the build context on the server side which the API
+	// does not transport yet
+	writeError(w, http.StatusNotImplemented, "build is not supported over the API, run sealer build on the build machine")
+}
+
+func (s *Server) handleJobs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "only GET is allowed")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.jobs.List())
+}
+
+func (s *Server) handleJob(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "only GET is allowed")
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, "/api/v1/jobs/")
+	job := s.jobs.Get(id)
+	if job == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("job %s not found", id))
+		return
+	}
+	writeJSON(w, http.StatusOK, job)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		logrus.Errorf("failed to write response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
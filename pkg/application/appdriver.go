@@ -17,10 +17,12 @@ package application
 import (
 	"encoding/json"
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/sealerio/sealer/common"
 	v1 "github.com/sealerio/sealer/pkg/define/application/v1"
@@ -66,6 +68,9 @@ type applicationDriver struct {
 
 	// appFileProcessorMap contains the whole FileProcessors with app name as its key.
 	appFileProcessorMap map[string][]FileProcessor
+
+	// appReadinessGatesMap contains the readiness gates with app name as its key.
+	appReadinessGatesMap map[string][]v2.ReadinessGate
 }
 
 func (a *applicationDriver) GetAppLaunchCmds(appName string) []string {
@@ -117,11 +122,30 @@ func (a *applicationDriver) Launch(infraDriver infradriver.InfraDriver) error {
 			continue
 		}
 
-		if err := infraDriver.CmdAsync(master0, nil, fmt.Sprintf(common.CdAndExecCmd, rootfsPath, cmdline)); err != nil {
+		// a cmd may declare the hosts it runs on via an on:role=/on:host=
+		// prefix, plain cmds keep running on master0 only.
+		target, cmd := parseCmdTarget(cmdline)
+		hosts := []net.IP{master0}
+		if target != nil {
+			var err error
+			hosts, err = resolveCmdTargetHosts(target, infraDriver)
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := infraDriver.Execute(hosts, func(host net.IP) error {
+			return infraDriver.CmdAsync(host, nil, fmt.Sprintf(common.CdAndExecCmd, rootfsPath, cmd))
+		}); err != nil {
 			return err
 		}
 	}
 
+	// evaluate readiness gates, so a successful launch means the apps are up.
+	if err := a.evaluateReadinessGates(infraDriver, master0); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -159,6 +183,16 @@ func (a *applicationDriver) Save(opts SaveOptions) error {
 		return err
 	}
 
+	if opts.ImageName != "" {
+		if err := RecordVersion(VersionRecord{
+			ImageName:   opts.ImageName,
+			AppNames:    a.GetAppNames(),
+			InstalledAt: time.Now(),
+		}); err != nil {
+			return fmt.Errorf("failed to record app install history: %v", err)
+		}
+	}
+
 	return nil
 }
 
@@ -240,6 +274,14 @@ func NewAppDriver(app *v2.Application, extension imagev1.ImageExtension) (Interf
 		}
 		appDriver.appFileProcessorMap[name] = fileProcessors
 
+		// initialize app readiness gates
+		for _, gate := range config.ReadinessGates {
+			if err := validateReadinessGate(gate); err != nil {
+				return nil, fmt.Errorf("invalid readiness gate of app %s: %v", name, err)
+			}
+		}
+		appDriver.appReadinessGatesMap[name] = config.ReadinessGates
+
 		// TODO initialize delete field
 	}
 
@@ -248,15 +290,16 @@ func NewAppDriver(app *v2.Application, extension imagev1.ImageExtension) (Interf
 
 func formatImageExtension(extension imagev1.ImageExtension) *applicationDriver {
 	appDriver := &applicationDriver{
-		extension:           extension,
-		globalCmds:          extension.Launch.Cmds,
-		globalEnv:           extension.Env,
-		launchApps:          extension.Launch.AppNames,
-		registeredApps:      []string{},
-		appLaunchCmdsMap:    map[string][]string{},
-		appRootMap:          map[string]string{},
-		appEnvMap:           map[string]map[string]string{},
-		appFileProcessorMap: map[string][]FileProcessor{},
+		extension:            extension,
+		globalCmds:           extension.Launch.Cmds,
+		globalEnv:            extension.Env,
+		launchApps:           extension.Launch.AppNames,
+		registeredApps:       []string{},
+		appLaunchCmdsMap:     map[string][]string{},
+		appRootMap:           map[string]string{},
+		appEnvMap:            map[string]map[string]string{},
+		appFileProcessorMap:  map[string][]FileProcessor{},
+		appReadinessGatesMap: map[string][]v2.ReadinessGate{},
 	}
 
 	for _, registeredApp := range extension.Applications {
@@ -262,6 +262,7 @@ func TestFormatExtensionToApplication(t *testing.T) {
 			"app2": {envRender{envData: appEnvMap["app2"]}},
 			"app3": {envRender{envData: appEnvMap["app3"]}},
 		},
+		appReadinessGatesMap: map[string][]v2.ReadinessGate{},
 	}
 
 	type args struct {
@@ -0,0 +1,117 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sealerio/sealer/common"
+	strUtils "github.com/sealerio/sealer/utils/strings"
+)
+
+// VersionRecord is one entry of the app install history: which image was
+// used to launch which apps and when. The history is the data source of
+// `sealer app rollback`.
+type VersionRecord struct {
+	// ImageName is the sealer image the apps were launched from.
+	ImageName string `json:"imageName"`
+
+	// AppNames is the app name list launched from ImageName.
+	AppNames []string `json:"appNames"`
+
+	// InstalledAt is the time the launch succeeded.
+	InstalledAt time.Time `json:"installedAt"`
+}
+
+// LoadHistory returns the app install history of the current cluster,
+// ordered from the oldest record to the latest one. A missing history file
+// means no record yet and is not an error.
+func LoadHistory() ([]VersionRecord, error) {
+	historyFile := common.GetDefaultApplicationHistoryFile()
+
+	content, err := os.ReadFile(filepath.Clean(historyFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var records []VersionRecord
+	if err := json.Unmarshal(content, &records); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal app install history %s: %v", historyFile, err)
+	}
+
+	return records, nil
+}
+
+// RecordVersion appends a new record to the app install history.
+func RecordVersion(record VersionRecord) error {
+	records, err := LoadHistory()
+	if err != nil {
+		return err
+	}
+
+	records = append(records, record)
+	content, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal app install history: %v", err)
+	}
+
+	historyFile := common.GetDefaultApplicationHistoryFile()
+	if err := os.MkdirAll(filepath.Dir(historyFile), 0750); err != nil {
+		return err
+	}
+
+	return os.WriteFile(historyFile, content, 0600)
+}
+
+// PreviousVersion returns the record of the version installed before the
+// currently installed one for the given app: the latest record holding the
+// app is the current version, the next older one is the rollback target.
+func PreviousVersion(appName string) (*VersionRecord, error) {
+	records, err := LoadHistory()
+	if err != nil {
+		return nil, err
+	}
+
+	return previousVersionFromRecords(records, appName)
+}
+
+func previousVersionFromRecords(records []VersionRecord, appName string) (*VersionRecord, error) {
+	var currentFound bool
+	for i := len(records) - 1; i >= 0; i-- {
+		if !strUtils.IsInSlice(appName, records[i].AppNames) {
+			continue
+		}
+
+		if !currentFound {
+			currentFound = true
+			continue
+		}
+
+		return &records[i], nil
+	}
+
+	if !currentFound {
+		return nil, fmt.Errorf("app %s is not found in the install history", appName)
+	}
+
+	return nil, fmt.Errorf("no previous version of app %s is recorded in the install history", appName)
+}
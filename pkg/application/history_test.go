@@ -0,0 +1,51 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreviousVersionFromRecords(t *testing.T) {
+	records := []VersionRecord{
+		{ImageName: "my-app:v1", AppNames: []string{"nginx", "dashboard"}},
+		{ImageName: "my-app:v2", AppNames: []string{"nginx"}},
+		{ImageName: "my-app:v3", AppNames: []string{"nginx", "dashboard"}},
+	}
+
+	t.Run("previous version is the record before the latest one", func(t *testing.T) {
+		previous, err := previousVersionFromRecords(records, "nginx")
+		assert.NoError(t, err)
+		assert.Equal(t, "my-app:v2", previous.ImageName)
+	})
+
+	t.Run("records without the app are skipped", func(t *testing.T) {
+		previous, err := previousVersionFromRecords(records, "dashboard")
+		assert.NoError(t, err)
+		assert.Equal(t, "my-app:v1", previous.ImageName)
+	})
+
+	t.Run("unknown app is reported", func(t *testing.T) {
+		_, err := previousVersionFromRecords(records, "mysql")
+		assert.Error(t, err)
+	})
+
+	t.Run("single version has nothing to rollback to", func(t *testing.T) {
+		_, err := previousVersionFromRecords(records[:1], "nginx")
+		assert.Error(t, err)
+	})
+}
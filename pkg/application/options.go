@@ -15,4 +15,8 @@
 package application
 
 type SaveOptions struct {
+	// ImageName: when set, a VersionRecord of this image and the launched
+	// apps is appended to the app install history, which feeds
+	// `sealer app rollback`.
+	ImageName string
 }
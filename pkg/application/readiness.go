@@ -0,0 +1,106 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/sealerio/sealer/pkg/infradriver"
+	v2 "github.com/sealerio/sealer/types/api/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// defaultReadinessTimeoutSeconds bounds a readiness gate when the config
+// does not set its own timeout.
+const defaultReadinessTimeoutSeconds = 300
+
+// validateReadinessGate checks that exactly one check type is set.
+func validateReadinessGate(gate v2.ReadinessGate) error {
+	var set int
+	for _, check := range []string{gate.KubectlWait, gate.RolloutStatus, gate.HTTPGet} {
+		if check != "" {
+			set++
+		}
+	}
+
+	if set != 1 {
+		return fmt.Errorf("exactly one of kubectlWait, rolloutStatus and httpGet must be set per readiness gate")
+	}
+
+	if gate.TimeoutSeconds < 0 {
+		return fmt.Errorf("readiness gate timeoutSeconds could not be negative")
+	}
+
+	return nil
+}
+
+// gateCommand renders the shell command which evaluates the gate on master0.
+func gateCommand(gate v2.ReadinessGate) string {
+	timeout := gate.TimeoutSeconds
+	if timeout == 0 {
+		timeout = defaultReadinessTimeoutSeconds
+	}
+
+	switch {
+	case gate.KubectlWait != "":
+		return fmt.Sprintf("kubectl wait --timeout=%ds %s", timeout, gate.KubectlWait)
+	case gate.RolloutStatus != "":
+		return fmt.Sprintf("kubectl rollout status --timeout=%ds %s", timeout, gate.RolloutStatus)
+	case gate.HTTPGet != "":
+		return fmt.Sprintf("timeout %d sh -c 'until curl -skf -o /dev/null %s; do sleep 5; done'", timeout, gate.HTTPGet)
+	}
+
+	return ""
+}
+
+// gateDescription is used in logs and failure output to name the gate.
+func gateDescription(gate v2.ReadinessGate) string {
+	switch {
+	case gate.KubectlWait != "":
+		return fmt.Sprintf("kubectl wait %s", gate.KubectlWait)
+	case gate.RolloutStatus != "":
+		return fmt.Sprintf("rollout status %s", gate.RolloutStatus)
+	case gate.HTTPGet != "":
+		return fmt.Sprintf("http get %s", gate.HTTPGet)
+	}
+
+	return ""
+}
+
+// evaluateReadinessGates runs the configured readiness gates of every
+// launched app on master0 after the launch commands finished, so a
+// successful launch means the apps are actually up.
+func (a *applicationDriver) evaluateReadinessGates(infraDriver infradriver.InfraDriver, master0 net.IP) error {
+	for _, appName := range a.launchApps {
+		gates := a.appReadinessGatesMap[appName]
+		if len(gates) == 0 {
+			continue
+		}
+
+		logrus.Infof("start to evaluate %d readiness gates of app %s", len(gates), appName)
+		for _, gate := range gates {
+			output, err := infraDriver.Cmd(master0, nil, gateCommand(gate))
+			if err != nil {
+				return fmt.Errorf("app %s is not ready, gate [%s] failed: %v, output: %s",
+					appName, gateDescription(gate), err, string(output))
+			}
+
+			logrus.Debugf("readiness gate [%s] of app %s passed", gateDescription(gate), appName)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"testing"
+
+	v2 "github.com/sealerio/sealer/types/api/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGateCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		gate v2.ReadinessGate
+		want string
+	}{
+		{
+			name: "kubectl wait with default timeout",
+			gate: v2.ReadinessGate{KubectlWait: "--for=condition=Available deployment/nginx -n default"},
+			want: "kubectl wait --timeout=300s --for=condition=Available deployment/nginx -n default",
+		},
+		{
+			name: "rollout status with custom timeout",
+			gate: v2.ReadinessGate{RolloutStatus: "deployment/nginx -n default", TimeoutSeconds: 60},
+			want: "kubectl rollout status --timeout=60s deployment/nginx -n default",
+		},
+		{
+			name: "http probe",
+			gate: v2.ReadinessGate{HTTPGet: "http://127.0.0.1:30080/healthz", TimeoutSeconds: 30},
+			want: "timeout 30 sh -c 'until curl -skf -o /dev/null http://127.0.0.1:30080/healthz; do sleep 5; done'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, gateCommand(tt.gate))
+		})
+	}
+}
+
+func TestValidateReadinessGate(t *testing.T) {
+	assert.NoError(t, validateReadinessGate(v2.ReadinessGate{HTTPGet: "http://127.0.0.1"}))
+	assert.Error(t, validateReadinessGate(v2.ReadinessGate{}))
+	assert.Error(t, validateReadinessGate(v2.ReadinessGate{HTTPGet: "http://127.0.0.1", RolloutStatus: "deployment/nginx"}))
+	assert.Error(t, validateReadinessGate(v2.ReadinessGate{HTTPGet: "http://127.0.0.1", TimeoutSeconds: -1}))
+}
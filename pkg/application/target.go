@@ -0,0 +1,90 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/sealerio/sealer/common"
+	"github.com/sealerio/sealer/pkg/infradriver"
+)
+
+// cmdTarget declares on which hosts a launch command runs. Without a target
+// a command keeps the default behavior and runs on master0 only.
+type cmdTarget struct {
+	// role is a host role from the Clusterfile, e.g. "master", "node" or a
+	// custom role like "ingress". "master0" addresses the first master,
+	// "all" addresses every cluster host.
+	role string
+
+	// hosts is an explicit comma separated IP list.
+	hosts string
+}
+
+// cmdTargetRegexp matches the optional target prefix of a launch command:
+// on:role=<role> <cmd> or on:host=<ip,...> <cmd>, e.g.
+// "on:role=ingress systemctl restart nginx".
+var cmdTargetRegexp = regexp.MustCompile(`^on:\s*(role|host)=(\S+)\s+(.+)$`)
+
+// parseCmdTarget splits the optional target prefix off a launch command.
+// It returns a nil target for a plain command.
+func parseCmdTarget(cmdline string) (*cmdTarget, string) {
+	matched := cmdTargetRegexp.FindStringSubmatch(strings.TrimSpace(cmdline))
+	if matched == nil {
+		return nil, cmdline
+	}
+
+	if matched[1] == "host" {
+		return &cmdTarget{hosts: matched[2]}, matched[3]
+	}
+
+	return &cmdTarget{role: matched[2]}, matched[3]
+}
+
+// resolveCmdTargetHosts resolves a command target to the cluster host IP list
+// it addresses.
+func resolveCmdTargetHosts(target *cmdTarget, infraDriver infradriver.InfraDriver) ([]net.IP, error) {
+	if target.hosts != "" {
+		var hosts []net.IP
+		for _, raw := range strings.Split(target.hosts, ",") {
+			ip := net.ParseIP(raw)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid host IP %s in command target", raw)
+			}
+			hosts = append(hosts, ip)
+		}
+		return hosts, nil
+	}
+
+	switch target.role {
+	case "all":
+		return infraDriver.GetHostIPList(), nil
+	case "master0":
+		masters := infraDriver.GetHostIPListByRole(common.MASTER)
+		if len(masters) == 0 {
+			return nil, fmt.Errorf("no master host found for command target role master0")
+		}
+		return masters[:1], nil
+	default:
+		hosts := infraDriver.GetHostIPListByRole(target.role)
+		if len(hosts) == 0 {
+			return nil, fmt.Errorf("no host with role %s found for command target", target.role)
+		}
+		return hosts, nil
+	}
+}
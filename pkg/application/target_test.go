@@ -0,0 +1,56 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCmdTarget(t *testing.T) {
+	t.Run("plain cmd has no target", func(t *testing.T) {
+		target, cmd := parseCmdTarget("kubectl apply -f nginx.yaml")
+		assert.Nil(t, target)
+		assert.Equal(t, "kubectl apply -f nginx.yaml", cmd)
+	})
+
+	t.Run("role target", func(t *testing.T) {
+		target, cmd := parseCmdTarget("on:role=ingress systemctl restart nginx")
+		assert.NotNil(t, target)
+		assert.Equal(t, "ingress", target.role)
+		assert.Equal(t, "systemctl restart nginx", cmd)
+	})
+
+	t.Run("role target with space after colon", func(t *testing.T) {
+		target, cmd := parseCmdTarget("on: role=master0 bash init.sh")
+		assert.NotNil(t, target)
+		assert.Equal(t, "master0", target.role)
+		assert.Equal(t, "bash init.sh", cmd)
+	})
+
+	t.Run("host target", func(t *testing.T) {
+		target, cmd := parseCmdTarget("on:host=192.168.0.2,192.168.0.3 bash agent.sh")
+		assert.NotNil(t, target)
+		assert.Equal(t, "192.168.0.2,192.168.0.3", target.hosts)
+		assert.Equal(t, "bash agent.sh", cmd)
+	})
+
+	t.Run("on without target spec stays a plain cmd", func(t *testing.T) {
+		target, cmd := parseCmdTarget("online-check --all")
+		assert.Nil(t, target)
+		assert.Equal(t, "online-check --all", cmd)
+	})
+}
@@ -0,0 +1,168 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package checkpoint persists the progress of a cluster apply so that a
+// failed `sealer run` can be re-executed and continue from the phase that
+// failed instead of re-running every phase from scratch.
+package checkpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	osi "github.com/sealerio/sealer/utils/os"
+)
+
+// checkpointDir holds one checkpoint file per cluster.
+var checkpointDir = "/var/lib/sealer/checkpoint"
+
+// Checkpoint records which phases of an apply already completed for one
+// cluster, together with the hosts that successfully joined.
+type Checkpoint struct {
+	ClusterName     string            `json:"clusterName"`
+	CompletedPhases map[string]string `json:"completedPhases"`
+	JoinedHosts     []string          `json:"joinedHosts,omitempty"`
+	// FailedJoinHosts are hosts that failed to join and were skipped by
+	// the join failure policy; `sealer join --retry-failed` picks them up.
+	FailedJoinHosts []string `json:"failedJoinHosts,omitempty"`
+}
+
+// Manager loads, updates and clears the checkpoint of one cluster.
+type Manager struct {
+	path       string
+	checkpoint *Checkpoint
+}
+
+// NewManager returns a Manager for the named cluster, loading an existing
+// checkpoint from disk when one is present.
+func NewManager(clusterName string) (*Manager, error) {
+	path := filepath.Join(checkpointDir, fmt.Sprintf("%s.json", clusterName))
+	manager := &Manager{
+		path: path,
+		checkpoint: &Checkpoint{
+			ClusterName:     clusterName,
+			CompletedPhases: map[string]string{},
+		},
+	}
+
+	if !osi.IsFileExist(path) {
+		return manager, nil
+	}
+
+	data, err := osi.NewFileReader(path).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint file %s: %v", path, err)
+	}
+	if err := json.Unmarshal(data, manager.checkpoint); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal checkpoint file %s: %v", path, err)
+	}
+	if manager.checkpoint.CompletedPhases == nil {
+		manager.checkpoint.CompletedPhases = map[string]string{}
+	}
+	return manager, nil
+}
+
+// IsPhaseDone reports whether the phase already completed in a previous
+// run against the same cluster.
+func (m *Manager) IsPhaseDone(phase string) bool {
+	_, ok := m.checkpoint.CompletedPhases[phase]
+	return ok
+}
+
+// MarkPhaseDone records the phase as completed and flushes the checkpoint
+// to disk immediately, so an interrupt right after the phase still leaves
+// a consistent record behind.
+func (m *Manager) MarkPhaseDone(phase string) error {
+	m.checkpoint.CompletedPhases[phase] = time.Now().Format(time.RFC3339)
+	return m.save()
+}
+
+// RecordJoinedHost appends one successfully joined host to the checkpoint.
+func (m *Manager) RecordJoinedHost(host string) error {
+	for _, joined := range m.checkpoint.JoinedHosts {
+		if joined == host {
+			return nil
+		}
+	}
+	m.checkpoint.JoinedHosts = append(m.checkpoint.JoinedHosts, host)
+	return m.save()
+}
+
+// JoinedHosts returns the hosts recorded as joined by previous runs.
+func (m *Manager) JoinedHosts() []string {
+	return m.checkpoint.JoinedHosts
+}
+
+// RecordFailedJoinHost appends one host skipped by the join failure
+// policy to the checkpoint.
+func (m *Manager) RecordFailedJoinHost(host string) error {
+	for _, failed := range m.checkpoint.FailedJoinHosts {
+		if failed == host {
+			return nil
+		}
+	}
+	m.checkpoint.FailedJoinHosts = append(m.checkpoint.FailedJoinHosts, host)
+	return m.save()
+}
+
+// FailedJoinHosts returns the hosts skipped by the join failure policy in
+// previous runs.
+func (m *Manager) FailedJoinHosts() []string {
+	return m.checkpoint.FailedJoinHosts
+}
+
+// ClearFailedJoinHosts drops the recorded failed hosts, typically right
+// before retrying them.
+func (m *Manager) ClearFailedJoinHosts() error {
+	if len(m.checkpoint.FailedJoinHosts) == 0 {
+		return nil
+	}
+	m.checkpoint.FailedJoinHosts = nil
+	return m.save()
+}
+
+// Clear removes the checkpoint, which marks the apply as fully finished
+// (or the cluster as deleted).
+func (m *Manager) Clear() error {
+	if !osi.IsFileExist(m.path) {
+		return nil
+	}
+	return os.Remove(m.path)
+}
+
+// ClearCompletedPhases drops the phase records after a successful apply
+// while keeping the failed join hosts around for a later retry. When
+// nothing is left the checkpoint file is removed entirely.
+func (m *Manager) ClearCompletedPhases() error {
+	m.checkpoint.CompletedPhases = map[string]string{}
+	m.checkpoint.JoinedHosts = nil
+	if len(m.checkpoint.FailedJoinHosts) == 0 {
+		return m.Clear()
+	}
+	return m.save()
+}
+
+func (m *Manager) save() error {
+	if err := os.MkdirAll(filepath.Dir(m.path), 0750); err != nil {
+		return fmt.Errorf("failed to create checkpoint dir: %v", err)
+	}
+	data, err := json.MarshalIndent(m.checkpoint, "", "  ")
+	if err != nil {
+		return err
+	}
+	return osi.NewAtomicWriter(m.path).WriteFile(data)
+}
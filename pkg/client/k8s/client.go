@@ -16,11 +16,16 @@ package k8s
 
 import (
 	"context"
+	"fmt"
+	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	v12 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/tools/clientcmd"
@@ -48,6 +53,13 @@ func NewK8sClient() (*Client, error) {
 	if home := homedir.HomeDir(); home != "" {
 		kubeconfig = filepath.Join(home, ".kube", "config")
 	}
+	// a cluster context carries its own kubeconfig, so several clusters can
+	// be managed from one machine
+	if ctxKubeConfig := common.GetClusterContextKubeConfig(); ctxKubeConfig != "" {
+		if _, err := os.Stat(ctxKubeConfig); err == nil {
+			kubeconfig = ctxKubeConfig
+		}
+	}
 
 	// use the current context in kubeconfig
 	config, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
@@ -69,6 +81,10 @@ func (c *Client) ConfigMap(ns string) v12.ConfigMapInterface {
 	return c.client.CoreV1().ConfigMaps(ns)
 }
 
+func (c *Client) Secret(ns string) v12.SecretInterface {
+	return c.client.CoreV1().Secrets(ns)
+}
+
 func (c *Client) ListNodes() (*v1.NodeList, error) {
 	nodes, err := c.client.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
 	if err != nil {
@@ -140,3 +156,81 @@ func (c *Client) GetEndpointsList(namespace string) (*v1.EndpointsList, error) {
 	}
 	return endpointsList, nil
 }
+
+// CordonNode marks the node unschedulable when cordon is true and
+// schedulable again when it is false.
+func (c *Client) CordonNode(name string, cordon bool) error {
+	patch := []byte(fmt.Sprintf(`{"spec":{"unschedulable":%t}}`, cordon))
+	if _, err := c.client.CoreV1().Nodes().Patch(context.TODO(), name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return errors.Wrapf(err, "failed to set unschedulable=%t on node(%s)", cordon, name)
+	}
+	return nil
+}
+
+// DrainNode deletes the deletable pods running on the node: daemonset
+// managed pods, mirror pods and pods that already finished are kept.
+func (c *Client) DrainNode(name string) error {
+	pods, err := c.client.CoreV1().Pods(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{
+		FieldSelector: "spec.nodeName=" + name,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to list pods on node(%s)", name)
+	}
+
+	for i := range pods.Items {
+		pod := pods.Items[i]
+		if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		if _, isMirror := pod.Annotations[v1.MirrorPodAnnotationKey]; isMirror {
+			continue
+		}
+		if ownedByDaemonSet(&pod) {
+			continue
+		}
+		if err := c.client.CoreV1().Pods(pod.Namespace).Delete(context.TODO(), pod.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete pod(%s/%s) on node(%s)", pod.Namespace, pod.Name, name)
+		}
+	}
+	return nil
+}
+
+func ownedByDaemonSet(pod *v1.Pod) bool {
+	for _, owner := range pod.OwnerReferences {
+		if owner.Kind == "DaemonSet" {
+			return true
+		}
+	}
+	return false
+}
+
+// IsNodeReady reports the Ready condition of the node.
+func IsNodeReady(node v1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == v1.NodeReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// WaitNodeReady polls the node until its Ready condition holds a heartbeat
+// newer than since, so a freshly rebooted node is not mistaken for ready.
+func (c *Client) WaitNodeReady(name string, since metav1.Time, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		node, err := c.client.CoreV1().Nodes().Get(context.TODO(), name, metav1.GetOptions{})
+		if err == nil {
+			for _, condition := range node.Status.Conditions {
+				if condition.Type == v1.NodeReady && condition.Status == v1.ConditionTrue &&
+					condition.LastHeartbeatTime.After(since.Time) {
+					return nil
+				}
+			}
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for node(%s) to become ready", name)
+		}
+		time.Sleep(5 * time.Second)
+	}
+}
@@ -0,0 +1,271 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sealer is the stable Go API for embedding sealer in other
+// programs. It exposes the cluster lifecycle (create, scale up, delete)
+// and application installation without going through the CLI layer; the
+// sealer commands themselves are built on top of it.
+package sealer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/sealerio/sealer/common"
+	"github.com/sealerio/sealer/pkg/clusterfile"
+	imagev1 "github.com/sealerio/sealer/pkg/define/image/v1"
+	"github.com/sealerio/sealer/pkg/define/options"
+	"github.com/sealerio/sealer/pkg/imageengine"
+	"github.com/sealerio/sealer/pkg/progress"
+	v2 "github.com/sealerio/sealer/types/api/v2"
+	netutils "github.com/sealerio/sealer/utils/net"
+)
+
+var (
+	// ErrEmptyClusterfile is returned when an operation receives no
+	// Clusterfile content.
+	ErrEmptyClusterfile = errors.New("clusterfile content is empty")
+	// ErrClusterNotExist is returned when an operation requires an
+	// existing cluster but none is recorded on this host.
+	ErrClusterNotExist = errors.New("no existing cluster found")
+)
+
+// Options configures a Client.
+type Options struct {
+	// Reporter receives progress callbacks for long-running phases. A nil
+	// Reporter keeps the process default, which renders progress bars on
+	// a TTY and structured log events otherwise.
+	Reporter progress.Reporter
+}
+
+// Client drives sealer cluster and application operations
+// programmatically.
+type Client struct {
+	imageEngine imageengine.Interface
+}
+
+// New returns a Client ready to run cluster operations.
+func New(opts Options) (*Client, error) {
+	progress.SetDefault(opts.Reporter)
+
+	engine, err := imageengine.NewImageEngine(options.EngineGlobalConfigurations{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize image engine: %v", err)
+	}
+
+	return &Client{imageEngine: engine}, nil
+}
+
+// CreateClusterOptions tunes CreateCluster.
+type CreateClusterOptions struct {
+	// RunMode is common.ApplyModeApply to install the cluster or
+	// common.ApplyModeLoadImage to only preload images to the registry.
+	RunMode      string
+	IgnoreCache  bool
+	Distribution DistributionMethod
+	// RollbackOnFailure resets the touched hosts and restores the previous
+	// Clusterfile snapshot when the install fails or is interrupted.
+	RollbackOnFailure bool
+}
+
+// CreateCluster creates a Kubernetes cluster from the given Clusterfile
+// content. Applications declared by the image are installed as part of
+// the run.
+func (c *Client) CreateCluster(ctx context.Context, clusterfileData []byte, opts CreateClusterOptions) error {
+	if len(clusterfileData) == 0 {
+		return ErrEmptyClusterfile
+	}
+
+	cf, err := clusterfile.NewClusterFile(clusterfileData)
+	if err != nil {
+		return err
+	}
+
+	cluster := cf.GetCluster()
+	imageSpec, err := c.pullAndInspect(ctx, cluster.Spec.Image)
+	if err != nil {
+		return err
+	}
+
+	mergedWithExt := MergeClusterWithImageExtension(&cluster, imageSpec.ImageExtension)
+	cf.SetCluster(*mergedWithExt)
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	kubeInstaller, err := NewKubeInstaller(cf, c.imageEngine, imageSpec)
+	if err != nil {
+		return err
+	}
+
+	runMode := opts.RunMode
+	if runMode == "" {
+		runMode = common.ApplyModeApply
+	}
+
+	return kubeInstaller.Install(mergedWithExt.Spec.Image, KubeInstallOptions{
+		RunMode:              runMode,
+		IgnoreCache:          opts.IgnoreCache,
+		P2PDistribution:      opts.Distribution == P2PDistribution,
+		RegistryDistribution: opts.Distribution == RegistryDistribution,
+		RollbackOnFailure:    opts.RollbackOnFailure,
+	})
+}
+
+// ScaleUp joins the given masters and workers to the cluster recorded on
+// this host. The hosts must be reachable with the SSH configuration of
+// the existing cluster.
+func (c *Client) ScaleUp(ctx context.Context, newMasters, newWorkers []net.IP, opts KubeScaleUpOptions) error {
+	cf, _, err := clusterfile.GetActualClusterFile()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrClusterNotExist, err)
+	}
+
+	cluster := cf.GetCluster()
+	mastersToJoin := appendHostsForRole(&cluster, common.MASTER, newMasters)
+	workersToJoin := appendHostsForRole(&cluster, common.NODE, newWorkers)
+	if len(mastersToJoin) == 0 && len(workersToJoin) == 0 {
+		return nil
+	}
+
+	imageSpec, err := c.pullAndInspect(ctx, cluster.Spec.Image)
+	if err != nil {
+		return err
+	}
+
+	mergedWithExt := MergeClusterWithImageExtension(&cluster, imageSpec.ImageExtension)
+	cf.SetCluster(*mergedWithExt)
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	kubeInstaller, err := NewKubeInstaller(cf, c.imageEngine, imageSpec)
+	if err != nil {
+		return err
+	}
+
+	return kubeInstaller.ScaleUp(mastersToJoin, workersToJoin, opts)
+}
+
+// Delete tears down the whole cluster recorded on this host.
+func (c *Client) Delete(ctx context.Context, opts KubeDeleteOptions) error {
+	cf, _, err := clusterfile.GetActualClusterFile()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrClusterNotExist, err)
+	}
+
+	cluster := cf.GetCluster()
+	imageSpec, err := c.pullAndInspect(ctx, cluster.Spec.Image)
+	if err != nil {
+		return err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	kubeInstaller, err := NewKubeInstaller(cf, c.imageEngine, imageSpec)
+	if err != nil {
+		return err
+	}
+
+	return kubeInstaller.Delete(opts)
+}
+
+// InstallApp installs the applications of the given sealer image on the
+// cluster recorded on this host. A nil app installs the launch defaults
+// declared by the image.
+func (c *Client) InstallApp(ctx context.Context, imageName string, app *v2.Application, opts AppInstallOptions) error {
+	imageSpec, err := c.pullAndInspect(ctx, imageName)
+	if err != nil {
+		return err
+	}
+
+	appSpec := ConstructApplication(app, nil, nil, nil)
+	appInstaller, err := NewApplicationInstaller(appSpec, imageSpec.ImageExtension, c.imageEngine)
+	if err != nil {
+		return err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if opts.RunMode == "" {
+		opts.RunMode = common.ApplyModeApply
+	}
+
+	return appInstaller.Install(imageName, opts)
+}
+
+// pullAndInspect makes sure the sealer image is present locally and
+// returns its image spec.
+func (c *Client) pullAndInspect(ctx context.Context, imageName string) (*imagev1.ImageSpec, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	id, err := c.imageEngine.Pull(&options.PullOptions{
+		Quiet:      false,
+		PullPolicy: "missing",
+		Image:      imageName,
+		Platform:   "local",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	imageSpec, err := c.imageEngine.Inspect(&options.InspectOptions{ImageNameOrID: id})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sealer image extension: %s", err)
+	}
+
+	return imageSpec, nil
+}
+
+// appendHostsForRole merges the given IPs into the hosts of the role,
+// skipping the ones already present, and returns the IPs that were
+// actually added.
+func appendHostsForRole(cluster *v2.Cluster, role string, ips []net.IP) []net.IP {
+	var toJoin []net.IP
+	for _, ip := range ips {
+		if !netutils.IsInIPList(ip, cluster.GetAllIPList()) {
+			toJoin = append(toJoin, ip)
+		}
+	}
+
+	if len(toJoin) == 0 {
+		return nil
+	}
+
+	for i := range cluster.Spec.Hosts {
+		for _, r := range cluster.Spec.Hosts[i].Roles {
+			if r == role {
+				cluster.Spec.Hosts[i].IPS = append(cluster.Spec.Hosts[i].IPS, toJoin...)
+				return toJoin
+			}
+		}
+	}
+
+	cluster.Spec.Hosts = append(cluster.Spec.Hosts, v2.Host{
+		IPS:   toJoin,
+		Roles: []string{role},
+	})
+
+	return toJoin
+}
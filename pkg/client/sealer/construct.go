@@ -0,0 +1,106 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sealer
+
+import (
+	"net"
+
+	"github.com/sealerio/sealer/common"
+	imagev1 "github.com/sealerio/sealer/pkg/define/image/v1"
+	"github.com/sealerio/sealer/types/api/constants"
+	v2 "github.com/sealerio/sealer/types/api/v2"
+	"github.com/sealerio/sealer/utils/maps"
+	netutils "github.com/sealerio/sealer/utils/net"
+	strUtils "github.com/sealerio/sealer/utils/strings"
+)
+
+// ConstructApplication merge flags to v2.Application
+func ConstructApplication(app *v2.Application, cmds, appNames, globalEnvs []string) *v2.Application {
+	var newApp *v2.Application
+
+	if app != nil {
+		newApp = app
+	} else {
+		newApp = &v2.Application{
+			Spec: v2.ApplicationSpec{},
+		}
+		newApp.Name = "my-application"
+		newApp.Kind = v2.GroupVersion.String()
+		newApp.APIVersion = constants.ApplicationKind
+	}
+
+	if len(cmds) > 0 {
+		newApp.Spec.Cmds = cmds
+	}
+
+	if appNames != nil {
+		newApp.Spec.LaunchApps = appNames
+	}
+
+	// add appEnvs from flag to application object.
+	if len(globalEnvs) > 0 {
+		var appConfigList []v2.ApplicationConfig
+		for _, appConfig := range newApp.Spec.Configs {
+			appConfig.Env = append(globalEnvs, appConfig.Env...)
+			appConfigList = append(appConfigList, appConfig)
+		}
+		newApp.Spec.Configs = appConfigList
+	}
+
+	return newApp
+}
+
+// MergeClusterWithImageExtension :set default value get from image extension,such as image global env
+func MergeClusterWithImageExtension(cluster *v2.Cluster, imageExt imagev1.ImageExtension) *v2.Cluster {
+	if len(imageExt.Env) > 0 {
+		envs := maps.ConvertToSlice(imageExt.Env)
+		envs = append(envs, cluster.Spec.Env...)
+		cluster.Spec.Env = envs
+	}
+
+	return cluster
+}
+
+func ConstructClusterForScaleDown(cluster *v2.Cluster, mastersToDelete, workersToDelete []net.IP) error {
+	if len(mastersToDelete) != 0 {
+		for i := range cluster.Spec.Hosts {
+			if strUtils.IsInSlice(common.MASTER, cluster.Spec.Hosts[i].Roles) {
+				cluster.Spec.Hosts[i].IPS = netutils.RemoveIPs(cluster.Spec.Hosts[i].IPS, mastersToDelete)
+			}
+			continue
+		}
+	}
+
+	if len(workersToDelete) != 0 {
+		for i := range cluster.Spec.Hosts {
+			if strUtils.IsInSlice(common.NODE, cluster.Spec.Hosts[i].Roles) {
+				cluster.Spec.Hosts[i].IPS = netutils.RemoveIPs(cluster.Spec.Hosts[i].IPS, workersToDelete)
+			}
+			continue
+		}
+	}
+
+	// if hosts have no ip address exist,then delete this host.
+	var hosts []v2.Host
+	for _, host := range cluster.Spec.Hosts {
+		if len(host.IPS) == 0 {
+			continue
+		}
+		hosts = append(hosts, host)
+	}
+	cluster.Spec.Hosts = hosts
+
+	return nil
+}
@@ -0,0 +1,812 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sealer
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/sealerio/sealer/common"
+	"github.com/sealerio/sealer/pkg/application"
+	clusterruntime "github.com/sealerio/sealer/pkg/cluster-runtime"
+	"github.com/sealerio/sealer/pkg/clusterfile"
+	containerruntime "github.com/sealerio/sealer/pkg/container-runtime"
+	imagev1 "github.com/sealerio/sealer/pkg/define/image/v1"
+	"github.com/sealerio/sealer/pkg/env"
+	"github.com/sealerio/sealer/pkg/imagedistributor"
+	"github.com/sealerio/sealer/pkg/imageengine"
+	"github.com/sealerio/sealer/pkg/infradriver"
+	"github.com/sealerio/sealer/pkg/progress"
+	"github.com/sealerio/sealer/pkg/registry"
+	"github.com/sealerio/sealer/pkg/runtime/kubernetes"
+	v1 "github.com/sealerio/sealer/types/api/v1"
+	v2 "github.com/sealerio/sealer/types/api/v2"
+	"github.com/sealerio/sealer/utils/os/fs"
+	"github.com/sealerio/sealer/utils/platform"
+	"github.com/sealerio/sealer/utils/ssh"
+	strUtils "github.com/sealerio/sealer/utils/strings"
+	"github.com/sirupsen/logrus"
+)
+
+type AppInstaller struct {
+	cf             clusterfile.Interface
+	imageExtension imagev1.ImageExtension
+	infraDriver    infradriver.InfraDriver
+	appDriver      application.Interface
+	imageEngine    imageengine.Interface
+}
+
+type AppInstallOptions struct {
+	Envs                    []string
+	RunMode                 string
+	SkipPrepareAppMaterials bool
+	IgnoreCache             bool
+	Distribution            DistributionMethod
+}
+
+func (i AppInstaller) Install(imageName string, options AppInstallOptions) error {
+	logrus.Infof("start to install application using image: %s", imageName)
+
+	i.infraDriver.AddClusterEnv(options.Envs)
+
+	if err := env.CheckRequiredEnv(i.infraDriver.GetClusterEnv(), i.imageExtension.EnvDocs); err != nil {
+		return err
+	}
+
+	if !options.SkipPrepareAppMaterials {
+		if err := i.prepareMaterials(imageName, options.RunMode, options.IgnoreCache, options.Distribution); err != nil {
+			return err
+		}
+	}
+	if options.RunMode == common.ApplyModeLoadImage {
+		return nil
+	}
+
+	reporter := progress.Default()
+	reporter.PhaseStarted(progress.PhaseLaunchApps, 0)
+	if err := i.appDriver.Launch(i.infraDriver); err != nil {
+		reporter.PhaseFinished(progress.PhaseLaunchApps, err)
+		return err
+	}
+	reporter.PhaseFinished(progress.PhaseLaunchApps, nil)
+	if err := i.appDriver.Save(application.SaveOptions{ImageName: imageName}); err != nil {
+		return err
+	}
+
+	//save and commit
+	i.cf.SetApplication(i.appDriver.GetApplication())
+	confPath := clusterruntime.GetClusterConfPath(i.imageExtension.Labels)
+	if err := i.cf.SaveAll(clusterfile.SaveOptions{CommitToCluster: true, ConfPath: confPath}); err != nil {
+		return err
+	}
+
+	logrus.Infof("succeeded in installing application with image %s", imageName)
+
+	return nil
+}
+
+func (i AppInstaller) prepareMaterials(appImageName string, mode string, ignoreCache bool, distribution DistributionMethod) error {
+	clusterHosts := i.infraDriver.GetHostIPList()
+	clusterHostsPlatform, err := i.infraDriver.GetHostsPlatform(clusterHosts)
+	if err != nil {
+		return err
+	}
+
+	imageMounter, err := imagedistributor.NewImageMounter(i.imageEngine, clusterHostsPlatform)
+	if err != nil {
+		return err
+	}
+
+	imageMountInfo, err := imageMounter.Mount(appImageName)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		err = imageMounter.Umount(appImageName, imageMountInfo)
+		if err != nil {
+			logrus.Errorf("failed to umount sealer image: %v", err)
+		}
+	}()
+
+	for _, info := range imageMountInfo {
+		err = i.appDriver.FileProcess(info.MountDir)
+		if err != nil {
+			return errors.Wrapf(err, "failed to execute file processor")
+		}
+	}
+
+	var distributor imagedistributor.Distributor
+	if distribution == P2PDistribution {
+		distributor, err = imagedistributor.NewP2PDistributor(imageMountInfo, i.infraDriver, nil, imagedistributor.DistributeOption{
+			IgnoreCache: ignoreCache,
+		})
+		if err != nil {
+			logrus.Warnf("failed to initialize P2P-based distributor: %s", err)
+		}
+	} else if distribution == RegistryDistribution {
+		distributor, err = imagedistributor.NewRegistryDistributor(imageMountInfo, i.infraDriver, nil, imagedistributor.DistributeOption{
+			IgnoreCache: ignoreCache,
+		})
+		if err != nil {
+			return err
+		}
+	} else {
+		distributor, err = imagedistributor.NewScpDistributor(imageMountInfo, i.infraDriver, nil, imagedistributor.DistributeOption{
+			IgnoreCache: ignoreCache,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if mode == common.ApplyModeLoadImage {
+		return loadToRegistry(i.infraDriver, distributor)
+	}
+
+	masters := i.infraDriver.GetHostIPListByRole(common.MASTER)
+	regConfig := i.infraDriver.GetClusterRegistry()
+	// distribute rootfs
+
+	if err := distributor.Distribute(masters, i.infraDriver.GetClusterRootfsPath()); err != nil {
+		return err
+	}
+
+	//if we use local registry service, load container image to registry
+	if regConfig.LocalRegistry == nil {
+		return nil
+	}
+	deployHosts := masters
+	if !*regConfig.LocalRegistry.HA {
+		deployHosts = []net.IP{masters[0]}
+	}
+
+	registryConfigurator, err := registry.NewConfigurator(deployHosts,
+		containerruntime.Info{},
+		regConfig, i.infraDriver, distributor)
+	if err != nil {
+		return err
+	}
+
+	registryDriver, err := registryConfigurator.GetDriver()
+	if err != nil {
+		return err
+	}
+
+	err = registryDriver.UploadContainerImages2Registry()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func NewApplicationInstaller(appSpec *v2.Application, extension imagev1.ImageExtension, imageEngine imageengine.Interface) (*AppInstaller, error) {
+	v2App, err := application.NewAppDriver(appSpec, extension)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse application:%v ", err)
+	}
+
+	cf, _, err := clusterfile.GetActualClusterFile()
+	if err != nil {
+		return nil, err
+	}
+
+	cluster := cf.GetCluster()
+	infraDriver, err := infradriver.NewInfraDriver(&cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	// secrets are resolved at apply time only; the resolved values live in
+	// the driver env and never reach the persisted Clusterfile
+	secretEnvs, err := clusterfile.ResolveSecrets(cf.GetSecrets())
+	if err != nil {
+		return nil, err
+	}
+	infraDriver.AddClusterEnv(secretEnvs)
+
+	return &AppInstaller{
+		cf:             cf,
+		imageExtension: extension,
+		appDriver:      v2App,
+		infraDriver:    infraDriver,
+		imageEngine:    imageEngine,
+	}, nil
+}
+
+type KubeInstaller struct {
+	cf          clusterfile.Interface
+	infraDriver infradriver.InfraDriver
+	imageEngine imageengine.Interface
+	imageSpec   *imagev1.ImageSpec
+}
+
+type KubeInstallOptions struct {
+	RunMode         string
+	IgnoreCache     bool
+	P2PDistribution bool
+	// RegistryDistribution lets the hosts pull the rootfs from the local
+	// registry instead of receiving it over scp; it degrades to scp when
+	// the registry is not serving yet.
+	RegistryDistribution bool
+	// RollbackOnFailure resets the touched hosts and restores the previous
+	// Clusterfile snapshot when the install fails or is interrupted.
+	RollbackOnFailure bool
+}
+
+type KubeScaleUpOptions struct {
+	IgnoreCache bool
+	// RegistryDistribution lets the joining hosts pull the rootfs from the
+	// already running local registry instead of receiving it over scp.
+	RegistryDistribution bool
+}
+
+type KubeScaleDownOptions struct {
+	Prune bool
+}
+
+type KubeDeleteOptions struct {
+	Prune bool
+}
+
+func (k KubeInstaller) Install(kubeImageName string, options KubeInstallOptions) error {
+	var (
+		// cluster parameters
+		cluster      = k.cf.GetCluster()
+		clusterHosts = k.infraDriver.GetHostIPList()
+
+		pluginsFromFile       = k.cf.GetPlugins()
+		configsFromFile       = k.cf.GetConfigs()
+		kubeadmConfigFromFile = k.cf.GetKubeadmConfig()
+
+		// app parameters
+		cmds     = k.infraDriver.GetClusterLaunchCmds()
+		appNames = k.infraDriver.GetClusterLaunchApps()
+	)
+
+	logrus.Infof("start to create new cluster with image: %s", kubeImageName)
+	logrus.Debugf("will create a new cluster using: %+v\n", cluster)
+
+	if err := clusterruntime.CheckSealerVersionCompat(k.imageSpec.ImageExtension.Labels); err != nil {
+		return err
+	}
+
+	clusterHostsPlatform, err := k.infraDriver.GetHostsPlatform(clusterHosts)
+	if err != nil {
+		return err
+	}
+
+	imageMounter, err := imagedistributor.NewImageMounter(k.imageEngine, clusterHostsPlatform)
+	if err != nil {
+		return err
+	}
+
+	imageMountInfo, err := imageMounter.Mount(kubeImageName)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		err = imageMounter.Umount(kubeImageName, imageMountInfo)
+		if err != nil {
+			logrus.Errorf("failed to umount sealer image")
+		}
+	}()
+
+	// new merge image extension with app
+	v2App, err := application.NewAppDriver(ConstructApplication(k.cf.GetApplication(), cmds, appNames, cluster.Spec.Env), k.imageSpec.ImageExtension)
+	if err != nil {
+		return fmt.Errorf("failed to parse application from Clusterfile:%v ", err)
+	}
+
+	// process app files
+	for _, info := range imageMountInfo {
+		err = v2App.FileProcess(info.MountDir)
+		if err != nil {
+			return errors.Wrapf(err, "failed to execute file processor")
+		}
+	}
+
+	// best effort analysis pass: catch env typos and unset required vars
+	// before anything is distributed to the hosts
+	referencedVars := make(map[string]bool)
+	for _, info := range imageMountInfo {
+		vars, err := env.CollectReferencedVars(info.MountDir)
+		if err != nil {
+			logrus.Debugf("failed to scan image templates for env lint: %v", err)
+			continue
+		}
+		for name := range vars {
+			referencedVars[name] = true
+		}
+	}
+	providedEnv := strUtils.ConvertStringSliceToMap(cluster.Spec.Env)
+	for _, warning := range env.LintEnvUsage(providedEnv, k.imageSpec.ImageExtension.Env, referencedVars) {
+		logrus.Warn(warning)
+	}
+	if err := env.CheckRequiredEnv(providedEnv, k.imageSpec.ImageExtension.EnvDocs); err != nil {
+		return err
+	}
+
+	var distributor imagedistributor.Distributor
+	if options.P2PDistribution {
+		distributor, err = imagedistributor.NewP2PDistributor(imageMountInfo, k.infraDriver, configsFromFile, imagedistributor.DistributeOption{
+			IgnoreCache: options.IgnoreCache,
+		})
+		if err != nil {
+			logrus.Warnf("failed to initialize P2P-based distributor: %s", err)
+		}
+	} else if options.RegistryDistribution {
+		distributor, err = imagedistributor.NewRegistryDistributor(imageMountInfo, k.infraDriver, configsFromFile, imagedistributor.DistributeOption{
+			IgnoreCache: options.IgnoreCache,
+		})
+		if err != nil {
+			return err
+		}
+	} else {
+		distributor, err = imagedistributor.NewScpDistributor(imageMountInfo, k.infraDriver, configsFromFile, imagedistributor.DistributeOption{
+			IgnoreCache: options.IgnoreCache,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if options.RunMode == common.ApplyModeLoadImage {
+		return clusterruntime.LoadToRegistry(k.infraDriver, distributor)
+	}
+
+	plugins, err := LoadPluginsFromImage(imageMountInfo)
+	if err != nil {
+		return err
+	}
+
+	if pluginsFromFile != nil {
+		plugins = append(plugins, pluginsFromFile...)
+	}
+
+	runtimeConfig := &clusterruntime.RuntimeConfig{
+		Distributor:            distributor,
+		Plugins:                plugins,
+		ContainerRuntimeConfig: cluster.Spec.ContainerRuntime,
+		ClusterUsers:           k.cf.GetUsers(),
+	}
+
+	if kubeadmConfigFromFile != nil {
+		runtimeConfig.KubeadmConfig = *kubeadmConfigFromFile
+	}
+
+	installer, err := clusterruntime.NewInstaller(k.infraDriver, *runtimeConfig, clusterruntime.GetClusterInstallInfo(k.imageSpec.ImageExtension.Labels, cluster.Spec.ContainerRuntime))
+	if err != nil {
+		return err
+	}
+
+	// keep the previous Clusterfile around before it is overwritten, the
+	// rollback path restores it
+	if options.RollbackOnFailure {
+		if err = clusterfile.CommitSnapshot(); err != nil {
+			return err
+		}
+	}
+
+	//we need to save desired clusterfile to local disk temporarily
+	//and will use it later to clean the cluster node if apply failed.
+	if err = k.cf.SaveAll(clusterfile.SaveOptions{}); err != nil {
+		return err
+	}
+
+	// install cluster
+	err = installer.Install()
+	if err != nil {
+		if options.RollbackOnFailure {
+			k.rollback(installer, err)
+		}
+		return err
+	}
+
+	// when a cluster context is active, fetch the kubeconfig from master0,
+	// so several clusters can be managed from this machine
+	if kubeConfig := common.GetClusterContextKubeConfig(); kubeConfig != "" {
+		master0 := k.infraDriver.GetHostIPListByRole(common.MASTER)[0]
+		if err := k.infraDriver.CopyR(master0, kubernetes.AdminKubeConfPath, kubeConfig); err != nil {
+			logrus.Warnf("failed to fetch kubeconfig of cluster context %s: %v", common.GetCurrentClusterContext(), err)
+		}
+	}
+
+	// install application
+	reporter := progress.Default()
+	reporter.PhaseStarted(progress.PhaseLaunchApps, 0)
+	if err = v2App.Launch(k.infraDriver); err != nil {
+		reporter.PhaseFinished(progress.PhaseLaunchApps, err)
+		return err
+	}
+	reporter.PhaseFinished(progress.PhaseLaunchApps, nil)
+	if err = v2App.Save(application.SaveOptions{ImageName: kubeImageName}); err != nil {
+		return err
+	}
+
+	//save and commit
+	confPath := clusterruntime.GetClusterConfPath(k.imageSpec.ImageExtension.Labels)
+	if err = k.cf.SaveAll(clusterfile.SaveOptions{CommitToCluster: true, ConfPath: confPath}); err != nil {
+		return err
+	}
+
+	logrus.Infof("succeeded in creating new cluster with image %s", kubeImageName)
+
+	return nil
+}
+
+// rollback resets the hosts touched by a failed or interrupted install
+// (kubeadm reset, remove the distributed rootfs) and restores the previous
+// Clusterfile snapshot. Rollback failures are logged, the install error
+// stays the one reported to the caller.
+func (k KubeInstaller) rollback(installer *clusterruntime.Installer, installErr error) {
+	logrus.Warnf("install failed, rolling back the touched hosts: %v", installErr)
+
+	// the install may have been stopped by Ctrl-C or the global timeout,
+	// in which case the process context is already canceled; give the
+	// cleanup commands a fresh one or they would be refused as well
+	ssh.SetProcessContext(context.Background())
+
+	if err := installer.UnInstall(); err != nil {
+		logrus.Errorf("rollback failed, the hosts may need manual cleaning via sealer delete: %v", err)
+		return
+	}
+
+	if err := clusterfile.RollBackClusterFile(); err != nil {
+		logrus.Errorf("failed to restore the previous Clusterfile snapshot: %v", err)
+		return
+	}
+
+	logrus.Info("rollback completed: hosts were reset and the previous Clusterfile was restored")
+}
+
+func (k KubeInstaller) ScaleUp(scaleUpMasterIPList, scaleUpNodeIPList []net.IP, options KubeScaleUpOptions) error {
+	logrus.Infof("start to scale up cluster")
+
+	var (
+		newHosts              = append(scaleUpMasterIPList, scaleUpNodeIPList...)
+		clusterImageName      = k.infraDriver.GetClusterImageName()
+		cluster               = k.cf.GetCluster()
+		pluginsFromFile       = k.cf.GetPlugins()
+		configsFromFile       = k.cf.GetConfigs()
+		kubeadmConfigFromFile = k.cf.GetKubeadmConfig()
+	)
+
+	if err := clusterruntime.CheckSealerVersionCompat(k.imageSpec.ImageExtension.Labels); err != nil {
+		return err
+	}
+	if err := clusterruntime.CheckKubeVersionSkew(k.imageSpec.ImageExtension.Labels,
+		clusterruntime.GetClusterKubeVersion(k.infraDriver), clusterruntime.CompatOperationScaleUp); err != nil {
+		return err
+	}
+
+	clusterHostsPlatform, err := k.infraDriver.GetHostsPlatform(newHosts)
+	if err != nil {
+		return err
+	}
+
+	imageMounter, err := imagedistributor.NewImageMounter(k.imageEngine, clusterHostsPlatform)
+	if err != nil {
+		return err
+	}
+
+	imageMountInfo, err := imageMounter.Mount(clusterImageName)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = imageMounter.Umount(clusterImageName, imageMountInfo)
+		if err != nil {
+			logrus.Errorf("failed to umount sealer image")
+		}
+	}()
+
+	var distributor imagedistributor.Distributor
+	if options.RegistryDistribution {
+		distributor, err = imagedistributor.NewRegistryDistributor(imageMountInfo, k.infraDriver, configsFromFile, imagedistributor.DistributeOption{
+			IgnoreCache: options.IgnoreCache,
+		})
+	} else {
+		distributor, err = imagedistributor.NewScpDistributor(imageMountInfo, k.infraDriver, configsFromFile, imagedistributor.DistributeOption{
+			IgnoreCache: options.IgnoreCache,
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	plugins, err := LoadPluginsFromImage(imageMountInfo)
+	if err != nil {
+		return err
+	}
+
+	if pluginsFromFile != nil {
+		plugins = append(plugins, pluginsFromFile...)
+	}
+
+	runtimeConfig := &clusterruntime.RuntimeConfig{
+		Distributor:            distributor,
+		Plugins:                plugins,
+		ContainerRuntimeConfig: cluster.Spec.ContainerRuntime,
+	}
+
+	if kubeadmConfigFromFile != nil {
+		runtimeConfig.KubeadmConfig = *kubeadmConfigFromFile
+	}
+
+	installer, err := clusterruntime.NewInstaller(k.infraDriver, *runtimeConfig,
+		clusterruntime.GetClusterInstallInfo(k.imageSpec.ImageExtension.Labels, runtimeConfig.ContainerRuntimeConfig))
+	if err != nil {
+		return err
+	}
+
+	//we need to save desired clusterfile to local disk temporarily.
+	//and will use it later to clean the cluster node if ScaleUp failed.
+	if err = k.cf.SaveAll(clusterfile.SaveOptions{}); err != nil {
+		return err
+	}
+
+	_, _, err = installer.ScaleUp(scaleUpMasterIPList, scaleUpNodeIPList)
+	if err != nil {
+		return err
+	}
+
+	confPath := clusterruntime.GetClusterConfPath(k.imageSpec.ImageExtension.Labels)
+	if err = k.cf.SaveAll(clusterfile.SaveOptions{CommitToCluster: true, ConfPath: confPath}); err != nil {
+		return err
+	}
+
+	logrus.Infof("succeeded in scaling up cluster")
+
+	return nil
+}
+
+func (k KubeInstaller) ScaleDown(deleteMasterIPList, deleteNodeIPList []net.IP, options KubeScaleDownOptions) error {
+	logrus.Infof("start to scale down cluster")
+
+	var (
+		newHosts              = append(deleteMasterIPList, deleteMasterIPList...)
+		clusterImageName      = k.infraDriver.GetClusterImageName()
+		cluster               = k.cf.GetCluster()
+		pluginsFromFile       = k.cf.GetPlugins()
+		kubeadmConfigFromFile = k.cf.GetKubeadmConfig()
+		runtimeConfig         = &clusterruntime.RuntimeConfig{
+			ContainerRuntimeConfig: cluster.Spec.ContainerRuntime,
+		}
+	)
+
+	clusterHostsPlatform, err := k.infraDriver.GetHostsPlatform(newHosts)
+	if err != nil {
+		logrus.Warn("failed to get hosts platform for deleting node, we will skip reset work on it in next steps")
+	} else {
+		imageMounter, err := imagedistributor.NewImageMounter(k.imageEngine, clusterHostsPlatform)
+		if err != nil {
+			return err
+		}
+
+		imageMountInfo, err := imageMounter.Mount(clusterImageName)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			err = imageMounter.Umount(clusterImageName, imageMountInfo)
+			if err != nil {
+				logrus.Errorf("failed to umount sealer image: %v", err)
+			}
+		}()
+
+		distributor, err := imagedistributor.NewScpDistributor(imageMountInfo, k.infraDriver, nil, imagedistributor.DistributeOption{
+			Prune: options.Prune,
+		})
+		if err != nil {
+			return err
+		}
+		runtimeConfig.Distributor = distributor
+
+		plugins, err := LoadPluginsFromImage(imageMountInfo)
+		if err != nil {
+			return err
+		}
+
+		if pluginsFromFile != nil {
+			plugins = append(plugins, pluginsFromFile...)
+		}
+		runtimeConfig.Plugins = plugins
+	}
+
+	if kubeadmConfigFromFile != nil {
+		runtimeConfig.KubeadmConfig = *kubeadmConfigFromFile
+	}
+
+	installer, err := clusterruntime.NewInstaller(k.infraDriver, *runtimeConfig,
+		clusterruntime.GetClusterInstallInfo(k.imageSpec.ImageExtension.Labels, cluster.Spec.ContainerRuntime))
+	if err != nil {
+		return err
+	}
+
+	_, _, err = installer.ScaleDown(deleteMasterIPList, deleteNodeIPList)
+	if err != nil {
+		return err
+	}
+
+	if err = ConstructClusterForScaleDown(&cluster, deleteMasterIPList, deleteNodeIPList); err != nil {
+		return err
+	}
+	k.cf.SetCluster(cluster)
+
+	confPath := clusterruntime.GetClusterConfPath(k.imageSpec.ImageExtension.Labels)
+	if err = k.cf.SaveAll(clusterfile.SaveOptions{CommitToCluster: true, ConfPath: confPath}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (k KubeInstaller) Delete(options KubeDeleteOptions) error {
+	logrus.Infof("start to delete cluster")
+
+	var (
+		clusterImageName      = k.infraDriver.GetClusterImageName()
+		cluster               = k.cf.GetCluster()
+		pluginsFromFile       = k.cf.GetPlugins()
+		kubeadmConfigFromFile = k.cf.GetKubeadmConfig()
+	)
+
+	clusterHostsPlatform, err := k.infraDriver.GetHostsPlatform(k.infraDriver.GetHostIPList())
+	if err != nil {
+		return err
+	}
+
+	imageMounter, err := imagedistributor.NewImageMounter(k.imageEngine, clusterHostsPlatform)
+	if err != nil {
+		return err
+	}
+
+	imageMountInfo, err := imageMounter.Mount(clusterImageName)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = imageMounter.Umount(clusterImageName, imageMountInfo)
+		if err != nil {
+			logrus.Errorf("failed to umount sealer image: %v", err)
+		}
+	}()
+
+	distributor, err := imagedistributor.NewScpDistributor(imageMountInfo, k.infraDriver, nil, imagedistributor.DistributeOption{
+		Prune: options.Prune,
+	})
+	if err != nil {
+		return err
+	}
+
+	plugins, err := LoadPluginsFromImage(imageMountInfo)
+	if err != nil {
+		return err
+	}
+
+	if pluginsFromFile != nil {
+		plugins = append(plugins, pluginsFromFile...)
+	}
+
+	runtimeConfig := &clusterruntime.RuntimeConfig{
+		Distributor:            distributor,
+		Plugins:                plugins,
+		ContainerRuntimeConfig: cluster.Spec.ContainerRuntime,
+	}
+
+	if kubeadmConfigFromFile != nil {
+		runtimeConfig.KubeadmConfig = *kubeadmConfigFromFile
+	}
+
+	installer, err := clusterruntime.NewInstaller(k.infraDriver, *runtimeConfig,
+		clusterruntime.GetClusterInstallInfo(k.imageSpec.ImageExtension.Labels, cluster.Spec.ContainerRuntime))
+	if err != nil {
+		return err
+	}
+
+	if err = installer.UnInstall(); err != nil {
+		return err
+	}
+	//delete local files,including clusterfile, application.json under sealer work dir
+	if err = os.Remove(common.GetDefaultClusterfile()); err != nil {
+		return err
+	}
+
+	if err = os.Remove(common.GetDefaultApplicationFile()); err != nil {
+		return err
+	}
+
+	//delete kubeconfig under home dir.
+	if err = fs.FS.RemoveAll(common.DefaultKubeConfigDir()); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func NewKubeInstaller(cf clusterfile.Interface, imageEngine imageengine.Interface, imageSpec *imagev1.ImageSpec) (*KubeInstaller, error) {
+	cluster := cf.GetCluster()
+
+	// merge image extension with cluster
+	mergedWithExt := MergeClusterWithImageExtension(&cluster, imageSpec.ImageExtension)
+
+	cf.SetCluster(*mergedWithExt)
+
+	infraDriver, err := infradriver.NewInfraDriver(mergedWithExt)
+	if err != nil {
+		return nil, err
+	}
+
+	secretEnvs, err := clusterfile.ResolveSecrets(cf.GetSecrets())
+	if err != nil {
+		return nil, err
+	}
+	infraDriver.AddClusterEnv(secretEnvs)
+
+	return &KubeInstaller{
+		imageEngine: imageEngine,
+		imageSpec:   imageSpec,
+		infraDriver: infraDriver,
+		cf:          cf,
+	}, nil
+}
+
+func LoadPluginsFromImage(imageMountInfo []imagedistributor.ClusterImageMountInfo) (plugins []v1.Plugin, err error) {
+	for _, info := range imageMountInfo {
+		defaultPlatform := platform.GetDefaultPlatform()
+		if info.Platform.ToString() == defaultPlatform.ToString() {
+			plugins, err = clusterruntime.LoadPluginsFromFile(filepath.Join(info.MountDir, "plugins"))
+			if err != nil {
+				return
+			}
+		}
+	}
+
+	return plugins, nil
+}
+
+// loadToRegistry just load container image to local registry
+func loadToRegistry(infraDriver infradriver.InfraDriver, distributor imagedistributor.Distributor) error {
+	regConfig := infraDriver.GetClusterRegistry()
+	// todo only support load image to local registry at present
+	if regConfig.LocalRegistry == nil {
+		return nil
+	}
+
+	deployHosts := infraDriver.GetHostIPListByRole(common.MASTER)
+	if len(deployHosts) < 1 {
+		return fmt.Errorf("local registry host can not be nil")
+	}
+	master0 := deployHosts[0]
+
+	logrus.Infof("start to apply with mode(%s)", common.ApplyModeLoadImage)
+	if !*regConfig.LocalRegistry.HA {
+		deployHosts = []net.IP{master0}
+	}
+
+	if err := distributor.DistributeRegistry(deployHosts, filepath.Join(infraDriver.GetClusterRootfsPath(), "registry")); err != nil {
+		return err
+	}
+
+	logrus.Infof("load image success")
+	return nil
+}
@@ -0,0 +1,30 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sealer
+
+// DistributionMethod selects how the sealer image content reaches the
+// cluster hosts.
+type DistributionMethod uint64
+
+const (
+	// SFTPDistribution copies the image content to every host over scp.
+	SFTPDistribution DistributionMethod = iota
+	// P2PDistribution spreads the image content peer to peer.
+	P2PDistribution
+	// RegistryDistribution publishes the rootfs to the local registry once
+	// and lets every host pull it from there; hosts fall back to scp when
+	// the registry is not serving yet.
+	RegistryDistribution
+)
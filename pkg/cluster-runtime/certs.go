@@ -0,0 +1,115 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterruntime
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// node side trust store locations for the two big distro families; the CA is
+// dropped into both, the update command below refreshes whichever exists.
+const (
+	debianCATrustDir = "/usr/local/share/ca-certificates"
+	redhatCATrustDir = "/etc/pki/ca-trust/source/anchors"
+)
+
+// updateCATrustCmd refreshes the system trust store with whichever tool the
+// distro ships.
+const updateCATrustCmd = `if command -v update-ca-certificates >/dev/null 2>&1; then update-ca-certificates; elif command -v update-ca-trust >/dev/null 2>&1; then update-ca-trust extract; else echo "no tool found to update the CA trust store"; exit 1; fi`
+
+// distributeExtraCAs installs the CA certificates from spec.certs.extraCAs
+// into the system trust store of every host and restarts the container
+// runtime, so pulls from private registries signed by a corporate CA work.
+// A CA file named after a registry domain is also placed into the runtime's
+// certs.d directory for exactly that registry.
+func (i *Installer) distributeExtraCAs(hosts []net.IP) error {
+	cas := i.infraDriver.GetClusterCerts().ExtraCAs
+	if len(cas) == 0 {
+		return nil
+	}
+
+	info, err := i.containerRuntimeInstaller.GetInfo()
+	if err != nil {
+		return err
+	}
+
+	for _, caFile := range cas {
+		if err := validateCAFile(caFile); err != nil {
+			return err
+		}
+	}
+
+	err = i.infraDriver.Execute(hosts, func(host net.IP) error {
+		for _, caFile := range cas {
+			name := trustStoreName(caFile)
+			for _, dest := range []string{
+				filepath.Join(debianCATrustDir, name),
+				filepath.Join(redhatCATrustDir, name),
+				// e.g. /etc/docker/certs.d/harbor.corp.com/ca.crt
+				filepath.Join(info.CertsDir, strings.TrimSuffix(name, ".crt"), "ca.crt"),
+			} {
+				if err := i.infraDriver.Copy(host, caFile, dest); err != nil {
+					return fmt.Errorf("failed to copy CA %s to host (%s): %v", caFile, host, err)
+				}
+			}
+		}
+
+		restartCmd := fmt.Sprintf("systemctl restart %s", info.Type)
+		if err := i.infraDriver.CmdAsync(host, nil, updateCATrustCmd, restartCmd); err != nil {
+			return fmt.Errorf("failed to update CA trust store on host (%s): %v", host, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateCAFile rejects files that are not a PEM encoded certificate before
+// anything is shipped to the nodes.
+func validateCAFile(caFile string) error {
+	content, err := os.ReadFile(filepath.Clean(caFile))
+	if err != nil {
+		return fmt.Errorf("failed to read extra CA %s: %v", caFile, err)
+	}
+
+	block, _ := pem.Decode(content)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return fmt.Errorf("extra CA %s is not a PEM encoded certificate", caFile)
+	}
+	if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+		return fmt.Errorf("extra CA %s does not contain a valid certificate: %v", caFile, err)
+	}
+	return nil
+}
+
+// trustStoreName returns the file name the CA is installed under;
+// update-ca-certificates only picks up files with a .crt suffix.
+func trustStoreName(caFile string) string {
+	name := filepath.Base(caFile)
+	ext := filepath.Ext(name)
+	if ext == ".crt" {
+		return name
+	}
+	return strings.TrimSuffix(name, ext) + ".crt"
+}
@@ -0,0 +1,141 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterruntime
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/sirupsen/logrus"
+
+	"github.com/sealerio/sealer/common"
+	"github.com/sealerio/sealer/pkg/infradriver"
+	"github.com/sealerio/sealer/version"
+)
+
+const (
+	// SupportedSealerVersionsLabel lets an image declare the sealer versions
+	// it was built for, as a semver constraint like ">=0.9.0 <2.0.0"; sealer
+	// refuses to apply an image whose constraint it does not satisfy.
+	SupportedSealerVersionsLabel = "cluster.alpha.sealer.io/supported-sealer-versions"
+	// KubeVersionLabel declares the kubernetes version the image installs;
+	// scale and upgrade validate the skew against the running cluster.
+	KubeVersionLabel = "cluster.alpha.sealer.io/kubernetes-version"
+
+	// clusterKubeVersionCmd prints the apiserver version of the running
+	// cluster, empty when kubectl cannot reach it.
+	clusterKubeVersionCmd = `kubectl version 2>/dev/null | awk '/Server Version/{print $3; exit}'`
+)
+
+// Compat operations with different skew rules.
+const (
+	CompatOperationScaleUp = "scale-up"
+	CompatOperationUpgrade = "upgrade"
+)
+
+// CheckSealerVersionCompat refuses images declaring a sealer version
+// constraint the running sealer does not satisfy. Images without the label
+// and dev builds without a parseable version pass with at most a warning.
+func CheckSealerVersionCompat(imageLabels map[string]string) error {
+	return checkSealerVersionCompat(version.Get().GitVersion, imageLabels)
+}
+
+func checkSealerVersionCompat(sealerVersion string, imageLabels map[string]string) error {
+	constraintStr := imageLabels[SupportedSealerVersionsLabel]
+	if constraintStr == "" {
+		return nil
+	}
+
+	constraint, err := semver.NewConstraint(constraintStr)
+	if err != nil {
+		return fmt.Errorf("image declares an invalid %s label(%s): %v", SupportedSealerVersionsLabel, constraintStr, err)
+	}
+
+	current, err := semver.NewVersion(strings.TrimPrefix(sealerVersion, "v"))
+	if err != nil {
+		logrus.Warnf("cannot parse the running sealer version %q, skipping the image compatibility check against %q", sealerVersion, constraintStr)
+		return nil
+	}
+
+	if !constraint.Check(current) {
+		return fmt.Errorf("sealer %s does not satisfy the versions the image supports(%s), use a matching sealer release or rebuild the image", sealerVersion, constraintStr)
+	}
+	return nil
+}
+
+// GetClusterKubeVersion best-effort reads the apiserver version of the
+// running cluster from master0, "" when it cannot be determined.
+func GetClusterKubeVersion(infraDriver infradriver.InfraDriver) string {
+	masters := infraDriver.GetHostIPListByRole(common.MASTER)
+	if len(masters) == 0 {
+		return ""
+	}
+	out, err := infraDriver.CmdToString(masters[0], nil, clusterKubeVersionCmd, "")
+	if err != nil {
+		logrus.Debugf("failed to read the cluster kubernetes version: %v", err)
+		return ""
+	}
+	return strings.TrimSpace(out)
+}
+
+// CheckKubeVersionSkew validates the kubernetes version of the image against
+// the running cluster. Scaling refuses images newer than the cluster (a new
+// kubelet must not join an old control plane) and more than two minors old;
+// upgrades refuse downgrades and jumps over more than one minor, following
+// the kubeadm upgrade policy. Unknown versions on either side skip the check.
+func CheckKubeVersionSkew(imageLabels map[string]string, clusterVersion, operation string) error {
+	imageVersionStr := imageLabels[KubeVersionLabel]
+	if imageVersionStr == "" || clusterVersion == "" {
+		return nil
+	}
+
+	imageVersion, err := semver.NewVersion(strings.TrimPrefix(imageVersionStr, "v"))
+	if err != nil {
+		logrus.Warnf("image declares an unparseable %s label(%s), skipping the skew check", KubeVersionLabel, imageVersionStr)
+		return nil
+	}
+	current, err := semver.NewVersion(strings.TrimPrefix(clusterVersion, "v"))
+	if err != nil {
+		logrus.Warnf("cannot parse the cluster kubernetes version %q, skipping the skew check", clusterVersion)
+		return nil
+	}
+
+	imageMinor := int(imageVersion.Major())*1000 + int(imageVersion.Minor())
+	clusterMinor := int(current.Major())*1000 + int(current.Minor())
+
+	switch operation {
+	case CompatOperationScaleUp:
+		if imageMinor > clusterMinor {
+			return fmt.Errorf("image kubernetes %s is newer than the cluster(%s): kubelets must not be newer than the control plane, upgrade the cluster first", imageVersionStr, clusterVersion)
+		}
+		if clusterMinor-imageMinor > 2 {
+			return fmt.Errorf("image kubernetes %s is more than two minor versions behind the cluster(%s), outside the supported skew", imageVersionStr, clusterVersion)
+		}
+		if imageMinor != clusterMinor {
+			logrus.Warnf("image kubernetes %s is behind the cluster(%s), the joined nodes will run an older kubelet", imageVersionStr, clusterVersion)
+		}
+	case CompatOperationUpgrade:
+		if imageMinor < clusterMinor || (imageMinor == clusterMinor && imageVersion.LessThan(current)) {
+			return fmt.Errorf("image kubernetes %s is older than the cluster(%s), downgrades are not supported", imageVersionStr, clusterVersion)
+		}
+		if imageMinor-clusterMinor > 1 {
+			return fmt.Errorf("image kubernetes %s skips more than one minor version from the cluster(%s), upgrade one minor at a time", imageVersionStr, clusterVersion)
+		}
+	default:
+		return fmt.Errorf("unknown compat operation %q", operation)
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterruntime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckSealerVersionCompat(t *testing.T) {
+	tests := []struct {
+		name          string
+		sealerVersion string
+		labels        map[string]string
+		wantErr       bool
+	}{
+		{
+			name:          "image without the label passes",
+			sealerVersion: "v0.9.0",
+			labels:        nil,
+		},
+		{
+			name:          "sealer inside the declared range passes",
+			sealerVersion: "v0.9.3",
+			labels:        map[string]string{SupportedSealerVersionsLabel: ">=0.9.0 <2.0.0"},
+		},
+		{
+			name:          "sealer outside the declared range is refused",
+			sealerVersion: "v0.8.6",
+			labels:        map[string]string{SupportedSealerVersionsLabel: ">=0.9.0 <2.0.0"},
+			wantErr:       true,
+		},
+		{
+			name:          "invalid constraint is refused",
+			sealerVersion: "v0.9.0",
+			labels:        map[string]string{SupportedSealerVersionsLabel: "not-a-constraint"},
+			wantErr:       true,
+		},
+		{
+			name:          "dev build without a parseable version passes",
+			sealerVersion: "unknown",
+			labels:        map[string]string{SupportedSealerVersionsLabel: ">=0.9.0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkSealerVersionCompat(tt.sealerVersion, tt.labels)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCheckKubeVersionSkew(t *testing.T) {
+	tests := []struct {
+		name           string
+		imageVersion   string
+		clusterVersion string
+		operation      string
+		wantErr        bool
+	}{
+		{
+			name:           "image without the label passes",
+			imageVersion:   "",
+			clusterVersion: "v1.19.8",
+			operation:      CompatOperationScaleUp,
+		},
+		{
+			name:           "unknown cluster version skips the check",
+			imageVersion:   "v1.22.15",
+			clusterVersion: "",
+			operation:      CompatOperationScaleUp,
+		},
+		{
+			name:           "scale-up with the matching version passes",
+			imageVersion:   "v1.22.15",
+			clusterVersion: "v1.22.15",
+			operation:      CompatOperationScaleUp,
+		},
+		{
+			name:           "scale-up with a newer image is refused",
+			imageVersion:   "v1.25.0",
+			clusterVersion: "v1.19.8",
+			operation:      CompatOperationScaleUp,
+			wantErr:        true,
+		},
+		{
+			name:           "scale-up more than two minors behind is refused",
+			imageVersion:   "v1.19.8",
+			clusterVersion: "v1.22.15",
+			operation:      CompatOperationScaleUp,
+			wantErr:        true,
+		},
+		{
+			name:           "scale-up one minor behind passes with a warning",
+			imageVersion:   "v1.21.14",
+			clusterVersion: "v1.22.15",
+			operation:      CompatOperationScaleUp,
+		},
+		{
+			name:           "upgrade to the next minor passes",
+			imageVersion:   "v1.23.17",
+			clusterVersion: "v1.22.15",
+			operation:      CompatOperationUpgrade,
+		},
+		{
+			name:           "upgrade to a newer patch passes",
+			imageVersion:   "v1.22.17",
+			clusterVersion: "v1.22.15",
+			operation:      CompatOperationUpgrade,
+		},
+		{
+			name:           "downgrade is refused",
+			imageVersion:   "v1.21.14",
+			clusterVersion: "v1.22.15",
+			operation:      CompatOperationUpgrade,
+			wantErr:        true,
+		},
+		{
+			name:           "upgrade skipping a minor is refused",
+			imageVersion:   "v1.25.0",
+			clusterVersion: "v1.22.15",
+			operation:      CompatOperationUpgrade,
+			wantErr:        true,
+		},
+		{
+			name:           "unknown operation is refused",
+			imageVersion:   "v1.22.15",
+			clusterVersion: "v1.22.15",
+			operation:      "rollback",
+			wantErr:        true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckKubeVersionSkew(map[string]string{KubeVersionLabel: tt.imageVersion}, tt.clusterVersion, tt.operation)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
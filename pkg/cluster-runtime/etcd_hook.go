@@ -0,0 +1,130 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterruntime
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/sealerio/sealer/pkg/infradriver"
+)
+
+const (
+	EtcdHook HookType = "ETCD"
+)
+
+const (
+	// defaultEtcdBackupDir is where snapshots land when the plugin data does
+	// not name a target path.
+	defaultEtcdBackupDir = "/var/lib/sealer/etcd-backup"
+
+	// kubeadm writes the etcd PKI here on every master; the healthcheck
+	// client pair exists exactly for operations like these.
+	etcdCACert     = "/etc/kubernetes/pki/etcd/ca.crt"
+	etcdClientCert = "/etc/kubernetes/pki/etcd/healthcheck-client.crt"
+	etcdClientKey  = "/etc/kubernetes/pki/etcd/healthcheck-client.key"
+)
+
+// etcdctlCmd renders an etcdctl invocation against the local etcd member
+// using the certs sealer already generated via kubeadm.
+func etcdctlCmd(host net.IP, args string) string {
+	return fmt.Sprintf("ETCDCTL_API=3 etcdctl --endpoints https://%s:2379 --cacert %s --cert %s --key %s %s",
+		host.String(), etcdCACert, etcdClientCert, etcdClientKey, args)
+}
+
+// parseEtcdActions turns the plugin data into etcdctl argument strings.
+// Each line is one action:
+//
+//	snapshot [dir]   save a snapshot under dir (default /var/lib/sealer/etcd-backup)
+//	defrag           defragment every member of the cluster
+//	healthcheck      check the health of every endpoint
+//
+// Empty data defaults to a single snapshot, which keeps the historical
+// "ETCD backup plugin without data" Clusterfiles working.
+func parseEtcdActions(data string) ([]string, error) {
+	var actions []string
+
+	lines := strings.Split(data, "\n")
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch strings.ToLower(fields[0]) {
+		case "snapshot", "backup":
+			dir := defaultEtcdBackupDir
+			if len(fields) > 1 {
+				dir = fields[1]
+			}
+			actions = append(actions, fmt.Sprintf("snapshot save %s/snapshot-$(date +%%Y%%m%%d%%H%%M%%S).db", strings.TrimRight(dir, "/")))
+		case "defrag", "defragment":
+			actions = append(actions, "defrag --cluster")
+		case "healthcheck", "health":
+			actions = append(actions, "endpoint health --cluster -w table")
+		default:
+			return nil, fmt.Errorf("unknown etcd plugin action %q, supported: snapshot, defrag, healthcheck", fields[0])
+		}
+	}
+
+	if len(actions) == 0 {
+		actions = append(actions, fmt.Sprintf("snapshot save %s/snapshot-$(date +%%Y%%m%%d%%H%%M%%S).db", defaultEtcdBackupDir))
+	}
+
+	return actions, nil
+}
+
+// NewEtcdHook runs etcd maintenance actions (snapshot/defrag/healthcheck)
+// against the cluster etcd. The commands are executed on the first given
+// host only: etcdctl reaches the remaining members itself through the
+// --cluster flag, and one snapshot per run is enough.
+func NewEtcdHook() HookFunc {
+	return func(data string, hosts []net.IP, driver infradriver.InfraDriver, extraOpts map[string]bool) error {
+		if len(hosts) == 0 {
+			return fmt.Errorf("no host to run etcd plugin on")
+		}
+		host := hosts[0]
+
+		actions, err := parseEtcdActions(data)
+		if err != nil {
+			return err
+		}
+
+		for _, action := range actions {
+			cmds := []string{etcdctlCmd(host, action)}
+			if strings.HasPrefix(action, "snapshot save") {
+				// the target directory must exist before etcdctl writes into it
+				cmds = append([]string{fmt.Sprintf("mkdir -p %s", defaultEtcdBackupDir)}, cmds...)
+				if fields := strings.Fields(action); len(fields) > 2 {
+					cmds[0] = fmt.Sprintf("mkdir -p $(dirname %s)", fields[2])
+				}
+			}
+
+			logrus.Infof("start to run etcd action(%s) on host(%s)", strings.Fields(action)[0], host)
+			if err := driver.CmdAsync(host, driver.GetHostEnv(host), cmds...); err != nil {
+				return fmt.Errorf("failed to run etcd action(%s) on host(%s): %v", action, host, err)
+			}
+		}
+
+		return nil
+	}
+}
+
+func init() {
+	Register(EtcdHook, NewEtcdHook())
+}
@@ -0,0 +1,39 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterruntime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseEtcdActions(t *testing.T) {
+	actions, err := parseEtcdActions("snapshot /backup/etcd\ndefrag\nhealthcheck")
+	assert.NoError(t, err)
+	assert.Len(t, actions, 3)
+	assert.Contains(t, actions[0], "snapshot save /backup/etcd/")
+	assert.Equal(t, "defrag --cluster", actions[1])
+	assert.Contains(t, actions[2], "endpoint health")
+
+	// empty data keeps the historical backup-only behavior
+	actions, err = parseEtcdActions("")
+	assert.NoError(t, err)
+	assert.Len(t, actions, 1)
+	assert.Contains(t, actions[0], defaultEtcdBackupDir)
+
+	_, err = parseEtcdActions("compact")
+	assert.Error(t, err)
+}
@@ -0,0 +1,114 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterruntime
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/sealerio/sealer/common"
+)
+
+// requiredFirewallPorts lists the ports a host must accept for the cluster to
+// come up, in firewall-cmd notation: the apiserver, etcd peers and the
+// kubelet on masters, the kubelet alone on workers, plus the local registry
+// port on the hosts serving it.
+func requiredFirewallPorts(isMaster bool, registryPort int) []string {
+	ports := []string{"10250/tcp"}
+	if isMaster {
+		ports = append(ports, "6443/tcp", "2379-2380/tcp")
+		if registryPort > 0 {
+			ports = append(ports, fmt.Sprintf("%d/tcp", registryPort))
+		}
+	}
+	return ports
+}
+
+// configureFirewall deals with a running firewalld before anything needs the
+// ports: by default it only reports required ports the firewall does not
+// allow, with FirewallMode=open-ports it opens them permanently, with
+// FirewallMode=skip it does nothing. Hosts without firewalld are untouched.
+func (i *Installer) configureFirewall(hosts []net.IP) error {
+	mode := common.FirewallModeCheck
+	if v, ok := i.infraDriver.GetClusterEnv()[common.EnvFirewallMode]; ok && v != "" {
+		mode = v
+	}
+
+	switch mode {
+	case common.FirewallModeSkip:
+		return nil
+	case common.FirewallModeCheck, common.FirewallModeOpenPorts:
+	default:
+		return fmt.Errorf("unknown %s value %q, expect %q, %q or %q",
+			common.EnvFirewallMode, mode, common.FirewallModeCheck, common.FirewallModeOpenPorts, common.FirewallModeSkip)
+	}
+
+	registryPort := 0
+	if i.regConfig.LocalRegistry != nil {
+		registryPort = i.regConfig.LocalRegistry.Port
+	}
+
+	masters := make(map[string]bool)
+	for _, master := range i.infraDriver.GetHostIPListByRole(common.MASTER) {
+		masters[master.String()] = true
+	}
+
+	return i.infraDriver.Execute(hosts, func(host net.IP) error {
+		state, err := i.infraDriver.CmdToString(host, nil, "systemctl is-active firewalld 2>/dev/null || true", "")
+		if err != nil {
+			return fmt.Errorf("failed to detect firewalld on host %s: %v", host, err)
+		}
+		if strings.TrimSpace(state) != "active" {
+			logrus.Debugf("firewalld is not running on host %s, nothing to configure", host)
+			return nil
+		}
+
+		ports := requiredFirewallPorts(masters[host.String()], registryPort)
+
+		if mode == common.FirewallModeOpenPorts {
+			var cmds []string
+			for _, port := range ports {
+				cmds = append(cmds, fmt.Sprintf("firewall-cmd --permanent --add-port=%s > /dev/null", port))
+			}
+			cmds = append(cmds, "firewall-cmd --reload > /dev/null")
+			if err := i.infraDriver.CmdAsync(host, nil, cmds...); err != nil {
+				return fmt.Errorf("failed to open required ports %v on host %s: %v", ports, host, err)
+			}
+			logrus.Infof("opened required ports %v in firewalld on host %s", ports, host)
+			return nil
+		}
+
+		var blocked []string
+		for _, port := range ports {
+			allowed, err := i.infraDriver.CmdToString(host, nil, fmt.Sprintf("firewall-cmd --query-port=%s 2>/dev/null || true", port), "")
+			if err != nil {
+				return fmt.Errorf("failed to query firewalld port %s on host %s: %v", port, host, err)
+			}
+			if strings.TrimSpace(allowed) != "yes" {
+				blocked = append(blocked, port)
+			}
+		}
+		if len(blocked) > 0 {
+			// report only: the rules may still allow the traffic through a
+			// zone or rich rule the per-port query does not see.
+			logrus.Warnf("firewalld on host %s does not allow required port(s) %s; open them, or set %s=%s to let sealer do it",
+				host, strings.Join(blocked, ", "), common.EnvFirewallMode, common.FirewallModeOpenPorts)
+		}
+		return nil
+	})
+}
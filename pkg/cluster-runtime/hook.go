@@ -34,6 +34,7 @@ import (
 	"github.com/sealerio/sealer/pkg/infradriver"
 	v1 "github.com/sealerio/sealer/types/api/v1"
 	netUtils "github.com/sealerio/sealer/utils/net"
+	strUtils "github.com/sealerio/sealer/utils/strings"
 	"github.com/sealerio/sealer/utils/yaml"
 )
 
@@ -169,12 +170,14 @@ func (i *Installer) runClusterHook(master0 net.IP, phase Phase) error {
 	return nil
 }
 
-// getHostIPListByScope get ip list for scope, support use '|' to specify multiple scopes, they are ORed
+// getHostIPListByScope get ip list for scope, support use '|' to specify multiple scopes, they are ORed.
+// Every entry is a role name, optionally written as "role=<name>", and any
+// custom role declared on Clusterfile hosts can be targeted, not only
+// master/node.
 func (i *Installer) getHostIPListByScope(scope Scope) []net.IP {
 	var ret []net.IP
-	scopes := strings.Split(string(scope), "|")
-	for _, s := range scopes {
-		hosts := i.infraDriver.GetHostIPListByRole(strings.TrimSpace(s))
+	for _, role := range parseScopeRoles(scope) {
+		hosts := i.infraDriver.GetHostIPListByRole(role)
 
 		// remove duplicates
 		for _, h := range hosts {
@@ -187,6 +190,21 @@ func (i *Installer) getHostIPListByScope(scope Scope) []net.IP {
 	return ret
 }
 
+// parseScopeRoles splits a hook scope into the role names it targets,
+// stripping the optional "role=" prefix.
+func parseScopeRoles(scope Scope) []string {
+	var roles []string
+	for _, s := range strings.Split(string(scope), "|") {
+		s = strings.TrimSpace(s)
+		s = strings.TrimPrefix(s, "role=")
+		if s == "" {
+			continue
+		}
+		roles = append(roles, s)
+	}
+	return roles
+}
+
 func NewShellHook() HookFunc {
 	return func(cmd string, hosts []net.IP, driver infradriver.InfraDriver, extraOpts map[string]bool) error {
 		rootfs := driver.GetClusterRootfsPath()
@@ -321,6 +339,61 @@ func decodePluginFile(pluginFile string) ([]v1.Plugin, error) {
 	}
 }
 
+const (
+	// PreInitScriptsDir and PostInitScriptsDir hold the per role host init
+	// scripts a sealer image may ship beside scripts/init.sh: every
+	// "<dir>/<role>/*.sh" is executed on the hosts of that role around the
+	// host init phase, with the cluster env rendered, instead of being
+	// jammed into one opaque init.sh.
+	PreInitScriptsDir  = "scripts/pre-init.d"
+	PostInitScriptsDir = "scripts/post-init.d"
+)
+
+// initScriptsHookData builds the shell snippet running every script of one
+// role directory; missing directories are skipped so images without the
+// convention stay untouched.
+func initScriptsHookData(scriptsDir, role string) string {
+	dir := fmt.Sprintf("%s/%s", scriptsDir, role)
+	return fmt.Sprintf(`if [ -d %[1]s ]; then for f in %[1]s/*.sh; do [ -e "$f" ] || continue; echo "running host init script $f"; bash "$f"; done; fi`, dir)
+}
+
+// rootfsInitHooks generates the pre/post host init hook configs for the
+// given cluster roles; they run after the plugin configured hooks of the
+// same phase thanks to the name prefix ordering.
+func rootfsInitHooks(roles []string) map[Phase]HookConfigList {
+	hooks := make(map[Phase]HookConfigList)
+	for _, role := range roles {
+		hooks[PreInitHost] = append(hooks[PreInitHost], HookConfig{
+			Name:  fmt.Sprintf("zz-rootfs-pre-init-%s", role),
+			Type:  ShellHook,
+			Data:  initScriptsHookData(PreInitScriptsDir, role),
+			Phase: PreInitHost,
+			Scope: Scope(role),
+		})
+		hooks[PostInitHost] = append(hooks[PostInitHost], HookConfig{
+			Name:  fmt.Sprintf("zz-rootfs-post-init-%s", role),
+			Type:  ShellHook,
+			Data:  initScriptsHookData(PostInitScriptsDir, role),
+			Phase: PostInitHost,
+			Scope: Scope(role),
+		})
+	}
+	return hooks
+}
+
+// clusterRoles collects the distinct roles declared on the cluster hosts.
+func clusterRoles(infraDriver infradriver.InfraDriver) []string {
+	var roles []string
+	for _, ip := range infraDriver.GetHostIPList() {
+		for _, role := range infraDriver.GetRoleListByHostIP(ip.String()) {
+			if !strUtils.IsInSlice(role, roles) {
+				roles = append(roles, role)
+			}
+		}
+	}
+	return roles
+}
+
 func init() {
 	Register(ShellHook, NewShellHook())
 }
@@ -14,6 +14,35 @@
 
 package clusterruntime
 
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseScopeRoles(t *testing.T) {
+	assert.Equal(t, []string{"master"}, parseScopeRoles("master"))
+	assert.Equal(t, []string{"master", "node"}, parseScopeRoles("master|node"))
+	assert.Equal(t, []string{"ingress", "storage"}, parseScopeRoles("role=ingress | role=storage"))
+	assert.Nil(t, parseScopeRoles(""))
+}
+
+func TestRootfsInitHooks(t *testing.T) {
+	hooks := rootfsInitHooks([]string{"master", "gpu"})
+
+	assert.Len(t, hooks[PreInitHost], 2)
+	assert.Len(t, hooks[PostInitHost], 2)
+
+	pre := hooks[PreInitHost][0]
+	assert.Equal(t, ShellHook, pre.Type)
+	assert.Equal(t, Scope("master"), pre.Scope)
+	assert.Contains(t, pre.Data, "scripts/pre-init.d/master")
+
+	post := hooks[PostInitHost][1]
+	assert.Equal(t, Scope("gpu"), post.Scope)
+	assert.Contains(t, post.Data, "scripts/post-init.d/gpu")
+}
+
 //func TestLoadPluginsFromFile(t *testing.T) {
 //	plugin1 := v1.Plugin{
 //		Spec: v1.PluginSpec{
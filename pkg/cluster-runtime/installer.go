@@ -22,10 +22,14 @@ import (
 	"time"
 
 	"github.com/sealerio/sealer/common"
+	"github.com/sealerio/sealer/pkg/checkpoint"
 	"github.com/sealerio/sealer/pkg/clusterfile"
 	containerruntime "github.com/sealerio/sealer/pkg/container-runtime"
 	"github.com/sealerio/sealer/pkg/imagedistributor"
 	"github.com/sealerio/sealer/pkg/infradriver"
+	"github.com/sealerio/sealer/pkg/logger"
+	"github.com/sealerio/sealer/pkg/metrics"
+	"github.com/sealerio/sealer/pkg/progress"
 	"github.com/sealerio/sealer/pkg/registry"
 	"github.com/sealerio/sealer/pkg/runtime"
 	"github.com/sealerio/sealer/pkg/runtime/k0s"
@@ -56,12 +60,22 @@ const (
 	RegistryConfigMapDataName = "registry"
 )
 
+// names of the phases recorded in the apply checkpoint.
+const (
+	checkpointPhaseDistributeRootfs = string(progress.PhaseDistributeRootfs)
+	checkpointPhaseContainerRuntime = "install-container-runtime"
+	checkpointPhaseClusterRuntime   = "install-cluster-runtime"
+)
+
 // RuntimeConfig for Installer
 type RuntimeConfig struct {
 	Distributor            imagedistributor.Distributor
 	ContainerRuntimeConfig v2.ContainerRuntimeConfig
 	KubeadmConfig          kubeadm.KubeadmConfig
 	Plugins                []v1.Plugin
+	// ClusterUsers are the initial users declared in the Clusterfile,
+	// created right after the cluster runtime serves.
+	ClusterUsers []v1.User
 }
 
 type Installer struct {
@@ -69,25 +83,42 @@ type Installer struct {
 	infraDriver               infradriver.InfraDriver
 	containerRuntimeInstaller containerruntime.Installer
 	clusterRuntimeType        string
+	containerRuntimeType      string
 	hooks                     map[Phase]HookConfigList
+	pipelinePlugins           []v1.Plugin
 	regConfig                 v2.Registry
 }
 
+// installContext carries the state shared between the pipeline steps of one
+// install run.
+type installContext struct {
+	masters []net.IP
+	master0 net.IP
+	workers []net.IP
+	all     []net.IP
+	rootfs  string
+
+	cp *checkpoint.Manager
+
+	crInfo               containerruntime.Info
+	registryDeployHosts  []net.IP
+	registryDriver       registry.Driver
+	registryInfo         registry.RegistryInfo
+	kubeRuntimeInstaller runtime.Installer
+}
+
 type InstallInfo struct {
 	ContainerRuntimeType string
 	ClusterRuntimeType   string
 }
 
-func getCRIInstaller(containerRuntime string, infraDriver infradriver.InfraDriver) (containerruntime.Installer, error) {
+func getCRIInstaller(containerRuntime string, conf v2.ContainerRuntimeConfig, infraDriver infradriver.InfraDriver) (containerruntime.Installer, error) {
 	switch containerRuntime {
-	case common.Docker:
-		return containerruntime.NewInstaller(v2.ContainerRuntimeConfig{
-			Type: common.Docker,
-		}, infraDriver)
-	case common.Containerd:
-		return containerruntime.NewInstaller(v2.ContainerRuntimeConfig{
-			Type: common.Containerd,
-		}, infraDriver)
+	case common.Docker, common.Containerd:
+		// the runtime type is decided by the image, the rest of the config
+		// (mirrors, insecure registries) comes from the Clusterfile
+		conf.Type = containerRuntime
+		return containerruntime.NewInstaller(conf, infraDriver)
 	default:
 		return nil, fmt.Errorf("not support container runtime %s", containerRuntime)
 	}
@@ -110,87 +141,205 @@ func NewInstaller(infraDriver infradriver.InfraDriver, runtimeConfig RuntimeConf
 	var (
 		err       error
 		installer = &Installer{
-			regConfig:          infraDriver.GetClusterRegistry(),
-			clusterRuntimeType: installInfo.ClusterRuntimeType,
+			regConfig:            infraDriver.GetClusterRegistry(),
+			clusterRuntimeType:   installInfo.ClusterRuntimeType,
+			containerRuntimeType: installInfo.ContainerRuntimeType,
 		}
 	)
 
 	installer.RuntimeConfig = runtimeConfig
 	// configure container runtime
 	//todo need to support other container runtimes
-	installer.containerRuntimeInstaller, err = getCRIInstaller(installInfo.ContainerRuntimeType, infraDriver)
+	installer.containerRuntimeInstaller, err = getCRIInstaller(installInfo.ContainerRuntimeType, runtimeConfig.ContainerRuntimeConfig, infraDriver)
 	if err != nil {
 		return nil, err
 	}
 
+	// pipeline customizations are not hooks, keep them apart
+	pipelinePlugins, hookPlugins := splitPipelinePlugins(runtimeConfig.Plugins)
+	installer.pipelinePlugins = pipelinePlugins
+
 	// add installer hooks
-	hooks, err := transferPluginsToHooks(runtimeConfig.Plugins)
+	hooks, err := transferPluginsToHooks(hookPlugins)
 	if err != nil {
 		return nil, err
 	}
+	// add the conventional per role host init scripts shipped in the rootfs
+	// under scripts/pre-init.d and scripts/post-init.d
+	for phase, hookConfigs := range rootfsInitHooks(clusterRoles(infraDriver)) {
+		hooks[phase] = append(hooks[phase], hookConfigs...)
+	}
 	installer.hooks = hooks
 	installer.infraDriver = infraDriver
 	return installer, nil
 }
 
+// defaultInstallPipeline returns the built-in install sequence. The names
+// are stable and can be targeted by PIPELINE plugins in the Clusterfile to
+// insert, replace or skip steps.
+func defaultInstallPipeline() *Pipeline {
+	return &Pipeline{steps: []Step{
+		{Name: "distribute-rootfs", Run: (*Installer).stepDistributeRootfs},
+		{Name: "pre-install-hooks", Run: func(i *Installer, c *installContext) error {
+			return i.runClusterHook(c.master0, PreInstallCluster)
+		}},
+		{Name: "pre-init-host-hooks", Run: func(i *Installer, c *installContext) error {
+			return i.runHostHook(PreInitHost, c.all)
+		}},
+		{Name: "check-host-os-compat", Run: func(i *Installer, c *installContext) error {
+			// refuse hosts whose distro/arch the image does not declare
+			// support for, a clear message here beats a kubeadm failure
+			// half an hour in.
+			return i.checkHostOSCompat(c.all)
+		}},
+		{Name: "configure-firewall", Run: func(i *Installer, c *installContext) error {
+			// a running firewalld silently eating apiserver or etcd traffic
+			// is painful to diagnose, surface (or fix) it up front.
+			return i.configureFirewall(c.all)
+		}},
+		{Name: "configure-selinux", Run: func(i *Installer, c *installContext) error {
+			// must precede the container runtime install so its scripts pick
+			// up the configs rendered for an enforcing host.
+			return i.configureSELinux(c.all)
+		}},
+		{Name: "install-os-packages", Run: func(i *Installer, c *installContext) error {
+			// install the OS dependencies bundled in the rootfs before
+			// anything needs them, minimal air-gapped installs ship
+			// without conntrack etc.
+			return i.installOfflineOSPackages(c.all)
+		}},
+		{Name: checkpointPhaseContainerRuntime, Run: (*Installer).stepInstallContainerRuntime},
+		{Name: "distribute-extra-cas", Run: func(i *Installer, c *installContext) error {
+			return i.distributeExtraCAs(c.all)
+		}},
+		// nothing to deploy when an external registry serves the images
+		{Name: "install-registry", Condition: func(i *Installer) bool {
+			return i.regConfig.LocalRegistry != nil
+		}, Run: (*Installer).stepInstallRegistry},
+		{Name: "configure-registry", Run: (*Installer).stepConfigureRegistry},
+		{Name: checkpointPhaseClusterRuntime, Run: (*Installer).stepInstallClusterRuntime},
+		{Name: "create-cluster-users", Run: func(i *Installer, c *installContext) error {
+			// day-one access without handing out the admin kubeconfig
+			return i.createClusterUsers(c.master0)
+		}},
+		{Name: "post-init-host-hooks", Run: func(i *Installer, c *installContext) error {
+			return i.runHostHook(PostInitHost, c.all)
+		}},
+		{Name: "post-install-hooks", Run: func(i *Installer, c *installContext) error {
+			return i.runClusterHook(c.master0, PostInstallCluster)
+		}},
+		{Name: "finalize-cluster", Run: (*Installer).stepFinalizeCluster},
+	}}
+}
+
 func (i *Installer) Install() error {
-	var (
-		masters = i.infraDriver.GetHostIPListByRole(common.MASTER)
-		master0 = masters[0]
-		workers = getWorkerIPList(i.infraDriver)
-		all     = append(masters, workers...)
-		rootfs  = i.infraDriver.GetClusterRootfsPath()
-	)
+	masters := i.infraDriver.GetHostIPListByRole(common.MASTER)
+	workers := getWorkerIPList(i.infraDriver)
+	// dedicated etcd hosts never join kubernetes but still get the rootfs,
+	// the host hooks and the preflight checks
+	etcds := getDedicatedEtcdIPList(i.infraDriver)
+
+	installStart := time.Now()
+	defer metrics.ObservePhase(i.infraDriver.GetClusterName(), "install", installStart)
+	logger.SetPhase("install")
+	defer logger.SetPhase("")
+
+	// load the checkpoint of previous failed runs, if any, so that the
+	// completed phases can be skipped on re-execution.
+	cp, err := checkpoint.NewManager(i.infraDriver.GetClusterName())
+	if err != nil {
+		return err
+	}
+
+	c := &installContext{
+		masters: masters,
+		master0: masters[0],
+		workers: workers,
+		all:     append(append(masters, workers...), etcds...),
+		rootfs:  i.infraDriver.GetClusterRootfsPath(),
+		cp:      cp,
+	}
 
 	// set HostAlias
-	if err := i.infraDriver.SetClusterHostAliases(all); err != nil {
+	if err := i.infraDriver.SetClusterHostAliases(c.all); err != nil {
 		return err
 	}
 
-	// distribute rootfs
-	if err := i.Distributor.Distribute(all, rootfs); err != nil {
+	pipeline := defaultInstallPipeline()
+	if err := applyPipelinePlugins(pipeline, i.pipelinePlugins); err != nil {
 		return err
 	}
 
-	if err := i.runClusterHook(master0, PreInstallCluster); err != nil {
-		return err
+	return pipeline.Run(i, c)
+}
+
+func (i *Installer) stepDistributeRootfs(c *installContext) error {
+	if c.cp.IsPhaseDone(checkpointPhaseDistributeRootfs) {
+		logrus.Infof("phase %s already completed in a previous run, skipping", checkpointPhaseDistributeRootfs)
+		return nil
 	}
 
-	if err := i.runHostHook(PreInitHost, all); err != nil {
+	reporter := progress.Default()
+	distributeStart := time.Now()
+	reporter.PhaseStarted(progress.PhaseDistributeRootfs, len(c.all))
+	if err := i.Distributor.Distribute(c.all, c.rootfs); err != nil {
+		reporter.PhaseFinished(progress.PhaseDistributeRootfs, err)
 		return err
 	}
+	reporter.PhaseFinished(progress.PhaseDistributeRootfs, nil)
+	metrics.ObservePhase(i.infraDriver.GetClusterName(), "distribute-rootfs", distributeStart)
+	return c.cp.MarkPhaseDone(checkpointPhaseDistributeRootfs)
+}
 
-	if err := i.containerRuntimeInstaller.InstallOn(all); err != nil {
-		return err
+func (i *Installer) stepInstallContainerRuntime(c *installContext) error {
+	if c.cp.IsPhaseDone(checkpointPhaseContainerRuntime) {
+		logrus.Infof("phase %s already completed in a previous run, skipping", checkpointPhaseContainerRuntime)
+	} else {
+		containerRuntimeStart := time.Now()
+		if err := i.containerRuntimeInstaller.InstallOn(c.all); err != nil {
+			return err
+		}
+		metrics.ObservePhase(i.infraDriver.GetClusterName(), "container-runtime", containerRuntimeStart)
+		if err := c.cp.MarkPhaseDone(checkpointPhaseContainerRuntime); err != nil {
+			return err
+		}
 	}
 
 	crInfo, err := i.containerRuntimeInstaller.GetInfo()
 	if err != nil {
 		return err
 	}
+	c.crInfo = crInfo
+	return nil
+}
 
-	var deployHosts []net.IP
-	if i.regConfig.LocalRegistry != nil {
-		installer := registry.NewInstaller(nil, i.regConfig.LocalRegistry, i.infraDriver, i.Distributor)
-		if *i.regConfig.LocalRegistry.HA {
-			deployHosts, err = installer.Reconcile(masters)
-			if err != nil {
-				return err
-			}
-		} else {
-			deployHosts, err = installer.Reconcile([]net.IP{master0})
-			if err != nil {
-				return err
-			}
-		}
+func (i *Installer) stepInstallRegistry(c *installContext) error {
+	var (
+		deployHosts []net.IP
+		err         error
+	)
+
+	installer := registry.NewInstaller(nil, i.regConfig.LocalRegistry, i.infraDriver, i.Distributor)
+	if *i.regConfig.LocalRegistry.HA {
+		deployHosts, err = installer.Reconcile(c.masters)
+	} else {
+		deployHosts, err = installer.Reconcile([]net.IP{c.master0})
+	}
+	if err != nil {
+		return err
 	}
 
-	registryConfigurator, err := registry.NewConfigurator(deployHosts, crInfo, i.regConfig, i.infraDriver, i.Distributor)
+	c.registryDeployHosts = deployHosts
+	return nil
+}
+
+func (i *Installer) stepConfigureRegistry(c *installContext) error {
+	registryConfigurator, err := registry.NewConfigurator(c.registryDeployHosts, c.crInfo, i.regConfig, i.infraDriver, i.Distributor)
 	if err != nil {
 		return err
 	}
 
-	if err = registryConfigurator.InstallOn(masters, workers); err != nil {
+	if err = registryConfigurator.InstallOn(c.masters, c.workers); err != nil {
 		return err
 	}
 
@@ -199,27 +348,32 @@ func (i *Installer) Install() error {
 		return err
 	}
 
-	registryInfo := registryConfigurator.GetRegistryInfo()
+	c.registryDriver = registryDriver
+	c.registryInfo = registryConfigurator.GetRegistryInfo()
+	return nil
+}
 
+func (i *Installer) stepInstallClusterRuntime(c *installContext) error {
 	kubeRuntimeInstaller, err := getClusterRuntimeInstaller(i.clusterRuntimeType, i.infraDriver,
-		crInfo, registryDriver.GetInfo(), i.KubeadmConfig)
+		c.crInfo, c.registryDriver.GetInfo(), i.KubeadmConfig)
 	if err != nil {
 		return err
 	}
+	c.kubeRuntimeInstaller = kubeRuntimeInstaller
 
-	if err = kubeRuntimeInstaller.Install(); err != nil {
-		return err
+	if c.cp.IsPhaseDone(checkpointPhaseClusterRuntime) {
+		logrus.Infof("phase %s already completed in a previous run, skipping", checkpointPhaseClusterRuntime)
+		return nil
 	}
 
-	if err = i.runHostHook(PostInitHost, all); err != nil {
-		return err
-	}
-
-	if err = i.runClusterHook(master0, PostInstallCluster); err != nil {
+	if err = kubeRuntimeInstaller.Install(); err != nil {
 		return err
 	}
+	return c.cp.MarkPhaseDone(checkpointPhaseClusterRuntime)
+}
 
-	runtimeDriver, err := kubeRuntimeInstaller.GetCurrentRuntimeDriver()
+func (i *Installer) stepFinalizeCluster(c *installContext) error {
+	runtimeDriver, err := c.kubeRuntimeInstaller.GetCurrentRuntimeDriver()
 	if err != nil {
 		return err
 	}
@@ -228,19 +382,22 @@ func (i *Installer) Install() error {
 		return err
 	}
 
-	if err = i.setNodeLabels(all, runtimeDriver); err != nil {
+	if err = i.setNodeLabels(c.all, runtimeDriver); err != nil {
 		return err
 	}
 
-	if err = i.setNodeTaints(all, runtimeDriver); err != nil {
+	if err = i.setNodeTaints(c.all, runtimeDriver); err != nil {
 		return err
 	}
 
-	if err = i.saveRegistryInfo(runtimeDriver, registryInfo); err != nil {
+	if err = i.saveRegistryInfo(runtimeDriver, c.registryInfo); err != nil {
 		return err
 	}
 
-	return nil
+	// the apply went through: drop the phase records so the next run
+	// starts from a clean slate, keeping any failed join hosts for a
+	// later `sealer join --retry-failed`.
+	return c.cp.ClearCompletedPhases()
 }
 
 func (i *Installer) GetCurrentDriver() (registry.Driver, runtime.Driver, error) {
@@ -281,7 +438,9 @@ func (i *Installer) GetCurrentDriver() (registry.Driver, runtime.Driver, error)
 	return registryDriver, runtimeDriver, nil
 }
 
-// setRoles save roles
+// setRoles labels every node with node-role.kubernetes.io/<role> for each of
+// its Clusterfile roles, including custom ones like ingress or storage, so
+// they can be matched by node selectors.
 func (i *Installer) setRoles(driver runtime.Driver) error {
 	nodeList := corev1.NodeList{}
 	if err := driver.List(context.TODO(), &nodeList); err != nil {
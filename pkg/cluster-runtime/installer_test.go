@@ -139,7 +139,7 @@ func Test_chooseCRIInstaller(t *testing.T) {
 				assert.Error(t, err)
 			}
 
-			got, err := getCRIInstaller(tt.args.containerRuntime, infraDriver)
+			got, err := getCRIInstaller(tt.args.containerRuntime, v2.ContainerRuntimeConfig{}, infraDriver)
 			if err != nil {
 				t.Errorf("chooseCRIInstaller() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -0,0 +1,166 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterruntime
+
+import (
+	"fmt"
+	"net"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/sealerio/sealer/utils/platform"
+)
+
+// SupportedOSFile is an optional rootfs file declaring the host OS matrix a
+// sealer image was built and tested for. Each non-comment line is
+//
+//	<distro-id> <version-prefix> <arch>
+//
+// where <distro-id> is the ID of /etc/os-release (centos, rhel, ubuntu,
+// anolis, kylin, openeuler, ...), <version-prefix> matches VERSION_ID by
+// prefix ("7" matches "7.9") and <arch> is a normalized architecture
+// (amd64/arm64). Any field may be "*". Images without the file skip the
+// check entirely.
+const SupportedOSFile = "supported-os"
+
+// detectHostOSCmd prints "<id> <version-id> <machine>" of the remote host in
+// one round trip; unknown fields degrade to "unknown" instead of failing.
+const detectHostOSCmd = `. /etc/os-release 2>/dev/null; echo "${ID:-unknown} ${VERSION_ID:-unknown} $(uname -m)"`
+
+// hostOS is the distro and architecture detected on one host.
+type hostOS struct {
+	ID      string
+	Version string
+	Arch    string
+}
+
+func (h hostOS) String() string {
+	return fmt.Sprintf("%s %s (%s)", h.ID, h.Version, h.Arch)
+}
+
+// osSupportEntry is one line of the supported OS matrix.
+type osSupportEntry struct {
+	ID      string
+	Version string
+	Arch    string
+}
+
+func (e osSupportEntry) String() string {
+	return fmt.Sprintf("%s %s %s", e.ID, e.Version, e.Arch)
+}
+
+func (e osSupportEntry) matches(h hostOS) bool {
+	if e.ID != "*" && e.ID != h.ID {
+		return false
+	}
+	if e.Arch != "*" && e.Arch != h.Arch {
+		return false
+	}
+	if e.Version != "*" && e.Version != h.Version && !strings.HasPrefix(h.Version, e.Version+".") {
+		return false
+	}
+	return true
+}
+
+// parseHostOS turns the detectHostOSCmd output into a hostOS with the same
+// normalization the matrix entries get, so comparison is literal.
+func parseHostOS(output string) (hostOS, error) {
+	fields := strings.Fields(output)
+	if len(fields) != 3 {
+		return hostOS{}, fmt.Errorf("unexpected host OS detection output: %q", output)
+	}
+
+	arch, _ := platform.NormalizeArch(fields[2], "")
+	return hostOS{
+		ID:      strings.ToLower(fields[0]),
+		Version: fields[1],
+		Arch:    arch,
+	}, nil
+}
+
+// parseSupportedOSMatrix parses the content of a supported-os file, skipping
+// blank lines and comments. Malformed lines are reported rather than
+// silently dropped, a typo there must not widen the matrix.
+func parseSupportedOSMatrix(data string) ([]osSupportEntry, error) {
+	var entries []osSupportEntry
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("invalid %s line %q: expected <distro-id> <version-prefix> <arch>", SupportedOSFile, line)
+		}
+
+		arch := fields[2]
+		if arch != "*" {
+			arch, _ = platform.NormalizeArch(arch, "")
+		}
+		entries = append(entries, osSupportEntry{
+			ID:      strings.ToLower(fields[0]),
+			Version: fields[1],
+			Arch:    arch,
+		})
+	}
+	return entries, nil
+}
+
+// checkHostOSCompat verifies every host runs an OS the distributed rootfs
+// declares support for, failing fast before anything gets installed. Images
+// that ship no supported-os file keep working unchecked.
+func (i *Installer) checkHostOSCompat(hosts []net.IP) error {
+	matrixPath := filepath.Join(i.infraDriver.GetClusterRootfsPath(), SupportedOSFile)
+
+	return i.infraDriver.Execute(hosts, func(host net.IP) error {
+		raw, err := i.infraDriver.CmdToString(host, nil, fmt.Sprintf("cat %s 2>/dev/null || true", matrixPath), "\n")
+		if err != nil {
+			return fmt.Errorf("failed to read %s on host %s: %v", SupportedOSFile, host, err)
+		}
+		if strings.TrimSpace(raw) == "" {
+			logrus.Debugf("no %s file in rootfs on host %s, skipping OS compatibility check", SupportedOSFile, host)
+			return nil
+		}
+
+		entries, err := parseSupportedOSMatrix(raw)
+		if err != nil {
+			return err
+		}
+
+		detected, err := i.infraDriver.CmdToString(host, nil, detectHostOSCmd, "")
+		if err != nil {
+			return fmt.Errorf("failed to detect OS of host %s: %v", host, err)
+		}
+		h, err := parseHostOS(detected)
+		if err != nil {
+			return fmt.Errorf("host %s: %v", host, err)
+		}
+
+		var supported []string
+		for _, entry := range entries {
+			if entry.matches(h) {
+				logrus.Debugf("host %s runs %s, matched by supported OS entry %q", host, h, entry)
+				return nil
+			}
+			supported = append(supported, entry.String())
+		}
+
+		return fmt.Errorf("host %s runs %s which this ClusterImage does not support, supported OS matrix: [%s]",
+			host, h, strings.Join(supported, ", "))
+	})
+}
@@ -0,0 +1,60 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterruntime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSupportedOSMatrix(t *testing.T) {
+	entries, err := parseSupportedOSMatrix(`
+# the matrix this image was verified on
+centos 7 x86_64
+openEuler * arm64
+* * amd64
+`)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 3)
+	// distro id lowercased, arch normalized
+	assert.Equal(t, osSupportEntry{ID: "centos", Version: "7", Arch: "amd64"}, entries[0])
+	assert.Equal(t, osSupportEntry{ID: "openeuler", Version: "*", Arch: "arm64"}, entries[1])
+	assert.Equal(t, osSupportEntry{ID: "*", Version: "*", Arch: "amd64"}, entries[2])
+
+	_, err = parseSupportedOSMatrix("centos 7")
+	assert.Error(t, err)
+}
+
+func TestParseHostOS(t *testing.T) {
+	h, err := parseHostOS("Kylin V10 aarch64")
+	assert.NoError(t, err)
+	assert.Equal(t, hostOS{ID: "kylin", Version: "V10", Arch: "arm64"}, h)
+
+	_, err = parseHostOS("")
+	assert.Error(t, err)
+}
+
+func TestOSSupportEntryMatches(t *testing.T) {
+	centos79 := hostOS{ID: "centos", Version: "7.9", Arch: "amd64"}
+
+	assert.True(t, osSupportEntry{ID: "centos", Version: "7", Arch: "amd64"}.matches(centos79))
+	assert.True(t, osSupportEntry{ID: "*", Version: "*", Arch: "amd64"}.matches(centos79))
+	// version prefix match is per component: "7" matches "7.9", not "79"
+	assert.False(t, osSupportEntry{ID: "centos", Version: "7", Arch: "amd64"}.matches(hostOS{ID: "centos", Version: "79", Arch: "amd64"}))
+	assert.False(t, osSupportEntry{ID: "centos", Version: "8", Arch: "amd64"}.matches(centos79))
+	assert.False(t, osSupportEntry{ID: "centos", Version: "7", Arch: "arm64"}.matches(centos79))
+	assert.False(t, osSupportEntry{ID: "ubuntu", Version: "*", Arch: "*"}.matches(centos79))
+}
@@ -0,0 +1,64 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterruntime
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/sealerio/sealer/pkg/logger"
+)
+
+// OSPackagesDir is the rootfs directory a sealer image may use to bundle
+// the OS dependencies kubeadm needs (conntrack, socat, ebtables,
+// nfs-utils, ...) for air-gapped installs. Packages are laid out per
+// distro and architecture:
+//
+//	os-pkgs/<distro-id>/<arch>/*.rpm
+//	os-pkgs/<distro-id>/<arch>/*.deb
+//
+// where <distro-id> is the ID of /etc/os-release (centos, ubuntu, ...)
+// and <arch> is the output of uname -m. A distro wide fallback without the
+// arch level is also honored.
+const OSPackagesDir = "os-pkgs"
+
+// installOSPackagesCmd resolves the package directory of the host and
+// installs everything in it through the native package tooling, entirely
+// offline. Hosts whose distro/arch is not bundled are skipped silently so
+// images without the convention keep working.
+const installOSPackagesCmd = `distro=$(. /etc/os-release 2>/dev/null && echo "$ID"); arch=$(uname -m)
+for dir in %[1]s/$distro/$arch %[1]s/$distro; do
+  [ -d "$dir" ] || continue
+  echo "installing bundled OS packages from $dir"
+  if ls "$dir"/*.rpm >/dev/null 2>&1; then rpm -Uvh --force --nodeps "$dir"/*.rpm; fi
+  if ls "$dir"/*.deb >/dev/null 2>&1; then dpkg -i "$dir"/*.deb; fi
+  break
+done
+true`
+
+// installOfflineOSPackages installs the OS packages bundled under
+// $rootfs/os-pkgs on every given host, before the container runtime needs
+// them. It is a no-op for images that bundle nothing.
+func (i *Installer) installOfflineOSPackages(hosts []net.IP) error {
+	cmd := fmt.Sprintf(installOSPackagesCmd, fmt.Sprintf("%s/%s", i.infraDriver.GetClusterRootfsPath(), OSPackagesDir))
+
+	return i.infraDriver.Execute(hosts, func(host net.IP) error {
+		logger.WithHost(host).Debug("checking bundled OS packages")
+		if err := i.infraDriver.CmdAsync(host, i.infraDriver.GetHostEnv(host), cmd); err != nil {
+			return fmt.Errorf("failed to install bundled OS packages on host %s: %v", host, err)
+		}
+		return nil
+	})
+}
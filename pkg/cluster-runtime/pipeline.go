@@ -0,0 +1,193 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterruntime
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	v1 "github.com/sealerio/sealer/types/api/v1"
+	"github.com/sirupsen/logrus"
+)
+
+// PipelinePluginType marks a Clusterfile plugin that customizes the install
+// pipeline instead of adding a hook.
+const PipelinePluginType = "PIPELINE"
+
+// actions supported by a PIPELINE plugin; the target step is given via the
+// plugin scope and the shell to run via the plugin data.
+const (
+	pipelineActionSkip         = "skip"
+	pipelineActionInsertBefore = "insert-before"
+	pipelineActionInsertAfter  = "insert-after"
+	pipelineActionReplace      = "replace"
+)
+
+// Step is one named phase of the install pipeline. A step with a Condition
+// only runs when the condition holds for the installer.
+type Step struct {
+	Name string
+	// Condition decides at run time whether the step applies; nil means
+	// the step always runs.
+	Condition func(i *Installer) bool
+	Run       func(i *Installer, c *installContext) error
+}
+
+// Pipeline is an ordered, named sequence of install steps. Steps can be
+// inserted, replaced or skipped by name before the pipeline runs, so images
+// and Clusterfiles can adjust the sequence without forking the installer.
+type Pipeline struct {
+	steps []Step
+}
+
+func (p *Pipeline) indexOf(name string) int {
+	for i, step := range p.steps {
+		if step.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// StepNames returns the step names in execution order.
+func (p *Pipeline) StepNames() []string {
+	names := make([]string, 0, len(p.steps))
+	for _, step := range p.steps {
+		names = append(names, step.Name)
+	}
+	return names
+}
+
+// InsertBefore inserts the step right before the named target.
+func (p *Pipeline) InsertBefore(target string, step Step) error {
+	idx := p.indexOf(target)
+	if idx < 0 {
+		return fmt.Errorf("pipeline step %s not found", target)
+	}
+	p.steps = append(p.steps[:idx], append([]Step{step}, p.steps[idx:]...)...)
+	return nil
+}
+
+// InsertAfter inserts the step right after the named target.
+func (p *Pipeline) InsertAfter(target string, step Step) error {
+	idx := p.indexOf(target)
+	if idx < 0 {
+		return fmt.Errorf("pipeline step %s not found", target)
+	}
+	p.steps = append(p.steps[:idx+1], append([]Step{step}, p.steps[idx+1:]...)...)
+	return nil
+}
+
+// Replace swaps the named step for the given one, keeping its position.
+func (p *Pipeline) Replace(target string, step Step) error {
+	idx := p.indexOf(target)
+	if idx < 0 {
+		return fmt.Errorf("pipeline step %s not found", target)
+	}
+	p.steps[idx] = step
+	return nil
+}
+
+// Skip removes the named step from the pipeline.
+func (p *Pipeline) Skip(target string) error {
+	idx := p.indexOf(target)
+	if idx < 0 {
+		return fmt.Errorf("pipeline step %s not found", target)
+	}
+	p.steps = append(p.steps[:idx], p.steps[idx+1:]...)
+	return nil
+}
+
+// Run executes the steps in order, skipping the ones whose condition does
+// not hold.
+func (p *Pipeline) Run(i *Installer, c *installContext) error {
+	for _, step := range p.steps {
+		if step.Condition != nil && !step.Condition(i) {
+			logrus.Debugf("pipeline step %s skipped, condition not met", step.Name)
+			continue
+		}
+		logrus.Debugf("running pipeline step %s", step.Name)
+		if err := step.Run(i, c); err != nil {
+			return fmt.Errorf("pipeline step %s failed: %v", step.Name, err)
+		}
+	}
+	return nil
+}
+
+// splitPipelinePlugins separates the pipeline customization plugins from the
+// hook plugins, so that transferPluginsToHooks only sees registered hook
+// types.
+func splitPipelinePlugins(plugins []v1.Plugin) (pipelinePlugins, hookPlugins []v1.Plugin) {
+	for _, plugin := range plugins {
+		if plugin.Spec.Type == PipelinePluginType {
+			pipelinePlugins = append(pipelinePlugins, plugin)
+			continue
+		}
+		hookPlugins = append(hookPlugins, plugin)
+	}
+	return pipelinePlugins, hookPlugins
+}
+
+// applyPipelinePlugins applies the Clusterfile pipeline customizations to
+// the pipeline: the plugin action selects the operation, the plugin scope
+// names the target step and, for inserted or replacement steps, the plugin
+// data is a shell command executed on master0 under the rootfs.
+func applyPipelinePlugins(p *Pipeline, plugins []v1.Plugin) error {
+	for _, plugin := range plugins {
+		var (
+			action = strings.TrimSpace(plugin.Spec.Action)
+			target = strings.TrimSpace(plugin.Spec.Scope)
+			data   = plugin.Spec.Data
+		)
+
+		if target == "" {
+			return fmt.Errorf("pipeline plugin %s: scope must name the target step, one of %v", plugin.Name, p.StepNames())
+		}
+
+		switch action {
+		case pipelineActionSkip:
+			if err := p.Skip(target); err != nil {
+				return fmt.Errorf("pipeline plugin %s: %v", plugin.Name, err)
+			}
+		case pipelineActionInsertBefore:
+			if err := p.InsertBefore(target, newShellStep(plugin.Name, data)); err != nil {
+				return fmt.Errorf("pipeline plugin %s: %v", plugin.Name, err)
+			}
+		case pipelineActionInsertAfter:
+			if err := p.InsertAfter(target, newShellStep(plugin.Name, data)); err != nil {
+				return fmt.Errorf("pipeline plugin %s: %v", plugin.Name, err)
+			}
+		case pipelineActionReplace:
+			if err := p.Replace(target, newShellStep(plugin.Name, data)); err != nil {
+				return fmt.Errorf("pipeline plugin %s: %v", plugin.Name, err)
+			}
+		default:
+			return fmt.Errorf("pipeline plugin %s: unknown action %s, expect one of skip, insert-before, insert-after, replace", plugin.Name, action)
+		}
+	}
+	return nil
+}
+
+// newShellStep wraps a shell command into a pipeline step running on
+// master0, the same way a cluster scope SHELL hook does.
+func newShellStep(name, data string) Step {
+	return Step{
+		Name: name,
+		Run: func(i *Installer, c *installContext) error {
+			return hookFactories[ShellHook](data, []net.IP{c.master0}, i.infraDriver, map[string]bool{})
+		},
+	}
+}
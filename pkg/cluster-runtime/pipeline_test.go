@@ -0,0 +1,76 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterruntime
+
+import (
+	"testing"
+
+	v1 "github.com/sealerio/sealer/types/api/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func testPipeline() *Pipeline {
+	return &Pipeline{steps: []Step{
+		{Name: "first"},
+		{Name: "second"},
+		{Name: "third"},
+	}}
+}
+
+func TestPipelineOrdering(t *testing.T) {
+	p := testPipeline()
+	assert.NoError(t, p.InsertBefore("second", Step{Name: "before-second"}))
+	assert.NoError(t, p.InsertAfter("third", Step{Name: "after-third"}))
+	assert.NoError(t, p.Replace("first", Step{Name: "replaced-first"}))
+	assert.NoError(t, p.Skip("second"))
+	assert.Equal(t, []string{"replaced-first", "before-second", "third", "after-third"}, p.StepNames())
+
+	assert.Error(t, p.InsertBefore("unknown", Step{Name: "x"}))
+	assert.Error(t, p.Skip("unknown"))
+}
+
+func TestApplyPipelinePlugins(t *testing.T) {
+	newPlugin := func(name, action, scope string) v1.Plugin {
+		plugin := v1.Plugin{}
+		plugin.Name = name
+		plugin.Spec.Type = PipelinePluginType
+		plugin.Spec.Action = action
+		plugin.Spec.Scope = scope
+		plugin.Spec.Data = "echo audit"
+		return plugin
+	}
+
+	p := testPipeline()
+	err := applyPipelinePlugins(p, []v1.Plugin{
+		newPlugin("audit", "insert-after", "second"),
+		newPlugin("skip-third", "skip", "third"),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"first", "second", "audit"}, p.StepNames())
+
+	assert.Error(t, applyPipelinePlugins(testPipeline(), []v1.Plugin{newPlugin("bad", "drop", "second")}))
+	assert.Error(t, applyPipelinePlugins(testPipeline(), []v1.Plugin{newPlugin("bad", "skip", "")}))
+}
+
+func TestSplitPipelinePlugins(t *testing.T) {
+	pipelinePlugin := v1.Plugin{}
+	pipelinePlugin.Spec.Type = PipelinePluginType
+	shellPlugin := v1.Plugin{}
+	shellPlugin.Spec.Type = string(ShellHook)
+
+	pipeline, hooks := splitPipelinePlugins([]v1.Plugin{pipelinePlugin, shellPlugin})
+	assert.Len(t, pipeline, 1)
+	assert.Len(t, hooks, 1)
+}
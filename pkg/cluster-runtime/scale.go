@@ -17,10 +17,13 @@ package clusterruntime
 import (
 	"fmt"
 	"net"
+	"time"
 
 	netutils "github.com/sealerio/sealer/utils/net"
 
 	"github.com/sealerio/sealer/common"
+	"github.com/sealerio/sealer/pkg/logger"
+	"github.com/sealerio/sealer/pkg/metrics"
 	"github.com/sealerio/sealer/pkg/registry"
 	"github.com/sealerio/sealer/pkg/runtime"
 	"github.com/sirupsen/logrus"
@@ -34,6 +37,11 @@ func (i *Installer) ScaleUp(newMasters, newWorkers []net.IP) (registry.Driver, r
 	all := append(newMasters, newWorkers...)
 	rootfs := i.infraDriver.GetClusterRootfsPath()
 
+	scaleUpStart := time.Now()
+	defer metrics.ObservePhase(i.infraDriver.GetClusterName(), "scale-up", scaleUpStart)
+	logger.SetPhase("scale-up")
+	defer logger.SetPhase("")
+
 	logrus.Debug("check ssh of new nodes")
 	_, err := CheckNodeSSH(i.infraDriver, append(newMasters, newWorkers...))
 	if err != nil {
@@ -65,6 +73,14 @@ func (i *Installer) ScaleUp(newMasters, newWorkers []net.IP) (registry.Driver, r
 		return nil, nil, err
 	}
 
+	if err := i.checkHostOSCompat(all); err != nil {
+		return nil, nil, err
+	}
+
+	if err := i.installOfflineOSPackages(all); err != nil {
+		return nil, nil, err
+	}
+
 	if err := i.containerRuntimeInstaller.InstallOn(all); err != nil {
 		return nil, nil, err
 	}
@@ -74,6 +90,10 @@ func (i *Installer) ScaleUp(newMasters, newWorkers []net.IP) (registry.Driver, r
 		return nil, nil, err
 	}
 
+	if err := i.distributeExtraCAs(all); err != nil {
+		return nil, nil, err
+	}
+
 	// reconcile registry node if local registry is ha mode.
 	if i.regConfig.LocalRegistry != nil && *i.regConfig.LocalRegistry.HA {
 		registryDeployHosts, err = registry.NewInstaller(netutils.RemoveIPs(masters, newMasters), i.regConfig.LocalRegistry, i.infraDriver, i.Distributor).Reconcile(masters)
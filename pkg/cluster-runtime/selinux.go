@@ -0,0 +1,164 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterruntime
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/sealerio/sealer/common"
+	osutils "github.com/sealerio/sealer/utils/os"
+)
+
+// detectSELinuxCmd prints the current enforcement state; hosts without
+// SELinux tooling report Disabled instead of failing.
+const detectSELinuxCmd = `getenforce 2>/dev/null || echo Disabled`
+
+// selinuxEnforcing is the getenforce output on an enforcing host.
+const selinuxEnforcing = "Enforcing"
+
+// selinuxModeCmds renders the commands moving a host from the detected
+// enforcement state to the requested permissive or disable mode: enforcement
+// is stopped immediately when active and the persistent config is updated so
+// the choice survives a reboot.
+func selinuxModeCmds(mode, state string) []string {
+	var cmds []string
+	if state == selinuxEnforcing {
+		cmds = append(cmds, "setenforce 0")
+	}
+
+	persistent := map[string]string{
+		common.SELinuxModePermissive: "permissive",
+		common.SELinuxModeDisable:    "disabled",
+	}[mode]
+	cmds = append(cmds,
+		fmt.Sprintf(`if [ -f /etc/selinux/config ]; then sed -i 's/^SELINUX=.*/SELINUX=%s/' /etc/selinux/config; fi`, persistent))
+	return cmds
+}
+
+// selinuxRelabelCmds labels the sealer dirs mounted into containers, most
+// importantly the local registry volume and its certs, so an enforcing policy
+// lets the containers read and write them.
+func selinuxRelabelCmds(rootfs string) []string {
+	var cmds []string
+	for _, dir := range []string{filepath.Join(rootfs, "registry"), filepath.Join(rootfs, "certs")} {
+		cmds = append(cmds, fmt.Sprintf(`if [ -d %[1]s ]; then chcon -R -t container_file_t %[1]s; fi`, dir))
+	}
+	return cmds
+}
+
+// containerdSELinuxCmd turns on enable_selinux in the containerd config
+// template shipped in the rootfs, before the install script applies it.
+func containerdSELinuxCmd(rootfs string) string {
+	config := filepath.Join(rootfs, "etc", "config.toml")
+	return fmt.Sprintf(`if [ -f %[1]s ]; then sed -i 's/enable_selinux *= *false/enable_selinux = true/' %[1]s; fi`, config)
+}
+
+// enableDockerSELinuxCmd sets selinux-enabled in the daemon.json template
+// under the local rootfs, the same file the registry mirror configuration is
+// merged into, and returns the command replicating it into the rootfs already
+// distributed to a host.
+func enableDockerSELinuxCmd(rootfs string) (string, error) {
+	daemonFile := filepath.Join(rootfs, "etc", "daemon.json")
+
+	conf := map[string]interface{}{}
+	if b, err := os.ReadFile(filepath.Clean(daemonFile)); err == nil && len(bytes.TrimSpace(b)) > 0 {
+		if err := json.Unmarshal(b, &conf); err != nil {
+			return "", fmt.Errorf("failed to load %s: %v", daemonFile, err)
+		}
+	}
+	conf["selinux-enabled"] = true
+
+	content, err := json.MarshalIndent(conf, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal %s: %v", daemonFile, err)
+	}
+	if err := osutils.NewCommonWriter(daemonFile).WriteFile(content); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(`mkdir -p %s && cat > %s <<'EOF'
+%s
+EOF`, filepath.Dir(daemonFile), daemonFile, content), nil
+}
+
+// configureSELinux implements the SELinuxMode preflight knob: "keep" (the
+// default) supports an enforcing host instead of fighting it, "permissive"
+// and "disable" lower enforcement for environments that want the historical
+// behavior. It runs before the container runtime so the rendered configs are
+// picked up by its install scripts.
+func (i *Installer) configureSELinux(hosts []net.IP) error {
+	mode := common.SELinuxModeKeep
+	if v, ok := i.infraDriver.GetClusterEnv()[common.EnvSELinuxMode]; ok && v != "" {
+		mode = v
+	}
+
+	switch mode {
+	case common.SELinuxModeKeep, common.SELinuxModePermissive, common.SELinuxModeDisable:
+	default:
+		return fmt.Errorf("unknown %s value %q, expect %q, %q or %q",
+			common.EnvSELinuxMode, mode, common.SELinuxModeKeep, common.SELinuxModePermissive, common.SELinuxModeDisable)
+	}
+
+	rootfs := i.infraDriver.GetClusterRootfsPath()
+
+	// render the runtime config changes once, they are identical per host
+	var runtimeCmds []string
+	if mode == common.SELinuxModeKeep {
+		switch i.containerRuntimeType {
+		case common.Docker:
+			cmd, err := enableDockerSELinuxCmd(rootfs)
+			if err != nil {
+				return err
+			}
+			runtimeCmds = []string{cmd}
+		case common.Containerd:
+			runtimeCmds = []string{containerdSELinuxCmd(rootfs)}
+		}
+	}
+
+	return i.infraDriver.Execute(hosts, func(host net.IP) error {
+		state, err := i.infraDriver.CmdToString(host, nil, detectSELinuxCmd, "")
+		if err != nil {
+			return fmt.Errorf("failed to detect SELinux state on host %s: %v", host, err)
+		}
+		state = strings.TrimSpace(state)
+
+		if mode != common.SELinuxModeKeep {
+			if err := i.infraDriver.CmdAsync(host, nil, selinuxModeCmds(mode, state)...); err != nil {
+				return fmt.Errorf("failed to set SELinux to %s on host %s: %v", mode, host, err)
+			}
+			return nil
+		}
+
+		if state != selinuxEnforcing {
+			return nil
+		}
+
+		logrus.Infof("host %s enforces SELinux, labeling sealer dirs and enabling selinux in the %s config", host, i.containerRuntimeType)
+		cmds := append(selinuxRelabelCmds(rootfs), runtimeCmds...)
+		if err := i.infraDriver.CmdAsync(host, nil, cmds...); err != nil {
+			return fmt.Errorf("failed to prepare SELinux enforcing host %s: %v", host, err)
+		}
+		return nil
+	})
+}
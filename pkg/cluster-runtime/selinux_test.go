@@ -0,0 +1,69 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterruntime
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sealerio/sealer/common"
+)
+
+func TestSelinuxModeCmds(t *testing.T) {
+	cmds := selinuxModeCmds(common.SELinuxModeDisable, selinuxEnforcing)
+	assert.Equal(t, "setenforce 0", cmds[0])
+	assert.Contains(t, cmds[1], "SELINUX=disabled")
+
+	// already not enforcing: only the persistent config changes
+	cmds = selinuxModeCmds(common.SELinuxModePermissive, "Disabled")
+	assert.Len(t, cmds, 1)
+	assert.Contains(t, cmds[0], "SELINUX=permissive")
+}
+
+func TestSelinuxRelabelCmds(t *testing.T) {
+	cmds := selinuxRelabelCmds("/var/lib/sealer/data/my-cluster/rootfs")
+	assert.Len(t, cmds, 2)
+	assert.Contains(t, cmds[0], "chcon -R -t container_file_t /var/lib/sealer/data/my-cluster/rootfs/registry")
+	assert.Contains(t, cmds[1], "/var/lib/sealer/data/my-cluster/rootfs/certs")
+}
+
+func TestEnableDockerSELinuxCmd(t *testing.T) {
+	rootfs := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(rootfs, "etc"), 0o755))
+	daemonFile := filepath.Join(rootfs, "etc", "daemon.json")
+	assert.NoError(t, os.WriteFile(daemonFile, []byte(`{"log-driver": "json-file"}`), 0o644))
+
+	cmd, err := enableDockerSELinuxCmd(rootfs)
+	assert.NoError(t, err)
+	assert.Contains(t, cmd, `"selinux-enabled": true`)
+
+	// the existing template keys survive the merge
+	updated, err := os.ReadFile(daemonFile)
+	assert.NoError(t, err)
+	assert.Contains(t, string(updated), `"log-driver": "json-file"`)
+	assert.Contains(t, string(updated), `"selinux-enabled": true`)
+
+	// broken template is reported, not overwritten
+	assert.NoError(t, os.WriteFile(daemonFile, []byte("{broken"), 0o644))
+	_, err = enableDockerSELinuxCmd(rootfs)
+	assert.Error(t, err)
+	kept, err := os.ReadFile(daemonFile)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(kept), "{broken"))
+}
@@ -0,0 +1,124 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterruntime
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"sigs.k8s.io/yaml"
+
+	"github.com/sealerio/sealer/pkg/infradriver"
+)
+
+const (
+	// SysctlHook declares kernel requirements (sysctls, modules, limits)
+	// in the plugin data instead of sed commands buried in rootfs scripts.
+	SysctlHook HookType = "SYSCTL"
+
+	// the files a SYSCTL hook owns on every target host; writing them
+	// whole keeps the hook idempotent across init, join and re-apply.
+	sysctlConfPath  = "/etc/sysctl.d/99-sealer.conf"
+	modulesConfPath = "/etc/modules-load.d/sealer.conf"
+	limitsConfPath  = "/etc/security/limits.d/99-sealer.conf"
+)
+
+// SysctlSpec is the YAML a SYSCTL plugin carries in its data field, e.g.:
+//
+//	sysctl:
+//	  net.ipv4.ip_forward: "1"
+//	  fs.inotify.max_user_watches: "1048576"
+//	modules:
+//	  - br_netfilter
+//	limits:
+//	  - "* soft nofile 65535"
+type SysctlSpec struct {
+	Sysctl  map[string]string `json:"sysctl,omitempty"`
+	Modules []string          `json:"modules,omitempty"`
+	Limits  []string          `json:"limits,omitempty"`
+}
+
+func parseSysctlSpec(data string) (SysctlSpec, error) {
+	var spec SysctlSpec
+	if err := yaml.UnmarshalStrict([]byte(data), &spec); err != nil {
+		return spec, fmt.Errorf("failed to parse SYSCTL plugin data: %v", err)
+	}
+	if len(spec.Sysctl) == 0 && len(spec.Modules) == 0 && len(spec.Limits) == 0 {
+		return spec, fmt.Errorf("SYSCTL plugin data declares no sysctl, modules or limits")
+	}
+	return spec, nil
+}
+
+// sysctlApplyCmds renders the idempotent command sequence installing the
+// spec on one host: each owned file is rewritten whole, modules are loaded
+// and the sysctls applied immediately so no reboot is needed.
+func sysctlApplyCmds(spec SysctlSpec) []string {
+	var cmds []string
+
+	if len(spec.Modules) > 0 {
+		cmds = append(cmds, fmt.Sprintf("echo \"%s\" > %s", strings.Join(spec.Modules, "\n"), modulesConfPath))
+		for _, module := range spec.Modules {
+			cmds = append(cmds, fmt.Sprintf("modprobe %s", module))
+		}
+	}
+
+	if len(spec.Sysctl) > 0 {
+		keys := make([]string, 0, len(spec.Sysctl))
+		for key := range spec.Sysctl {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		var lines []string
+		for _, key := range keys {
+			lines = append(lines, fmt.Sprintf("%s = %s", key, spec.Sysctl[key]))
+		}
+		cmds = append(cmds,
+			fmt.Sprintf("echo \"%s\" > %s", strings.Join(lines, "\n"), sysctlConfPath),
+			"sysctl --system > /dev/null")
+	}
+
+	if len(spec.Limits) > 0 {
+		cmds = append(cmds, fmt.Sprintf("echo \"%s\" > %s", strings.Join(spec.Limits, "\n"), limitsConfPath))
+	}
+
+	return cmds
+}
+
+func NewSysctlHook() HookFunc {
+	return func(data string, hosts []net.IP, driver infradriver.InfraDriver, extraOpts map[string]bool) error {
+		spec, err := parseSysctlSpec(data)
+		if err != nil {
+			return err
+		}
+
+		cmds := sysctlApplyCmds(spec)
+		for _, ip := range hosts {
+			logrus.Infof("start to apply kernel parameters on host %s", ip.String())
+			if err := driver.CmdAsync(ip, driver.GetHostEnv(ip), cmds...); err != nil {
+				return fmt.Errorf("failed to apply kernel parameters on host(%s): %v", ip.String(), err)
+			}
+		}
+
+		return nil
+	}
+}
+
+func init() {
+	Register(SysctlHook, NewSysctlHook())
+}
@@ -0,0 +1,61 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterruntime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSysctlSpec(t *testing.T) {
+	spec, err := parseSysctlSpec(`sysctl:
+  net.ipv4.ip_forward: "1"
+  fs.inotify.max_user_watches: "1048576"
+modules:
+  - br_netfilter
+limits:
+  - "* soft nofile 65535"
+`)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", spec.Sysctl["net.ipv4.ip_forward"])
+	assert.Equal(t, []string{"br_netfilter"}, spec.Modules)
+	assert.Equal(t, []string{"* soft nofile 65535"}, spec.Limits)
+
+	_, err = parseSysctlSpec("")
+	assert.Error(t, err, "empty data declares nothing")
+
+	_, err = parseSysctlSpec("sysctls:\n  net.ipv4.ip_forward: \"1\"\n")
+	assert.Error(t, err, "unknown fields are typos, reject them")
+}
+
+func TestSysctlApplyCmds(t *testing.T) {
+	cmds := sysctlApplyCmds(SysctlSpec{
+		Sysctl:  map[string]string{"net.ipv4.ip_forward": "1", "net.bridge.bridge-nf-call-iptables": "1"},
+		Modules: []string{"br_netfilter", "overlay"},
+		Limits:  []string{"* soft nofile 65535"},
+	})
+
+	assert.Equal(t, []string{
+		"echo \"br_netfilter\noverlay\" > /etc/modules-load.d/sealer.conf",
+		"modprobe br_netfilter",
+		"modprobe overlay",
+		"echo \"net.bridge.bridge-nf-call-iptables = 1\nnet.ipv4.ip_forward = 1\" > /etc/sysctl.d/99-sealer.conf",
+		"sysctl --system > /dev/null",
+		"echo \"* soft nofile 65535\" > /etc/security/limits.d/99-sealer.conf",
+	}, cmds)
+
+	assert.Empty(t, sysctlApplyCmds(SysctlSpec{}))
+}
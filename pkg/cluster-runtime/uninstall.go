@@ -18,6 +18,7 @@ import (
 	"net"
 
 	"github.com/sealerio/sealer/common"
+	"github.com/sealerio/sealer/pkg/checkpoint"
 	containerruntime "github.com/sealerio/sealer/pkg/container-runtime"
 	"github.com/sealerio/sealer/pkg/registry"
 )
@@ -26,7 +27,7 @@ func (i *Installer) UnInstall() error {
 	masters := i.infraDriver.GetHostIPListByRole(common.MASTER)
 	master0 := masters[0]
 	workers := getWorkerIPList(i.infraDriver)
-	all := append(masters, workers...)
+	all := append(append(masters, workers...), getDedicatedEtcdIPList(i.infraDriver)...)
 
 	_, err := CheckNodeSSH(i.infraDriver, all)
 	if err != nil {
@@ -102,5 +103,10 @@ func (i *Installer) UnInstall() error {
 		return err
 	}
 
-	return nil
+	// the cluster is gone, drop any apply checkpoint left behind.
+	cp, err := checkpoint.NewManager(i.infraDriver.GetClusterName())
+	if err != nil {
+		return err
+	}
+	return cp.Clear()
 }
@@ -0,0 +1,148 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterruntime
+
+import (
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/util/keyutil"
+
+	"github.com/sealerio/sealer/common"
+	"github.com/sealerio/sealer/pkg/clustercert"
+	"github.com/sealerio/sealer/pkg/clustercert/cert"
+	v1 "github.com/sealerio/sealer/types/api/v1"
+)
+
+// createClusterUsers creates the users declared in the Clusterfile once the
+// cluster serves: a credential each (client cert or service account token),
+// the RBAC bindings for their cluster roles and a ready-to-use kubeconfig
+// under ~/.sealer/users on the machine running sealer.
+func (i *Installer) createClusterUsers(master0 net.IP) error {
+	if len(i.ClusterUsers) == 0 {
+		return nil
+	}
+
+	pkiPath := filepath.Join(i.infraDriver.GetClusterRootfsPath(), "pki")
+	caPEM, err := os.ReadFile(filepath.Clean(cert.PathForCert(pkiPath, "ca")))
+	if err != nil {
+		return fmt.Errorf("failed to read cluster CA: %v", err)
+	}
+
+	serverURL := fmt.Sprintf("https://%s", net.JoinHostPort(master0.String(), "6443"))
+	outDir := filepath.Join(common.GetSealerWorkDir(), "users")
+	if err := os.MkdirAll(outDir, 0700); err != nil {
+		return err
+	}
+
+	for _, user := range i.ClusterUsers {
+		kubeConfigPath := filepath.Join(outDir, fmt.Sprintf("%s-%s.conf", i.infraDriver.GetClusterName(), user.Name))
+		switch user.Spec.Auth {
+		case "", v1.UserAuthCert:
+			err = i.createCertUser(user, pkiPath, serverURL, caPEM, kubeConfigPath)
+		case v1.UserAuthToken:
+			err = i.createTokenUser(user, master0, serverURL, caPEM, kubeConfigPath)
+		default:
+			err = fmt.Errorf("unknown auth %q", user.Spec.Auth)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to create cluster user %s: %v", user.Name, err)
+		}
+
+		if err := i.bindClusterRoles(user, master0); err != nil {
+			return fmt.Errorf("failed to bind cluster roles of user %s: %v", user.Name, err)
+		}
+		logrus.Infof("created cluster user %s, kubeconfig: %s", user.Name, kubeConfigPath)
+	}
+
+	return nil
+}
+
+// createCertUser mints a client certificate with the cluster CA, groups as
+// organizations, and writes the kubeconfig.
+func (i *Installer) createCertUser(user v1.User, pkiPath, serverURL string, caPEM []byte, kubeConfigPath string) error {
+	caCert, caKey, err := cert.NewCertificateFileManger(pkiPath, "ca").Read()
+	if err != nil {
+		return fmt.Errorf("failed to load cluster CA: %v", err)
+	}
+
+	generator, err := cert.NewCommonCertificateGenerator(cert.CertificateDescriptor{
+		CommonName:   user.Name,
+		Organization: user.Spec.Groups,
+		Year:         1,
+		Usages:       []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, caCert, caKey)
+	if err != nil {
+		return err
+	}
+	userCert, userKey, err := generator.Generate()
+	if err != nil {
+		return fmt.Errorf("failed to mint client certificate: %v", err)
+	}
+
+	keyPEM, err := keyutil.MarshalPrivateKeyToPEM(userKey)
+	if err != nil {
+		return err
+	}
+	config := clustercert.CreateWithCerts(serverURL, i.infraDriver.GetClusterName(), user.Name, caPEM, keyPEM, cert.EncodeCertPEM(userCert))
+	return clustercert.WriteToDisk(kubeConfigPath, config)
+}
+
+// createTokenUser creates a service account in kube-system and writes a
+// kubeconfig holding its token. `kubectl create token` serves short-lived
+// tokens on current kubernetes, the legacy secret token is the fallback.
+func (i *Installer) createTokenUser(user v1.User, master0 net.IP, serverURL string, caPEM []byte, kubeConfigPath string) error {
+	ensureSACmd := fmt.Sprintf("kubectl -n kube-system get sa %[1]s >/dev/null 2>&1 || kubectl -n kube-system create sa %[1]s", user.Name)
+	if err := i.infraDriver.CmdAsync(master0, nil, ensureSACmd); err != nil {
+		return fmt.Errorf("failed to create service account on master0(%s): %v", master0, err)
+	}
+
+	tokenCmd := fmt.Sprintf("kubectl -n kube-system create token %[1]s --duration=8760h 2>/dev/null"+
+		" || kubectl -n kube-system get secret $(kubectl -n kube-system get sa %[1]s -o jsonpath='{.secrets[0].name}') -o jsonpath='{.data.token}' | base64 -d", user.Name)
+	token, err := i.infraDriver.CmdToString(master0, nil, tokenCmd, "")
+	if err != nil {
+		return fmt.Errorf("failed to obtain token on master0(%s): %v", master0, err)
+	}
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return fmt.Errorf("got an empty token for service account %s", user.Name)
+	}
+
+	config := clustercert.CreateWithToken(serverURL, i.infraDriver.GetClusterName(), user.Name, caPEM, token)
+	return clustercert.WriteToDisk(kubeConfigPath, config)
+}
+
+// bindClusterRoles idempotently binds the declared cluster roles to the user
+// subject: the certificate user name, or the service account for token auth.
+func (i *Installer) bindClusterRoles(user v1.User, master0 net.IP) error {
+	subject := fmt.Sprintf("--user=%s", user.Name)
+	if user.Spec.Auth == v1.UserAuthToken {
+		subject = fmt.Sprintf("--serviceaccount=kube-system:%s", user.Name)
+	}
+
+	for _, role := range user.Spec.ClusterRoles {
+		bindCmd := fmt.Sprintf("kubectl create clusterrolebinding sealer-user-%s-%s --clusterrole=%s %s --dry-run=client -o yaml | kubectl apply -f -",
+			user.Name, role, role, subject)
+		if err := i.infraDriver.CmdAsync(master0, nil, bindCmd); err != nil {
+			return fmt.Errorf("failed to bind cluster role %s: %v", role, err)
+		}
+	}
+	return nil
+}
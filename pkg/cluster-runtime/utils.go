@@ -22,29 +22,48 @@ import (
 	"github.com/sealerio/sealer/common"
 	"github.com/sealerio/sealer/pkg/imagedistributor"
 	"github.com/sealerio/sealer/pkg/infradriver"
+	strUtils "github.com/sealerio/sealer/utils/strings"
 
 	"github.com/sirupsen/logrus"
 )
 
+// getWorkerIPList returns every host that does not carry the master role;
+// hosts with only custom roles join the cluster as workers, except dedicated
+// etcd hosts, which serve the cluster store without joining kubernetes.
 func getWorkerIPList(infraDriver infradriver.InfraDriver) []net.IP {
 	masters := make(map[string]bool)
 	for _, master := range infraDriver.GetHostIPListByRole(common.MASTER) {
 		masters[master.String()] = true
 	}
-	all := infraDriver.GetHostIPList()
-	workers := make([]net.IP, len(all)-len(masters))
+	etcds := make(map[string]bool)
+	for _, etcd := range getDedicatedEtcdIPList(infraDriver) {
+		etcds[etcd.String()] = true
+	}
 
-	index := 0
-	for _, ip := range all {
-		if !masters[ip.String()] {
-			workers[index] = ip
-			index++
+	var workers []net.IP
+	for _, ip := range infraDriver.GetHostIPList() {
+		if !masters[ip.String()] && !etcds[ip.String()] {
+			workers = append(workers, ip)
 		}
 	}
 
 	return workers
 }
 
+// getDedicatedEtcdIPList returns the hosts carrying the etcd role without a
+// master or node role: they run external etcd members only. An etcd role
+// combined with master or node keeps the host in the kubernetes cluster.
+func getDedicatedEtcdIPList(infraDriver infradriver.InfraDriver) []net.IP {
+	var etcds []net.IP
+	for _, ip := range infraDriver.GetHostIPListByRole(common.ETCD) {
+		roles := infraDriver.GetRoleListByHostIP(ip.String())
+		if !strUtils.IsInSlice(common.MASTER, roles) && !strUtils.IsInSlice(common.NODE, roles) {
+			etcds = append(etcds, ip)
+		}
+	}
+	return etcds
+}
+
 // LoadToRegistry just load container image to local registry
 func LoadToRegistry(infraDriver infradriver.InfraDriver, distributor imagedistributor.Distributor) error {
 	regConfig := infraDriver.GetClusterRegistry()
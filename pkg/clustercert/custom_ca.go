@@ -0,0 +1,113 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clustercert
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"time"
+
+	certutil "k8s.io/client-go/util/cert"
+	"k8s.io/client-go/util/keyutil"
+
+	"github.com/sealerio/sealer/pkg/clustercert/cert"
+)
+
+// LoadCustomCA reads a user supplied CA pair from the given files and
+// validates it can issue cluster certificates: the certificate must be a CA
+// (a corporate root or an intermediate), currently valid, and the key must
+// belong to it. When the cert file carries a chain the first certificate is
+// the issuing one.
+func LoadCustomCA(certFile, keyFile string) (*x509.Certificate, crypto.Signer, error) {
+	certs, err := certutil.CertsFromFile(certFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load custom CA cert(%s): %v", certFile, err)
+	}
+	caCert := certs[0]
+
+	privateKey, err := keyutil.PrivateKeyFromFile(keyFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load custom CA key(%s): %v", keyFile, err)
+	}
+	var caKey crypto.Signer
+	switch k := privateKey.(type) {
+	case *rsa.PrivateKey:
+		caKey = k
+	case *ecdsa.PrivateKey:
+		caKey = k
+	default:
+		return nil, nil, fmt.Errorf("unsupported custom CA key type in %s, only RSA and ECDSA keys work", keyFile)
+	}
+
+	if !caCert.IsCA {
+		return nil, nil, fmt.Errorf("custom CA cert(%s) is not a CA certificate", certFile)
+	}
+	now := time.Now()
+	if now.Before(caCert.NotBefore) || now.After(caCert.NotAfter) {
+		return nil, nil, fmt.Errorf("custom CA cert(%s) is not valid now, validity %s to %s", certFile, caCert.NotBefore, caCert.NotAfter)
+	}
+
+	certPub, err := x509.MarshalPKIXPublicKey(caCert.PublicKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to compare custom CA key pair: %v", err)
+	}
+	keyPub, err := x509.MarshalPKIXPublicKey(caKey.Public())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to compare custom CA key pair: %v", err)
+	}
+	if !bytes.Equal(certPub, keyPub) {
+		return nil, nil, fmt.Errorf("custom CA key(%s) does not belong to cert(%s)", keyFile, certFile)
+	}
+
+	return caCert, caKey, nil
+}
+
+// SeedCustomCA places a user supplied CA pair as the kubernetes and etcd CA
+// under the given pki dirs, so GenerateAllKubernetesCerts signs every
+// certificate with it instead of creating self-signed CAs and the cluster
+// chains to corporate PKI. Seeding is idempotent; a pki dir that already
+// holds a different CA fails rather than being overwritten. The front-proxy
+// CA stays generated: it is a separate trust domain the apiserver must not
+// share with client certificates.
+func SeedCustomCA(certFile, keyFile string, pkiDirs ...string) error {
+	caCert, caKey, err := LoadCustomCA(certFile, keyFile)
+	if err != nil {
+		return err
+	}
+
+	for _, dir := range pkiDirs {
+		manager := cert.NewCertificateFileManger(dir, "ca")
+		if _, err := os.Stat(cert.PathForCert(dir, "ca")); err == nil {
+			existing, _, err := manager.Read()
+			if err != nil {
+				return fmt.Errorf("failed to load existing CA under %s: %v", dir, err)
+			}
+			if !existing.Equal(caCert) {
+				return fmt.Errorf("pki dir %s already holds a different CA, refusing to overwrite it", dir)
+			}
+			continue
+		}
+		if err := manager.Write(caCert, caKey); err != nil {
+			return fmt.Errorf("failed to seed custom CA under %s: %v", dir, err)
+		}
+	}
+
+	return nil
+}
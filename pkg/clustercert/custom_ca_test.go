@@ -0,0 +1,120 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clustercert
+
+import (
+	"crypto/x509"
+	"path/filepath"
+	"testing"
+
+	"github.com/sealerio/sealer/pkg/clustercert/cert"
+)
+
+// writeTestCA generates a CA pair under dir with the given name and returns
+// the cert and key file paths.
+func writeTestCA(t *testing.T, dir, name string) (string, string) {
+	t.Helper()
+	generator := cert.NewAuthorityCertificateGenerator(cert.CertificateDescriptor{
+		CommonName: "corp-root-ca",
+		Year:       100,
+	})
+	caCert, caKey, err := generator.Generate()
+	if err != nil {
+		t.Fatalf("failed to generate test CA: %v", err)
+	}
+	if err := cert.NewCertificateFileManger(dir, name).Write(caCert, caKey); err != nil {
+		t.Fatalf("failed to write test CA: %v", err)
+	}
+	return cert.PathForCert(dir, name), cert.PathForKey(dir, name)
+}
+
+func TestLoadCustomCA(t *testing.T) {
+	caDir := t.TempDir()
+	certFile, keyFile := writeTestCA(t, caDir, "ca")
+
+	caCert, _, err := LoadCustomCA(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("LoadCustomCA() error = %v", err)
+	}
+	if !caCert.IsCA {
+		t.Errorf("LoadCustomCA() returned a non CA certificate")
+	}
+
+	// a leaf certificate signed by the CA must be rejected
+	loadedCA, loadedKey, err := cert.NewCertificateFileManger(caDir, "ca").Read()
+	if err != nil {
+		t.Fatalf("failed to read test CA back: %v", err)
+	}
+	generator, err := cert.NewCommonCertificateGenerator(cert.CertificateDescriptor{
+		CommonName: "leaf",
+		Year:       100,
+		Usages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, loadedCA, loadedKey)
+	if err != nil {
+		t.Fatalf("failed to build leaf generator: %v", err)
+	}
+	leafCert, leafKey, err := generator.Generate()
+	if err != nil {
+		t.Fatalf("failed to generate leaf cert: %v", err)
+	}
+	if err := cert.NewCertificateFileManger(caDir, "leaf").Write(leafCert, leafKey); err != nil {
+		t.Fatalf("failed to write leaf cert: %v", err)
+	}
+	if _, _, err := LoadCustomCA(cert.PathForCert(caDir, "leaf"), cert.PathForKey(caDir, "leaf")); err == nil {
+		t.Errorf("LoadCustomCA() accepted a non CA certificate")
+	}
+
+	// a key that does not belong to the cert must be rejected
+	otherDir := t.TempDir()
+	_, otherKeyFile := writeTestCA(t, otherDir, "ca")
+	if _, _, err := LoadCustomCA(certFile, otherKeyFile); err == nil {
+		t.Errorf("LoadCustomCA() accepted a mismatched key pair")
+	}
+}
+
+func TestSeedCustomCA(t *testing.T) {
+	caDir := t.TempDir()
+	certFile, keyFile := writeTestCA(t, caDir, "ca")
+
+	pkiDir := t.TempDir()
+	etcdDir := filepath.Join(pkiDir, "etcd")
+	if err := SeedCustomCA(certFile, keyFile, pkiDir, etcdDir); err != nil {
+		t.Fatalf("SeedCustomCA() error = %v", err)
+	}
+
+	seeded, _, err := cert.NewCertificateFileManger(pkiDir, "ca").Read()
+	if err != nil {
+		t.Fatalf("failed to read seeded CA: %v", err)
+	}
+	custom, _, err := LoadCustomCA(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("LoadCustomCA() error = %v", err)
+	}
+	if !seeded.Equal(custom) {
+		t.Errorf("seeded CA differs from the supplied one")
+	}
+
+	// seeding the same CA again is a no-op
+	if err := SeedCustomCA(certFile, keyFile, pkiDir, etcdDir); err != nil {
+		t.Errorf("SeedCustomCA() is not idempotent: %v", err)
+	}
+
+	// a pki dir holding a different CA must not be overwritten
+	otherDir := t.TempDir()
+	otherCertFile, otherKeyFile := writeTestCA(t, otherDir, "ca")
+	if err := SeedCustomCA(otherCertFile, otherKeyFile, pkiDir); err == nil {
+		t.Errorf("SeedCustomCA() overwrote an existing different CA")
+	}
+}
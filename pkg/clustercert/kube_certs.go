@@ -396,6 +396,64 @@ func getEtcdCertificateConfig(etcdCertPath, certPath, nodeName string, nodeIP ne
 	return etcdCert
 }
 
+// GenerateEtcdMemberCerts signs a server and peer certificate pair for one
+// external etcd member with the etcd CA under etcdCertPath, writing the pair
+// to memberCertPath. The CA is created on first use and reused afterwards, so
+// every member and the kubernetes certs end up under the same authority.
+func GenerateEtcdMemberCerts(etcdCertPath, memberCertPath, nodeName string, nodeIP net.IP) error {
+	altNames := cert.AltNames{
+		DNSNames: map[string]string{
+			"localhost": "localhost",
+			nodeName:    nodeName,
+		},
+		IPs: map[string]net.IP{
+			net.IPv4(127, 0, 0, 1).String(): net.IPv4(127, 0, 0, 1),
+			nodeIP.String():                 nodeIP,
+			net.IPv6loopback.String():       net.IPv6loopback,
+		},
+	}
+
+	memberCert := CertificateConfigFamily{
+		certPath: etcdCertPath,
+		caConfig: CertificateConfig{
+			certName: "ca",
+			descriptor: &cert.CertificateDescriptor{
+				CommonName:   "etcd-ca",
+				Organization: nil,
+				Year:         100,
+				AltNames:     cert.AltNames{},
+				Usages:       nil,
+			},
+		},
+		commonConfig: []CertificateConfig{
+			{
+				certPath: memberCertPath,
+				certName: "server",
+				descriptor: &cert.CertificateDescriptor{
+					CommonName:   nodeName,
+					Organization: nil,
+					Year:         100,
+					AltNames:     altNames,
+					Usages:       []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+				},
+			},
+			{
+				certPath: memberCertPath,
+				certName: "peer",
+				descriptor: &cert.CertificateDescriptor{
+					CommonName:   nodeName,
+					Organization: nil,
+					Year:         100,
+					AltNames:     altNames,
+					Usages:       []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+				},
+			},
+		},
+	}
+
+	return memberCert.GenerateAll()
+}
+
 func getFrontProxyCertificateConfig(certPath string) CertificateConfigFamily {
 	return CertificateConfigFamily{
 		certPath: certPath,
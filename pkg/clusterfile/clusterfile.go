@@ -40,6 +40,12 @@ const (
 	ClusterfileConfigMapNamespace = "kube-system"
 	ClusterfileConfigMapName      = "sealer-clusterfile"
 	ClusterfileConfigMapDataName  = "Clusterfile"
+
+	// ClusterfileSecretName is the authoritative copy of the applied
+	// Clusterfile. It lives in a Secret since the Clusterfile carries
+	// ssh credentials; the ConfigMap of older sealer versions is only
+	// kept as a read fallback.
+	ClusterfileSecretName = "sealer-clusterfile"
 )
 
 type Interface interface {
@@ -47,6 +53,8 @@ type Interface interface {
 	SetCluster(v2.Cluster)
 	SetApplication(app v2.Application)
 	GetConfigs() []v1.Config
+	GetSecrets() []v1.Secret
+	GetUsers() []v1.User
 	GetPlugins() []v1.Plugin
 	GetApplication() *v2.Application
 	GetKubeadmConfig() *kubeadm.KubeadmConfig
@@ -62,6 +70,8 @@ type SaveOptions struct {
 type ClusterFile struct {
 	cluster       *v2.Cluster
 	configs       []v1.Config
+	secrets       []v1.Secret
+	users         []v1.User
 	kubeadmConfig kubeadm.KubeadmConfig
 	plugins       []v1.Plugin
 	app           *v2.Application
@@ -84,6 +94,14 @@ func (c *ClusterFile) GetConfigs() []v1.Config {
 	return c.configs
 }
 
+func (c *ClusterFile) GetSecrets() []v1.Secret {
+	return c.secrets
+}
+
+func (c *ClusterFile) GetUsers() []v1.User {
+	return c.users
+}
+
 func (c *ClusterFile) GetApplication() *v2.Application {
 	logrus.Debugf("loaded application from clusterfile: %+v\n", c.app)
 	return c.app
@@ -112,7 +130,14 @@ func (c *ClusterFile) SaveAll(opts SaveOptions) error {
 		return fmt.Errorf("failed to mkdir %s: %v", fileName, err)
 	}
 
-	cluster, err := yaml.Marshal(c.cluster)
+	// persist a copy with encrypted ssh credentials, the in-memory
+	// cluster keeps the clear-text ones
+	clusterToSave := c.cluster.DeepCopy()
+	if err := encryptClusterCredentials(clusterToSave); err != nil {
+		return err
+	}
+
+	cluster, err := yaml.Marshal(clusterToSave)
 	if err != nil {
 		return err
 	}
@@ -136,6 +161,32 @@ func (c *ClusterFile) SaveAll(opts SaveOptions) error {
 		}
 	}
 
+	if len(c.secrets) != 0 {
+		for _, s := range c.secrets {
+			// persist a copy with encrypted literal values, only refs and
+			// ciphertext ever hit the disk
+			secretToSave := s.DeepCopy()
+			if err := encryptSecretValues(secretToSave); err != nil {
+				return err
+			}
+			secret, err := yaml.Marshal(secretToSave)
+			if err != nil {
+				return err
+			}
+			clusterfileBytes = append(clusterfileBytes, secret)
+		}
+	}
+
+	if len(c.users) != 0 {
+		for _, u := range c.users {
+			user, err := yaml.Marshal(u)
+			if err != nil {
+				return err
+			}
+			clusterfileBytes = append(clusterfileBytes, user)
+		}
+	}
+
 	if len(c.plugins) != 0 {
 		for _, p := range c.plugins {
 			plugin, err = yaml.Marshal(p)
@@ -208,25 +259,37 @@ func saveToCluster(data []byte, confPath string) error {
 		return fmt.Errorf("failed to new k8s runtime client via adminconf: %v", err)
 	}
 
-	cm := &corev1.ConfigMap{
+	secret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      ClusterfileConfigMapName,
+			Name:      ClusterfileSecretName,
 			Namespace: ClusterfileConfigMapNamespace,
 		},
-		Data: map[string]string{ClusterfileConfigMapDataName: string(data)},
+		Data: map[string][]byte{ClusterfileConfigMapDataName: data},
 	}
 
 	ctx := context.Background()
-	if err := cli.Create(ctx, cm, &client.CreateOptions{}); err != nil {
+	if err := cli.Create(ctx, secret, &client.CreateOptions{}); err != nil {
 		if !apierrors.IsAlreadyExists(err) {
-			return fmt.Errorf("unable to create configmap: %v", err)
+			return fmt.Errorf("unable to create clusterfile secret: %v", err)
 		}
 
-		if err := cli.Update(ctx, cm, &client.UpdateOptions{}); err != nil {
-			return fmt.Errorf("unable to update configmap: %v", err)
+		if err := cli.Update(ctx, secret, &client.UpdateOptions{}); err != nil {
+			return fmt.Errorf("unable to update clusterfile secret: %v", err)
 		}
 	}
 
+	// best effort migration: drop the configmap written by older sealer
+	// versions, the secret above is the authoritative copy now
+	legacyCM := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ClusterfileConfigMapName,
+			Namespace: ClusterfileConfigMapNamespace,
+		},
+	}
+	if err := cli.Delete(ctx, legacyCM, &client.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		logrus.Debugf("failed to remove legacy clusterfile configmap: %v", err)
+	}
+
 	return nil
 }
 
@@ -277,12 +340,19 @@ func getClusterfileFromCluster() (*ClusterFile, error) {
 		return nil, err
 	}
 
-	cm, err := cli.ConfigMap(ClusterfileConfigMapNamespace).Get(context.TODO(), ClusterfileConfigMapName, metav1.GetOptions{})
-	if err != nil {
-		return nil, err
+	var data string
+	secret, err := cli.Secret(ClusterfileConfigMapNamespace).Get(context.TODO(), ClusterfileSecretName, metav1.GetOptions{})
+	if err == nil {
+		data = string(secret.Data[ClusterfileConfigMapDataName])
+	} else {
+		// fall back to the configmap written by older sealer versions
+		cm, cmErr := cli.ConfigMap(ClusterfileConfigMapNamespace).Get(context.TODO(), ClusterfileConfigMapName, metav1.GetOptions{})
+		if cmErr != nil {
+			return nil, err
+		}
+		data = cm.Data[ClusterfileConfigMapDataName]
 	}
 
-	data := cm.Data[ClusterfileConfigMapDataName]
 	if len(data) > 0 {
 		err = decodeClusterFile(bytes.NewReader([]byte(data)), clusterFile)
 		if err != nil {
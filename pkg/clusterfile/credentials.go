@@ -0,0 +1,100 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterfile
+
+import (
+	"fmt"
+
+	v1 "github.com/sealerio/sealer/types/api/v1"
+	v2 "github.com/sealerio/sealer/types/api/v2"
+	"github.com/sealerio/sealer/utils/hash"
+)
+
+// encryptClusterCredentials encrypts the ssh password and private key
+// passphrase of the cluster and all its hosts in place, so a persisted
+// Clusterfile never carries clear-text credentials. The key lives in
+// ~/.sealer, see utils/hash.
+func encryptClusterCredentials(cluster *v2.Cluster) error {
+	if err := encryptSSHCredentials(&cluster.Spec.SSH); err != nil {
+		return err
+	}
+	for i := range cluster.Spec.Hosts {
+		if err := encryptSSHCredentials(&cluster.Spec.Hosts[i].SSH); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decryptClusterCredentials is the inverse of
+// encryptClusterCredentials, applied transparently on load.
+func decryptClusterCredentials(cluster *v2.Cluster) error {
+	if err := decryptSSHCredentials(&cluster.Spec.SSH); err != nil {
+		return err
+	}
+	for i := range cluster.Spec.Hosts {
+		if err := decryptSSHCredentials(&cluster.Spec.Hosts[i].SSH); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func encryptSSHCredentials(ssh *v1.SSH) error {
+	if ssh.Encrypted {
+		return nil
+	}
+	if ssh.Passwd == "" && ssh.PkPasswd == "" {
+		return nil
+	}
+	if ssh.Passwd != "" {
+		passwd, err := hash.AesEncrypt([]byte(ssh.Passwd))
+		if err != nil {
+			return fmt.Errorf("failed to encrypt ssh password: %v", err)
+		}
+		ssh.Passwd = passwd
+	}
+	if ssh.PkPasswd != "" {
+		pkPasswd, err := hash.AesEncrypt([]byte(ssh.PkPasswd))
+		if err != nil {
+			return fmt.Errorf("failed to encrypt private key passphrase: %v", err)
+		}
+		ssh.PkPasswd = pkPasswd
+	}
+	ssh.Encrypted = true
+	return nil
+}
+
+func decryptSSHCredentials(ssh *v1.SSH) error {
+	if !ssh.Encrypted {
+		return nil
+	}
+	if ssh.Passwd != "" {
+		passwd, err := hash.AesDecrypt([]byte(ssh.Passwd))
+		if err != nil {
+			return fmt.Errorf("failed to decrypt ssh password: %v", err)
+		}
+		ssh.Passwd = passwd
+	}
+	if ssh.PkPasswd != "" {
+		pkPasswd, err := hash.AesDecrypt([]byte(ssh.PkPasswd))
+		if err != nil {
+			return fmt.Errorf("failed to decrypt private key passphrase: %v", err)
+		}
+		ssh.PkPasswd = pkPasswd
+	}
+	ssh.Encrypted = false
+	return nil
+}
@@ -30,8 +30,10 @@ import (
 	"k8s.io/kubelet/config/v1beta1"
 	"k8s.io/kubernetes/cmd/kubeadm/app/apis/kubeadm/v1beta3"
 	kubeadmConstants "k8s.io/kubernetes/cmd/kubeadm/app/constants"
+	strictyaml "sigs.k8s.io/yaml"
 
 	"github.com/sealerio/sealer/common"
+	"github.com/sealerio/sealer/pkg/migration"
 	"github.com/sealerio/sealer/types/api/constants"
 	v1 "github.com/sealerio/sealer/types/api/v1"
 	v2 "github.com/sealerio/sealer/types/api/v2"
@@ -73,13 +75,40 @@ func decodeClusterFile(reader io.Reader, clusterfile *ClusterFile) error {
 		case constants.ClusterKind:
 			var cluster v2.Cluster
 
-			if err := yaml.Unmarshal(ext.Raw, &cluster); err != nil {
-				return fmt.Errorf("failed to decode %s[%s]: %v", metaType.Kind, metaType.APIVersion, err)
+			if migration.IsLegacyClusterAPIVersion(metaType.APIVersion) {
+				// Clusterfiles written by sealer before 0.9 carry the v1
+				// cluster spec, convert it so old clusters can be taken
+				// over without being recreated.
+				var oldCluster v1.Cluster
+				if err := yaml.Unmarshal(ext.Raw, &oldCluster); err != nil {
+					return fmt.Errorf("failed to decode %s[%s]: %v", metaType.Kind, metaType.APIVersion, err)
+				}
+
+				converted, err := migration.ConvertV1Cluster(&oldCluster)
+				if err != nil {
+					return fmt.Errorf("failed to migrate %s[%s]: %v", metaType.Kind, metaType.APIVersion, err)
+				}
+				cluster = *converted
+			} else {
+				// strict decoding reports unknown fields right away
+				// instead of silently dropping them
+				if err := strictyaml.UnmarshalStrict(ext.Raw, &cluster); err != nil {
+					return fmt.Errorf("failed to decode %s[%s]: %v", metaType.Kind, metaType.APIVersion, err)
+				}
 			}
+
 			if err := checkAndFillCluster(&cluster); err != nil {
 				return fmt.Errorf("failed to check and complete cluster: %v", err)
 			}
 
+			if err := decryptClusterCredentials(&cluster); err != nil {
+				return fmt.Errorf("failed to decrypt cluster credentials: %v", err)
+			}
+
+			if err := validateCluster(&cluster); err != nil {
+				return err
+			}
+
 			clusterfile.cluster = &cluster
 		case constants.ConfigKind:
 			var cfg v1.Config
@@ -97,6 +126,34 @@ func decodeClusterFile(reader io.Reader, clusterfile *ClusterFile) error {
 			}
 
 			clusterfile.configs = append(clusterfile.configs, cfg)
+		case constants.SecretKind:
+			var sec v1.Secret
+
+			if err := strictyaml.UnmarshalStrict(ext.Raw, &sec); err != nil {
+				return fmt.Errorf("failed to decode %s[%s]: %v", metaType.Kind, metaType.APIVersion, err)
+			}
+
+			if err := validateSecret(&sec); err != nil {
+				return err
+			}
+
+			if err := decryptSecretValues(&sec); err != nil {
+				return fmt.Errorf("failed to decrypt secret %s: %v", sec.Name, err)
+			}
+
+			clusterfile.secrets = append(clusterfile.secrets, sec)
+		case constants.UserKind:
+			var user v1.User
+
+			if err := strictyaml.UnmarshalStrict(ext.Raw, &user); err != nil {
+				return fmt.Errorf("failed to decode %s[%s]: %v", metaType.Kind, metaType.APIVersion, err)
+			}
+
+			if err := validateUser(&user); err != nil {
+				return err
+			}
+
+			clusterfile.users = append(clusterfile.users, user)
 		case constants.PluginKind:
 			var plu v1.Plugin
 
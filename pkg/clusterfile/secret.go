@@ -0,0 +1,200 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	v1 "github.com/sealerio/sealer/types/api/v1"
+	"github.com/sealerio/sealer/utils/hash"
+)
+
+const defaultVaultTokenEnv = "VAULT_TOKEN"
+
+var vaultHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// validateSecret rejects entries without a name or with an ambiguous source.
+func validateSecret(secret *v1.Secret) error {
+	for _, value := range secret.Spec.Values {
+		if value.Name == "" {
+			return fmt.Errorf("failed to decode secret %s: value name is empty", secret.Name)
+		}
+
+		sources := 0
+		if value.Value != "" {
+			sources++
+		}
+		if value.ValueFromEnv != "" {
+			sources++
+		}
+		if value.ValueFromFile != "" {
+			sources++
+		}
+		if value.ValueFromVault != nil {
+			sources++
+		}
+		if sources != 1 {
+			return fmt.Errorf("failed to decode secret %s: value %s must set exactly one of value, valueFromEnv, valueFromFile, valueFromVault", secret.Name, value.Name)
+		}
+	}
+	return nil
+}
+
+// encryptSecretValues encrypts the literal values of the secret in place,
+// like the ssh credentials, so a persisted Clusterfile never carries
+// clear-text secrets; ref style values hold no secret material and are
+// left untouched.
+func encryptSecretValues(secret *v1.Secret) error {
+	for i := range secret.Spec.Values {
+		value := &secret.Spec.Values[i]
+		if value.Value == "" || value.Encrypted {
+			continue
+		}
+		encrypted, err := hash.AesEncrypt([]byte(value.Value))
+		if err != nil {
+			return fmt.Errorf("failed to encrypt secret value %s: %v", value.Name, err)
+		}
+		value.Value = encrypted
+		value.Encrypted = true
+	}
+	return nil
+}
+
+// decryptSecretValues is the inverse of encryptSecretValues, applied
+// transparently on load.
+func decryptSecretValues(secret *v1.Secret) error {
+	for i := range secret.Spec.Values {
+		value := &secret.Spec.Values[i]
+		if !value.Encrypted {
+			continue
+		}
+		decrypted, err := hash.AesDecrypt([]byte(value.Value))
+		if err != nil {
+			return fmt.Errorf("failed to decrypt secret value %s: %v", value.Name, err)
+		}
+		value.Value = decrypted
+		value.Encrypted = false
+	}
+	return nil
+}
+
+// ResolveSecrets resolves every secret value at apply time and returns them
+// as "name=value" entries, ready for InfraDriver.AddClusterEnv: that makes
+// them available to Config data, app templates and shell hooks without
+// ever being written back to the saved Clusterfile.
+func ResolveSecrets(secrets []v1.Secret) ([]string, error) {
+	var envs []string
+	for i := range secrets {
+		for _, value := range secrets[i].Spec.Values {
+			resolved, err := resolveSecretValue(&value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve secret %s: %v", secrets[i].Name, err)
+			}
+			envs = append(envs, fmt.Sprintf("%s=%s", value.Name, resolved))
+		}
+	}
+	return envs, nil
+}
+
+func resolveSecretValue(value *v1.SecretValue) (string, error) {
+	switch {
+	case value.Value != "":
+		return value.Value, nil
+	case value.ValueFromEnv != "":
+		env, ok := os.LookupEnv(value.ValueFromEnv)
+		if !ok {
+			return "", fmt.Errorf("env var %s for value %s is not set", value.ValueFromEnv, value.Name)
+		}
+		return env, nil
+	case value.ValueFromFile != "":
+		content, err := os.ReadFile(filepath.Clean(value.ValueFromFile))
+		if err != nil {
+			return "", fmt.Errorf("failed to read file for value %s: %v", value.Name, err)
+		}
+		return strings.TrimRight(string(content), "\n"), nil
+	case value.ValueFromVault != nil:
+		return resolveVaultValue(value.Name, value.ValueFromVault)
+	default:
+		return "", fmt.Errorf("value %s has no source", value.Name)
+	}
+}
+
+// resolveVaultValue fetches one key of a Vault KV secret over its HTTP API,
+// supporting both KV v2 (data.data) and KV v1 (data) response layouts.
+func resolveVaultValue(name string, ref *v1.VaultValueRef) (string, error) {
+	tokenEnv := ref.TokenEnv
+	if tokenEnv == "" {
+		tokenEnv = defaultVaultTokenEnv
+	}
+	token, ok := os.LookupEnv(tokenEnv)
+	if !ok {
+		return "", fmt.Errorf("vault token env var %s for value %s is not set", tokenEnv, name)
+	}
+
+	url := fmt.Sprintf("%s/v1/%s", strings.TrimRight(ref.Address, "/"), strings.TrimLeft(ref.Path, "/"))
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", token)
+
+	resp, err := vaultHTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to reach vault for value %s: %v", name, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault returned status %d for value %s", resp.StatusCode, name)
+	}
+
+	var raw struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return "", fmt.Errorf("failed to decode vault response for value %s: %v", name, err)
+	}
+
+	// KV v2 nests the key/value pairs one level deeper than KV v1.
+	data := make(map[string]interface{})
+	if nested, ok := raw.Data["data"]; ok {
+		if err := json.Unmarshal(nested, &data); err == nil && len(data) > 0 {
+			if v, ok := data[ref.Key]; ok {
+				return fmt.Sprintf("%v", v), nil
+			}
+		}
+	}
+	for k, v := range raw.Data {
+		if k != ref.Key {
+			continue
+		}
+		var plain interface{}
+		if err := json.Unmarshal(v, &plain); err != nil {
+			return "", fmt.Errorf("failed to decode vault value %s: %v", name, err)
+		}
+		return fmt.Sprintf("%v", plain), nil
+	}
+
+	return "", fmt.Errorf("key %s not found at vault path %s for value %s", ref.Key, ref.Path, name)
+}
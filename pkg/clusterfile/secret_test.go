@@ -0,0 +1,93 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "github.com/sealerio/sealer/types/api/v1"
+)
+
+func TestValidateSecret(t *testing.T) {
+	valid := &v1.Secret{
+		Spec: v1.SecretSpec{Values: []v1.SecretValue{
+			{Name: "db-password", Value: "plain"},
+			{Name: "api-token", ValueFromEnv: "API_TOKEN"},
+		}},
+	}
+	assert.NoError(t, validateSecret(valid))
+
+	noName := &v1.Secret{
+		Spec: v1.SecretSpec{Values: []v1.SecretValue{{Value: "plain"}}},
+	}
+	assert.Error(t, validateSecret(noName))
+
+	twoSources := &v1.Secret{
+		Spec: v1.SecretSpec{Values: []v1.SecretValue{
+			{Name: "db-password", Value: "plain", ValueFromEnv: "DB_PASSWORD"},
+		}},
+	}
+	assert.Error(t, validateSecret(twoSources))
+}
+
+func TestEncryptDecryptSecretValues(t *testing.T) {
+	secret := &v1.Secret{
+		Spec: v1.SecretSpec{Values: []v1.SecretValue{
+			{Name: "db-password", Value: "plain"},
+			{Name: "api-token", ValueFromEnv: "API_TOKEN"},
+		}},
+	}
+
+	assert.NoError(t, encryptSecretValues(secret))
+	assert.True(t, secret.Spec.Values[0].Encrypted)
+	assert.NotEqual(t, "plain", secret.Spec.Values[0].Value)
+	// ref style values carry no secret material and stay untouched
+	assert.False(t, secret.Spec.Values[1].Encrypted)
+
+	assert.NoError(t, decryptSecretValues(secret))
+	assert.Equal(t, "plain", secret.Spec.Values[0].Value)
+	assert.False(t, secret.Spec.Values[0].Encrypted)
+}
+
+func TestResolveSecrets(t *testing.T) {
+	t.Setenv("SEALER_TEST_SECRET", "from-env")
+
+	secretFile := filepath.Join(t.TempDir(), "token")
+	assert.NoError(t, os.WriteFile(secretFile, []byte("from-file\n"), 0o600))
+
+	secrets := []v1.Secret{{
+		Spec: v1.SecretSpec{Values: []v1.SecretValue{
+			{Name: "literal", Value: "plain"},
+			{Name: "fromEnv", ValueFromEnv: "SEALER_TEST_SECRET"},
+			{Name: "fromFile", ValueFromFile: secretFile},
+		}},
+	}}
+
+	envs, err := ResolveSecrets(secrets)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"literal=plain", "fromEnv=from-env", "fromFile=from-file"}, envs)
+
+	missingEnv := []v1.Secret{{
+		Spec: v1.SecretSpec{Values: []v1.SecretValue{
+			{Name: "missing", ValueFromEnv: "SEALER_TEST_SECRET_MISSING"},
+		}},
+	}}
+	_, err = ResolveSecrets(missingEnv)
+	assert.Error(t, err)
+}
@@ -0,0 +1,84 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterfile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/sealerio/sealer/common"
+	utilsos "github.com/sealerio/sealer/utils/os"
+	"github.com/sirupsen/logrus"
+)
+
+// snapshotSuffix is appended to the default Clusterfile path to hold the
+// pre-apply snapshot used by rollback.
+const snapshotSuffix = ".snapshot"
+
+func snapshotPath() string {
+	return common.GetDefaultClusterfile() + snapshotSuffix
+}
+
+// CommitSnapshot saves the currently persisted Clusterfile aside before an
+// apply overwrites it, so a failed or interrupted run can be rolled back.
+// On a fresh install with no persisted Clusterfile, any stale snapshot is
+// dropped instead.
+func CommitSnapshot() error {
+	fileName := common.GetDefaultClusterfile()
+
+	data, err := os.ReadFile(filepath.Clean(fileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			if err := os.Remove(snapshotPath()); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to drop stale clusterfile snapshot: %v", err)
+			}
+			return nil
+		}
+		return fmt.Errorf("failed to read clusterfile for snapshot: %v", err)
+	}
+
+	if err := utilsos.NewCommonWriter(snapshotPath()).WriteFile(data); err != nil {
+		return fmt.Errorf("failed to commit clusterfile snapshot: %v", err)
+	}
+	return nil
+}
+
+// RollBackClusterFile restores the Clusterfile saved by CommitSnapshot.
+// When no snapshot exists the apply started from scratch, so the
+// half-written Clusterfile is removed instead.
+func RollBackClusterFile() error {
+	fileName := common.GetDefaultClusterfile()
+
+	data, err := os.ReadFile(filepath.Clean(snapshotPath()))
+	if err != nil {
+		if os.IsNotExist(err) {
+			if err := os.Remove(fileName); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to remove clusterfile of the failed apply: %v", err)
+			}
+			return nil
+		}
+		return fmt.Errorf("failed to read clusterfile snapshot: %v", err)
+	}
+
+	if err := utilsos.NewCommonWriter(fileName).WriteFile(data); err != nil {
+		return fmt.Errorf("failed to restore clusterfile from snapshot: %v", err)
+	}
+
+	if err := os.Remove(snapshotPath()); err != nil && !os.IsNotExist(err) {
+		logrus.Debugf("failed to remove used clusterfile snapshot: %v", err)
+	}
+	return nil
+}
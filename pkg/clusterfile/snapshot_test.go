@@ -0,0 +1,53 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sealerio/sealer/common"
+	utilsos "github.com/sealerio/sealer/utils/os"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotRollback(t *testing.T) {
+	clusterFilePath := common.GetDefaultClusterfile()
+	assert.NoError(t, os.MkdirAll(filepath.Dir(clusterFilePath), common.FileMode0755))
+	defer func() {
+		_ = os.Remove(clusterFilePath)
+		_ = os.Remove(snapshotPath())
+	}()
+
+	// previous state exists: rollback restores it
+	assert.NoError(t, utilsos.NewCommonWriter(clusterFilePath).WriteFile([]byte("previous")))
+	assert.NoError(t, CommitSnapshot())
+	assert.NoError(t, utilsos.NewCommonWriter(clusterFilePath).WriteFile([]byte("desired")))
+
+	assert.NoError(t, RollBackClusterFile())
+	data, err := os.ReadFile(filepath.Clean(clusterFilePath))
+	assert.NoError(t, err)
+	assert.Equal(t, "previous", string(data))
+
+	// fresh install: no snapshot, rollback removes the half-written file
+	assert.NoError(t, os.Remove(clusterFilePath))
+	assert.NoError(t, CommitSnapshot())
+	assert.NoError(t, utilsos.NewCommonWriter(clusterFilePath).WriteFile([]byte("desired")))
+
+	assert.NoError(t, RollBackClusterFile())
+	_, err = os.Stat(clusterFilePath)
+	assert.True(t, os.IsNotExist(err))
+}
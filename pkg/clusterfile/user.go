@@ -0,0 +1,47 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterfile
+
+import (
+	"fmt"
+
+	v1 "github.com/sealerio/sealer/types/api/v1"
+)
+
+// validateUser rejects user documents sealer could not act on at install
+// time: missing names, unknown auth modes, groups on token users.
+func validateUser(user *v1.User) error {
+	if user.Name == "" {
+		return fmt.Errorf("failed to decode user: metadata.name is empty")
+	}
+
+	switch user.Spec.Auth {
+	case "", v1.UserAuthCert:
+	case v1.UserAuthToken:
+		if len(user.Spec.Groups) > 0 {
+			return fmt.Errorf("failed to decode user %s: groups only work with cert auth, token users are service accounts", user.Name)
+		}
+	default:
+		return fmt.Errorf("failed to decode user %s: unknown auth %q, only %q and %q are supported", user.Name, user.Spec.Auth, v1.UserAuthCert, v1.UserAuthToken)
+	}
+
+	for _, role := range user.Spec.ClusterRoles {
+		if role == "" {
+			return fmt.Errorf("failed to decode user %s: empty cluster role name", user.Name)
+		}
+	}
+
+	return nil
+}
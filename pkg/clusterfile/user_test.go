@@ -0,0 +1,85 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterfile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/sealerio/sealer/types/api/v1"
+)
+
+func TestValidateUser(t *testing.T) {
+	newUser := func(name string, spec v1.UserSpec) *v1.User {
+		return &v1.User{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       spec,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		user    *v1.User
+		wantErr bool
+	}{
+		{
+			name: "cert user with groups and roles",
+			user: newUser("alice", v1.UserSpec{
+				Groups:       []string{"dev"},
+				ClusterRoles: []string{"view", "edit"},
+			}),
+		},
+		{
+			name: "token user",
+			user: newUser("ci-bot", v1.UserSpec{
+				Auth:         v1.UserAuthToken,
+				ClusterRoles: []string{"view"},
+			}),
+		},
+		{
+			name:    "missing name",
+			user:    newUser("", v1.UserSpec{}),
+			wantErr: true,
+		},
+		{
+			name:    "unknown auth",
+			user:    newUser("bob", v1.UserSpec{Auth: "password"}),
+			wantErr: true,
+		},
+		{
+			name:    "token user with groups",
+			user:    newUser("ci-bot", v1.UserSpec{Auth: v1.UserAuthToken, Groups: []string{"dev"}}),
+			wantErr: true,
+		},
+		{
+			name:    "empty cluster role",
+			user:    newUser("carol", v1.UserSpec{ClusterRoles: []string{""}}),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateUser(tt.user)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterfile
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/sealerio/sealer/common"
+	v1 "github.com/sealerio/sealer/types/api/v1"
+	v2 "github.com/sealerio/sealer/types/api/v2"
+)
+
+var roleNameRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// validateCluster checks the decoded cluster for the mistakes that
+// otherwise only surface deep inside the install flow, and reports all
+// of them at once with the offending field path.
+func validateCluster(cluster *v2.Cluster) error {
+	var problems []string
+
+	if cluster.Name == "" {
+		problems = append(problems, "metadata.name: cluster name is required")
+	}
+	if len(cluster.Spec.Hosts) == 0 {
+		problems = append(problems, "spec.hosts: at least one host is required")
+	}
+
+	var (
+		seenIPs   = map[string]string{}
+		hasMaster bool
+	)
+	for i, host := range cluster.Spec.Hosts {
+		field := fmt.Sprintf("spec.hosts[%d]", i)
+
+		if len(host.IPS) == 0 {
+			problems = append(problems, fmt.Sprintf("%s.ips: at least one ip is required", field))
+		}
+		for _, ip := range host.IPS {
+			if len(ip) == 0 {
+				problems = append(problems, fmt.Sprintf("%s.ips: invalid ip address", field))
+				continue
+			}
+			if previous, ok := seenIPs[ip.String()]; ok {
+				problems = append(problems, fmt.Sprintf("%s.ips: duplicate ip %s, already listed in %s", field, ip, previous))
+				continue
+			}
+			seenIPs[ip.String()] = field
+		}
+
+		if len(host.ExternalIPS) != 0 && len(host.ExternalIPS) != len(host.IPS) {
+			problems = append(problems, fmt.Sprintf("%s.externalIPs: expect one external ip per entry in ips, got %d for %d ips", field, len(host.ExternalIPS), len(host.IPS)))
+		}
+		for _, ip := range host.ExternalIPS {
+			if len(ip) == 0 {
+				problems = append(problems, fmt.Sprintf("%s.externalIPs: invalid ip address", field))
+			}
+		}
+
+		if len(host.Roles) == 0 {
+			problems = append(problems, fmt.Sprintf("%s.roles: at least one role is required", field))
+		}
+		for _, role := range host.Roles {
+			if !roleNameRegexp.MatchString(role) {
+				problems = append(problems, fmt.Sprintf("%s.roles: invalid role name %q", field, role))
+			}
+			if role == common.MASTER {
+				hasMaster = true
+			}
+		}
+
+		problems = append(problems, validateSSH(field+".ssh", host.SSH)...)
+		problems = append(problems, validateEnv(field+".env", host.Env)...)
+	}
+
+	if len(cluster.Spec.Hosts) != 0 && !hasMaster {
+		problems = append(problems, fmt.Sprintf("spec.hosts: no host has the %q role", common.MASTER))
+	}
+
+	problems = append(problems, validateSSH("spec.ssh", cluster.Spec.SSH)...)
+	problems = append(problems, validateEnv("spec.env", cluster.Spec.Env)...)
+
+	if len(problems) != 0 {
+		return fmt.Errorf("invalid Clusterfile:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+func validateSSH(field string, ssh v1.SSH) []string {
+	if ssh.Port == "" {
+		return nil
+	}
+	port, err := strconv.Atoi(ssh.Port)
+	if err != nil || port < 1 || port > 65535 {
+		return []string{fmt.Sprintf("%s.port: invalid port %q, must be in range 1-65535", field, ssh.Port)}
+	}
+	return nil
+}
+
+func validateEnv(field string, env []string) []string {
+	var problems []string
+	for _, e := range env {
+		kv := strings.SplitN(e, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			problems = append(problems, fmt.Sprintf("%s: invalid env %q, expected key=value", field, e))
+		}
+	}
+	return problems
+}
@@ -0,0 +1,111 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterfile
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/sealerio/sealer/types/api/v1"
+	v2 "github.com/sealerio/sealer/types/api/v2"
+)
+
+func validTestCluster() *v2.Cluster {
+	return &v2.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cluster"},
+		Spec: v2.ClusterSpec{
+			Image: "docker.io/sealerio/kubernetes:v1.22.15",
+			Hosts: []v2.Host{
+				{
+					IPS:   []net.IP{net.ParseIP("192.168.0.2")},
+					Roles: []string{"master"},
+				},
+				{
+					IPS:   []net.IP{net.ParseIP("192.168.0.3")},
+					Roles: []string{"node"},
+				},
+			},
+		},
+	}
+}
+
+func TestValidateCluster(t *testing.T) {
+	assert.NoError(t, validateCluster(validTestCluster()))
+
+	tests := []struct {
+		name    string
+		mutate  func(cluster *v2.Cluster)
+		wantErr string
+	}{
+		{
+			name:    "missing name",
+			mutate:  func(cluster *v2.Cluster) { cluster.Name = "" },
+			wantErr: "metadata.name",
+		},
+		{
+			name:    "no hosts",
+			mutate:  func(cluster *v2.Cluster) { cluster.Spec.Hosts = nil },
+			wantErr: "spec.hosts",
+		},
+		{
+			name: "duplicate ip",
+			mutate: func(cluster *v2.Cluster) {
+				cluster.Spec.Hosts[1].IPS = cluster.Spec.Hosts[0].IPS
+			},
+			wantErr: "duplicate ip 192.168.0.2",
+		},
+		{
+			name: "invalid role name",
+			mutate: func(cluster *v2.Cluster) {
+				cluster.Spec.Hosts[1].Roles = []string{"Worker Node"}
+			},
+			wantErr: `invalid role name "Worker Node"`,
+		},
+		{
+			name: "no master role",
+			mutate: func(cluster *v2.Cluster) {
+				cluster.Spec.Hosts[0].Roles = []string{"node"}
+			},
+			wantErr: `no host has the "master" role`,
+		},
+		{
+			name: "invalid ssh port",
+			mutate: func(cluster *v2.Cluster) {
+				cluster.Spec.SSH = v1.SSH{Port: "70000"}
+			},
+			wantErr: `spec.ssh.port: invalid port "70000"`,
+		},
+		{
+			name: "invalid env",
+			mutate: func(cluster *v2.Cluster) {
+				cluster.Spec.Env = []string{"PodCIDR"}
+			},
+			wantErr: `spec.env: invalid env "PodCIDR"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := validTestCluster()
+			tt.mutate(cluster)
+			err := validateCluster(cluster)
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
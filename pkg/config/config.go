@@ -21,6 +21,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	jsonpatch "github.com/evanphx/json-patch"
 	"github.com/imdario/mergo"
 	"github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v3"
@@ -34,6 +35,17 @@ import (
 
 const (
 	Merge = "merge"
+	// Override replaces the whole target file, the historical default.
+	Override = "override"
+	// Append adds the config data to the end of the target file.
+	Append = "append"
+	// JSONPatch applies the config data as an RFC 6902 patch (written in
+	// YAML or JSON) to every document of the target file.
+	JSONPatch = "jsonpatch"
+	// StrategicMerge applies the config data as an RFC 7386 merge patch to
+	// every document of the target file; unlike Merge a null value deletes
+	// the key and lists are replaced, not merged.
+	StrategicMerge = "strategic-merge"
 )
 
 type Interface interface {
@@ -71,50 +83,106 @@ func (c *Dumper) WriteFiles(configs []v1.Config) error {
 			return err
 		}
 
-		configData := []byte(config.Spec.Data)
 		path := config.Spec.Path
 		if config.Spec.APPName != "" {
 			path = filepath.Join(rootfs.GlobalManager.App().Root(), config.Spec.APPName, path)
 		}
-		configPath := filepath.Join(c.rootPath, path)
 
-		logrus.Debugf("dumping config:%+v\n on the target file", config)
-		if !os.IsFileExist(configPath) {
-			err := os.NewCommonWriter(configPath).WriteFile(configData)
-			if err != nil {
-				return fmt.Errorf("failed to overwrite config file %s: %v", configPath, err)
-			}
-			continue
-		}
-
-		contents, err := stdos.ReadFile(filepath.Clean(configPath))
+		configPaths, err := c.resolveConfigPaths(path)
 		if err != nil {
 			return err
 		}
 
-		// todo: its strange to use config.Spec.Process to control config dump strategy.
-		if strings.Contains(config.Spec.Process, toSecretProcessorName) {
-			if configData, err = convertSecretYaml(contents, configData); err != nil {
-				return fmt.Errorf("faild to convert to secret file: %v", err)
-			}
-		}
-		//Only files in yaml format are supported.
-		//if Strategy is "Merge" will deeply merge each yaml file section.
-		//if not, overwrite the whole file content with config data.
-		if config.Spec.Strategy == Merge {
-			if configData, err = getMergeConfigData(contents, configData); err != nil {
+		for _, configPath := range configPaths {
+			if err := c.writeOneFile(&config, configPath); err != nil {
 				return err
 			}
 		}
+	}
+	return nil
+}
+
+// resolveConfigPaths expands a config path into the target files. A path
+// with glob metacharacters ('*', '?', '[') is matched against the existing
+// files under the root path, so one Config can tweak e.g. "apps/*/values.yaml";
+// a plain path is returned as is and may name a file to be created.
+func (c *Dumper) resolveConfigPaths(path string) ([]string, error) {
+	configPath := filepath.Join(c.rootPath, path)
+	if !strings.ContainsAny(path, "*?[") {
+		return []string{configPath}, nil
+	}
+
+	matches, err := filepath.Glob(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config path pattern %s: %v", path, err)
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("config path pattern %s matches no file", path)
+	}
+	return matches, nil
+}
+
+func (c *Dumper) writeOneFile(config *v1.Config, configPath string) error {
+	configData := []byte(config.Spec.Data)
 
-		err = os.NewCommonWriter(configPath).WriteFile(configData)
+	logrus.Debugf("dumping config:%+v\n on the target file", config)
+	if !os.IsFileExist(configPath) {
+		// patch strategies need an existing file to patch.
+		if config.Spec.Strategy == JSONPatch || config.Spec.Strategy == StrategicMerge {
+			return fmt.Errorf("config strategy %s requires an existing target file: %s", config.Spec.Strategy, configPath)
+		}
+		err := os.NewCommonWriter(configPath).WriteFile(configData)
 		if err != nil {
-			return fmt.Errorf("failed to write config file %s: %v", configPath, err)
+			return fmt.Errorf("failed to overwrite config file %s: %v", configPath, err)
+		}
+		return nil
+	}
+
+	contents, err := stdos.ReadFile(filepath.Clean(configPath))
+	if err != nil {
+		return err
+	}
+
+	// todo: its strange to use config.Spec.Process to control config dump strategy.
+	if strings.Contains(config.Spec.Process, toSecretProcessorName) {
+		if configData, err = convertSecretYaml(contents, configData); err != nil {
+			return fmt.Errorf("faild to convert to secret file: %v", err)
 		}
 	}
+
+	if configData, err = applyConfigData(contents, configData, config.Spec.Strategy); err != nil {
+		return fmt.Errorf("failed to apply config on %s: %v", configPath, err)
+	}
+
+	err = os.NewCommonWriter(configPath).WriteFile(configData)
+	if err != nil {
+		return fmt.Errorf("failed to write config file %s: %v", configPath, err)
+	}
 	return nil
 }
 
+// applyConfigData combines the existing file contents with the config data
+// according to the strategy; an empty strategy keeps the historical
+// whole-file override. Only files in yaml format are supported by the
+// merge and patch strategies.
+func applyConfigData(contents, data []byte, strategy string) ([]byte, error) {
+	switch strategy {
+	case "", Override:
+		return data, nil
+	case Merge:
+		return getMergeConfigData(contents, data)
+	case Append:
+		return append(bytes.TrimRight(contents, "\n"), append([]byte("\n"), data...)...), nil
+	case JSONPatch:
+		return getJSONPatchConfigData(contents, data)
+	case StrategicMerge:
+		return getStrategicMergeConfigData(contents, data)
+	default:
+		logrus.Warnf("unknown config strategy %q, overriding the whole file", strategy)
+		return data, nil
+	}
+}
+
 // getMergeConfigData merge data to each section of given file with overriding.
 // given file is must be yaml marshalled.
 func getMergeConfigData(contents, data []byte) ([]byte, error) {
@@ -151,6 +219,65 @@ func getMergeConfigData(contents, data []byte) ([]byte, error) {
 	return bytes.Join(configs, []byte("---\n")), nil
 }
 
+// getJSONPatchConfigData applies data as an RFC 6902 patch to every yaml
+// document of the given file. The patch itself may be written in yaml.
+func getJSONPatchConfigData(contents, data []byte) ([]byte, error) {
+	patchJSON, err := k8sYaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load json patch: %v", err)
+	}
+
+	patch, err := jsonpatch.DecodePatch(patchJSON)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode json patch: %v", err)
+	}
+
+	return patchEachSection(contents, func(doc []byte) ([]byte, error) {
+		return patch.Apply(doc)
+	})
+}
+
+// getStrategicMergeConfigData applies data as an RFC 7386 merge patch to
+// every yaml document of the given file: null values delete keys and lists
+// are replaced as a whole.
+func getStrategicMergeConfigData(contents, data []byte) ([]byte, error) {
+	patchJSON, err := k8sYaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load merge patch: %v", err)
+	}
+
+	return patchEachSection(contents, func(doc []byte) ([]byte, error) {
+		return jsonpatch.MergePatch(doc, patchJSON)
+	})
+}
+
+// patchEachSection converts each yaml document of contents to json, runs the
+// patch function on it and reassembles the multi document yaml file.
+func patchEachSection(contents []byte, patchFunc func(doc []byte) ([]byte, error)) ([]byte, error) {
+	var configs [][]byte
+
+	for _, rawCfgData := range bytes.Split(contents, []byte("---\n")) {
+		doc, err := k8sYaml.YAMLToJSON(rawCfgData)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config file section: %v", err)
+		}
+
+		patched, err := patchFunc(doc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to patch config file section: %v", err)
+		}
+
+		cfg, err := k8sYaml.JSONToYAML(patched)
+		if err != nil {
+			return nil, err
+		}
+
+		configs = append(configs, cfg)
+	}
+
+	return bytes.Join(configs, []byte("---\n")), nil
+}
+
 func convertSecretYaml(contents, data []byte) ([]byte, error) {
 	secret := k8sv1.Secret{}
 	dataMap := make(map[string]string)
@@ -0,0 +1,67 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_applyConfigData(t *testing.T) {
+	contents := `image:
+  repository: nginx
+  tag: v1
+replicas: 2
+`
+
+	t.Run("override is the default", func(t *testing.T) {
+		got, err := applyConfigData([]byte(contents), []byte("replicas: 3"), "")
+		assert.NoError(t, err)
+		assert.Equal(t, "replicas: 3", string(got))
+
+		got, err = applyConfigData([]byte(contents), []byte("replicas: 3"), Override)
+		assert.NoError(t, err)
+		assert.Equal(t, "replicas: 3", string(got))
+	})
+
+	t.Run("append keeps the original contents", func(t *testing.T) {
+		got, err := applyConfigData([]byte(contents), []byte("extra: true"), Append)
+		assert.NoError(t, err)
+		assert.Equal(t, contents+"extra: true", string(got))
+	})
+
+	t.Run("jsonpatch changes a single field", func(t *testing.T) {
+		patch := `- op: replace
+  path: /image/tag
+  value: v2
+`
+		got, err := applyConfigData([]byte(contents), []byte(patch), JSONPatch)
+		assert.NoError(t, err)
+		assert.Contains(t, string(got), "tag: v2")
+		assert.Contains(t, string(got), "repository: nginx")
+	})
+
+	t.Run("strategic merge deletes keys with null", func(t *testing.T) {
+		patch := `image:
+  tag: v2
+replicas: null
+`
+		got, err := applyConfigData([]byte(contents), []byte(patch), StrategicMerge)
+		assert.NoError(t, err)
+		assert.Contains(t, string(got), "tag: v2")
+		assert.NotContains(t, string(got), "replicas")
+	})
+}
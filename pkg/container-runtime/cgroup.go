@@ -0,0 +1,93 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package containerruntime
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// detectCgroupV2Cmd prints cgroup2fs on hosts booted with the unified cgroup
+// v2 hierarchy; anything else (including missing stat) means v1.
+const detectCgroupV2Cmd = `stat -f -c %T /sys/fs/cgroup 2>/dev/null || true`
+
+// findRootfsRuncCmd locates the runc binary bundled in the rootfs, if the
+// image ships one, so its version can be checked before it gets installed.
+const findRootfsRuncCmd = `find %s -maxdepth 3 -type f -name runc 2>/dev/null | head -1`
+
+var runcVersionRegexp = regexp.MustCompile(`runc version (\d+)\.(\d+)\.\d+(?:-rc(\d+))?`)
+
+// runcSupportsCgroupV2 reports whether the `runc --version` output names a
+// release with cgroup v2 support, which landed in 1.0.0-rc93. Output that
+// does not look like a runc version is treated as supported: failing an
+// install over an unparsable banner would be worse than letting runc itself
+// error out.
+func runcSupportsCgroupV2(versionOutput string) bool {
+	m := runcVersionRegexp.FindStringSubmatch(versionOutput)
+	if m == nil {
+		return true
+	}
+
+	major, _ := strconv.Atoi(m[1])
+	if major < 1 {
+		return false
+	}
+	if m[3] != "" {
+		rc, _ := strconv.Atoi(m[3])
+		return major > 1 || m[2] != "0" || rc >= 93
+	}
+	return true
+}
+
+// checkCgroupCompat verifies a cgroup v2 host will get compatible runtime
+// settings before the install scripts run: the systemd cgroup driver must be
+// in use and the runc shipped in the rootfs, when there is one, must be a
+// release with cgroup v2 support. cgroup v1 hosts pass unchecked.
+func (d *DefaultInstaller) checkCgroupCompat(host net.IP) error {
+	fsType, err := d.driver.CmdToString(host, nil, detectCgroupV2Cmd, "")
+	if err != nil {
+		return fmt.Errorf("failed to detect cgroup hierarchy on host (%s): %v", host, err)
+	}
+	if strings.TrimSpace(fsType) != "cgroup2fs" {
+		return nil
+	}
+	logrus.Debugf("host %s runs the unified cgroup v2 hierarchy", host)
+
+	if d.Info.CgroupDriver != DefaultCgroupDriver {
+		return fmt.Errorf("host %s uses cgroup v2 which requires the systemd cgroup driver, remove %s=%s from the Clusterfile env or set it to %s",
+			host, CgroupDriverArg, d.Info.CgroupDriver, DefaultCgroupDriver)
+	}
+
+	runcPath, err := d.driver.CmdToString(host, nil, fmt.Sprintf(findRootfsRuncCmd, d.rootfs), "")
+	if err != nil || strings.TrimSpace(runcPath) == "" {
+		logrus.Debugf("no runc bundled in the rootfs on host %s, skipping the cgroup v2 runc check", host)
+		return nil
+	}
+
+	version, err := d.driver.CmdToString(host, nil, fmt.Sprintf("%s --version 2>/dev/null || true", strings.TrimSpace(runcPath)), " ")
+	if err != nil {
+		return fmt.Errorf("failed to read the bundled runc version on host (%s): %v", host, err)
+	}
+	if !runcSupportsCgroupV2(version) {
+		return fmt.Errorf("host %s uses cgroup v2 but this ClusterImage ships %q which predates cgroup v2 support (runc 1.0.0-rc93), use an image with a newer runc or boot the host with cgroup v1",
+			host, strings.TrimSpace(version))
+	}
+	return nil
+}
@@ -18,10 +18,19 @@ import (
 	"fmt"
 	"net"
 	"path/filepath"
+	"strings"
 
+	"github.com/sirupsen/logrus"
+
+	"github.com/sealerio/sealer/common"
 	"github.com/sealerio/sealer/pkg/infradriver"
+	strUtils "github.com/sealerio/sealer/utils/strings"
 )
 
+// detectGPUCmd probes for NVIDIA devices without relying on the driver
+// stack being installed yet.
+const detectGPUCmd = `ls /dev/nvidia* >/dev/null 2>&1 || lspci 2>/dev/null | grep -qi nvidia`
+
 type DefaultInstaller struct {
 	Info
 	envs   map[string]string
@@ -31,11 +40,71 @@ type DefaultInstaller struct {
 
 func (d *DefaultInstaller) InstallOn(hosts []net.IP) error {
 	installCmd := fmt.Sprintf("bash %s", filepath.Join(d.rootfs, "scripts", d.getInstallScriptName()))
+	proxyCmd := d.proxyDropInCmd()
 	for _, ip := range hosts {
+		// fail before the install scripts run when a cgroup v2 host would
+		// get incompatible runtime settings
+		if err := d.checkCgroupCompat(ip); err != nil {
+			return err
+		}
+		if proxyCmd != "" {
+			if err := d.driver.CmdAsync(ip, nil, proxyCmd); err != nil {
+				return fmt.Errorf("failed to configure %s proxy drop-in on host (%s): %v", d.Type, ip, err)
+			}
+		}
 		err := d.driver.CmdAsync(ip, d.envs, installCmd)
 		if err != nil {
 			return fmt.Errorf("failed to install %s: execute command(%s) on host (%s): error(%v)", d.Type, installCmd, ip, err)
 		}
+		if d.isGPUHost(ip) {
+			if err := d.enableGPURuntime(ip); err != nil {
+				return err
+			}
+		}
+	}
+
+	return d.SyncRegistryConfig(hosts)
+}
+
+// proxyDropInCmd renders the cluster proxy env into a systemd drop-in for
+// the container runtime service, so image pulls on the nodes go through the
+// corporate proxy. It returns "" when no proxy is configured.
+func (d *DefaultInstaller) proxyDropInCmd() string {
+	var lines []string
+	for _, key := range []string{"HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY"} {
+		if v := d.envs[key]; v != "" {
+			lines = append(lines, fmt.Sprintf("Environment=\"%s=%s\"", key, v))
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+
+	dropInDir := fmt.Sprintf("/etc/systemd/system/%s.service.d", d.Type)
+	return fmt.Sprintf(`mkdir -p %s && cat > %s/http-proxy.conf <<'EOF'
+[Service]
+%s
+EOF
+systemctl daemon-reload`, dropInDir, dropInDir, strings.Join(lines, "\n"))
+}
+
+func (d *DefaultInstaller) isGPUHost(ip net.IP) bool {
+	return strUtils.IsInSlice(common.GPU, d.driver.GetRoleListByHostIP(ip.String()))
+}
+
+// enableGPURuntime configures the nvidia runtime on a gpu host through
+// the per-runtime enablement script shipped in the rootfs.
+func (d *DefaultInstaller) enableGPURuntime(ip net.IP) error {
+	// a gpu host without NVIDIA devices is most likely a Clusterfile
+	// mistake; report it but keep going
+	if _, err := d.driver.Cmd(ip, nil, detectGPUCmd); err != nil {
+		logrus.Warnf("no NVIDIA device detected on gpu host %s: %v", ip, err)
+	}
+
+	script := filepath.Join(d.rootfs, "scripts", fmt.Sprintf("nvidia-%s.sh", d.Type))
+	enableCmd := fmt.Sprintf(`if [ -f %[1]s ]; then bash %[1]s; else echo "nvidia runtime script %[1]s not found, skipping"; fi`, script)
+	if err := d.driver.CmdAsync(ip, d.envs, enableCmd); err != nil {
+		return fmt.Errorf("failed to configure nvidia runtime on host (%s): %v", ip, err)
 	}
 	return nil
 }
@@ -44,6 +44,10 @@ type Installer interface {
 
 	UnInstallFrom(hosts []net.IP) error
 
+	// SyncRegistryConfig applies the registry mirrors and insecure
+	// registries from the Clusterfile to the runtime config of the hosts.
+	SyncRegistryConfig(hosts []net.IP) error
+
 	//Upgrade() (ContainerRuntimeInfo, error)
 	//Rollback() (ContainerRuntimeInfo, error)
 }
@@ -0,0 +1,180 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package containerruntime
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/sealerio/sealer/common"
+	osutils "github.com/sealerio/sealer/utils/os"
+	strUtils "github.com/sealerio/sealer/utils/strings"
+)
+
+// dockerHubDomain is the only registry docker's registry-mirrors field
+// applies to, mirrors for any other domain need containerd.
+const dockerHubDomain = "docker.io"
+
+// SyncRegistryConfig renders the registry mirrors and insecure registries
+// from the Clusterfile into the container runtime configuration of the given
+// hosts. It is run as part of the runtime installation and can be re-run on
+// a live cluster via `sealer alpha registry sync-config`.
+func (d *DefaultInstaller) SyncRegistryConfig(hosts []net.IP) error {
+	if len(d.Mirrors) == 0 && len(d.InsecureRegistries) == 0 {
+		return nil
+	}
+
+	switch d.Type {
+	case common.Docker:
+		return d.syncDockerRegistryConfig(hosts)
+	case common.Containerd:
+		return d.syncContainerdRegistryConfig(hosts)
+	default:
+		return nil
+	}
+}
+
+// syncDockerRegistryConfig merges the mirrors and insecure registries into
+// the daemon.json template under the rootfs, so later configuration passes
+// (e.g. the local registry setup) keep them, then ships it to the hosts and
+// restarts docker to apply it.
+func (d *DefaultInstaller) syncDockerRegistryConfig(hosts []net.IP) error {
+	daemonFile := filepath.Join(d.rootfs, "etc", "daemon.json")
+
+	conf := map[string]interface{}{}
+	if b, err := os.ReadFile(filepath.Clean(daemonFile)); err == nil && len(bytes.TrimSpace(b)) > 0 {
+		if err := json.Unmarshal(b, &conf); err != nil {
+			return fmt.Errorf("failed to load %s: %v", daemonFile, err)
+		}
+	}
+
+	var mirrors []string
+	for _, domain := range sortedKeys(d.Mirrors) {
+		if domain != dockerHubDomain {
+			logrus.Warnf("docker only supports registry mirrors for %s, ignoring the mirrors configured for %s", dockerHubDomain, domain)
+			continue
+		}
+		for _, endpoint := range d.Mirrors[domain] {
+			mirrors = append(mirrors, withScheme(endpoint, "https"))
+		}
+	}
+	if len(mirrors) > 0 {
+		conf["registry-mirrors"] = strUtils.RemoveDuplicate(mirrors)
+	}
+	if len(d.InsecureRegistries) > 0 {
+		conf["insecure-registries"] = strUtils.RemoveDuplicate(d.InsecureRegistries)
+	}
+
+	content, err := json.MarshalIndent(conf, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %v", daemonFile, err)
+	}
+	if err := osutils.NewCommonWriter(daemonFile).WriteFile(content); err != nil {
+		return err
+	}
+
+	return d.driver.Execute(hosts, func(host net.IP) error {
+		if err := d.driver.Copy(host, daemonFile, "/etc/docker/daemon.json"); err != nil {
+			return fmt.Errorf("failed to copy daemon.json to host (%s): %v", host, err)
+		}
+		return d.driver.CmdAsync(host, nil, "systemctl daemon-reload && systemctl restart docker")
+	})
+}
+
+// syncContainerdRegistryConfig writes one certs.d/<domain>/hosts.toml per
+// configured domain. Containerd picks the files up without a restart.
+func (d *DefaultInstaller) syncContainerdRegistryConfig(hosts []net.IP) error {
+	insecure := make(map[string]bool, len(d.InsecureRegistries))
+	for _, registry := range d.InsecureRegistries {
+		insecure[registry] = true
+	}
+
+	var cmds []string
+	for _, domain := range sortedKeys(d.Mirrors) {
+		cmds = append(cmds, writeHostsTOMLCmd(domain, containerdHostsTOML(domain, d.Mirrors[domain], insecure[domain])))
+	}
+	for _, registry := range strUtils.RemoveDuplicate(d.InsecureRegistries) {
+		if _, ok := d.Mirrors[registry]; ok {
+			continue
+		}
+		cmds = append(cmds, writeHostsTOMLCmd(registry, containerdHostsTOML(registry, nil, true)))
+	}
+
+	return d.driver.Execute(hosts, func(host net.IP) error {
+		if err := d.driver.CmdAsync(host, nil, cmds...); err != nil {
+			return fmt.Errorf("failed to write containerd registry hosts.toml on host (%s): %v", host, err)
+		}
+		return nil
+	})
+}
+
+// containerdHostsTOML renders the hosts.toml for one registry domain: its
+// mirror endpoints in order, and skip_verify/plain HTTP when the domain is
+// listed as insecure.
+func containerdHostsTOML(domain string, mirrors []string, insecure bool) string {
+	scheme := "https"
+	if insecure {
+		scheme = "http"
+	}
+
+	server := domain
+	if domain == dockerHubDomain {
+		server = "registry-1.docker.io"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "server = %q\n", scheme+"://"+server)
+	for _, mirror := range mirrors {
+		fmt.Fprintf(&b, "\n[host.%q]\n  capabilities = [\"pull\", \"resolve\"]\n", withScheme(mirror, scheme))
+		if insecure {
+			b.WriteString("  skip_verify = true\n")
+		}
+	}
+	if len(mirrors) == 0 && insecure {
+		fmt.Fprintf(&b, "\n[host.%q]\n  capabilities = [\"pull\", \"resolve\", \"push\"]\n  skip_verify = true\n", scheme+"://"+server)
+	}
+	return b.String()
+}
+
+func writeHostsTOMLCmd(domain, content string) string {
+	dir := filepath.Join(DefaultContainerdCertsDir, domain)
+	return fmt.Sprintf(`mkdir -p %s && cat > %s <<'EOF'
+%s
+EOF`, dir, filepath.Join(dir, "hosts.toml"), strings.TrimRight(content, "\n"))
+}
+
+func withScheme(endpoint, defaultScheme string) string {
+	if strings.Contains(endpoint, "://") {
+		return endpoint
+	}
+	return defaultScheme + "://" + endpoint
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -18,5 +18,6 @@ const (
 	KubeApp    string = "kube"
 	HelmApp    string = "helm"
 	ShellApp   string = "shell"
+	HostApp    string = "host"
 	UnknownApp string = "unknown"
 )
@@ -81,18 +81,64 @@ func GetAppLaunchCmd(appRoot string, app *Application) string {
 		}
 		return strings.Join(cmds, " && ")
 	case application.HelmApp:
-		return fmt.Sprintf("helm install %s %s", app.Name(), appRoot)
+		// upgrade --install keeps the app registered as a proper secrets-backed
+		// helm release across re-launches, so it stays visible to helm list and
+		// its health can be queried via helm status.
+		return fmt.Sprintf("helm upgrade --install %s %s", app.Name(), appRoot)
 	case application.ShellApp:
 		var cmds []string
 		for _, file := range app.FilesVar {
 			cmds = append(cmds, fmt.Sprintf("bash %s", filepath.Join(appRoot, file)))
 		}
 		return strings.Join(cmds, " && ")
+	case application.HostApp:
+		return getHostAppLaunchCmd(appRoot, app)
 	default:
 		return ""
 	}
 }
 
+// getHostAppLaunchCmd installs the app payload onto the hosts themselves
+// instead of the cluster: systemd units go to /etc/systemd/system and get
+// enabled, shell files are executed, everything else is installed as a
+// binary under /usr/local/bin. The on:role=all target makes the launch run
+// on every cluster host instead of master0 only.
+func getHostAppLaunchCmd(appRoot string, app *Application) string {
+	var (
+		cmds  []string
+		units []string
+	)
+
+	for _, file := range app.FilesVar {
+		src := filepath.Join(appRoot, file)
+		base := filepath.Base(file)
+
+		switch strings.ToLower(filepath.Ext(file)) {
+		case ".service", ".timer":
+			cmds = append(cmds, fmt.Sprintf("cp -f %s /etc/systemd/system/%s", src, base))
+			units = append(units, base)
+		case ".sh":
+			cmds = append(cmds, fmt.Sprintf("bash %s", src))
+		default:
+			cmds = append(cmds, fmt.Sprintf("install -m 0755 %s /usr/local/bin/%s", src, base))
+		}
+	}
+
+	if len(cmds) == 0 {
+		return ""
+	}
+
+	if len(units) > 0 {
+		cmds = append(cmds, "systemctl daemon-reload")
+		for _, unit := range units {
+			// restart picks up a new payload version on upgrade.
+			cmds = append(cmds, fmt.Sprintf("systemctl enable %s && systemctl restart %s", unit, unit))
+		}
+	}
+
+	return "on:role=all " + strings.Join(cmds, " && ")
+}
+
 func NewV1Application(
 	name string,
 	appType string, files []string) version.VersionedApplication {
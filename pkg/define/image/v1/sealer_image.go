@@ -104,10 +104,31 @@ type ImageExtension struct {
 	// user could overwrite it through v2.ClusterSpec at run stage.
 	Env map[string]string `json:"env,omitempty"`
 
+	// EnvDocs documents the env keys the image expects: default value,
+	// description and whether the key must be set at run stage.
+	EnvDocs []EnvDoc `json:"envDocs,omitempty"`
+
 	// launch spec will declare
 	Launch Launch `json:"launch,omitempty"`
 }
 
+// EnvDoc is the metadata of one env key declared via the Kubefile ENV
+// instruction. It serves as image documentation for `sealer inspect` and
+// lets `sealer run` fail fast when a required key is absent.
+type EnvDoc struct {
+	// Key is the env key name.
+	Key string `json:"key"`
+
+	// Default is the default value, empty when the key is declared bare.
+	Default string `json:"default,omitempty"`
+
+	// Description explains what the key is used for.
+	Description string `json:"description,omitempty"`
+
+	// Required marks the key as mandatory at run stage.
+	Required bool `json:"required,omitempty"`
+}
+
 type BuildClient struct {
 	SealerVersion string `json:"sealerVersion"`
 
@@ -136,6 +157,8 @@ type v1ImageExtension struct {
 	Launch Launch `json:"launch,omitempty"`
 	// Env global env
 	Env map[string]string `json:"env,omitempty"`
+	// EnvDocs metadata of the declared env keys
+	EnvDocs []EnvDoc `json:"envDocs,omitempty"`
 }
 
 func (ie *ImageExtension) UnmarshalJSON(data []byte) error {
@@ -149,6 +172,7 @@ func (ie *ImageExtension) UnmarshalJSON(data []byte) error {
 	(*ie).SchemaVersion = v1Ex.SchemaVersion
 	(*ie).Labels = v1Ex.Labels
 	(*ie).Env = v1Ex.Env
+	(*ie).EnvDocs = v1Ex.EnvDocs
 	(*ie).Type = v1Ex.Type
 	(*ie).Applications = make([]version.VersionedApplication, len(v1Ex.Applications))
 	for i, app := range v1Ex.Applications {
@@ -17,11 +17,15 @@ package options
 const (
 	WithLiteMode = "lite"
 	WithAllMode  = "all"
+	// WithCaptureMode builds the image from the rootfs snapshot of a
+	// running cluster instead of a user provided build context.
+	WithCaptureMode = "capture"
 )
 
 var SupportedBuildModes = []string{
 	WithLiteMode,
 	WithAllMode,
+	WithCaptureMode,
 }
 
 // BuildOptions should be out of buildah scope.
@@ -42,10 +46,26 @@ type BuildOptions struct {
 	ImageList         string
 	ImageListWithAuth string
 	IgnoredImageList  string
+	// CacheFrom/CacheTo name a remote repository used as a shared layer
+	// cache, so CI runners on different machines can reuse each other's
+	// intermediate layers. CacheTTL limits how old a cached layer may be.
+	CacheFrom string
+	CacheTo   string
+	CacheTTL  string
+	// ScanContext statically scans the whole build context for image
+	// references in YAML manifests and charts and merges them with the
+	// explicit image list.
+	ScanContext bool
 
 	//BuildMode means whether to download container image during the build process
 	// default value is download all container images.
 	BuildMode string
+
+	// Squash collapses the layers of the built image into one.
+	Squash bool
+	// Minimize strips binaries of foreign architectures from the rootfs
+	// and hard links identical files before the image is committed.
+	Minimize bool
 }
 
 type FromOptions struct {
@@ -113,6 +133,9 @@ type PushOptions struct {
 	Quiet         bool
 	SkipTLSVerify bool
 	All           bool
+	// MaxParallelLayers caps how many layers are uploaded concurrently,
+	// 0 keeps the engine default.
+	MaxParallelLayers int
 }
 
 type PullOptions struct {
@@ -122,6 +145,9 @@ type PullOptions struct {
 	PullPolicy    string
 	Image         string
 	Platform      string
+	// MaxParallelLayers caps how many layers are downloaded concurrently,
+	// 0 keeps the engine default.
+	MaxParallelLayers int
 }
 
 type ImagesOptions struct {
@@ -132,6 +158,9 @@ type ImagesOptions struct {
 	Quiet     bool
 	History   bool
 	JSON      bool
+	// Output selects a machine-readable encoding(json or yaml) of the
+	// image list, set from the global --output flag.
+	Output string
 }
 
 type SaveOptions struct {
@@ -143,12 +172,24 @@ type SaveOptions struct {
 	Quiet             bool
 	ImageNameOrID     string
 	TmpDir            string
+	// EncryptionKeys name the recipients (e.g. jwe:/path/to/pub.pem) whose
+	// keys encrypt the image layers at rest; EncryptLayers selects which
+	// layers to encrypt, all of them when empty.
+	EncryptionKeys []string
+	EncryptLayers  []int
 }
 
 type LoadOptions struct {
 	Input  string
 	TmpDir string
 	Quiet  bool
+	// Format names the archive format of the input explicitly, e.g.
+	// docker-archive for tars written by docker save; empty or "sealer"
+	// auto-detects sealer's own bundle.
+	Format string
+	// DecryptionKeys are private keys (e.g. /path/to/key.pem or
+	// /path/to/key.pem:passphrase) used to decrypt encrypted layers.
+	DecryptionKeys []string
 }
 
 type InspectOptions struct {
@@ -168,6 +209,14 @@ type RemoveImageOptions struct {
 	Prune           bool
 }
 
+type PruneOptions struct {
+	// don't print what is removed
+	Quiet bool
+}
+
+type DiskUsageOptions struct {
+}
+
 type EngineGlobalConfigurations struct {
 	AuthFile  string
 	GraphRoot string
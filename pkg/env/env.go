@@ -22,6 +22,10 @@ import (
 	"sort"
 	"strings"
 	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+
+	"github.com/sealerio/sealer/common"
 )
 
 const templateSuffix = ".tmpl"
@@ -38,8 +42,20 @@ func base64decode(v string) string {
 	return string(data)
 }
 
+// templateFuncMap is the function set available in rendered templates: the
+// full sprig library (default, ternary, trim, ...) plus the historical
+// b64enc/b64dec, which keep their original behavior for existing images.
+func templateFuncMap() template.FuncMap {
+	funcMap := sprig.TxtFuncMap()
+	funcMap["b64enc"] = base64encode
+	funcMap["b64dec"] = base64decode
+	return funcMap
+}
+
 // RenderTemplate :using renderData got from clusterfile to render all the files in dir with ".tmpl" as suffix.
-// The scope of renderData comes from cluster.spec.env
+// The scope of renderData comes from cluster.spec.env.
+// Templates are plain go templates, so conditional blocks
+// ({{ if eq .Mode "ha" }}...{{ end }}) and sprig functions are available.
 func RenderTemplate(dir string, renderData map[string]string) error {
 	return filepath.Walk(dir, func(path string, info os.FileInfo, errIn error) error {
 		if errIn != nil {
@@ -55,10 +71,7 @@ func RenderTemplate(dir string, renderData map[string]string) error {
 		defer func() {
 			_ = writer.Close()
 		}()
-		t, err := template.New(info.Name()).Funcs(template.FuncMap{
-			"b64enc": base64encode,
-			"b64dec": base64decode,
-		}).ParseFiles(path)
+		t, err := template.New(info.Name()).Funcs(templateFuncMap()).ParseFiles(path)
 		if err != nil {
 			return fmt.Errorf("failed to create template(%s): %v", path, err)
 		}
@@ -69,18 +82,38 @@ func RenderTemplate(dir string, renderData map[string]string) error {
 	})
 }
 
+// RenderString renders one template string with the same function set and
+// render data as RenderTemplate.
+func RenderString(content string, renderData map[string]string) (string, error) {
+	t, err := template.New("render-string").Funcs(templateFuncMap()).Parse(content)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template string: %v", err)
+	}
+
+	var out strings.Builder
+	if err := t.Execute(&out, renderData); err != nil {
+		return "", fmt.Errorf("failed to render template string: %v", err)
+	}
+	return out.String(), nil
+}
+
 // WrapperShell :If target host already set env like DATADISK=/data in the clusterfile,
 // This function will WrapperShell cmd like:
 // Input shell: cat /etc/hosts
 // Output shell: DATADISK=/data cat /etc/hosts
 // it is convenient for user to get env in scripts
-// The scope of env comes from cluster.spec.env and host.env
+// The scope of env comes from cluster.spec.env and host.env, plus the
+// per-host variables injected by the infra driver (HostIP, HostRoles,
+// HostLabels); HostName is resolved on the target host itself.
 func WrapperShell(shell string, wrapperData map[string]string) string {
 	env := getEnvFromData(wrapperData)
 
 	if len(env) == 0 {
 		return shell
 	}
+	if _, ok := wrapperData[common.EnvHostName]; !ok {
+		env = append(env, fmt.Sprintf("export %s=\"$(uname -n)\";", common.EnvHostName))
+	}
 	return fmt.Sprintf("%s %s", strings.Join(env, " "), shell)
 }
 
@@ -37,7 +37,7 @@ func Test_processor_WrapperShell(t *testing.T) {
 				},
 				shell: "hostname",
 			},
-			"export IP=\"127.0.0.1\"; export foo=\"bar\"; hostname",
+			"export IP=\"127.0.0.1\"; export foo=\"bar\"; export HostName=\"$(uname -n)\"; hostname",
 		},
 	}
 	for _, tt := range tests {
@@ -80,3 +80,50 @@ func Test_processor_RenderAll(t *testing.T) {
 		})
 	}
 }
+
+func Test_RenderString(t *testing.T) {
+	renderData := map[string]string{
+		"Mode":   "ha",
+		"Passwd": "seadent",
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			"plain variable",
+			"mode: {{ .Mode }}",
+			"mode: ha",
+		},
+		{
+			"conditional block",
+			"{{ if eq .Mode \"ha\" }}replicas: 3{{ else }}replicas: 1{{ end }}",
+			"replicas: 3",
+		},
+		{
+			"sprig default for missing key",
+			"cidr: {{ .PodCIDR | default \"100.64.0.0/10\" }}",
+			"cidr: 100.64.0.0/10",
+		},
+		{
+			"historical b64enc keeps working",
+			"passwd: {{ .Passwd | b64enc }}",
+			"passwd: c2VhZGVudA==",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderString(tt.content, renderData)
+			if err != nil {
+				t.Errorf("RenderString() error = %v", err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("RenderString() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/sealerio/sealer/common"
+	imagev1 "github.com/sealerio/sealer/pkg/define/image/v1"
+)
+
+// templateVarRegexp matches the first-level field references of a template,
+// e.g. {{ .PodCIDR }} or {{ if eq .Mode "ha" }}. A regexp scan is a
+// heuristic, but good enough to flag typos before an install runs.
+var templateVarRegexp = regexp.MustCompile(`\{\{[^{}]*?\.([A-Za-z_][A-Za-z0-9_]*)`)
+
+// builtinEnvKeys are injected by sealer itself and never count as unused.
+var builtinEnvKeys = map[string]bool{
+	common.EnvHostIP:                 true,
+	common.EnvHostIPFamily:           true,
+	common.EnvHostName:               true,
+	common.EnvHostRoles:              true,
+	common.EnvHostLabels:             true,
+	common.EnvContainerRuntime:       true,
+	common.EnvIPv6DualStack:          true,
+	common.EnvRegistryDomain:         true,
+	common.EnvRegistryPort:           true,
+	common.EnvRegistryURL:            true,
+	common.EnvLocalRegistryDomain:    true,
+	common.EnvLocalRegistryPort:      true,
+	common.EnvLocalRegistryURL:       true,
+	common.EnvExternalRegistryDomain: true,
+	common.EnvExternalRegistryPort:   true,
+	common.EnvExternalRegistryURL:    true,
+	common.EnvCertSANs:               true,
+	common.EnvIPvsVIPForIPv4:         true,
+	common.EnvIPvsVIPForIPv6:         true,
+	common.EnvMaxJoinFailures:        true,
+	common.EnvSSHConcurrency:         true,
+	common.EnvSvcCIDR:                true,
+	common.EnvPodCIDR:                true,
+	common.EnvDNSDomain:              true,
+	common.EnvDNSSvcIP:               true,
+	common.EnvKubeSvcIP:              true,
+	common.EnvUseIPasNodeName:        true,
+	common.EnvNodeIP:                 true,
+	common.EnvNodeInterface:          true,
+	common.EnvLvscareImage:           true,
+	common.EnvFirewallMode:           true,
+	common.EnvSELinuxMode:            true,
+	common.EnvSecretEncryption:       true,
+	common.EnvKMSEndpoint:            true,
+	common.EnvAuditLog:               true,
+	common.EnvAuditLogMaxAge:         true,
+	common.EnvAuditLogMaxBackup:      true,
+	common.EnvAuditLogMaxSize:        true,
+	common.EnvHardeningProfile:       true,
+	common.EnvOIDCIssuerURL:          true,
+	common.EnvOIDCClientID:           true,
+	common.EnvOIDCUsernameClaim:      true,
+	common.EnvOIDCUsernamePrefix:     true,
+	common.EnvOIDCGroupsClaim:        true,
+	common.EnvCustomCACert:           true,
+	common.EnvCustomCAKey:            true,
+	common.EnvVIPMode:                true,
+	common.EnvKubeVIPImage:           true,
+	common.EnvKubeVIPMode:            true,
+	common.EnvKubeVIPInterface:       true,
+	common.EnvKubeVIPEnableServices:  true,
+	common.EnvKubeVIPBGPRouterID:     true,
+	common.EnvKubeVIPBGPAS:           true,
+	common.EnvKubeVIPBGPPeers:        true,
+}
+
+// CollectReferencedVars walks dir and returns the variable names referenced
+// by all ".tmpl" files below it.
+func CollectReferencedVars(dir string) (map[string]bool, error) {
+	referenced := make(map[string]bool)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, errIn error) error {
+		if errIn != nil {
+			return errIn
+		}
+		if info.IsDir() || !strings.HasSuffix(info.Name(), templateSuffix) {
+			return nil
+		}
+		content, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			return err
+		}
+		for name := range CollectVarsFromString(string(content)) {
+			referenced[name] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan templates under %s: %v", dir, err)
+	}
+	return referenced, nil
+}
+
+// CollectVarsFromString returns the variable names referenced by one
+// template string, e.g. the data of an app config.
+func CollectVarsFromString(content string) map[string]bool {
+	referenced := make(map[string]bool)
+	for _, match := range templateVarRegexp.FindAllStringSubmatch(content, -1) {
+		referenced[match[1]] = true
+	}
+	return referenced
+}
+
+// CheckRequiredEnv fails fast when an env key the image marked as required
+// is still unset. Keys with a default never fail: the default always
+// satisfies the requirement.
+func CheckRequiredEnv(providedEnv map[string]string, docs []imagev1.EnvDoc) error {
+	var missing []string
+	for _, doc := range docs {
+		if !doc.Required || doc.Default != "" {
+			continue
+		}
+		if v := providedEnv[doc.Key]; v != "" {
+			continue
+		}
+		if doc.Description != "" {
+			missing = append(missing, fmt.Sprintf("%s (%s)", doc.Key, doc.Description))
+		} else {
+			missing = append(missing, doc.Key)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	sort.Strings(missing)
+	return fmt.Errorf("the image requires env which is not set, add it via --env or the Clusterfile: %s", strings.Join(missing, ", "))
+}
+
+// LintEnvUsage compares the env provided via Clusterfile/CLI with the
+// variables the image actually consumes and returns human readable
+// warnings: provided vars nothing references (likely typos) and vars the
+// image declares without a default (required) that are still unset.
+func LintEnvUsage(providedEnv, imageDefaults map[string]string, referenced map[string]bool) []string {
+	var warnings []string
+
+	var unused []string
+	for key := range providedEnv {
+		if builtinEnvKeys[key] || referenced[key] {
+			continue
+		}
+		if _, declared := imageDefaults[key]; declared {
+			continue
+		}
+		unused = append(unused, key)
+	}
+	sort.Strings(unused)
+	for _, key := range unused {
+		warnings = append(warnings, fmt.Sprintf("env %q is not referenced by any template of the image, possible typo", key))
+	}
+
+	var missing []string
+	for key, def := range imageDefaults {
+		if def != "" {
+			continue
+		}
+		if v, ok := providedEnv[key]; !ok || v == "" {
+			missing = append(missing, key)
+		}
+	}
+	sort.Strings(missing)
+	for _, key := range missing {
+		warnings = append(warnings, fmt.Sprintf("env %q is declared by the image without a default and is not set", key))
+	}
+
+	return warnings
+}
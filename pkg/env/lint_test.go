@@ -0,0 +1,82 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"testing"
+
+	imagev1 "github.com/sealerio/sealer/pkg/define/image/v1"
+)
+
+func Test_CollectVarsFromString(t *testing.T) {
+	content := `cidr: {{ .PodCIDR }}
+{{ if eq .Mode "ha" }}replicas: 3{{ end }}
+passwd: {{ .Passwd | b64enc }}`
+
+	vars := CollectVarsFromString(content)
+	for _, want := range []string{"PodCIDR", "Mode", "Passwd"} {
+		if !vars[want] {
+			t.Errorf("CollectVarsFromString() missing %s", want)
+		}
+	}
+	if len(vars) != 3 {
+		t.Errorf("CollectVarsFromString() = %v, want 3 vars", vars)
+	}
+}
+
+func Test_LintEnvUsage(t *testing.T) {
+	providedEnv := map[string]string{
+		"PodCIDR":  "100.64.0.0/10",
+		"PodCIDRR": "typo",
+		"HostIP":   "builtin, never reported",
+	}
+	imageDefaults := map[string]string{
+		"Mode":     "ha",
+		"Required": "",
+	}
+	referenced := map[string]bool{"PodCIDR": true, "Mode": true}
+
+	warnings := LintEnvUsage(providedEnv, imageDefaults, referenced)
+	if len(warnings) != 2 {
+		t.Fatalf("LintEnvUsage() = %v, want 2 warnings", warnings)
+	}
+	if want := `env "PodCIDRR" is not referenced by any template of the image, possible typo`; warnings[0] != want {
+		t.Errorf("LintEnvUsage()[0] = %v, want %v", warnings[0], want)
+	}
+	if want := `env "Required" is declared by the image without a default and is not set`; warnings[1] != want {
+		t.Errorf("LintEnvUsage()[1] = %v, want %v", warnings[1], want)
+	}
+}
+
+func Test_CheckRequiredEnv(t *testing.T) {
+	docs := []imagev1.EnvDoc{
+		{Key: "Mode", Default: "ha", Required: true},
+		{Key: "VaultToken", Required: true, Description: "token to fetch secrets"},
+		{Key: "PodCIDR", Description: "optional"},
+	}
+
+	err := CheckRequiredEnv(map[string]string{"VaultToken": "s.xxx"}, docs)
+	if err != nil {
+		t.Errorf("CheckRequiredEnv() error = %v, want nil", err)
+	}
+
+	err = CheckRequiredEnv(map[string]string{}, docs)
+	if err == nil {
+		t.Fatal("CheckRequiredEnv() = nil, want error for unset VaultToken")
+	}
+	if want := "the image requires env which is not set, add it via --env or the Clusterfile: VaultToken (token to fetch secrets)"; err.Error() != want {
+		t.Errorf("CheckRequiredEnv() = %v, want %v", err, want)
+	}
+}
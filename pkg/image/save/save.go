@@ -353,7 +353,7 @@ func (is *DefaultImageSaver) saveManifestAndGetDigest(nameds []Named, repo distr
 			}
 			imageDigest, err := is.handleManifest(manifest, desc.Digest, platform)
 			if err != nil {
-				return fmt.Errorf("failed to get digest: %v", err)
+				return fmt.Errorf("failed to resolve the manifest of image %s:%s for platform %s: %v", tmpnamed.repo, tmpnamed.tag, platform.ToString(), err)
 			}
 
 			lock.Lock()
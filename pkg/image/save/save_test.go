@@ -15,7 +15,10 @@
 package save
 
 import (
+	"strings"
 	"testing"
+
+	v1 "github.com/sealerio/sealer/types/api/v1"
 )
 
 /* func TestSaveImages(t *testing.T) {
@@ -116,3 +119,71 @@ func Test_parseNormalizedNamed(t *testing.T) {
 		})
 	}
 }
+
+func Test_matched(t *testing.T) {
+	tests := []struct {
+		name string
+		src  v1.Platform
+		dest v1.Platform
+		want bool
+	}{
+		{
+			name: "same os and architecture",
+			src:  v1.Platform{OS: "linux", Architecture: "amd64"},
+			dest: v1.Platform{OS: "linux", Architecture: "amd64"},
+			want: true,
+		},
+		{
+			name: "different architecture",
+			src:  v1.Platform{OS: "linux", Architecture: "arm64"},
+			dest: v1.Platform{OS: "linux", Architecture: "amd64"},
+			want: false,
+		},
+		{
+			name: "arm64 ignores variant",
+			src:  v1.Platform{OS: "linux", Architecture: "arm64", Variant: "v8"},
+			dest: v1.Platform{OS: "linux", Architecture: "arm64"},
+			want: true,
+		},
+		{
+			name: "empty variant matches any variant",
+			src:  v1.Platform{OS: "linux", Architecture: "arm"},
+			dest: v1.Platform{OS: "linux", Architecture: "arm", Variant: "v7"},
+			want: true,
+		},
+		{
+			name: "different variant",
+			src:  v1.Platform{OS: "linux", Architecture: "arm", Variant: "v6"},
+			dest: v1.Platform{OS: "linux", Architecture: "arm", Variant: "v7"},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matched(tt.src, tt.dest); got != tt.want {
+				t.Errorf("matched(%v, %v) = %v, want %v", tt.src, tt.dest, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_getImageManifestDigest(t *testing.T) {
+	payload := []byte(`{"schemaVersion":2,"manifests":[
+		{"digest":"sha256:aaa","platform":{"os":"linux","architecture":"amd64"}},
+		{"digest":"sha256:bbb","platform":{"os":"linux","architecture":"arm64","variant":"v8"}}]}`)
+
+	got, err := getImageManifestDigest(payload, v1.Platform{OS: "linux", Architecture: "arm64"})
+	if err != nil || string(got) != "sha256:bbb" {
+		t.Errorf("getImageManifestDigest() = %s, %v, want sha256:bbb", got, err)
+	}
+
+	_, err = getImageManifestDigest(payload, v1.Platform{OS: "linux", Architecture: "riscv64"})
+	if err == nil {
+		t.Fatal("expected an error for a platform the image does not provide")
+	}
+	for _, want := range []string{"linux/riscv64", "linux/amd64", "linux/arm64/v8"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q should mention %s", err.Error(), want)
+		}
+	}
+}
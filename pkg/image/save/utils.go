@@ -164,15 +164,19 @@ func getImageManifestDigest(payload []byte, plat v1.Platform) (digest.Digest, er
 		return "", fmt.Errorf("json unmarshal error: %v", err)
 	}
 
-	var resDigest []digest.Digest
+	var (
+		resDigest []digest.Digest
+		available []string
+	)
 	for _, item := range manifestList.List {
+		available = append(available, item.Platform.ToString())
 		if matched(item.Platform, plat) {
 			resDigest = append(resDigest, digest.Digest(item.Digest))
 		}
 	}
 
 	if len(resDigest) == 0 {
-		return "", fmt.Errorf("no manifest of the corresponding platform")
+		return "", fmt.Errorf("no manifest matches the requested platform %s, the image provides: %s", plat.ToString(), strings.Join(available, ", "))
 	}
 
 	if len(resDigest) > 1 {
@@ -181,14 +185,15 @@ func getImageManifestDigest(payload []byte, plat v1.Platform) (digest.Digest, er
 	return resDigest[0], nil
 }
 
-// Matched check if src == dest
+// matched reports whether the manifest platform src serves the requested
+// platform dest. arm64 manifests are all effectively v8, and a variant left
+// empty on either side matches any variant of the same architecture.
 func matched(src, dest v1.Platform) bool {
-	if src.OS == dest.OS &&
-		src.Architecture == "arm64" && dest.Architecture == "arm64" {
+	if src.OS != dest.OS || src.Architecture != dest.Architecture {
+		return false
+	}
+	if src.Architecture == "arm64" || src.Variant == "" || dest.Variant == "" {
 		return true
 	}
-
-	return src.OS == dest.OS &&
-		src.Architecture == dest.Architecture &&
-		src.Variant == dest.Variant
+	return src.Variant == dest.Variant
 }
@@ -0,0 +1,132 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imagedistributor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/sealerio/sealer/utils/hash"
+)
+
+// RootfsManifestFileName is the file name of the rootfs file manifest which
+// is saved under the remote rootfs cache dir after each distribution. It maps
+// the relative path of each distributed file to its md5 sum and is used to
+// compute the delta against a new image version on re-apply.
+const RootfsManifestFileName = "manifest.json"
+
+// rootfsDiff is the result of comparing the local mounted rootfs with the
+// manifest of the rootfs currently distributed on a remote host.
+type rootfsDiff struct {
+	// Changed holds relative paths of files which are new or whose content differs.
+	Changed []string
+	// Removed holds relative paths of files which no longer exist in the new rootfs.
+	Removed []string
+}
+
+func (d rootfsDiff) isEmpty() bool {
+	return len(d.Changed) == 0 && len(d.Removed) == 0
+}
+
+// buildRootfsManifest walks the mounted rootfs dir and counts md5 for each
+// regular file, keyed by its path relative to mountDir. The registry data dir
+// and distribution cache flags are not part of the synced rootfs, so they are
+// skipped.
+func buildRootfsManifest(mountDir, imageID string) (map[string]string, error) {
+	manifest := map[string]string{}
+
+	err := filepath.Walk(mountDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(mountDir, path)
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			// skip registry directory, it is distributed separately.
+			if rel == RegistryDirName {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		// skip the image cache flag written by former distributions.
+		if rel == imageID {
+			return nil
+		}
+
+		md5sum, err := hash.FileMD5(path)
+		if err != nil {
+			return fmt.Errorf("failed to count md5 of %s: %v", path, err)
+		}
+
+		manifest[rel] = md5sum
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// diffRootfsManifest compares the desired manifest built from the new image
+// with the manifest of the rootfs currently distributed on the remote host.
+func diffRootfsManifest(desired, current map[string]string) rootfsDiff {
+	var diff rootfsDiff
+
+	for path, md5sum := range desired {
+		if current[path] != md5sum {
+			diff.Changed = append(diff.Changed, path)
+		}
+	}
+
+	for path := range current {
+		if _, ok := desired[path]; !ok {
+			diff.Removed = append(diff.Removed, path)
+		}
+	}
+
+	sort.Strings(diff.Changed)
+	sort.Strings(diff.Removed)
+	return diff
+}
+
+func marshalRootfsManifest(manifest map[string]string) ([]byte, error) {
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal rootfs manifest: %v", err)
+	}
+	return content, nil
+}
+
+func unmarshalRootfsManifest(content []byte) (map[string]string, error) {
+	manifest := map[string]string{}
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal rootfs manifest: %v", err)
+	}
+	return manifest, nil
+}
@@ -0,0 +1,331 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imagedistributor
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+
+	"github.com/sealerio/sealer/common"
+	"github.com/sealerio/sealer/pkg/infradriver"
+	v1 "github.com/sealerio/sealer/types/api/v1"
+	v2 "github.com/sealerio/sealer/types/api/v2"
+	"github.com/sealerio/sealer/utils/archive"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// registryRootfsRepo is the repository inside the local registry that
+	// holds the published rootfs artifacts, one tag per image ID.
+	registryRootfsRepo = "sealer/rootfs"
+
+	rootfsLayerMediaType  = "application/vnd.sealer.rootfs.tar.gzip"
+	rootfsConfigMediaType = "application/vnd.sealer.rootfs.config.v1+json"
+	ociManifestMediaType  = "application/vnd.oci.image.manifest.v1+json"
+)
+
+// registryDistributor publishes the rendered rootfs to the local sea.hub
+// registry as an OCI artifact once, and lets every host pull it from there
+// with a resumable download, instead of master0 scp'ing the whole directory
+// to each host. The registry only starts after the rootfs first reaches the
+// hosts, so a fresh install transparently degrades to scp distribution;
+// scale-up and upgrade hit the fast path.
+type registryDistributor struct {
+	scp            *scpDistributor
+	infraDriver    infradriver.InfraDriver
+	imageMountInfo []ClusterImageMountInfo
+	registry       v2.Registry
+	rootfsCacheDir string
+	options        DistributeOption
+}
+
+// DistributeRegistry always copies over scp: the registry data seeds the
+// registry itself, so it cannot be pulled from it.
+func (r *registryDistributor) DistributeRegistry(deployHosts []net.IP, dataDir string) error {
+	return r.scp.DistributeRegistry(deployHosts, dataDir)
+}
+
+func (r *registryDistributor) Restore(targetDir string, hosts []net.IP) error {
+	return r.scp.Restore(targetDir, hosts)
+}
+
+func (r *registryDistributor) Distribute(hosts []net.IP, dest string) error {
+	local := r.registry.LocalRegistry
+	if local == nil {
+		logrus.Debugf("cluster has no local registry, fall back to scp distribution")
+		return r.scp.Distribute(hosts, dest)
+	}
+
+	masters := r.infraDriver.GetHostIPListByRole(common.MASTER)
+	if len(masters) == 0 {
+		return r.scp.Distribute(hosts, dest)
+	}
+	pusher := masters[0]
+
+	endpoint := r.endpoint(local)
+	probe := fmt.Sprintf("curl -skf -m 5 %s -o /dev/null %s/v2/", r.curlAuth(local), endpoint)
+	if err := r.infraDriver.CmdAsync(pusher, nil, probe); err != nil {
+		logrus.Infof("registry %s is not serving yet, fall back to scp distribution", endpoint)
+		return r.scp.Distribute(hosts, dest)
+	}
+
+	for _, info := range r.imageMountInfo {
+		if err := r.scp.dumpConfigToRootfs(info.MountDir); err != nil {
+			return err
+		}
+
+		if err := r.scp.renderRootfs(info.MountDir); err != nil {
+			return err
+		}
+
+		digest, err := r.publishRootfs(pusher, info, local)
+		if err != nil {
+			logrus.Warnf("failed to publish rootfs of image %s to registry %s, fall back to scp distribution: %v",
+				info.ImageID, endpoint, err)
+			return r.scp.Distribute(hosts, dest)
+		}
+
+		eg, _ := errgroup.WithContext(context.Background())
+		localCacheFile := filepath.Join(info.MountDir, info.ImageID)
+		remoteCacheFile := filepath.Join(r.rootfsCacheDir, info.ImageID)
+
+		for _, ip := range info.Hosts {
+			host := ip
+			eg.Go(func() error {
+				if !r.options.IgnoreCache {
+					existed, err := r.infraDriver.IsFileExist(host, remoteCacheFile)
+					if err != nil {
+						return fmt.Errorf("failed to detect rootfs cache %s on host %s: %v",
+							remoteCacheFile, host.String(), err)
+					}
+
+					if existed {
+						logrus.Debugf("cache %s hits on: %s, skip to do distribution", info.ImageID, host.String())
+						return nil
+					}
+				}
+
+				if err := r.pullRootfs(host, dest, digest, info.ImageID, local); err != nil {
+					logrus.Warnf("failed to pull rootfs from registry on host %s, fall back to scp: %v",
+						host.String(), err)
+					if err := r.scp.filterCopy(info.MountDir, dest, host); err != nil {
+						return fmt.Errorf("failed to copy rootfs files: %v", err)
+					}
+				}
+
+				if err := r.scp.writeCacheFlag(localCacheFile, remoteCacheFile, host); err != nil {
+					return fmt.Errorf("failed to write rootfs cache %s on host %s: %v",
+						remoteCacheFile, host.String(), err)
+				}
+
+				return nil
+			})
+		}
+
+		if err := eg.Wait(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// publishRootfs tars the rendered rootfs locally, uploads the single
+// compressed blob to the pusher host and pushes it into the registry as an
+// OCI artifact tagged with the image ID. It returns the layer digest the
+// pull hosts fetch.
+func (r *registryDistributor) publishRootfs(pusher net.IP, info ClusterImageMountInfo, local *v2.LocalRegistry) (string, error) {
+	blobPath, digest, size, err := r.buildRootfsBlob(info)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err := os.Remove(blobPath); err != nil {
+			logrus.Warnf("failed to delete intermediate file %s: %v", blobPath, err)
+		}
+	}()
+
+	var (
+		auth     = r.curlAuth(local)
+		endpoint = r.endpoint(local)
+	)
+
+	// skip the upload when the registry already holds the blob, e.g. a
+	// previous run of the same image was interrupted after the push.
+	headBlob := fmt.Sprintf("curl -skf %s -o /dev/null %s/v2/%s/blobs/%s", auth, endpoint, registryRootfsRepo, digest)
+	if err := r.infraDriver.CmdAsync(pusher, nil, headBlob); err != nil {
+		remoteBlob := fmt.Sprintf("/tmp/sealer-rootfs-%s.tar.gz", info.ImageID)
+		if err := r.infraDriver.Copy(pusher, blobPath, remoteBlob); err != nil {
+			return "", fmt.Errorf("failed to copy rootfs blob to host %s: %v", pusher.String(), err)
+		}
+
+		uploadBlob := fmt.Sprintf("%s && rm -f %s", r.uploadBlobCmd(local, fmt.Sprintf("--data-binary @%s", remoteBlob), digest), remoteBlob)
+		if err := r.infraDriver.CmdAsync(pusher, nil, uploadBlob); err != nil {
+			return "", fmt.Errorf("failed to upload rootfs blob %s: %v", digest, err)
+		}
+	}
+
+	// an artifact manifest keeps the blob referenced, so a registry
+	// garbage collection run does not delete it between scale-ups.
+	configDigest := fmt.Sprintf("sha256:%x", sha256.Sum256([]byte("{}")))
+	uploadConfig := r.uploadBlobCmd(local, "--data-binary '{}'", configDigest)
+	if err := r.infraDriver.CmdAsync(pusher, nil, uploadConfig); err != nil {
+		return "", fmt.Errorf("failed to upload rootfs config blob: %v", err)
+	}
+
+	manifest := fmt.Sprintf(`{"schemaVersion":2,"mediaType":"%s","config":{"mediaType":"%s","digest":"%s","size":2},"layers":[{"mediaType":"%s","digest":"%s","size":%d}]}`,
+		ociManifestMediaType, rootfsConfigMediaType, configDigest, rootfsLayerMediaType, digest, size)
+	putManifest := fmt.Sprintf("curl -skf %s -X PUT -H 'Content-Type: %s' --data-binary '%s' %s/v2/%s/manifests/%s",
+		auth, ociManifestMediaType, manifest, endpoint, registryRootfsRepo, info.ImageID)
+	if err := r.infraDriver.CmdAsync(pusher, nil, putManifest); err != nil {
+		return "", fmt.Errorf("failed to push rootfs manifest: %v", err)
+	}
+
+	logrus.Infof("published rootfs of image %s to %s/%s", info.ImageID, endpoint, registryRootfsRepo)
+	return digest, nil
+}
+
+// uploadBlobCmd renders the registry two step blob upload: start an upload
+// session, then put the data against the returned location.
+func (r *registryDistributor) uploadBlobCmd(local *v2.LocalRegistry, data, digest string) string {
+	var (
+		auth     = r.curlAuth(local)
+		endpoint = r.endpoint(local)
+	)
+
+	return fmt.Sprintf(`location=$(curl -skf %s -X POST -D - -o /dev/null %s/v2/%s/blobs/uploads/ | tr -d '\r' | awk 'tolower($1)=="location:" {print $2}') && case "$location" in /*) location=%s$location ;; esac && case "$location" in *\?*) sep='&' ;; *) sep='?' ;; esac && curl -skf %s -X PUT -H 'Content-Type: application/octet-stream' %s "$location${sep}digest=%s"`,
+		auth, endpoint, registryRootfsRepo, endpoint, auth, data, digest)
+}
+
+// pullRootfs fetches the rootfs blob on the target host with a resumable
+// download, verifies its digest and unpacks it into dest.
+func (r *registryDistributor) pullRootfs(host net.IP, dest, digest, imageID string, local *v2.LocalRegistry) error {
+	blob := fmt.Sprintf("/tmp/sealer-rootfs-%s.tar.gz", imageID)
+	cmd := fmt.Sprintf("mkdir -p %s && curl -sk %s --retry 3 -C - -o %s %s/v2/%s/blobs/%s && echo '%s  %s' | sha256sum -c - >/dev/null && tar -xzf %s -C %s && rm -f %s",
+		dest, r.curlAuth(local), blob, r.endpoint(local), registryRootfsRepo, digest,
+		digestHex(digest), blob, blob, dest, blob)
+
+	return r.infraDriver.CmdAsync(host, nil, cmd)
+}
+
+// buildRootfsBlob tars every top level entry of the mounted rootfs except
+// the registry data into a gzip compressed blob and returns its path, OCI
+// digest and size.
+func (r *registryDistributor) buildRootfsBlob(info ClusterImageMountInfo) (string, string, int64, error) {
+	files, err := os.ReadDir(info.MountDir)
+	if err != nil {
+		return "", "", 0, fmt.Errorf("failed to read dir %s: %s", info.MountDir, err)
+	}
+
+	var paths []string
+	for _, f := range files {
+		// skip registry directory, it seeds the registry itself
+		if f.IsDir() && f.Name() == RegistryDirName {
+			continue
+		}
+		paths = append(paths, filepath.Join(info.MountDir, f.Name()))
+	}
+
+	if len(paths) == 0 {
+		return "", "", 0, fmt.Errorf("nothing to distribute in %s", info.MountDir)
+	}
+
+	tarReader, err := archive.TarWithRootDir(paths...)
+	if err != nil {
+		return "", "", 0, fmt.Errorf("failed to get tar reader for %s, err: %s", info.MountDir, err)
+	}
+	defer func() {
+		if err := tarReader.Close(); err != nil {
+			logrus.Errorf("failed to close file: %v", err)
+		}
+	}()
+
+	gzReader, done := archive.GzipCompress(tarReader)
+
+	blobPath := filepath.Join(os.TempDir(), fmt.Sprintf("sealer-rootfs-%s.tar.gz", info.ImageID))
+	blobFile, err := os.Create(filepath.Clean(blobPath))
+	if err != nil {
+		return "", "", 0, fmt.Errorf("failed to create %s, err: %v", blobPath, err)
+	}
+	defer func() {
+		if err := blobFile.Close(); err != nil {
+			logrus.Errorf("failed to close file: %v", err)
+		}
+	}()
+
+	hasher := sha256.New()
+	size, err := io.Copy(io.MultiWriter(blobFile, hasher), gzReader)
+	if err != nil {
+		return "", "", 0, fmt.Errorf("failed to write rootfs blob %s: %v", blobPath, err)
+	}
+	<-done
+
+	return blobPath, "sha256:" + hex.EncodeToString(hasher.Sum(nil)), size, nil
+}
+
+func (r *registryDistributor) endpoint(local *v2.LocalRegistry) string {
+	domain := local.Domain
+	if domain == "" {
+		domain = common.DefaultRegistryDomain
+	}
+
+	port := local.Port
+	if port == 0 {
+		port = common.DefaultRegistryPort
+	}
+
+	return fmt.Sprintf("https://%s:%d", domain, port)
+}
+
+func (r *registryDistributor) curlAuth(local *v2.LocalRegistry) string {
+	if local.Username == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("-u '%s:%s'", local.Username, local.Password)
+}
+
+func digestHex(digest string) string {
+	const prefix = "sha256:"
+	if len(digest) > len(prefix) {
+		return digest[len(prefix):]
+	}
+	return digest
+}
+
+func NewRegistryDistributor(imageMountInfo []ClusterImageMountInfo, driver infradriver.InfraDriver, configs []v1.Config, options DistributeOption) (Distributor, error) {
+	return &registryDistributor{
+		scp: &scpDistributor{
+			configs:          configs,
+			imageMountInfo:   imageMountInfo,
+			infraDriver:      driver,
+			registryCacheDir: filepath.Join(driver.GetClusterRootfsPath(), "cache", RegistryCacheDirName),
+			rootfsCacheDir:   filepath.Join(driver.GetClusterRootfsPath(), "cache", RootfsCacheDirName),
+			options:          options,
+		},
+		infraDriver:    driver,
+		imageMountInfo: imageMountInfo,
+		registry:       driver.GetClusterRegistry(),
+		rootfsCacheDir: filepath.Join(driver.GetClusterRootfsPath(), "cache", RootfsCacheDirName),
+		options:        options,
+	}, nil
+}
@@ -106,6 +106,19 @@ func (s *scpDistributor) Distribute(hosts []net.IP, dest string) error {
 			return err
 		}
 
+		// build the manifest of the new rootfs once, it is used to delta
+		// sync against hosts which already hold a former image version and
+		// saved on each host for the next re-apply.
+		desiredManifest, err := buildRootfsManifest(info.MountDir, info.ImageID)
+		if err != nil {
+			return fmt.Errorf("failed to build rootfs manifest: %v", err)
+		}
+
+		localManifestFile, err := s.saveLocalManifest(desiredManifest)
+		if err != nil {
+			return err
+		}
+
 		eg, _ := errgroup.WithContext(context.Background())
 		localCacheFile := filepath.Join(info.MountDir, info.ImageID)
 		remoteCacheFile := filepath.Join(s.rootfsCacheDir, info.ImageID)
@@ -125,12 +138,31 @@ func (s *scpDistributor) Distribute(hosts []net.IP, dest string) error {
 						logrus.Debugf("cache %s hits on: %s, skip to do distribution", info.ImageID, host.String())
 						return nil
 					}
+
+					// the host holds a former rootfs version, only ship the
+					// changed pieces against its manifest.
+					synced, err := s.deltaCopy(info.MountDir, dest, host, desiredManifest)
+					if err != nil {
+						return fmt.Errorf("failed to delta sync rootfs on host %s: %v", host.String(), err)
+					}
+
+					if !synced {
+						// no former manifest on the host, fall back to full copy.
+						if err := s.filterCopy(info.MountDir, dest, host); err != nil {
+							return fmt.Errorf("failed to copy rootfs files: %v", err)
+						}
+					}
+				} else {
+					// copy rootfs data
+					if err := s.filterCopy(info.MountDir, dest, host); err != nil {
+						return fmt.Errorf("failed to copy rootfs files: %v", err)
+					}
 				}
 
-				// copy rootfs data
-				err := s.filterCopy(info.MountDir, dest, host)
+				// save the new manifest for the next delta sync
+				err := s.infraDriver.Copy(host, localManifestFile, filepath.Join(s.rootfsCacheDir, RootfsManifestFileName))
 				if err != nil {
-					return fmt.Errorf("failed to copy rootfs files: %v", err)
+					return fmt.Errorf("failed to copy rootfs manifest on host %s: %v", host.String(), err)
 				}
 
 				// write cache flag
@@ -144,7 +176,11 @@ func (s *scpDistributor) Distribute(hosts []net.IP, dest string) error {
 			})
 		}
 
-		if err := eg.Wait(); err != nil {
+		err = eg.Wait()
+		if cleanErr := os.Remove(localManifestFile); cleanErr != nil {
+			logrus.Warnf("failed to clean local rootfs manifest %s: %v", localManifestFile, cleanErr)
+		}
+		if err != nil {
 			return err
 		}
 	}
@@ -152,6 +188,96 @@ func (s *scpDistributor) Distribute(hosts []net.IP, dest string) error {
 	return nil
 }
 
+// saveLocalManifest dumps the rootfs manifest to a local temp file, it will
+// be copied to the rootfs cache dir of each host after distribution.
+func (s *scpDistributor) saveLocalManifest(manifest map[string]string) (string, error) {
+	content, err := marshalRootfsManifest(manifest)
+	if err != nil {
+		return "", err
+	}
+
+	tmpFile, err := os.CreateTemp("", "sealer-rootfs-manifest")
+	if err != nil {
+		return "", fmt.Errorf("failed to create local rootfs manifest file: %v", err)
+	}
+
+	if _, err := tmpFile.Write(content); err != nil {
+		_ = tmpFile.Close()
+		return "", fmt.Errorf("failed to write local rootfs manifest file: %v", err)
+	}
+
+	return tmpFile.Name(), tmpFile.Close()
+}
+
+// deltaCopy ships only the files which differ from the rootfs currently
+// distributed on the host, described by the manifest saved under the remote
+// cache dir. It returns false if the host holds no manifest, which means a
+// full copy is needed.
+func (s *scpDistributor) deltaCopy(mountDir, dest string, host net.IP, desiredManifest map[string]string) (bool, error) {
+	remoteManifestFile := filepath.Join(s.rootfsCacheDir, RootfsManifestFileName)
+
+	existed, err := s.infraDriver.IsFileExist(host, remoteManifestFile)
+	if err != nil {
+		return false, fmt.Errorf("failed to detect rootfs manifest %s: %v", remoteManifestFile, err)
+	}
+	if !existed {
+		return false, nil
+	}
+
+	tmpFile, err := os.CreateTemp("", "sealer-remote-manifest")
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		if err := os.Remove(tmpFile.Name()); err != nil {
+			logrus.Warnf("failed to clean remote rootfs manifest copy %s: %v", tmpFile.Name(), err)
+		}
+	}()
+	if err := tmpFile.Close(); err != nil {
+		return false, err
+	}
+
+	if err := s.infraDriver.CopyR(host, remoteManifestFile, tmpFile.Name()); err != nil {
+		return false, fmt.Errorf("failed to fetch rootfs manifest %s: %v", remoteManifestFile, err)
+	}
+
+	content, err := os.ReadFile(filepath.Clean(tmpFile.Name()))
+	if err != nil {
+		return false, err
+	}
+
+	currentManifest, err := unmarshalRootfsManifest(content)
+	if err != nil {
+		// former manifest is broken, fall back to full copy.
+		logrus.Warnf("invalid rootfs manifest on host %s, fall back to full copy: %v", host.String(), err)
+		return false, nil
+	}
+
+	diff := diffRootfsManifest(desiredManifest, currentManifest)
+	if diff.isEmpty() {
+		logrus.Debugf("rootfs on host %s is up to date, skip to do distribution", host.String())
+		return true, nil
+	}
+
+	logrus.Infof("delta sync rootfs on host %s: %d files changed, %d files removed",
+		host.String(), len(diff.Changed), len(diff.Removed))
+
+	for _, rel := range diff.Changed {
+		if err := s.infraDriver.Copy(host, filepath.Join(mountDir, rel), filepath.Join(dest, rel)); err != nil {
+			return false, fmt.Errorf("failed to copy changed rootfs file %s: %v", rel, err)
+		}
+	}
+
+	for _, rel := range diff.Removed {
+		rmCMD := fmt.Sprintf("rm -f %s", filepath.Join(dest, rel))
+		if err := s.infraDriver.CmdAsync(host, nil, rmCMD); err != nil {
+			return false, fmt.Errorf("failed to remove stale rootfs file %s: %v", rel, err)
+		}
+	}
+
+	return true, nil
+}
+
 func (s *scpDistributor) filterCopy(mountDir, dest string, host net.IP) error {
 	files, err := os.ReadDir(mountDir)
 	if err != nil {
@@ -32,6 +32,7 @@ import (
 	buildahutil "github.com/containers/buildah/pkg/util"
 	"github.com/containers/buildah/util"
 	"github.com/containers/common/pkg/auth"
+	"github.com/docker/distribution/reference"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
@@ -90,6 +91,7 @@ func (engine *Engine) migrateFlags2Wrapper(opts *options.BuildOptions, wrapper *
 	// cache intermediate layers during build, it is enabled when len(opts.Platforms) <= 1 and "no-cache" is false
 	wrapper.Layers = len(opts.Platforms) <= 1 && !opts.NoCache
 	wrapper.NoCache = opts.NoCache
+	wrapper.Squash = opts.Squash
 	// tags. Like -t kubernetes:v1.16
 	wrapper.Tag = []string{opts.Tag}
 	// Hardcoded for network configuration.
@@ -106,6 +108,10 @@ func (engine *Engine) migrateFlags2Wrapper(opts *options.BuildOptions, wrapper *
 	wrapper.Pull = opts.PullPolicy
 	wrapper.Label = append(wrapper.Label, opts.Labels...)
 	wrapper.Annotation = append(wrapper.Annotation, opts.Annotations...)
+	// remote layer cache shared between build machines
+	wrapper.CacheFrom = opts.CacheFrom
+	wrapper.CacheTo = opts.CacheTo
+	wrapper.CacheTTL = opts.CacheTTL
 	return nil
 }
 
@@ -259,6 +265,32 @@ func (engine *Engine) wrapper2Options(opts *options.BuildOptions, wrapper *build
 		compression = define.Uncompressed
 	}
 
+	// a remote repository acting as layer cache source/destination; pushing
+	// cache layers only works with layered builds
+	var cacheFrom, cacheTo reference.Named
+	if wrapper.CacheFrom != "" {
+		cacheFrom, err = parse.RepoNameToNamedReference(wrapper.CacheFrom)
+		if err != nil {
+			return define.BuildOptions{}, []string{}, errors.Wrapf(err, "unable to parse --cache-from %q", wrapper.CacheFrom)
+		}
+	}
+	if wrapper.CacheTo != "" {
+		if !layers {
+			return define.BuildOptions{}, []string{}, errors.New("--cache-to requires layered builds, it cannot be combined with --no-cache or multi-platform builds")
+		}
+		cacheTo, err = parse.RepoNameToNamedReference(wrapper.CacheTo)
+		if err != nil {
+			return define.BuildOptions{}, []string{}, errors.Wrapf(err, "unable to parse --cache-to %q", wrapper.CacheTo)
+		}
+	}
+	var cacheTTL time.Duration
+	if wrapper.CacheTTL != "" {
+		cacheTTL, err = time.ParseDuration(wrapper.CacheTTL)
+		if err != nil {
+			return define.BuildOptions{}, []string{}, errors.Wrapf(err, "unable to parse --cache-ttl %q", wrapper.CacheTTL)
+		}
+	}
+
 	options := define.BuildOptions{
 		AddCapabilities:         wrapper.CapAdd,
 		AdditionalTags:          tags,
@@ -267,6 +299,9 @@ func (engine *Engine) wrapper2Options(opts *options.BuildOptions, wrapper *build
 		Architecture:            systemCxt.ArchitectureChoice,
 		Args:                    args,
 		BlobDirectory:           wrapper.BlobCache,
+		CacheFrom:               cacheFrom,
+		CacheTo:                 cacheTo,
+		CacheTTL:                cacheTTL,
 		CNIConfigDir:            wrapper.CNIConfigDir,
 		CNIPluginPath:           wrapper.CNIPlugInPath,
 		CommonBuildOpts:         commonOpts,
@@ -26,12 +26,14 @@ import (
 
 	"github.com/containers/buildah/define"
 	"github.com/containers/buildah/pkg/parse"
+	"github.com/containers/common/pkg/config"
 	"github.com/containers/common/pkg/umask"
 	is "github.com/containers/image/v5/storage"
 	"github.com/containers/image/v5/types"
 	"github.com/containers/storage"
 	"github.com/containers/storage/pkg/unshare"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 )
 
 const (
@@ -45,8 +47,31 @@ const (
 	OCIArchive      = "oci-archive"
 	V2s2ManifestDir = "docker-dir"
 	V2s2Archive     = "docker-archive"
+	// OCILayout saves the image as a plain OCI image layout directory, so
+	// the result can be consumed directly by OCI tooling such as skopeo or
+	// oras without unpacking sealer's multi-arch tar bundle first.
+	OCILayout = "oci"
+	// SealerArchive names sealer's own multi-arch tar bundle explicitly:
+	// per-instance oci archives plus the manifest metadata file.
+	SealerArchive = "sealer"
 )
 
+// setMaxParallelLayers caps how many layers the underlying image copy code
+// transfers concurrently for the rest of the process. The copier reads this
+// from the process-wide containers config, so the cap is planted there; a
+// value of zero or less keeps the engine default.
+func setMaxParallelLayers(n int) {
+	if n <= 0 {
+		return
+	}
+	defaultConfig, err := config.Default()
+	if err != nil {
+		logrus.Warnf("failed to load container config to cap layer parallelism: %v", err)
+		return
+	}
+	defaultConfig.Engine.ImageParallelCopies = uint(n)
+}
+
 func getStore(configurations *options.EngineGlobalConfigurations) (storage.Store, error) {
 	options, err := storage.DefaultStoreOptions(unshare.IsRootless(), unshare.GetRootlessUID())
 	if err != nil {
@@ -0,0 +1,88 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buildah
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/sealerio/sealer/common"
+	"github.com/sealerio/sealer/pkg/define/options"
+)
+
+// DiskUsage prints a summary of the space sealer occupies on this machine:
+// images and their reclaimable (dangling) share, build containers, the
+// overlay layer store and the per-cluster data under /var/lib/sealer.
+func (engine *Engine) DiskUsage(_ *options.DiskUsageOptions) error {
+	store := engine.ImageStore()
+
+	usages, err := engine.ImageRuntime().DiskUsage(getContext())
+	if err != nil {
+		return fmt.Errorf("failed to calculate image disk usage: %v", err)
+	}
+
+	var (
+		imagesSize  int64
+		reclaimable int64
+	)
+	seen := map[string]struct{}{}
+	for _, usage := range usages {
+		// DiskUsage yields one entry per repository tag, count each image once
+		if _, ok := seen[usage.ID]; ok {
+			continue
+		}
+		seen[usage.ID] = struct{}{}
+		imagesSize += usage.Size
+		if usage.Containers == 0 && usage.Repository == "<none>" {
+			reclaimable += usage.UniqueSize
+		}
+	}
+
+	containers, err := store.Containers()
+	if err != nil {
+		return fmt.Errorf("failed to list build containers: %v", err)
+	}
+
+	overlaySize, err := safeDirSize(filepath.Join(store.GraphRoot(), "overlay"))
+	if err != nil {
+		return err
+	}
+
+	clusterDataSize, err := safeDirSize(common.DefaultSealerDataDir)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 8, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "TYPE\tTOTAL\tSIZE\tRECLAIMABLE")
+	fmt.Fprintf(w, "Images\t%d\t%s\t%s\n", len(seen), formattedSize(imagesSize), formattedSize(reclaimable))
+	fmt.Fprintf(w, "Build containers\t%d\t-\t-\n", len(containers))
+	fmt.Fprintf(w, "Layer store\t-\t%s\t-\n", formattedSize(overlaySize))
+	fmt.Fprintf(w, "Cluster data\t-\t%s\t-\n", formattedSize(clusterDataSize))
+	return w.Flush()
+}
+
+// safeDirSize is dirSize for directories that may not exist yet.
+func safeDirSize(root string) (int64, error) {
+	if _, err := os.Stat(root); err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return dirSize(root)
+}
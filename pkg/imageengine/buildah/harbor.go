@@ -0,0 +1,273 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buildah
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/containers/image/v5/docker/reference"
+	"github.com/containers/image/v5/pkg/docker/config"
+	"github.com/containers/image/v5/types"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	harborAPIPrefix = "/api/v2.0"
+	// harborRobotPrefix marks Harbor robot account names. Robots authenticate
+	// with plain basic auth just like normal users, but they are scoped to
+	// existing projects and can never create new ones.
+	harborRobotPrefix = "robot$"
+)
+
+// harborClient is a minimal client for the bits of the Harbor API sealer
+// cares about: detecting Harbor itself, looking up a project and creating a
+// missing one. All calls are best-effort with a short timeout so talking to
+// a plain distribution registry never slows a push down noticeably.
+type harborClient struct {
+	baseURL  string
+	username string
+	password string
+	client   *http.Client
+}
+
+// harborProject is the subset of Harbor's project model sealer reads. A
+// non-zero RegistryID means the project is a proxy cache fronting another
+// registry, which Harbor serves pulls from but refuses pushes to.
+type harborProject struct {
+	Name       string `json:"name"`
+	RegistryID int64  `json:"registry_id"`
+}
+
+// newHarborClient probes the registry for a Harbor API endpoint and returns
+// nil if the registry does not answer like Harbor.
+func newHarborClient(registry string, authConfig types.DockerAuthConfig, skipTLSVerify bool) *harborClient {
+	httpClient := &http.Client{
+		Timeout: 5 * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: skipTLSVerify}, // #nosec G402: mirrors the user's --skip-tls-verify choice
+		},
+	}
+
+	for _, scheme := range []string{"https", "http"} {
+		c := &harborClient{
+			baseURL:  fmt.Sprintf("%s://%s%s", scheme, registry, harborAPIPrefix),
+			username: authConfig.Username,
+			password: authConfig.Password,
+			client:   httpClient,
+		}
+
+		var info struct {
+			HarborVersion string `json:"harbor_version"`
+		}
+		if err := c.get("/systeminfo", &info); err != nil {
+			continue
+		}
+		if info.HarborVersion == "" {
+			return nil
+		}
+		logrus.Debugf("registry %s is Harbor %s", registry, info.HarborVersion)
+		return c
+	}
+
+	return nil
+}
+
+func (c *harborClient) isRobot() bool {
+	return strings.HasPrefix(c.username, harborRobotPrefix)
+}
+
+func (c *harborClient) do(method, path string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequest(method, c.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return c.client.Do(req)
+}
+
+func (c *harborClient) get(path string, out interface{}) error {
+	resp, err := c.do(http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			logrus.Debugf("failed to close response body: %v", err)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s from %s", resp.Status, path)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// getProject returns the project and whether it exists at all.
+func (c *harborClient) getProject(name string) (*harborProject, bool, error) {
+	resp, err := c.do(http.MethodGet, "/projects/"+name, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			logrus.Debugf("failed to close response body: %v", err)
+		}
+	}()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		project := &harborProject{}
+		if err := json.NewDecoder(resp.Body).Decode(project); err != nil {
+			return nil, false, err
+		}
+		return project, true, nil
+	case http.StatusNotFound:
+		return nil, false, nil
+	default:
+		return nil, false, fmt.Errorf("unexpected status %s looking up project %q", resp.Status, name)
+	}
+}
+
+func (c *harborClient) createProject(name string) error {
+	payload, err := json.Marshal(map[string]interface{}{
+		"project_name": name,
+		"metadata":     map[string]string{"public": "false"},
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.do(http.MethodPost, "/projects", payload)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			logrus.Debugf("failed to close response body: %v", err)
+		}
+	}()
+
+	// 409 means someone else created it in between, which is just as good.
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusConflict {
+		return fmt.Errorf("unexpected status %s creating project %q", resp.Status, name)
+	}
+
+	return nil
+}
+
+// ensureHarborProject prepares the destination of a push when it lives in a
+// Harbor registry: it creates the target project if it is missing, and fails
+// fast when the project is a proxy cache, which Harbor never accepts pushes
+// to. Registries that are not Harbor, or that we have no credentials for,
+// are left entirely alone.
+func ensureHarborProject(sysCtx *types.SystemContext, named reference.Named, skipTLSVerify bool) error {
+	domain := reference.Domain(named)
+	pathParts := strings.SplitN(reference.Path(named), "/", 2)
+	if len(pathParts) < 2 {
+		// no project component in the repository path
+		return nil
+	}
+	project := pathParts[0]
+
+	authConfig, err := config.GetCredentials(sysCtx, domain)
+	if err != nil || authConfig.Username == "" {
+		return nil
+	}
+
+	harbor := newHarborClient(domain, authConfig, skipTLSVerify)
+	if harbor == nil {
+		return nil
+	}
+
+	existing, found, err := harbor.getProject(project)
+	if err != nil {
+		logrus.Debugf("failed to look up harbor project %q on %s: %v", project, domain, err)
+		return nil
+	}
+
+	if found {
+		if existing.RegistryID != 0 {
+			return fmt.Errorf("harbor project %q on %s is a proxy cache project and does not accept pushes, choose a regular project instead", project, domain)
+		}
+		return nil
+	}
+
+	if harbor.isRobot() {
+		logrus.Warnf("harbor project %q on %s does not exist and robot account %q cannot create projects, create it with an admin account first", project, domain, authConfig.Username)
+		return nil
+	}
+
+	if err := harbor.createProject(project); err != nil {
+		logrus.Warnf("failed to create harbor project %q on %s: %v, the push may be rejected", project, domain, err)
+		return nil
+	}
+
+	logrus.Infof("created missing harbor project %q on %s", project, domain)
+	return nil
+}
+
+// noteHarborProxyCache logs when a pull is served through a Harbor proxy
+// cache project, so a slow first pull of an upstream image is explainable.
+// It is only consulted at debug level to keep regular pulls free of extra
+// registry round trips.
+func noteHarborProxyCache(sysCtx *types.SystemContext, imageName string, skipTLSVerify bool) {
+	if !logrus.IsLevelEnabled(logrus.DebugLevel) {
+		return
+	}
+
+	named, err := reference.ParseNormalizedNamed(imageName)
+	if err != nil {
+		return
+	}
+
+	domain := reference.Domain(named)
+	pathParts := strings.SplitN(reference.Path(named), "/", 2)
+	if len(pathParts) < 2 {
+		return
+	}
+	project := pathParts[0]
+
+	authConfig, err := config.GetCredentials(sysCtx, domain)
+	if err != nil {
+		return
+	}
+
+	harbor := newHarborClient(domain, authConfig, skipTLSVerify)
+	if harbor == nil {
+		return
+	}
+
+	existing, found, err := harbor.getProject(project)
+	if err != nil || !found {
+		return
+	}
+
+	if existing.RegistryID != 0 {
+		logrus.Debugf("image %s is served through harbor proxy cache project %q, the first pull fetches from the upstream registry", imageName, project)
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buildah
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/containers/image/v5/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newFakeHarbor(t *testing.T, projects map[string]harborProject) (*httptest.Server, string) {
+	mux := http.NewServeMux()
+	mux.HandleFunc(harborAPIPrefix+"/systeminfo", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"harbor_version":"v2.7.0"}`))
+	})
+	mux.HandleFunc(harborAPIPrefix+"/projects/", func(w http.ResponseWriter, r *http.Request) {
+		name := r.URL.Path[len(harborAPIPrefix+"/projects/"):]
+		project, ok := projects[name]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"name":"` + project.Name + `"}`))
+	})
+	mux.HandleFunc(harborAPIPrefix+"/projects", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	server := httptest.NewServer(mux)
+	u, err := url.Parse(server.URL)
+	assert.NoError(t, err)
+	return server, u.Host
+}
+
+func TestNewHarborClient(t *testing.T) {
+	server, host := newFakeHarbor(t, nil)
+	defer server.Close()
+
+	client := newHarborClient(host, types.DockerAuthConfig{Username: "admin", Password: "pwd"}, false)
+	assert.NotNil(t, client)
+	assert.False(t, client.isRobot())
+
+	robot := newHarborClient(host, types.DockerAuthConfig{Username: "robot$builder", Password: "token"}, false)
+	assert.NotNil(t, robot)
+	assert.True(t, robot.isRobot())
+}
+
+func TestHarborClientProjects(t *testing.T) {
+	server, host := newFakeHarbor(t, map[string]harborProject{
+		"library": {Name: "library"},
+	})
+	defer server.Close()
+
+	client := newHarborClient(host, types.DockerAuthConfig{Username: "admin", Password: "pwd"}, false)
+	assert.NotNil(t, client)
+
+	_, found, err := client.getProject("library")
+	assert.NoError(t, err)
+	assert.True(t, found)
+
+	_, found, err = client.getProject("missing")
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	assert.NoError(t, client.createProject("missing"))
+}
@@ -17,6 +17,7 @@ package buildah
 import (
 	"encoding/json"
 	"fmt"
+	"os"
 	"sort"
 	"strings"
 	"time"
@@ -27,6 +28,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/sealerio/sealer/pkg/define/options"
+	"github.com/sealerio/sealer/pkg/printer"
 )
 
 const none = "<none>"
@@ -102,6 +104,18 @@ func (engine *Engine) Images(opts *options.ImagesOptions) error {
 		history:   opts.History,
 	}
 
+	if opts.Output != "" {
+		jsonImages, err := collectImagesForEncoding(images, imageOpts)
+		if err != nil {
+			return err
+		}
+		format, err := printer.ParseFormat(opts.Output)
+		if err != nil {
+			return err
+		}
+		return printer.New(format, os.Stdout).PrintObj(jsonImages)
+	}
+
 	if opts.JSON {
 		return formatImagesJSON(images, imageOpts)
 	}
@@ -134,13 +148,13 @@ func outputHeader(opts imageOptions) string {
 	return format
 }
 
-func formatImagesJSON(images []*libimage.Image, opts imageOptions) error {
+func collectImagesForEncoding(images []*libimage.Image, opts imageOptions) ([]jsonImage, error) {
 	jsonImages := []jsonImage{}
 	for _, image := range images {
 		// Copy the base data over to the output param.
 		size, err := image.Size()
 		if err != nil {
-			return err
+			return nil, err
 		}
 		created := image.Created()
 		jsonImages = append(jsonImages,
@@ -155,6 +169,14 @@ func formatImagesJSON(images []*libimage.Image, opts imageOptions) error {
 				Size:         formattedSize(size),
 			})
 	}
+	return jsonImages, nil
+}
+
+func formatImagesJSON(images []*libimage.Image, opts imageOptions) error {
+	jsonImages, err := collectImagesForEncoding(images, opts)
+	if err != nil {
+		return err
+	}
 
 	data, err := json.MarshalIndent(jsonImages, "", "    ")
 	if err != nil {
@@ -24,6 +24,7 @@ import (
 	"strings"
 
 	"github.com/containers/common/libimage"
+	enchelpers "github.com/containers/ocicrypt/helpers"
 	"github.com/go-errors/errors"
 	"github.com/sealerio/sealer/common"
 	"github.com/sealerio/sealer/pkg/define/options"
@@ -35,7 +36,8 @@ var LoadError = errors.Errorf("failed to load new image")
 
 func (engine *Engine) Load(opts *options.LoadOptions) error {
 	imageSrc := opts.Input
-	if _, err := os.Stat(imageSrc); err != nil {
+	fi, err := os.Stat(imageSrc)
+	if err != nil {
 		return err
 	}
 
@@ -44,6 +46,33 @@ func (engine *Engine) Load(opts *options.LoadOptions) error {
 		loadOpts.Writer = os.Stderr
 	}
 
+	if len(opts.DecryptionKeys) > 0 {
+		cryptoConfig, err := enchelpers.CreateDecryptCryptoConfig(opts.DecryptionKeys, nil)
+		if err != nil {
+			return fmt.Errorf("failed to create decryption config from %v: %v", opts.DecryptionKeys, err)
+		}
+		loadOpts.OciDecryptConfig = cryptoConfig.DecryptConfig
+	}
+
+	// a directory input is an OCI image layout (or a docker dir), e.g. one
+	// produced by `sealer save --format oci`, skopeo or oras; hand it to the
+	// image runtime directly instead of treating it as a tar archive.
+	if fi.IsDir() {
+		return engine.loadOneImage(imageSrc, loadOpts)
+	}
+
+	// an explicit non-sealer format skips the bundle detection, so archives
+	// produced by docker save or podman save load without being unpacked
+	// into the temp dir first.
+	switch opts.Format {
+	case "", SealerArchive:
+	case V2s2Archive, OCIArchive, OCILayout, OCIManifestDir, V2s2ManifestDir:
+		return engine.loadOneImage(imageSrc, loadOpts)
+	default:
+		return fmt.Errorf("unsupported load format %s, expected one of %s, %s, %s, %s, %s or %s",
+			opts.Format, SealerArchive, V2s2Archive, OCIArchive, OCILayout, OCIManifestDir, V2s2ManifestDir)
+	}
+
 	srcFile, err := os.Open(filepath.Clean(imageSrc))
 	if err != nil {
 		return fmt.Errorf("failed to open %s, err : %v", imageSrc, err)
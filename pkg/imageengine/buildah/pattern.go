@@ -0,0 +1,85 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buildah
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/containers/common/libimage"
+
+	strUtils "github.com/sealerio/sealer/utils/strings"
+)
+
+// expandImageNamePatterns resolves glob patterns (as understood by
+// path.Match) among namesOrIDs against the names of local images, so CI
+// cleanup scripts can say `sealer rmi 'myapp:*-rc*'`. Arguments without glob
+// metacharacters pass through untouched. A pattern that matches nothing is
+// an error, which keeps a typo from silently removing nothing.
+func (engine *Engine) expandImageNamePatterns(namesOrIDs []string) ([]string, error) {
+	hasPattern := false
+	for _, nameOrID := range namesOrIDs {
+		if strings.ContainsAny(nameOrID, "*?[") {
+			hasPattern = true
+			break
+		}
+	}
+	if !hasPattern {
+		return namesOrIDs, nil
+	}
+
+	images, err := engine.ImageRuntime().ListImages(getContext(), nil, &libimage.ListImagesOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var expanded []string
+	for _, nameOrID := range namesOrIDs {
+		if !strings.ContainsAny(nameOrID, "*?[") {
+			expanded = append(expanded, nameOrID)
+			continue
+		}
+
+		var matched []string
+		for _, image := range images {
+			for _, name := range image.Names() {
+				if matchesImagePattern(nameOrID, name) {
+					matched = append(matched, name)
+				}
+			}
+		}
+		if len(matched) == 0 {
+			return nil, fmt.Errorf("no local image name matches pattern %q", nameOrID)
+		}
+		expanded = append(expanded, matched...)
+	}
+
+	return strUtils.RemoveDuplicate(expanded), nil
+}
+
+// matchesImagePattern matches an image name against a glob pattern, both as
+// the stored fully qualified name and as the familiar short form without
+// the default docker.io[/library] prefix.
+func matchesImagePattern(pattern, name string) bool {
+	if ok, err := path.Match(pattern, name); err == nil && ok {
+		return true
+	}
+
+	short := strings.TrimPrefix(name, "docker.io/")
+	short = strings.TrimPrefix(short, "library/")
+	ok, err := path.Match(pattern, short)
+	return err == nil && ok
+}
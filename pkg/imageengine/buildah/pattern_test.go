@@ -0,0 +1,40 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buildah
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchesImagePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"myapp:*-rc*", "docker.io/library/myapp:v1-rc2", true},
+		{"myapp:*-rc*", "docker.io/library/myapp:v1", false},
+		{"docker.io/sealerio/kubernetes:*", "docker.io/sealerio/kubernetes:v1-22-15-sealerio-2", true},
+		{"sealerio/kubernetes:v1-*", "docker.io/sealerio/kubernetes:v1-22-15-sealerio-2", true},
+		{"other/kubernetes:*", "docker.io/sealerio/kubernetes:v1-22-15-sealerio-2", false},
+		{"myapp:v1", "docker.io/library/myapp:v1", true}, // a literal pattern still matches the short form exactly
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, matchesImagePattern(tt.pattern, tt.name), "pattern %q against %q", tt.pattern, tt.name)
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buildah
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/sealerio/sealer/pkg/define/options"
+)
+
+// Prune reclaims disk space on a long-lived build machine: it deletes all
+// build containers (they only exist between a failed or interrupted build
+// and the next one), removes dangling images, and sweeps overlay layer
+// directories that are no longer referenced by the layer store.
+func (engine *Engine) Prune(opts *options.PruneOptions) error {
+	// build containers first, they pin the layers they were started from
+	if err := engine.RemoveContainer(&options.RemoveContainerOptions{All: true}); err != nil {
+		return fmt.Errorf("failed to remove build containers: %v", err)
+	}
+
+	if err := engine.RemoveImage(&options.RemoveImageOptions{Prune: true}); err != nil {
+		return fmt.Errorf("failed to remove dangling images: %v", err)
+	}
+
+	removed, reclaimed, err := engine.pruneOverlayLeftovers(opts.Quiet)
+	if err != nil {
+		return fmt.Errorf("failed to sweep unreferenced overlay directories: %v", err)
+	}
+
+	if !opts.Quiet {
+		logrus.Infof("removed %d unreferenced overlay directories, reclaimed %s in total", removed, formattedSize(reclaimed))
+	}
+	return nil
+}
+
+// pruneOverlayLeftovers removes directories under the overlay graph root
+// that the layer store no longer knows about. Such orphans are left behind
+// by builds killed halfway through and are invisible to image removal.
+func (engine *Engine) pruneOverlayLeftovers(quiet bool) (int, int64, error) {
+	store := engine.ImageStore()
+
+	layers, err := store.Layers()
+	if err != nil {
+		return 0, 0, err
+	}
+	known := make(map[string]struct{}, len(layers))
+	for _, layer := range layers {
+		known[layer.ID] = struct{}{}
+	}
+
+	overlayDir := filepath.Join(store.GraphRoot(), "overlay")
+	entries, err := os.ReadDir(overlayDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, 0, nil
+		}
+		return 0, 0, err
+	}
+
+	var (
+		removed   int
+		reclaimed int64
+	)
+	for _, entry := range entries {
+		// "l" holds the short-name symlinks the overlay driver mounts with
+		if !entry.IsDir() || entry.Name() == "l" {
+			continue
+		}
+		if _, ok := known[entry.Name()]; ok {
+			continue
+		}
+
+		path := filepath.Join(overlayDir, entry.Name())
+		size, err := dirSize(path)
+		if err != nil {
+			logrus.Debugf("failed to measure %s: %v", path, err)
+		}
+		if err := os.RemoveAll(path); err != nil {
+			return removed, reclaimed, fmt.Errorf("failed to remove %s: %v", path, err)
+		}
+		if !quiet {
+			logrus.Infof("removed unreferenced overlay directory %s (%s)", entry.Name(), formattedSize(size))
+		}
+		removed++
+		reclaimed += size
+	}
+
+	return removed, reclaimed, nil
+}
+
+func dirSize(root string) (int64, error) {
+	var size int64
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
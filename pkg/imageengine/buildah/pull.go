@@ -70,6 +70,10 @@ func (engine *Engine) Pull(opts *options.PullOptions) (string, error) {
 		options.ReportWriter = nil // Turns off logging output
 	}
 
+	noteHarborProxyCache(newSystemCxt, opts.Image, opts.SkipTLSVerify)
+
+	setMaxParallelLayers(opts.MaxParallelLayers)
+
 	id, err := buildah.Pull(getContext(), opts.Image, options)
 	if err != nil {
 		return "", err
@@ -50,6 +50,7 @@ func (engine *Engine) Push(opts *options.PushOptions) error {
 	}
 	compress := define.Gzip
 	store := engine.ImageStore()
+	setMaxParallelLayers(opts.MaxParallelLayers)
 	dest, err := alltransports.ParseImageName(destSpec)
 	// add the docker:// transport to see if they neglected it.
 	if err != nil {
@@ -71,6 +72,16 @@ func (engine *Engine) Push(opts *options.PushOptions) error {
 		logrus.Debugf("Assuming docker:// as the transport method for DESTINATION: %s", destSpec)
 	}
 
+	// when the destination registry is Harbor, make sure the target project
+	// exists before buildah starts pushing layers into the void.
+	if dest.Transport().Name() == "docker" {
+		if named := dest.DockerReference(); named != nil {
+			if err := ensureHarborProject(systemCxt, named, opts.SkipTLSVerify); err != nil {
+				return err
+			}
+		}
+	}
+
 	img, _, err := engine.ImageRuntime().LookupImage(src, &libimage.LookupImageOptions{
 		ManifestList: true,
 	})
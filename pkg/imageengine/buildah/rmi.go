@@ -32,6 +32,11 @@ func (engine *Engine) RemoveImage(opts *options.RemoveImageOptions) error {
 	if len(opts.ImageNamesOrIDs) > 0 && opts.Prune {
 		return errors.Errorf("when using the --prune switch, you may not pass any images names or IDs")
 	}
+	imageNamesOrIDs, err := engine.expandImageNamePatterns(opts.ImageNamesOrIDs)
+	if err != nil {
+		return err
+	}
+
 	options := &libimage.RemoveImagesOptions{
 		Filters: []string{"readonly=false"},
 	}
@@ -42,7 +47,7 @@ func (engine *Engine) RemoveImage(opts *options.RemoveImageOptions) error {
 	options.Force = opts.Force
 
 	// take it as image first
-	rmiReports, rmiErrors := engine.ImageRuntime().RemoveImages(context.Background(), opts.ImageNamesOrIDs, options)
+	rmiReports, rmiErrors := engine.ImageRuntime().RemoveImages(context.Background(), imageNamesOrIDs, options)
 	for _, r := range rmiReports {
 		for _, u := range r.Untagged {
 			logrus.Infof("untagged: %s", u)
@@ -60,7 +65,7 @@ func (engine *Engine) RemoveImage(opts *options.RemoveImageOptions) error {
 
 	// take it as manifestList and try again
 	options.LookupManifest = true
-	rmiReports, rmiErrors2 := engine.ImageRuntime().RemoveImages(context.Background(), opts.ImageNamesOrIDs, options)
+	rmiReports, rmiErrors2 := engine.ImageRuntime().RemoveImages(context.Background(), imageNamesOrIDs, options)
 	for _, r := range rmiReports {
 		for _, u := range r.Untagged {
 			logrus.Infof("untagged: %s", u)
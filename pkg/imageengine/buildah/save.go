@@ -23,6 +23,7 @@ import (
 
 	"github.com/containers/common/libimage"
 	"github.com/containers/common/libimage/manifests"
+	enchelpers "github.com/containers/ocicrypt/helpers"
 	"github.com/pkg/errors"
 	"github.com/sealerio/sealer/common"
 	"github.com/sealerio/sealer/pkg/define/options"
@@ -41,8 +42,16 @@ func (engine *Engine) Save(opts *options.SaveOptions) error {
 	if len(imageNameOrID) == 0 {
 		return errors.New("image name or id must be specified")
 	}
-	if opts.Compress && (opts.Format != OCIManifestDir && opts.Format != V2s2ManifestDir) {
-		return errors.New("--compress can only be set when --format is either 'oci-dir' or 'docker-dir'")
+	// the sealer bundle stores its per-instance images as oci archives
+	if opts.Format == SealerArchive {
+		opts.Format = OCIArchive
+	}
+	if opts.Compress && (opts.Format != OCIManifestDir && opts.Format != V2s2ManifestDir && opts.Format != OCILayout) {
+		return errors.New("--compress can only be set when --format is either 'oci', 'oci-dir' or 'docker-dir'")
+	}
+	// layer encryption is defined for OCI media types only
+	if len(opts.EncryptionKeys) > 0 && (opts.Format == V2s2Archive || opts.Format == V2s2ManifestDir) {
+		return errors.New("--encryption-key can only be set when --format is 'oci', 'oci-archive' or 'oci-dir'")
 	}
 
 	img, _, err := engine.ImageRuntime().LookupImage(imageNameOrID, &libimage.LookupImageOptions{
@@ -57,8 +66,12 @@ func (engine *Engine) Save(opts *options.SaveOptions) error {
 		return err
 	}
 
+	if opts.Format == OCILayout {
+		return engine.saveOCILayout(img, isManifest, opts)
+	}
+
 	if !isManifest {
-		return engine.saveOneImage(imageNameOrID, opts.Format, imageTar, opts.Compress)
+		return engine.saveOneImage(imageNameOrID, opts.Format, imageTar, opts)
 	}
 
 	// save multi-arch images :including each platform images and manifest.
@@ -123,7 +136,7 @@ func (engine *Engine) Save(opts *options.SaveOptions) error {
 			instanceName = instance.Names[0]
 		}
 
-		err = engine.saveOneImage(instanceName, opts.Format, instanceTar, opts.Compress)
+		err = engine.saveOneImage(instanceName, opts.Format, instanceTar, opts)
 		if err != nil {
 			return err
 		}
@@ -154,10 +167,58 @@ func (engine *Engine) Save(opts *options.SaveOptions) error {
 	return err
 }
 
-func (engine *Engine) saveOneImage(imageNameOrID, format, path string, compress bool) error {
+// saveOCILayout writes the image straight into an OCI image layout directory
+// at opts.Output instead of sealer's tar bundle. For a manifest list every
+// platform instance is saved into the same layout, so the layout index keeps
+// one entry per platform and any OCI-compatible tooling can pick from it.
+func (engine *Engine) saveOCILayout(img *libimage.Image, isManifest bool, opts *options.SaveOptions) error {
+	output := opts.Output
+	store := engine.ImageStore()
+
+	if !isManifest {
+		return engine.saveOneImage(opts.ImageNameOrID, OCIManifestDir, output, opts)
+	}
+
+	imageName := img.Names()[0]
+	logrus.Infof("image %q is a manifest list, saving all instances into OCI layout %s", opts.ImageNameOrID, output)
+	manifestList, err := engine.ImageRuntime().LookupManifestList(imageName)
+	if err != nil {
+		return err
+	}
+
+	_, list, err := manifests.LoadFromImage(store, manifestList.ID())
+	if err != nil {
+		return err
+	}
+
+	for _, instanceDigest := range list.Instances() {
+		images, err := store.ImagesByDigest(instanceDigest)
+		if err != nil {
+			return err
+		}
+
+		if len(images) == 0 {
+			return fmt.Errorf("no image matched with digest %s", instanceDigest)
+		}
+
+		instance := images[0]
+		instanceName := instance.ID
+		if len(instance.Names) > 0 {
+			instanceName = instance.Names[0]
+		}
+
+		if err := engine.saveOneImage(instanceName, OCIManifestDir, output, opts); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (engine *Engine) saveOneImage(imageNameOrID, format, path string, opts *options.SaveOptions) error {
 	saveOptions := &libimage.SaveOptions{
 		CopyOptions: libimage.CopyOptions{
-			DirForceCompress:            compress,
+			DirForceCompress:            opts.Compress,
 			OciAcceptUncompressedLayers: false,
 			// Force signature removal to preserve backwards compat.
 			// See https://github.com/containers/podman/pull/11669#issuecomment-925250264
@@ -165,6 +226,17 @@ func (engine *Engine) saveOneImage(imageNameOrID, format, path string, compress
 		},
 	}
 
+	if len(opts.EncryptionKeys) > 0 {
+		cryptoConfig, err := enchelpers.CreateCryptoConfig(opts.EncryptionKeys, nil)
+		if err != nil {
+			return fmt.Errorf("failed to create encryption config from %v: %v", opts.EncryptionKeys, err)
+		}
+		saveOptions.OciEncryptConfig = cryptoConfig.EncryptConfig
+		if len(opts.EncryptLayers) > 0 {
+			saveOptions.OciEncryptLayers = &opts.EncryptLayers
+		}
+	}
+
 	names := []string{imageNameOrID}
 	return engine.ImageRuntime().Save(context.Background(), names, format, path, saveOptions)
 }
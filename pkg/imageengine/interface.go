@@ -55,6 +55,10 @@ type Interface interface {
 
 	RemoveContainer(opts *options.RemoveContainerOptions) error
 
+	Prune(opts *options.PruneOptions) error
+
+	DiskUsage(opts *options.DiskUsageOptions) error
+
 	Tag(opts *options.TagOptions) error
 
 	Inspect(opts *options.InspectOptions) (*v1.ImageSpec, error)
@@ -0,0 +1,449 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package aws provisions cluster hosts on Amazon EC2. It drives the aws CLI
+// instead of the SDK so no extra dependency is vendored; credentials and the
+// region come from the regular aws CLI configuration or environment.
+package aws
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/sealerio/sealer/common"
+	v1 "github.com/sealerio/sealer/types/api/v1"
+	"github.com/sealerio/sealer/utils/yaml"
+)
+
+const (
+	AWSCloud = "AWS_CLOUD"
+
+	Master = "master"
+	Node   = "node"
+
+	AwsDomain         = "sea.aws.com/"
+	VpcID             = AwsDomain + "VpcID"
+	SubnetID          = AwsDomain + "SubnetID"
+	SecurityGroupID   = AwsDomain + "SecurityGroupID"
+	InternetGatewayID = AwsDomain + "InternetGatewayID"
+	EipAllocationID   = AwsDomain + "EipAllocationID"
+	Eip               = AwsDomain + "ClusterEIP"
+	MasterIDs         = AwsDomain + "MasterIDs"
+	NodeIDs           = AwsDomain + "NodeIDs"
+
+	DefaultVpcCidrBlock    = "172.16.0.0/16"
+	DefaultSubnetCidrBlock = "172.16.0.0/20"
+	DefaultInstanceType    = "t3.large"
+
+	// env knobs understood on top of the regular aws CLI configuration
+	InstanceTypeEnv = "AWS_INSTANCE_TYPE"
+	ImageIDEnv      = "AWS_AMI_ID"
+	KeyPairEnv      = "AWS_KEY_PAIR"
+
+	// public SSM parameter resolving the latest Amazon Linux 2 AMI of the region
+	amazonLinux2AMIParameter = "/aws/service/ami-amazon-linux-latest/amzn2-ami-hvm-x86_64-gp2"
+
+	clusterTagKey = "sealer.io/cluster"
+)
+
+type AwsProvider struct {
+	Cluster *v1.Cluster
+}
+
+// CheckCLI verifies the aws CLI the provider shells out to is installed.
+func CheckCLI() error {
+	if _, err := exec.LookPath("aws"); err != nil {
+		return fmt.Errorf("the %s provider requires the aws CLI on PATH, install and configure it first: %v", AWSCloud, err)
+	}
+	return nil
+}
+
+// awsCli runs one aws CLI call and returns its trimmed text output.
+func (a *AwsProvider) awsCli(args ...string) (string, error) {
+	var (
+		stdout bytes.Buffer
+		stderr bytes.Buffer
+	)
+
+	cmd := exec.Command("aws", append(args, "--output", "text")...) // #nosec
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("aws %s: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+func (a *AwsProvider) tagSpecification(resourceType string) string {
+	return fmt.Sprintf("ResourceType=%s,Tags=[{Key=%s,Value=%s}]", resourceType, clusterTagKey, a.Cluster.Name)
+}
+
+func (a *AwsProvider) ReconcileResource(resourceKey string, action func() error) error {
+	if a.Cluster.Annotations[resourceKey] != "" {
+		return nil
+	}
+	if err := action(); err != nil {
+		return err
+	}
+	logrus.Infof("create resource success %s: %s", resourceKey, a.Cluster.Annotations[resourceKey])
+	return a.SaveToDisk()
+}
+
+func (a *AwsProvider) DeleteResource(resourceKey string, action func() error) {
+	if a.Cluster.Annotations[resourceKey] == "" {
+		return
+	}
+	if err := action(); err != nil {
+		logrus.Errorf("delete resource %s failed err: %s", resourceKey, err)
+		return
+	}
+	logrus.Infof("delete resource Success %s", a.Cluster.Annotations[resourceKey])
+	delete(a.Cluster.Annotations, resourceKey)
+}
+
+func (a *AwsProvider) SaveToDisk() error {
+	fileName := common.GetDefaultClusterfile()
+	if err := os.MkdirAll(filepath.Dir(fileName), os.ModePerm); err != nil {
+		return fmt.Errorf("mkdir failed %s %v", fileName, err)
+	}
+	return yaml.MarshalToFile(fileName, a.Cluster)
+}
+
+func (a *AwsProvider) CreateVPC() error {
+	vpcID, err := a.awsCli("ec2", "create-vpc",
+		"--cidr-block", DefaultVpcCidrBlock,
+		"--tag-specifications", a.tagSpecification("vpc"),
+		"--query", "Vpc.VpcId")
+	if err != nil {
+		return err
+	}
+	a.Cluster.SetAnnotations(VpcID, vpcID)
+	return nil
+}
+
+func (a *AwsProvider) CreateSubnet() error {
+	subnetID, err := a.awsCli("ec2", "create-subnet",
+		"--vpc-id", a.Cluster.Annotations[VpcID],
+		"--cidr-block", DefaultSubnetCidrBlock,
+		"--tag-specifications", a.tagSpecification("subnet"),
+		"--query", "Subnet.SubnetId")
+	if err != nil {
+		return err
+	}
+	a.Cluster.SetAnnotations(SubnetID, subnetID)
+	return nil
+}
+
+// CreateInternetGateway attaches an internet gateway to the VPC and routes
+// the default traffic through it, so the provisioned hosts are reachable.
+func (a *AwsProvider) CreateInternetGateway() error {
+	igwID, err := a.awsCli("ec2", "create-internet-gateway",
+		"--tag-specifications", a.tagSpecification("internet-gateway"),
+		"--query", "InternetGateway.InternetGatewayId")
+	if err != nil {
+		return err
+	}
+
+	vpcID := a.Cluster.Annotations[VpcID]
+	if _, err := a.awsCli("ec2", "attach-internet-gateway", "--internet-gateway-id", igwID, "--vpc-id", vpcID); err != nil {
+		return err
+	}
+
+	routeTableID, err := a.awsCli("ec2", "describe-route-tables",
+		"--filters", fmt.Sprintf("Name=vpc-id,Values=%s", vpcID),
+		"--query", "RouteTables[0].RouteTableId")
+	if err != nil {
+		return err
+	}
+	if _, err := a.awsCli("ec2", "create-route", "--route-table-id", routeTableID,
+		"--destination-cidr-block", "0.0.0.0/0", "--gateway-id", igwID); err != nil {
+		return err
+	}
+
+	a.Cluster.SetAnnotations(InternetGatewayID, igwID)
+	return nil
+}
+
+func (a *AwsProvider) CreateSecurityGroup() error {
+	groupID, err := a.awsCli("ec2", "create-security-group",
+		"--group-name", fmt.Sprintf("sealer-%s", a.Cluster.Name),
+		"--description", fmt.Sprintf("security group of sealer cluster %s", a.Cluster.Name),
+		"--vpc-id", a.Cluster.Annotations[VpcID],
+		"--tag-specifications", a.tagSpecification("security-group"),
+		"--query", "GroupId")
+	if err != nil {
+		return err
+	}
+
+	// ssh and apiserver from outside, everything inside the group
+	for _, port := range []string{"22", "6443"} {
+		if _, err := a.awsCli("ec2", "authorize-security-group-ingress", "--group-id", groupID,
+			"--protocol", "tcp", "--port", port, "--cidr", "0.0.0.0/0"); err != nil {
+			return err
+		}
+	}
+	if _, err := a.awsCli("ec2", "authorize-security-group-ingress", "--group-id", groupID,
+		"--protocol", "-1", "--source-group", groupID); err != nil {
+		return err
+	}
+
+	a.Cluster.SetAnnotations(SecurityGroupID, groupID)
+	return nil
+}
+
+func (a *AwsProvider) instanceType() string {
+	if t := os.Getenv(InstanceTypeEnv); t != "" {
+		return t
+	}
+	return DefaultInstanceType
+}
+
+func (a *AwsProvider) imageID() (string, error) {
+	if id := os.Getenv(ImageIDEnv); id != "" {
+		return id, nil
+	}
+	return a.awsCli("ssm", "get-parameter",
+		"--name", amazonLinux2AMIParameter,
+		"--query", "Parameter.Value")
+}
+
+func (a *AwsProvider) ReconcileInstances(instanceRole string) error {
+	var (
+		hosts       *v1.Hosts
+		instanceKey string
+	)
+	switch instanceRole {
+	case Master:
+		hosts = &a.Cluster.Spec.Masters
+		instanceKey = MasterIDs
+	case Node:
+		hosts = &a.Cluster.Spec.Nodes
+		instanceKey = NodeIDs
+	default:
+		return fmt.Errorf("unknown instance role %s", instanceRole)
+	}
+
+	count, err := strconv.Atoi(strings.TrimSpace(hosts.Count))
+	if err != nil || count < 0 {
+		if instanceRole == Node && strings.TrimSpace(hosts.Count) == "" {
+			return nil
+		}
+		return fmt.Errorf("invalid %s count %q", instanceRole, hosts.Count)
+	}
+	if count == 0 {
+		return nil
+	}
+
+	if a.Cluster.Annotations[instanceKey] == "" {
+		imageID, err := a.imageID()
+		if err != nil {
+			return err
+		}
+
+		args := []string{"ec2", "run-instances",
+			"--count", strconv.Itoa(count),
+			"--instance-type", a.instanceType(),
+			"--image-id", imageID,
+			"--subnet-id", a.Cluster.Annotations[SubnetID],
+			"--security-group-ids", a.Cluster.Annotations[SecurityGroupID],
+			"--tag-specifications", a.tagSpecification("instance"),
+			"--query", "Instances[].InstanceId"}
+		if keyPair := os.Getenv(KeyPairEnv); keyPair != "" {
+			args = append(args, "--key-name", keyPair)
+		}
+
+		out, err := a.awsCli(args...)
+		if err != nil {
+			return err
+		}
+		a.Cluster.SetAnnotations(instanceKey, strings.Join(strings.Fields(out), ","))
+		if err := a.SaveToDisk(); err != nil {
+			return err
+		}
+	}
+
+	instanceIDs := strings.Split(a.Cluster.Annotations[instanceKey], ",")
+	waitArgs := append([]string{"ec2", "wait", "instance-running", "--instance-ids"}, instanceIDs...)
+	if _, err := a.awsCli(waitArgs...); err != nil {
+		return err
+	}
+
+	describeArgs := append([]string{"ec2", "describe-instances", "--instance-ids"}, instanceIDs...)
+	describeArgs = append(describeArgs, "--query", "Reservations[].Instances[].PrivateIpAddress")
+	out, err := a.awsCli(describeArgs...)
+	if err != nil {
+		return err
+	}
+
+	var ipList []net.IP
+	for _, ipStr := range strings.Fields(out) {
+		ip := net.ParseIP(ipStr)
+		if ip == nil {
+			return fmt.Errorf("instance of role %s reported invalid ip %q", instanceRole, ipStr)
+		}
+		ipList = append(ipList, ip)
+	}
+	if len(ipList) != count {
+		return fmt.Errorf("expect %d %s instances but got ips %v", count, instanceRole, ipList)
+	}
+	hosts.IPList = ipList
+
+	return nil
+}
+
+// BindEipForMaster0 exposes the first master with an elastic IP.
+// todo front the apiservers with an ELB once HA masters on AWS are wanted.
+func (a *AwsProvider) BindEipForMaster0() error {
+	masterIDs := strings.Split(a.Cluster.Annotations[MasterIDs], ",")
+	if len(masterIDs) == 0 || masterIDs[0] == "" {
+		return fmt.Errorf("no master instance to bind the elastic IP to")
+	}
+
+	allocationID, err := a.awsCli("ec2", "allocate-address",
+		"--tag-specifications", a.tagSpecification("elastic-ip"),
+		"--query", "AllocationId")
+	if err != nil {
+		return err
+	}
+	if _, err := a.awsCli("ec2", "associate-address",
+		"--allocation-id", allocationID, "--instance-id", masterIDs[0]); err != nil {
+		return err
+	}
+
+	publicIP, err := a.awsCli("ec2", "describe-addresses",
+		"--allocation-ids", allocationID,
+		"--query", "Addresses[0].PublicIp")
+	if err != nil {
+		return err
+	}
+
+	a.Cluster.SetAnnotations(EipAllocationID, allocationID)
+	a.Cluster.SetAnnotations(Eip, publicIP)
+	return nil
+}
+
+func (a *AwsProvider) ReleaseEip() error {
+	_, err := a.awsCli("ec2", "release-address", "--allocation-id", a.Cluster.Annotations[EipAllocationID])
+	return err
+}
+
+func (a *AwsProvider) ClearInstances() error {
+	var instanceIDs []string
+	for _, key := range []string{MasterIDs, NodeIDs} {
+		if a.Cluster.Annotations[key] != "" {
+			instanceIDs = append(instanceIDs, strings.Split(a.Cluster.Annotations[key], ",")...)
+		}
+	}
+	if len(instanceIDs) == 0 {
+		return nil
+	}
+
+	terminateArgs := append([]string{"ec2", "terminate-instances", "--instance-ids"}, instanceIDs...)
+	if _, err := a.awsCli(terminateArgs...); err != nil {
+		return err
+	}
+	waitArgs := append([]string{"ec2", "wait", "instance-terminated", "--instance-ids"}, instanceIDs...)
+	if _, err := a.awsCli(waitArgs...); err != nil {
+		return err
+	}
+	delete(a.Cluster.Annotations, MasterIDs)
+	delete(a.Cluster.Annotations, NodeIDs)
+	return nil
+}
+
+func (a *AwsProvider) DeleteSecurityGroup() error {
+	_, err := a.awsCli("ec2", "delete-security-group", "--group-id", a.Cluster.Annotations[SecurityGroupID])
+	return err
+}
+
+func (a *AwsProvider) DeleteInternetGateway() error {
+	igwID := a.Cluster.Annotations[InternetGatewayID]
+	if _, err := a.awsCli("ec2", "detach-internet-gateway",
+		"--internet-gateway-id", igwID, "--vpc-id", a.Cluster.Annotations[VpcID]); err != nil {
+		return err
+	}
+	_, err := a.awsCli("ec2", "delete-internet-gateway", "--internet-gateway-id", igwID)
+	return err
+}
+
+func (a *AwsProvider) DeleteSubnet() error {
+	_, err := a.awsCli("ec2", "delete-subnet", "--subnet-id", a.Cluster.Annotations[SubnetID])
+	return err
+}
+
+func (a *AwsProvider) DeleteVPC() error {
+	_, err := a.awsCli("ec2", "delete-vpc", "--vpc-id", a.Cluster.Annotations[VpcID])
+	return err
+}
+
+func (a *AwsProvider) ClearCluster() {
+	a.DeleteResource(EipAllocationID, a.ReleaseEip)
+	if err := a.ClearInstances(); err != nil {
+		logrus.Errorf("delete instances failed err: %s", err)
+	}
+	a.DeleteResource(SecurityGroupID, a.DeleteSecurityGroup)
+	a.DeleteResource(InternetGatewayID, a.DeleteInternetGateway)
+	a.DeleteResource(SubnetID, a.DeleteSubnet)
+	a.DeleteResource(VpcID, a.DeleteVPC)
+}
+
+func (a *AwsProvider) Reconcile() error {
+	if a.Cluster.Annotations == nil {
+		a.Cluster.Annotations = make(map[string]string)
+	}
+	if a.Cluster.DeletionTimestamp != nil {
+		logrus.Info("DeletionTimestamp not nil Clear Cluster")
+		a.ClearCluster()
+		return nil
+	}
+
+	todolist := []struct {
+		resourceKey string
+		action      func() error
+	}{
+		{VpcID, a.CreateVPC},
+		{SubnetID, a.CreateSubnet},
+		{InternetGatewayID, a.CreateInternetGateway},
+		{SecurityGroupID, a.CreateSecurityGroup},
+	}
+	for _, todo := range todolist {
+		if err := a.ReconcileResource(todo.resourceKey, todo.action); err != nil {
+			return err
+		}
+	}
+
+	if err := a.ReconcileInstances(Master); err != nil {
+		return err
+	}
+	if err := a.ReconcileInstances(Node); err != nil {
+		return err
+	}
+	if err := a.SaveToDisk(); err != nil {
+		return err
+	}
+
+	return a.ReconcileResource(EipAllocationID, a.BindEipForMaster0)
+}
+
+func (a *AwsProvider) Apply() error {
+	return a.Reconcile()
+}
@@ -0,0 +1,149 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infra
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/sealerio/sealer/common"
+	v1 "github.com/sealerio/sealer/types/api/v1"
+	v2 "github.com/sealerio/sealer/types/api/v2"
+	"github.com/sealerio/sealer/utils/yaml"
+)
+
+// CloudApplier drives a cloud infra provider for clusters whose Clusterfile
+// declares spec.provider instead of baremetal host IPs: it brings the IaaS
+// resources up before the installation and tears them down on delete.
+type CloudApplier interface {
+	// Provision reconciles the IaaS resources and returns the cluster in the
+	// v2 form the regular install pipeline consumes, hosts filled with the
+	// allocated IPs.
+	Provision() (*v2.Cluster, error)
+	// Teardown releases every IaaS resource recorded on the cluster.
+	Teardown() error
+}
+
+type cloudApplier struct {
+	cluster *v1.Cluster
+}
+
+func NewCloudApplier(cluster *v1.Cluster) CloudApplier {
+	return &cloudApplier{cluster: cluster}
+}
+
+// CloudClusterfilePath is where the provisioned cloud cluster, resource
+// annotations included, is persisted between apply and delete.
+func CloudClusterfilePath() string {
+	return filepath.Join(common.GetSealerWorkDir(), "CloudClusterfile")
+}
+
+func (c *cloudApplier) Provision() (*v2.Cluster, error) {
+	// carry over the resources of a previous apply so changing the counts
+	// scales the existing infra instead of provisioning a fresh set
+	saved, err := LoadCloudCluster()
+	if err != nil {
+		return nil, err
+	}
+	if saved != nil && saved.Name == c.cluster.Name && saved.Spec.Provider == c.cluster.Spec.Provider {
+		c.cluster.Annotations = saved.Annotations
+		c.cluster.Spec.Masters.IPList = saved.Spec.Masters.IPList
+		c.cluster.Spec.Nodes.IPList = saved.Spec.Nodes.IPList
+	}
+
+	provider, err := NewDefaultProvider(c.cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := provider.Apply(); err != nil {
+		return nil, fmt.Errorf("failed to provision cloud resources with provider %s: %v", c.cluster.Spec.Provider, err)
+	}
+
+	// keep the v1 cluster with its resource annotations so delete can
+	// release what was created here
+	if err := os.MkdirAll(common.GetSealerWorkDir(), 0750); err != nil {
+		return nil, err
+	}
+	if err := yaml.MarshalToFile(CloudClusterfilePath(), c.cluster); err != nil {
+		return nil, fmt.Errorf("failed to save cloud cluster: %v", err)
+	}
+
+	return ToV2Cluster(c.cluster), nil
+}
+
+func (c *cloudApplier) Teardown() error {
+	now := metav1.Now()
+	c.cluster.DeletionTimestamp = &now
+
+	provider, err := NewDefaultProvider(c.cluster)
+	if err != nil {
+		return err
+	}
+
+	if err := provider.Apply(); err != nil {
+		return fmt.Errorf("failed to tear down cloud resources with provider %s: %v", c.cluster.Spec.Provider, err)
+	}
+
+	if err := os.Remove(CloudClusterfilePath()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// LoadCloudCluster reads back the cloud cluster saved by Provision.
+// It returns nil without error when the current cluster was not provisioned
+// through a cloud provider.
+func LoadCloudCluster() (*v1.Cluster, error) {
+	path := CloudClusterfilePath()
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	cluster := &v1.Cluster{}
+	if err := yaml.UnmarshalFile(path, cluster); err != nil {
+		return nil, fmt.Errorf("failed to load cloud cluster from %s: %v", path, err)
+	}
+	return cluster, nil
+}
+
+// ToV2Cluster converts a provisioned v1 cluster into the v2 form: the
+// allocated master and node IPs become role-tagged host entries.
+func ToV2Cluster(cluster *v1.Cluster) *v2.Cluster {
+	ret := &v2.Cluster{}
+	ret.Kind = "Cluster"
+	ret.APIVersion = v2.GroupVersion.String()
+	ret.Name = cluster.Name
+	ret.Spec.Image = cluster.Spec.Image
+	ret.Spec.Env = cluster.Spec.Env
+	ret.Spec.SSH = cluster.Spec.SSH
+
+	if len(cluster.Spec.Masters.IPList) != 0 {
+		ret.Spec.Hosts = append(ret.Spec.Hosts, v2.Host{
+			IPS:   cluster.Spec.Masters.IPList,
+			Roles: []string{common.MASTER},
+		})
+	}
+	if len(cluster.Spec.Nodes.IPList) != 0 {
+		ret.Spec.Hosts = append(ret.Spec.Hosts, v2.Host{
+			IPS:   cluster.Spec.Nodes.IPList,
+			Roles: []string{common.NODE},
+		})
+	}
+	return ret
+}
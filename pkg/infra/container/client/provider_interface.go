@@ -24,6 +24,8 @@ type ProviderService interface {
 	GetContainerInfo(containerID string, networkName string) (*Container, error)
 	RmContainer(containerID string) error
 	PullImage(imageName string) (string, error)
+	PrepareNetworkResource(networkName string) (string, error)
+	DeleteNetworkResource(id string) error
 }
 
 type Container struct {
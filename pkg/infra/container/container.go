@@ -39,6 +39,7 @@ const (
 	RoleLabel           = "sealer-io-role"
 	RoleLabelMaster     = "sealer-io-role-is-master"
 	NetworkName         = "sealer-network"
+	NetworkIDKey        = "sealer-network-id"
 	ImageName           = "sealerio/sealer-base-image:v1"
 	SealerImageRootPath = "/var/lib/sealer"
 	// for check rootless docker in info.SecurityOptions
@@ -72,6 +73,25 @@ func (a *ApplyProvider) Apply() error {
 		}
 		a.Cluster.Annotations = make(map[string]string)
 	}
+
+	// empty count means keep the current size
+	if a.Cluster.Spec.Masters.Count == "" {
+		a.Cluster.Spec.Masters.Count = strconv.Itoa(len(a.Cluster.Spec.Masters.IPList))
+	}
+	if a.Cluster.Spec.Nodes.Count == "" {
+		a.Cluster.Spec.Nodes.Count = strconv.Itoa(len(a.Cluster.Spec.Nodes.IPList))
+	}
+
+	// the containers of one cluster share a dedicated bridge network, keep
+	// its id so CleanUp can remove it with the last container
+	if a.Cluster.Annotations[NetworkIDKey] == "" {
+		networkID, err := a.Provider.PrepareNetworkResource(NetworkName)
+		if err != nil {
+			return fmt.Errorf("failed to prepare network %s: %v", NetworkName, err)
+		}
+		a.Cluster.Annotations[NetworkIDKey] = networkID
+	}
+
 	// change apply: scale up or scale down,count!=len(iplist)
 	if a.Cluster.Spec.Masters.Count != strconv.Itoa(len(a.Cluster.Spec.Masters.IPList)) ||
 		a.Cluster.Spec.Nodes.Count != strconv.Itoa(len(a.Cluster.Spec.Nodes.IPList)) {
@@ -121,13 +141,19 @@ func (a *ApplyProvider) ReconcileContainer() error {
 	// scale up: apply diff container, append ip list.
 	// scale down: delete diff container by id,delete ip list. if no container,need do cleanup
 	currentMasterNum := len(a.Cluster.Spec.Masters.IPList)
-	num, list, _ := getDiff(a.Cluster.Spec.Masters)
+	num, list, err := getDiff(a.Cluster.Spec.Masters)
+	if err != nil {
+		return fmt.Errorf("failed to get master diff: %v", err)
+	}
 	masterApplyResult := &ApplyResult{
 		ToJoinNumber:   num,
 		ToDeleteIPList: list,
 		Role:           MASTER,
 	}
-	num, list, _ = getDiff(a.Cluster.Spec.Nodes)
+	num, list, err = getDiff(a.Cluster.Spec.Nodes)
+	if err != nil {
+		return fmt.Errorf("failed to get node diff: %v", err)
+	}
 	nodeApplyResult := &ApplyResult{
 		ToJoinNumber:   num,
 		ToDeleteIPList: list,
@@ -286,6 +312,17 @@ func (a *ApplyProvider) CleanUp() error {
 			logrus.Infof("failed to delete container:%s", id)
 			return err
 		}
+		delete(a.Cluster.Annotations, ip.String())
+	}
+	a.Cluster.Spec.Masters.IPList = nil
+	a.Cluster.Spec.Nodes.IPList = nil
+
+	if networkID := a.Cluster.Annotations[NetworkIDKey]; networkID != "" {
+		if err := a.Provider.DeleteNetworkResource(networkID); err != nil {
+			logrus.Warnf("failed to delete network %s: %v", networkID, err)
+		} else {
+			delete(a.Cluster.Annotations, NetworkIDKey)
+		}
 	}
 
 	return nil
@@ -27,10 +27,10 @@ import (
 
 func IsDockerAvailable() bool {
 	lines, err := exec.RunSimpleCmd("docker -v")
-	if err != nil || len(lines) != 1 {
+	if err != nil {
 		return false
 	}
-	return strings.Contains(lines, "docker version")
+	return strings.Contains(strings.ToLower(lines), "docker version")
 }
 
 func getDiff(host v1.Hosts) (int, []net.IP, error) {
@@ -38,7 +38,7 @@ func getDiff(host v1.Hosts) (int, []net.IP, error) {
 	var iplist []net.IP
 	count, err := strconv.Atoi(host.Count)
 	if err != nil {
-		return 0, nil, err
+		return 0, nil, fmt.Errorf("count must be an instance number, got %q: %v", host.Count, err)
 	}
 	if count > len(host.IPList) {
 		//scale up
@@ -18,7 +18,10 @@ import (
 	"fmt"
 
 	"github.com/sealerio/sealer/pkg/infra/aliyun"
+	"github.com/sealerio/sealer/pkg/infra/aws"
 	"github.com/sealerio/sealer/pkg/infra/container"
+	"github.com/sealerio/sealer/pkg/infra/libvirt"
+	"github.com/sealerio/sealer/pkg/infra/terraform"
 	v1 "github.com/sealerio/sealer/types/api/v1"
 )
 
@@ -32,6 +35,12 @@ func NewDefaultProvider(cluster *v1.Cluster) (Interface, error) {
 	switch cluster.Spec.Provider {
 	case aliyun.AliCloud:
 		return NewAliProvider(cluster)
+	case aws.AWSCloud:
+		return NewAwsProvider(cluster)
+	case libvirt.LIBVIRT:
+		return NewLibvirtProvider(cluster)
+	case terraform.TERRAFORM:
+		return NewTerraformProvider(cluster)
 	case container.CONTAINER:
 		return NewContainerProvider(cluster)
 	default:
@@ -55,8 +64,35 @@ func NewAliProvider(cluster *v1.Cluster) (Interface, error) {
 	return aliProvider, nil
 }
 
+func NewAwsProvider(cluster *v1.Cluster) (Interface, error) {
+	if err := aws.CheckCLI(); err != nil {
+		return nil, err
+	}
+	awsProvider := new(aws.AwsProvider)
+	awsProvider.Cluster = cluster
+	return awsProvider, nil
+}
+
+func NewLibvirtProvider(cluster *v1.Cluster) (Interface, error) {
+	if err := libvirt.CheckCLI(); err != nil {
+		return nil, err
+	}
+	libvirtProvider := new(libvirt.LibvirtProvider)
+	libvirtProvider.Cluster = cluster
+	return libvirtProvider, nil
+}
+
+func NewTerraformProvider(cluster *v1.Cluster) (Interface, error) {
+	if err := terraform.CheckCLI(); err != nil {
+		return nil, err
+	}
+	terraformProvider := new(terraform.TerraformProvider)
+	terraformProvider.Cluster = cluster
+	return terraformProvider, nil
+}
+
 func NewContainerProvider(cluster *v1.Cluster) (Interface, error) {
-	if container.IsDockerAvailable() {
+	if !container.IsDockerAvailable() {
 		return nil, fmt.Errorf("please install docker on your system")
 	}
 
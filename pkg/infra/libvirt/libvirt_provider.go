@@ -0,0 +1,256 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package libvirt provisions local KVM virtual machines for dev/test
+// clusters. Each host is cloned from a base qcow2 image through the stock
+// virsh/virt-install/qemu-img tooling, so no libvirt binding is vendored.
+package libvirt
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/sealerio/sealer/common"
+	v1 "github.com/sealerio/sealer/types/api/v1"
+	"github.com/sealerio/sealer/utils/yaml"
+)
+
+const (
+	LIBVIRT = "LIBVIRT"
+
+	Master = "master"
+	Node   = "node"
+
+	LibvirtDomain = "sea.libvirt.com/"
+	MasterIDs     = LibvirtDomain + "MasterIDs"
+	NodeIDs       = LibvirtDomain + "NodeIDs"
+
+	// BaseImageEnv must point at a bootable qcow2 with sshd enabled; the
+	// remaining knobs have workable defaults for a dev box.
+	BaseImageEnv = "LIBVIRT_BASE_IMAGE"
+	NetworkEnv   = "LIBVIRT_NETWORK"
+	PoolDirEnv   = "LIBVIRT_POOL_DIR"
+	VCPUEnv      = "LIBVIRT_VCPUS"
+	MemoryMBEnv  = "LIBVIRT_MEMORY_MB"
+
+	DefaultNetwork  = "default"
+	DefaultPoolDir  = "/var/lib/libvirt/images"
+	DefaultVCPUs    = "2"
+	DefaultMemoryMB = "4096"
+
+	ipWaitInterval = 5 * time.Second
+	ipWaitTimeout  = 5 * time.Minute
+)
+
+type LibvirtProvider struct {
+	Cluster *v1.Cluster
+}
+
+// CheckCLI verifies the libvirt tooling the provider shells out to is installed.
+func CheckCLI() error {
+	for _, bin := range []string{"virsh", "virt-install", "qemu-img"} {
+		if _, err := exec.LookPath(bin); err != nil {
+			return fmt.Errorf("the %s provider requires %s on PATH, install the libvirt client tooling first: %v", LIBVIRT, bin, err)
+		}
+	}
+	return nil
+}
+
+func run(name string, args ...string) (string, error) {
+	var (
+		stdout bytes.Buffer
+		stderr bytes.Buffer
+	)
+
+	cmd := exec.Command(name, args...) // #nosec
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%s %s: %v: %s", name, strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+func envOrDefault(key, defaultValue string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return defaultValue
+}
+
+func (l *LibvirtProvider) SaveToDisk() error {
+	fileName := common.GetDefaultClusterfile()
+	if err := os.MkdirAll(filepath.Dir(fileName), os.ModePerm); err != nil {
+		return fmt.Errorf("mkdir failed %s %v", fileName, err)
+	}
+	return yaml.MarshalToFile(fileName, l.Cluster)
+}
+
+func (l *LibvirtProvider) domainName(role string, index int) string {
+	return fmt.Sprintf("sealer-%s-%s-%d", l.Cluster.Name, role, index)
+}
+
+// createDomain clones the base image into a fresh overlay disk and boots a
+// domain from it on the configured libvirt network.
+func (l *LibvirtProvider) createDomain(name, baseImage string) error {
+	diskPath := filepath.Join(envOrDefault(PoolDirEnv, DefaultPoolDir), name+".qcow2")
+	if _, err := run("qemu-img", "create", "-f", "qcow2", "-F", "qcow2", "-b", baseImage, diskPath); err != nil {
+		return err
+	}
+
+	_, err := run("virt-install",
+		"--name", name,
+		"--import",
+		"--disk", fmt.Sprintf("path=%s,format=qcow2,bus=virtio", diskPath),
+		"--network", fmt.Sprintf("network=%s,model=virtio", envOrDefault(NetworkEnv, DefaultNetwork)),
+		"--vcpus", envOrDefault(VCPUEnv, DefaultVCPUs),
+		"--memory", envOrDefault(MemoryMBEnv, DefaultMemoryMB),
+		"--os-variant", "generic",
+		"--noautoconsole")
+	return err
+}
+
+// domainIP polls `virsh domifaddr` until the guest reports its lease.
+func (l *LibvirtProvider) domainIP(name string) (net.IP, error) {
+	deadline := time.Now().Add(ipWaitTimeout)
+	for {
+		out, err := run("virsh", "domifaddr", name)
+		if err == nil {
+			for _, field := range strings.Fields(out) {
+				if !strings.Contains(field, "/") {
+					continue
+				}
+				if ip := net.ParseIP(strings.SplitN(field, "/", 2)[0]); ip != nil {
+					return ip, nil
+				}
+			}
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for domain %s to report an IP address", name)
+		}
+		time.Sleep(ipWaitInterval)
+	}
+}
+
+func (l *LibvirtProvider) ReconcileDomains(role string) error {
+	var (
+		hosts     *v1.Hosts
+		domainKey string
+	)
+	switch role {
+	case Master:
+		hosts = &l.Cluster.Spec.Masters
+		domainKey = MasterIDs
+	case Node:
+		hosts = &l.Cluster.Spec.Nodes
+		domainKey = NodeIDs
+	default:
+		return fmt.Errorf("unknown instance role %s", role)
+	}
+
+	if strings.TrimSpace(hosts.Count) == "" {
+		return nil
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(hosts.Count))
+	if err != nil || count < 0 {
+		return fmt.Errorf("invalid %s count %q", role, hosts.Count)
+	}
+	if count == 0 {
+		return nil
+	}
+
+	if l.Cluster.Annotations[domainKey] == "" {
+		baseImage := os.Getenv(BaseImageEnv)
+		if baseImage == "" {
+			return fmt.Errorf("the %s provider requires env %s pointing at a bootable qcow2 base image", LIBVIRT, BaseImageEnv)
+		}
+
+		var names []string
+		for i := 0; i < count; i++ {
+			name := l.domainName(role, i)
+			if err := l.createDomain(name, baseImage); err != nil {
+				return err
+			}
+			names = append(names, name)
+		}
+		l.Cluster.SetAnnotations(domainKey, strings.Join(names, ","))
+		if err := l.SaveToDisk(); err != nil {
+			return err
+		}
+	}
+
+	var ipList []net.IP
+	for _, name := range strings.Split(l.Cluster.Annotations[domainKey], ",") {
+		ip, err := l.domainIP(name)
+		if err != nil {
+			return err
+		}
+		logrus.Infof("domain %s is up with ip %s", name, ip)
+		ipList = append(ipList, ip)
+	}
+	hosts.IPList = ipList
+
+	return nil
+}
+
+func (l *LibvirtProvider) ClearCluster() {
+	for _, domainKey := range []string{MasterIDs, NodeIDs} {
+		if l.Cluster.Annotations[domainKey] == "" {
+			continue
+		}
+		for _, name := range strings.Split(l.Cluster.Annotations[domainKey], ",") {
+			if _, err := run("virsh", "destroy", name); err != nil {
+				logrus.Debugf("destroy domain %s: %s", name, err)
+			}
+			if _, err := run("virsh", "undefine", name, "--remove-all-storage"); err != nil {
+				logrus.Errorf("undefine domain %s failed err: %s", name, err)
+				continue
+			}
+			logrus.Infof("delete domain Success %s", name)
+		}
+		delete(l.Cluster.Annotations, domainKey)
+	}
+}
+
+func (l *LibvirtProvider) Reconcile() error {
+	if l.Cluster.Annotations == nil {
+		l.Cluster.Annotations = make(map[string]string)
+	}
+	if l.Cluster.DeletionTimestamp != nil {
+		logrus.Info("DeletionTimestamp not nil Clear Cluster")
+		l.ClearCluster()
+		return nil
+	}
+
+	if err := l.ReconcileDomains(Master); err != nil {
+		return err
+	}
+	if err := l.ReconcileDomains(Node); err != nil {
+		return err
+	}
+	return l.SaveToDisk()
+}
+
+func (l *LibvirtProvider) Apply() error {
+	return l.Reconcile()
+}
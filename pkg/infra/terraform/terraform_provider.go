@@ -0,0 +1,184 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package terraform provisions cluster hosts through a user supplied
+// Terraform module, so any cloud Terraform supports can feed sealer without
+// a dedicated Go provider. The module receives the cluster name and the
+// master/node counts as input variables and must expose the allocated
+// addresses through the master_ips and node_ips outputs.
+package terraform
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/sealerio/sealer/common"
+	v1 "github.com/sealerio/sealer/types/api/v1"
+	"github.com/sealerio/sealer/utils/yaml"
+)
+
+const (
+	TERRAFORM = "TERRAFORM"
+
+	// ModuleDirEnv points at the Terraform module directory; it is the only
+	// required configuration of this provider.
+	ModuleDirEnv = "SEALER_TERRAFORM_DIR"
+
+	// input variables passed to the module
+	varClusterName = "cluster_name"
+	varMasterCount = "master_count"
+	varNodeCount   = "node_count"
+
+	// outputs the module must define
+	outputMasterIPs = "master_ips"
+	outputNodeIPs   = "node_ips"
+)
+
+type TerraformProvider struct {
+	Cluster *v1.Cluster
+}
+
+// CheckCLI verifies the terraform binary the provider shells out to is installed.
+func CheckCLI() error {
+	if _, err := exec.LookPath("terraform"); err != nil {
+		return fmt.Errorf("the %s provider requires terraform on PATH, install it first: %v", TERRAFORM, err)
+	}
+	return nil
+}
+
+func (t *TerraformProvider) moduleDir() (string, error) {
+	dir := os.Getenv(ModuleDirEnv)
+	if dir == "" {
+		return "", fmt.Errorf("the %s provider requires env %s pointing at a terraform module directory", TERRAFORM, ModuleDirEnv)
+	}
+	if _, err := os.Stat(dir); err != nil {
+		return "", fmt.Errorf("terraform module directory %s is not accessible: %v", dir, err)
+	}
+	return dir, nil
+}
+
+// terraform runs one terraform command inside the module directory, streaming
+// nothing back but the raw stdout.
+func (t *TerraformProvider) terraform(dir string, args ...string) (string, error) {
+	var (
+		stdout bytes.Buffer
+		stderr bytes.Buffer
+	)
+
+	cmd := exec.Command("terraform", append([]string{"-chdir=" + dir}, args...)...) // #nosec
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("terraform %s: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+func (t *TerraformProvider) countVars() []string {
+	masterCount := strings.TrimSpace(t.Cluster.Spec.Masters.Count)
+	if masterCount == "" {
+		masterCount = "1"
+	}
+	nodeCount := strings.TrimSpace(t.Cluster.Spec.Nodes.Count)
+	if nodeCount == "" {
+		nodeCount = "0"
+	}
+	return []string{
+		"-var", fmt.Sprintf("%s=%s", varClusterName, t.Cluster.Name),
+		"-var", fmt.Sprintf("%s=%s", varMasterCount, masterCount),
+		"-var", fmt.Sprintf("%s=%s", varNodeCount, nodeCount),
+	}
+}
+
+func (t *TerraformProvider) SaveToDisk() error {
+	fileName := common.GetDefaultClusterfile()
+	if err := os.MkdirAll(filepath.Dir(fileName), os.ModePerm); err != nil {
+		return fmt.Errorf("mkdir failed %s %v", fileName, err)
+	}
+	return yaml.MarshalToFile(fileName, t.Cluster)
+}
+
+// readOutput decodes one of the module outputs into an IP list.
+func (t *TerraformProvider) readOutput(dir, name string) ([]net.IP, error) {
+	out, err := t.terraform(dir, "output", "-json", name)
+	if err != nil {
+		return nil, fmt.Errorf("the terraform module must define output %q: %v", name, err)
+	}
+
+	var ipStrs []string
+	if err := json.Unmarshal([]byte(out), &ipStrs); err != nil {
+		return nil, fmt.Errorf("output %q must be a list of IP strings: %v", name, err)
+	}
+
+	var ipList []net.IP
+	for _, ipStr := range ipStrs {
+		ip := net.ParseIP(ipStr)
+		if ip == nil {
+			return nil, fmt.Errorf("output %q contains invalid ip %q", name, ipStr)
+		}
+		ipList = append(ipList, ip)
+	}
+	return ipList, nil
+}
+
+func (t *TerraformProvider) Reconcile() error {
+	dir, err := t.moduleDir()
+	if err != nil {
+		return err
+	}
+
+	if t.Cluster.DeletionTimestamp != nil {
+		logrus.Info("DeletionTimestamp not nil Clear Cluster")
+		_, err := t.terraform(dir, append([]string{"destroy", "-auto-approve"}, t.countVars()...)...)
+		return err
+	}
+
+	// init is idempotent and cheap once the providers are cached
+	if _, err := t.terraform(dir, "init", "-input=false"); err != nil {
+		return err
+	}
+
+	logrus.Infof("start to apply terraform module %s", dir)
+	if _, err := t.terraform(dir, append([]string{"apply", "-auto-approve", "-input=false"}, t.countVars()...)...); err != nil {
+		return err
+	}
+
+	masterIPs, err := t.readOutput(dir, outputMasterIPs)
+	if err != nil {
+		return err
+	}
+	nodeIPs, err := t.readOutput(dir, outputNodeIPs)
+	if err != nil {
+		return err
+	}
+	if len(masterIPs) == 0 {
+		return fmt.Errorf("the terraform module allocated no master, check output %q", outputMasterIPs)
+	}
+	t.Cluster.Spec.Masters.IPList = masterIPs
+	t.Cluster.Spec.Nodes.IPList = nodeIPs
+
+	return t.SaveToDisk()
+}
+
+func (t *TerraformProvider) Apply() error {
+	return t.Reconcile()
+}
@@ -0,0 +1,139 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infradriver
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/syslog"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/sealerio/sealer/common"
+	"github.com/sealerio/sealer/pkg/metrics"
+)
+
+// audit operation kinds, one per way sealer touches a remote machine.
+const (
+	auditOpCmd        = "cmd"
+	auditOpCopy       = "copy"
+	auditOpCopyRemote = "copy-remote"
+)
+
+// auditRecord is one line of the audit log: what sealer executed or copied,
+// where, how long it took and whether it succeeded.
+type auditRecord struct {
+	Time       time.Time `json:"time"`
+	Cluster    string    `json:"cluster"`
+	Host       string    `json:"host"`
+	Op         string    `json:"op"`
+	Detail     string    `json:"detail"`
+	DurationMS int64     `json:"durationMs"`
+	Status     string    `json:"status"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// auditor appends structured records of every remote command and file copy
+// to a per-apply log file, and optionally mirrors them to syslog. All
+// methods are nil-safe so a failed setup degrades to no auditing instead of
+// blocking the apply.
+type auditor struct {
+	cluster string
+	mu      sync.Mutex
+	enc     *json.Encoder
+	syslog  *syslog.Writer
+}
+
+// newAuditor opens a fresh audit log under the sealer log dir, named after
+// the cluster and the start time of this apply. It returns nil (and warns)
+// when the log cannot be created, e.g. sealer runs without the permission
+// to write /var/lib/sealer.
+func newAuditor(clusterName string) *auditor {
+	a, err := newAuditorAt(filepath.Join(common.DefaultLogDir, "audit"), clusterName)
+	if err != nil {
+		logrus.Warnf("failed to initialize remote command audit log: %v", err)
+		return nil
+	}
+
+	if os.Getenv(common.EnvAuditSyslog) == "true" {
+		w, err := syslog.New(syslog.LOG_INFO|syslog.LOG_AUTHPRIV, "sealer")
+		if err != nil {
+			logrus.Warnf("failed to connect audit log to syslog: %v", err)
+		} else {
+			a.syslog = w
+		}
+	}
+
+	return a
+}
+
+func newAuditorAt(dir, clusterName string) (*auditor, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create audit log dir %s: %v", dir, err)
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("%s-%s.audit.log", clusterName, time.Now().Format("20060102-150405")))
+	// audit logs record what ran on production machines, keep them
+	// owner-readable only
+	f, err := os.OpenFile(filepath.Clean(path), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create audit log %s: %v", path, err)
+	}
+
+	logrus.Debugf("auditing remote commands to %s", path)
+	return &auditor{cluster: clusterName, enc: json.NewEncoder(f)}, nil
+}
+
+// record appends one entry; failures to write the audit trail are reported
+// but never fail the operation being audited.
+func (a *auditor) record(host net.IP, op, detail string, start time.Time, opErr error) {
+	metrics.ObserveRemoteOp(host.String(), op, start, opErr)
+	if a == nil {
+		return
+	}
+
+	r := auditRecord{
+		Time:       start,
+		Cluster:    a.cluster,
+		Host:       host.String(),
+		Op:         op,
+		Detail:     detail,
+		DurationMS: time.Since(start).Milliseconds(),
+		Status:     "ok",
+	}
+	if opErr != nil {
+		r.Status = "failed"
+		r.Error = opErr.Error()
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.enc.Encode(r); err != nil {
+		logrus.Warnf("failed to write audit record: %v", err)
+	}
+	if a.syslog != nil {
+		data, err := json.Marshal(r)
+		if err == nil {
+			if err := a.syslog.Info(string(data)); err != nil {
+				logrus.Warnf("failed to send audit record to syslog: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infradriver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditorRecord(t *testing.T) {
+	dir := t.TempDir()
+	a, err := newAuditorAt(dir, "my-cluster")
+	assert.NoError(t, err)
+
+	host := net.ParseIP("192.168.0.2")
+	a.record(host, auditOpCmd, "uname -m", time.Now(), nil)
+	a.record(host, auditOpCopy, "/tmp/rootfs -> /var/lib/sealer", time.Now(), fmt.Errorf("connection refused"))
+
+	files, err := filepath.Glob(filepath.Join(dir, "my-cluster-*.audit.log"))
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+
+	data, err := os.ReadFile(files[0])
+	assert.NoError(t, err)
+
+	var records []auditRecord
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var r auditRecord
+		assert.NoError(t, json.Unmarshal([]byte(line), &r))
+		records = append(records, r)
+	}
+	assert.Len(t, records, 2)
+
+	assert.Equal(t, "my-cluster", records[0].Cluster)
+	assert.Equal(t, "192.168.0.2", records[0].Host)
+	assert.Equal(t, auditOpCmd, records[0].Op)
+	assert.Equal(t, "uname -m", records[0].Detail)
+	assert.Equal(t, "ok", records[0].Status)
+	assert.Empty(t, records[0].Error)
+
+	assert.Equal(t, auditOpCopy, records[1].Op)
+	assert.Equal(t, "failed", records[1].Status)
+	assert.Equal(t, "connection refused", records[1].Error)
+}
+
+func TestAuditorNilSafe(t *testing.T) {
+	var a *auditor
+	// a nil auditor must swallow records instead of panicking
+	a.record(net.ParseIP("192.168.0.2"), auditOpCmd, "uname -m", time.Now(), nil)
+}
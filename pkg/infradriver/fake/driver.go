@@ -0,0 +1,405 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fake provides an in-memory implementation of
+// infradriver.InfraDriver so the runtime and processor packages can be unit
+// tested without real machines. A Driver records every remote command and
+// file copy in order, answers commands from canned outputs or a replayed
+// Session, and keeps a per-host map of remote files for Copy/CopyR and the
+// existence checks.
+package fake
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	k8sv1 "k8s.io/api/core/v1"
+
+	"github.com/sealerio/sealer/common"
+	v1 "github.com/sealerio/sealer/types/api/v1"
+	v2 "github.com/sealerio/sealer/types/api/v2"
+	mapUtils "github.com/sealerio/sealer/utils/maps"
+	strUtil "github.com/sealerio/sealer/utils/strings"
+)
+
+// ExecutedCommand is one remote command a test ran through the Driver.
+type ExecutedCommand struct {
+	Host string
+	Cmd  string
+}
+
+// CopyRecord is one file transfer a test ran through the Driver.
+type CopyRecord struct {
+	Host     string
+	Local    string
+	Remote   string
+	ToRemote bool
+}
+
+// Driver implements infradriver.InfraDriver in memory. The exported fields
+// are plain test fixtures: populate them directly or through AddHost,
+// SetCmdOutput and SetFile before handing the Driver to the code under test,
+// then assert on Commands and Copies afterwards.
+type Driver struct {
+	mu sync.Mutex
+
+	ClusterName string
+	ImageName   string
+	// DataRoot mirrors cluster.Spec.DataRoot, common.DefaultSealerDataDir
+	// when empty.
+	DataRoot   string
+	LaunchCmds []string
+	LaunchApps []string
+	Registry   v2.Registry
+	Certs      v2.Certs
+	// Platform is reported for every host.
+	Platform v1.Platform
+
+	ClusterEnv map[string]string
+	HostEnv    map[string]map[string]string
+	HostLabels map[string]map[string]string
+	HostTaints map[string][]k8sv1.Taint
+	HostNames  map[string]string
+
+	// Commands and Copies record every remote interaction in execution
+	// order.
+	Commands []ExecutedCommand
+	Copies   []CopyRecord
+
+	// OnCmd, when set, answers every command before canned outputs and
+	// replayed sessions are consulted.
+	OnCmd func(host net.IP, cmd string) (string, error)
+
+	hosts     []net.IP
+	hostRoles map[string][]string
+	roleHosts map[string][]net.IP
+
+	cmdOutputs map[string]string
+	cmdErrors  map[string]error
+	// files maps host -> remote path -> content.
+	files map[string]map[string][]byte
+
+	session *Session
+}
+
+// NewDriver returns an empty Driver for the cluster name the repo test
+// fixtures use; add hosts with AddHost before use.
+func NewDriver() *Driver {
+	return &Driver{
+		ClusterName: "my-cluster",
+		ImageName:   "docker.io/sealerio/kubernetes:v1.22.15",
+		Platform:    v1.Platform{Architecture: "amd64", OS: "linux"},
+		ClusterEnv:  map[string]string{},
+		HostEnv:     map[string]map[string]string{},
+		HostLabels:  map[string]map[string]string{},
+		HostTaints:  map[string][]k8sv1.Taint{},
+		HostNames:   map[string]string{},
+		hostRoles:   map[string][]string{},
+		roleHosts:   map[string][]net.IP{},
+		cmdOutputs:  map[string]string{},
+		cmdErrors:   map[string]error{},
+		files:       map[string]map[string][]byte{},
+	}
+}
+
+// AddHost registers hosts under the given roles, appending roles for hosts
+// added before.
+func (d *Driver) AddHost(roles []string, ips ...net.IP) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, ip := range ips {
+		if !strUtil.IsInSlice(ip.String(), d.hostRoleKeys()) {
+			d.hosts = append(d.hosts, ip)
+		}
+		for _, role := range roles {
+			if strUtil.IsInSlice(role, d.hostRoles[ip.String()]) {
+				continue
+			}
+			d.hostRoles[ip.String()] = append(d.hostRoles[ip.String()], role)
+			d.roleHosts[role] = append(d.roleHosts[role], ip)
+		}
+	}
+}
+
+func (d *Driver) hostRoleKeys() []string {
+	keys := make([]string, 0, len(d.hostRoles))
+	for key := range d.hostRoles {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// SetCmdOutput cans the output (and optional error) returned when exactly
+// cmd is run on any host.
+func (d *Driver) SetCmdOutput(cmd, output string, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.cmdOutputs[cmd] = output
+	if err != nil {
+		d.cmdErrors[cmd] = err
+	}
+}
+
+// SetFile seeds a remote file so IsFileExist reports it and CopyR can fetch
+// it.
+func (d *Driver) SetFile(host net.IP, remoteFilePath string, content []byte) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.files[host.String()] == nil {
+		d.files[host.String()] = map[string][]byte{}
+	}
+	d.files[host.String()][remoteFilePath] = content
+}
+
+// Replay answers commands from a recorded Session before canned outputs;
+// see NewRecorder for producing one against real machines.
+func (d *Driver) Replay(session *Session) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.session = session
+}
+
+func (d *Driver) run(host net.IP, cmd string) (string, error) {
+	d.mu.Lock()
+	d.Commands = append(d.Commands, ExecutedCommand{Host: host.String(), Cmd: cmd})
+	onCmd := d.OnCmd
+	session := d.session
+	output, canned := d.cmdOutputs[cmd]
+	err := d.cmdErrors[cmd]
+	d.mu.Unlock()
+
+	if onCmd != nil {
+		return onCmd(host, cmd)
+	}
+	if session != nil {
+		if output, err, ok := session.lookup(host, cmd); ok {
+			return output, err
+		}
+	}
+	if canned {
+		return output, err
+	}
+	return "", nil
+}
+
+func (d *Driver) GetHostTaints(host net.IP) []k8sv1.Taint {
+	return d.HostTaints[host.String()]
+}
+
+func (d *Driver) GetHostIPList() []net.IP {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]net.IP{}, d.hosts...)
+}
+
+func (d *Driver) GetHostIPListByRole(role string) []net.IP {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]net.IP{}, d.roleHosts[role]...)
+}
+
+func (d *Driver) GetRoleListByHostIP(ip string) []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string{}, d.hostRoles[ip]...)
+}
+
+func (d *Driver) GetHostsPlatform(hosts []net.IP) (map[v1.Platform][]net.IP, error) {
+	return map[v1.Platform][]net.IP{d.Platform: hosts}, nil
+}
+
+func (d *Driver) GetHostEnv(host net.IP) map[string]string {
+	return mapUtils.Merge(d.HostEnv[host.String()], d.ClusterEnv)
+}
+
+func (d *Driver) GetHostLabels(host net.IP) map[string]string {
+	return d.HostLabels[host.String()]
+}
+
+func (d *Driver) GetClusterEnv() map[string]string {
+	return d.ClusterEnv
+}
+
+func (d *Driver) AddClusterEnv(envs []string) {
+	if d.ClusterEnv == nil || envs == nil {
+		return
+	}
+	for _, env := range envs {
+		kv := strings.SplitN(env, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		d.ClusterEnv[kv[0]] = kv[1]
+	}
+}
+
+func (d *Driver) GetClusterName() string {
+	return d.ClusterName
+}
+
+func (d *Driver) GetClusterImageName() string {
+	return d.ImageName
+}
+
+func (d *Driver) GetClusterLaunchCmds() []string {
+	return d.LaunchCmds
+}
+
+func (d *Driver) GetClusterLaunchApps() []string {
+	return d.LaunchApps
+}
+
+func (d *Driver) GetClusterRootfsPath() string {
+	return filepath.Join(d.GetClusterBasePath(), "rootfs")
+}
+
+func (d *Driver) GetClusterBasePath() string {
+	dataRoot := d.DataRoot
+	if dataRoot == "" {
+		dataRoot = common.DefaultSealerDataDir
+	}
+	return filepath.Join(dataRoot, d.ClusterName)
+}
+
+// Execute runs f over the hosts sequentially so recorded commands keep a
+// deterministic order, unlike the concurrent real driver.
+func (d *Driver) Execute(hosts []net.IP, f func(host net.IP) error) error {
+	for _, host := range hosts {
+		if err := f(host); err != nil {
+			return fmt.Errorf("on host [%s]: %v", host.String(), err)
+		}
+	}
+	return nil
+}
+
+func (d *Driver) Copy(host net.IP, localFilePath, remoteFilePath string) error {
+	// missing local files are tolerated so tests need not lay out a full
+	// rootfs; the remote file is still marked present.
+	content, err := os.ReadFile(filepath.Clean(localFilePath))
+	if err != nil {
+		content = []byte{}
+	}
+	d.SetFile(host, remoteFilePath, content)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.Copies = append(d.Copies, CopyRecord{Host: host.String(), Local: localFilePath, Remote: remoteFilePath, ToRemote: true})
+	return nil
+}
+
+func (d *Driver) CopyR(host net.IP, remoteFilePath, localFilePath string) error {
+	d.mu.Lock()
+	content, ok := d.files[host.String()][remoteFilePath]
+	d.Copies = append(d.Copies, CopyRecord{Host: host.String(), Local: localFilePath, Remote: remoteFilePath, ToRemote: false})
+	d.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("no fake content seeded for %s on host(%s), use SetFile", remoteFilePath, host.String())
+	}
+	if err := os.MkdirAll(filepath.Dir(localFilePath), os.FileMode(0700)); err != nil {
+		return err
+	}
+	return os.WriteFile(localFilePath, content, os.FileMode(0600))
+}
+
+func (d *Driver) CmdAsync(host net.IP, env map[string]string, cmd ...string) error {
+	for _, c := range cmd {
+		if _, err := d.run(host, c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *Driver) Cmd(host net.IP, env map[string]string, cmd string) ([]byte, error) {
+	out, err := d.run(host, cmd)
+	return []byte(out), err
+}
+
+func (d *Driver) CmdToString(host net.IP, env map[string]string, cmd, spilt string) (string, error) {
+	out, err := d.run(host, cmd)
+	if err != nil {
+		return out, err
+	}
+	out = strings.ReplaceAll(out, "\r\n", spilt)
+	out = strings.ReplaceAll(out, "\n", spilt)
+	return out, nil
+}
+
+func (d *Driver) IsFileExist(host net.IP, remoteFilePath string) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, ok := d.files[host.String()][remoteFilePath]
+	return ok, nil
+}
+
+func (d *Driver) IsDirExist(host net.IP, remoteDirPath string) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	prefix := strings.TrimSuffix(remoteDirPath, "/") + "/"
+	for path := range d.files[host.String()] {
+		if strings.HasPrefix(path, prefix) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (d *Driver) GetPlatform(host net.IP) (v1.Platform, error) {
+	return d.Platform, nil
+}
+
+func (d *Driver) GetHostName(host net.IP) (string, error) {
+	if name, ok := d.HostNames[host.String()]; ok {
+		return name, nil
+	}
+	return "node-" + strings.NewReplacer(".", "-", ":", "-").Replace(host.String()), nil
+}
+
+func (d *Driver) Ping(host net.IP) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.hostRoles[host.String()]; !ok {
+		return fmt.Errorf("ip(%s) is not in cluster", host.String())
+	}
+	return nil
+}
+
+func (d *Driver) SetHostName(host net.IP, hostName string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.HostNames[host.String()] = hostName
+	return nil
+}
+
+func (d *Driver) SetClusterHostAliases(hosts []net.IP) error {
+	return nil
+}
+
+func (d *Driver) DeleteClusterHostAliases(hosts []net.IP) error {
+	return nil
+}
+
+func (d *Driver) GetClusterRegistry() v2.Registry {
+	return d.Registry
+}
+
+func (d *Driver) GetClusterCerts() v2.Certs {
+	return d.Certs
+}
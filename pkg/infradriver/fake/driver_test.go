@@ -0,0 +1,125 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fake
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sealerio/sealer/common"
+	"github.com/sealerio/sealer/pkg/infradriver"
+)
+
+var _ infradriver.InfraDriver = &Driver{}
+var _ infradriver.InfraDriver = &Recorder{}
+
+func TestDriverHostsAndRoles(t *testing.T) {
+	driver := NewDriver()
+	master := net.ParseIP("192.168.0.2")
+	node := net.ParseIP("192.168.0.3")
+	driver.AddHost([]string{common.MASTER}, master)
+	driver.AddHost([]string{common.NODE}, node)
+
+	assert.Len(t, driver.GetHostIPList(), 2)
+	assert.Equal(t, []net.IP{master}, driver.GetHostIPListByRole(common.MASTER))
+	assert.Equal(t, []string{common.NODE}, driver.GetRoleListByHostIP(node.String()))
+
+	// the same host gains roles without duplicating
+	driver.AddHost([]string{common.MASTER, "etcd"}, master)
+	assert.Len(t, driver.GetHostIPList(), 2)
+	assert.Equal(t, []string{common.MASTER, "etcd"}, driver.GetRoleListByHostIP(master.String()))
+
+	assert.NoError(t, driver.Ping(master))
+	assert.Error(t, driver.Ping(net.ParseIP("192.168.0.9")))
+}
+
+func TestDriverCommands(t *testing.T) {
+	driver := NewDriver()
+	master := net.ParseIP("192.168.0.2")
+	driver.AddHost([]string{common.MASTER}, master)
+	driver.SetCmdOutput("hostname", "master0\n", nil)
+
+	out, err := driver.CmdToString(master, nil, "hostname", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "master0", out)
+
+	// unknown commands succeed with empty output
+	assert.NoError(t, driver.CmdAsync(master, nil, "systemctl restart kubelet"))
+
+	assert.Equal(t, []ExecutedCommand{
+		{Host: master.String(), Cmd: "hostname"},
+		{Host: master.String(), Cmd: "systemctl restart kubelet"},
+	}, driver.Commands)
+}
+
+func TestDriverFiles(t *testing.T) {
+	driver := NewDriver()
+	master := net.ParseIP("192.168.0.2")
+	driver.AddHost([]string{common.MASTER}, master)
+
+	local := filepath.Join(t.TempDir(), "admin.conf")
+	assert.NoError(t, os.WriteFile(local, []byte("kubeconfig"), 0600))
+	assert.NoError(t, driver.Copy(master, local, "/etc/kubernetes/admin.conf"))
+
+	exist, err := driver.IsFileExist(master, "/etc/kubernetes/admin.conf")
+	assert.NoError(t, err)
+	assert.True(t, exist)
+
+	exist, err = driver.IsDirExist(master, "/etc/kubernetes")
+	assert.NoError(t, err)
+	assert.True(t, exist)
+
+	fetched := filepath.Join(t.TempDir(), "fetched.conf")
+	assert.NoError(t, driver.CopyR(master, "/etc/kubernetes/admin.conf", fetched))
+	content, err := os.ReadFile(fetched)
+	assert.NoError(t, err)
+	assert.Equal(t, "kubeconfig", string(content))
+
+	// fetching a file nobody seeded is an error, not silent emptiness
+	assert.Error(t, driver.CopyR(master, "/etc/kubernetes/missing.conf", fetched))
+}
+
+func TestSessionRecordAndReplay(t *testing.T) {
+	master := net.ParseIP("192.168.0.2")
+
+	// record against one fake standing in for the real machines
+	source := NewDriver()
+	source.AddHost([]string{common.MASTER}, master)
+	source.SetCmdOutput("kubectl version", "v1.22.15", nil)
+
+	recorder := NewRecorder(source)
+	out, err := recorder.CmdToString(master, nil, "kubectl version", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.22.15", out)
+
+	path := filepath.Join(t.TempDir(), "session.json")
+	assert.NoError(t, recorder.Session.Save(path))
+
+	// replay into a fresh fake without canned outputs
+	session, err := LoadSession(path)
+	assert.NoError(t, err)
+
+	replayed := NewDriver()
+	replayed.AddHost([]string{common.MASTER}, master)
+	replayed.Replay(session)
+
+	out, err = replayed.CmdToString(master, nil, "kubectl version", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.22.15", out)
+}
@@ -0,0 +1,149 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/sealerio/sealer/pkg/infradriver"
+)
+
+// Record is one remote command of a recorded session, with the output it
+// produced.
+type Record struct {
+	Host   string `json:"host"`
+	Cmd    string `json:"cmd"`
+	Output string `json:"output,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Session is an ordered list of remote commands and their outputs. Record
+// one against real machines with NewRecorder, save it next to the test, and
+// feed it to Driver.Replay so the test answers the same commands the same
+// way without the machines.
+type Session struct {
+	Records []Record `json:"records"`
+}
+
+// Save writes the session as JSON, pretty printed so recorded fixtures
+// review well.
+func (s *Session) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), os.FileMode(0750)); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, os.FileMode(0644))
+}
+
+// LoadSession reads a session saved by Save.
+func LoadSession(path string) (*Session, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load session %s: %v", path, err)
+	}
+	session := &Session{}
+	if err := json.Unmarshal(data, session); err != nil {
+		return nil, fmt.Errorf("failed to parse session %s: %v", path, err)
+	}
+	return session, nil
+}
+
+// lookup answers cmd from the session, preferring a record of the same host
+// and falling back to the same command on any host.
+func (s *Session) lookup(host net.IP, cmd string) (string, error, bool) {
+	var fallback *Record
+	for i := range s.Records {
+		record := &s.Records[i]
+		if record.Cmd != cmd {
+			continue
+		}
+		if record.Host == host.String() {
+			return record.Output, record.err(), true
+		}
+		if fallback == nil {
+			fallback = record
+		}
+	}
+	if fallback != nil {
+		return fallback.Output, fallback.err(), true
+	}
+	return "", nil, false
+}
+
+func (r *Record) err() error {
+	if r.Error == "" {
+		return nil
+	}
+	return fmt.Errorf("%s", r.Error)
+}
+
+// Recorder wraps a real InfraDriver and records every command and its
+// output into Session; all other calls pass through the embedded driver.
+type Recorder struct {
+	infradriver.InfraDriver
+
+	mu      sync.Mutex
+	Session *Session
+}
+
+// NewRecorder returns a Recorder around inner, typically a driver built by
+// infradriver.NewInfraDriver against real machines.
+func NewRecorder(inner infradriver.InfraDriver) *Recorder {
+	return &Recorder{
+		InfraDriver: inner,
+		Session:     &Session{},
+	}
+}
+
+func (r *Recorder) record(host net.IP, cmd, output string, err error) {
+	record := Record{Host: host.String(), Cmd: cmd, Output: output}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Session.Records = append(r.Session.Records, record)
+}
+
+func (r *Recorder) Cmd(host net.IP, env map[string]string, cmd string) ([]byte, error) {
+	out, err := r.InfraDriver.Cmd(host, env, cmd)
+	r.record(host, cmd, string(out), err)
+	return out, err
+}
+
+func (r *Recorder) CmdToString(host net.IP, env map[string]string, cmd, spilt string) (string, error) {
+	out, err := r.InfraDriver.CmdToString(host, env, cmd, spilt)
+	r.record(host, cmd, out, err)
+	return out, err
+}
+
+func (r *Recorder) CmdAsync(host net.IP, env map[string]string, cmd ...string) error {
+	for _, c := range cmd {
+		err := r.InfraDriver.CmdAsync(host, env, c)
+		r.record(host, c, "", err)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -103,6 +103,9 @@ type InfraDriver interface {
 	DeleteClusterHostAliases(hosts []net.IP) error
 
 	GetClusterRegistry() v2.Registry
+
+	// GetClusterCerts return the extra trust material of the cluster
+	GetClusterCerts() v2.Certs
 	// SetLvsRule add or update host name on host
 	//SetLvsRule(host net.IP, hostName string) error
 }
@@ -18,12 +18,18 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/containers/buildah/util"
 	"github.com/imdario/mergo"
 	"github.com/sealerio/sealer/common"
+	"github.com/sealerio/sealer/pkg/logger"
+	"github.com/sealerio/sealer/pkg/metrics"
 	v1 "github.com/sealerio/sealer/types/api/v1"
 	v2 "github.com/sealerio/sealer/types/api/v2"
 	mapUtils "github.com/sealerio/sealer/utils/maps"
@@ -36,15 +42,25 @@ import (
 )
 
 type SSHInfraDriver struct {
-	sshConfigs   map[string]ssh.Interface
-	hosts        []net.IP
-	hostTaint    map[string][]k8sv1.Taint
-	hostRolesMap map[string][]string
-	roleHostsMap map[string][]net.IP
-	hostLabels   map[string]map[string]string
-	hostEnvMap   map[string]map[string]string
-	clusterEnv   map[string]string
-	cluster      v2.Cluster
+	sshConfigs map[string]ssh.Interface
+	// hostExternalIP maps an internal host IP to the external address to
+	// dial instead when the host sits behind NAT; hosts reachable directly
+	// have no entry.
+	hostExternalIP map[string]net.IP
+	hosts          []net.IP
+	hostTaint      map[string][]k8sv1.Taint
+	hostRolesMap   map[string][]string
+	roleHostsMap   map[string][]net.IP
+	hostLabels     map[string]map[string]string
+	hostEnvMap     map[string]map[string]string
+	clusterEnv     map[string]string
+	cluster        v2.Cluster
+	// sshConcurrency caps how many hosts Execute works on at the same
+	// time; 0 means no limit.
+	sshConcurrency int
+	// audit records every remote command and file copy of this apply; nil
+	// when the audit log could not be set up.
+	audit *auditor
 }
 
 func convertTaints(taints []string) ([]k8sv1.Taint, error) {
@@ -63,10 +79,11 @@ func convertTaints(taints []string) ([]k8sv1.Taint, error) {
 func NewInfraDriver(cluster *v2.Cluster) (InfraDriver, error) {
 	var err error
 	ret := &SSHInfraDriver{
-		cluster:      *cluster,
-		sshConfigs:   map[string]ssh.Interface{},
-		roleHostsMap: map[string][]net.IP{},
-		hostRolesMap: map[string][]string{},
+		cluster:        *cluster,
+		sshConfigs:     map[string]ssh.Interface{},
+		hostExternalIP: map[string]net.IP{},
+		roleHostsMap:   map[string][]net.IP{},
+		hostRolesMap:   map[string][]string{},
 		// todo need to separate env into app render data and sys render data
 		hostEnvMap: map[string]map[string]string{},
 		hostLabels: map[string]map[string]string{},
@@ -86,6 +103,10 @@ func NewInfraDriver(cluster *v2.Cluster) (InfraDriver, error) {
 		return nil, err
 	}
 
+	ret.audit = newAuditor(cluster.Name)
+	metrics.ServeIfConfigured()
+	logger.SetCluster(cluster.Name)
+
 	if k8snet.IsIPv6String(ret.hosts[0].String()) {
 		hostIPFamilyEnv := fmt.Sprintf("%s=%s", common.EnvHostIPFamily, k8snet.IPv6)
 		if !util.StringInSlice(hostIPFamilyEnv, cluster.Spec.Env) {
@@ -93,13 +114,21 @@ func NewInfraDriver(cluster *v2.Cluster) (InfraDriver, error) {
 		}
 	}
 
-	// initialize sshConfigs field
+	// initialize sshConfigs and hostExternalIP fields; the configs stay
+	// keyed by the internal IP since that is how every caller names a host
 	for i := range cluster.Spec.Hosts {
 		if err = mergo.Merge(&cluster.Spec.Hosts[i].SSH, &cluster.Spec.SSH); err != nil {
 			return nil, err
 		}
-		for _, ip := range cluster.Spec.Hosts[i].IPS {
+		host := cluster.Spec.Hosts[i]
+		if len(host.ExternalIPS) != 0 && len(host.ExternalIPS) != len(host.IPS) {
+			return nil, fmt.Errorf("host entry %v: expect one external ip per entry in ips, got %d for %d ips", host.IPS, len(host.ExternalIPS), len(host.IPS))
+		}
+		for j, ip := range host.IPS {
 			ret.sshConfigs[ip.String()] = ssh.NewSSHClient(&cluster.Spec.Hosts[i].SSH, true)
+			if len(host.ExternalIPS) != 0 {
+				ret.hostExternalIP[ip.String()] = host.ExternalIPS[j]
+			}
 		}
 	}
 
@@ -119,6 +148,16 @@ func NewInfraDriver(cluster *v2.Cluster) (InfraDriver, error) {
 	}
 
 	ret.clusterEnv = strUtil.ConvertStringSliceToMap(cluster.Spec.Env)
+	injectProxyEnv(ret.clusterEnv, cluster, ret.hosts)
+
+	// initialize sshConcurrency field from cluster env
+	if v, ok := ret.clusterEnv[common.EnvSSHConcurrency]; ok {
+		sshConcurrency, err := strconv.Atoi(v)
+		if err != nil || sshConcurrency < 0 {
+			return nil, fmt.Errorf("invalid env %s(%s): expect a non-negative integer", common.EnvSSHConcurrency, v)
+		}
+		ret.sshConcurrency = sshConcurrency
+	}
 
 	// initialize hostEnvMap and host labels field
 	// merge the host ENV and global env, the host env will overwrite cluster.Spec.Env
@@ -141,6 +180,46 @@ func NewInfraDriver(cluster *v2.Cluster) (InfraDriver, error) {
 	return ret, err
 }
 
+// injectProxyEnv exports cluster.Spec.Proxy into the cluster env, so every
+// remote command and rootfs script runs proxy aware. Explicit env entries
+// win over the proxy fields, and NO_PROXY always covers localhost, the
+// cluster hosts and the local registry domain.
+func injectProxyEnv(clusterEnv map[string]string, cluster *v2.Cluster, hosts []net.IP) {
+	proxy := cluster.Spec.Proxy
+	if proxy.HTTPProxy == "" && proxy.HTTPSProxy == "" && proxy.NoProxy == "" {
+		return
+	}
+
+	noProxyEntries := []string{"localhost", "127.0.0.1"}
+	if proxy.NoProxy != "" {
+		noProxyEntries = append(noProxyEntries, strings.Split(proxy.NoProxy, ",")...)
+	}
+	for _, ip := range hosts {
+		noProxyEntries = append(noProxyEntries, ip.String())
+	}
+	if reg := cluster.Spec.Registry.LocalRegistry; reg != nil && reg.Domain != "" {
+		noProxyEntries = append(noProxyEntries, reg.Domain)
+	}
+
+	setIfAbsent := func(key, value string) {
+		if value == "" {
+			return
+		}
+		if _, ok := clusterEnv[key]; !ok {
+			clusterEnv[key] = value
+		}
+	}
+	// both spellings: systemd and most tooling read the upper case form,
+	// while curl and many scripts only honor the lower case one
+	setIfAbsent("HTTP_PROXY", proxy.HTTPProxy)
+	setIfAbsent("http_proxy", proxy.HTTPProxy)
+	setIfAbsent("HTTPS_PROXY", proxy.HTTPSProxy)
+	setIfAbsent("https_proxy", proxy.HTTPSProxy)
+	noProxy := strings.Join(noProxyEntries, ",")
+	setIfAbsent("NO_PROXY", noProxy)
+	setIfAbsent("no_proxy", noProxy)
+}
+
 func (d *SSHInfraDriver) GetHostTaints(host net.IP) []k8sv1.Taint {
 	return d.hostTaint[host.String()]
 }
@@ -163,6 +242,21 @@ func (d *SSHInfraDriver) GetHostEnv(host net.IP) map[string]string {
 	if _, ok := hostEnv[common.EnvHostIP]; !ok {
 		hostEnv[common.EnvHostIP] = host.String()
 	}
+	if _, ok := hostEnv[common.EnvHostRoles]; !ok {
+		if roles := d.GetRoleListByHostIP(host.String()); len(roles) > 0 {
+			hostEnv[common.EnvHostRoles] = strings.Join(roles, ",")
+		}
+	}
+	if _, ok := hostEnv[common.EnvHostLabels]; !ok {
+		if labels := d.GetHostLabels(host); len(labels) > 0 {
+			var kvs []string
+			for k, v := range labels {
+				kvs = append(kvs, fmt.Sprintf("%s=%s", k, v))
+			}
+			sort.Strings(kvs)
+			hostEnv[common.EnvHostLabels] = strings.Join(kvs, ",")
+		}
+	}
 	return hostEnv
 }
 
@@ -188,12 +282,34 @@ func (d *SSHInfraDriver) GetClusterRegistry() v2.Registry {
 	return d.cluster.Spec.Registry
 }
 
+func (d *SSHInfraDriver) GetClusterCerts() v2.Certs {
+	return d.cluster.Spec.Certs
+}
+
+// transportIP returns the address to dial for the given host: the external
+// IP when one is declared, otherwise the host IP itself. Audit records and
+// metrics keep using the internal IP, the name of the host in the cluster.
+func (d *SSHInfraDriver) transportIP(host net.IP) net.IP {
+	if external, ok := d.hostExternalIP[host.String()]; ok {
+		return external
+	}
+	return host
+}
+
 func (d *SSHInfraDriver) Copy(host net.IP, localFilePath, remoteFilePath string) error {
 	client := d.sshConfigs[host.String()]
 	if client == nil {
 		return fmt.Errorf("ip(%s) is not in cluster", host.String())
 	}
-	return client.Copy(host, localFilePath, remoteFilePath)
+	start := time.Now()
+	err := client.Copy(d.transportIP(host), localFilePath, remoteFilePath)
+	d.audit.record(host, auditOpCopy, fmt.Sprintf("%s -> %s", localFilePath, remoteFilePath), start, err)
+	if err == nil {
+		if info, statErr := os.Stat(localFilePath); statErr == nil && !info.IsDir() {
+			metrics.AddCopiedBytes(host.String(), info.Size())
+		}
+	}
+	return err
 }
 
 func (d *SSHInfraDriver) CopyR(host net.IP, remoteFilePath, localFilePath string) error {
@@ -202,7 +318,10 @@ func (d *SSHInfraDriver) CopyR(host net.IP, remoteFilePath, localFilePath string
 		return fmt.Errorf("ip(%s) is not in cluster", host.String())
 	}
 	//client.CopyR take remoteFilePath as src file
-	return client.CopyR(host, localFilePath, remoteFilePath)
+	start := time.Now()
+	err := client.CopyR(d.transportIP(host), localFilePath, remoteFilePath)
+	d.audit.record(host, auditOpCopyRemote, fmt.Sprintf("%s -> %s", remoteFilePath, localFilePath), start, err)
+	return err
 }
 
 func (d *SSHInfraDriver) CmdAsync(host net.IP, env map[string]string, cmd ...string) error {
@@ -210,7 +329,10 @@ func (d *SSHInfraDriver) CmdAsync(host net.IP, env map[string]string, cmd ...str
 	if client == nil {
 		return fmt.Errorf("ip(%s) is not in cluster", host.String())
 	}
-	return client.CmdAsync(host, env, cmd...)
+	start := time.Now()
+	err := client.CmdAsync(d.transportIP(host), env, cmd...)
+	d.audit.record(host, auditOpCmd, strings.Join(cmd, "; "), start, err)
+	return err
 }
 
 func (d *SSHInfraDriver) Cmd(host net.IP, env map[string]string, cmd string) ([]byte, error) {
@@ -218,7 +340,10 @@ func (d *SSHInfraDriver) Cmd(host net.IP, env map[string]string, cmd string) ([]
 	if client == nil {
 		return nil, fmt.Errorf("ip(%s) is not in cluster", host.String())
 	}
-	return client.Cmd(host, env, cmd)
+	start := time.Now()
+	out, err := client.Cmd(d.transportIP(host), env, cmd)
+	d.audit.record(host, auditOpCmd, cmd, start, err)
+	return out, err
 }
 
 func (d *SSHInfraDriver) CmdToString(host net.IP, env map[string]string, cmd, spilt string) (string, error) {
@@ -226,7 +351,10 @@ func (d *SSHInfraDriver) CmdToString(host net.IP, env map[string]string, cmd, sp
 	if client == nil {
 		return "", fmt.Errorf("ip(%s) is not in cluster", host.String())
 	}
-	return client.CmdToString(host, env, cmd, spilt)
+	start := time.Now()
+	out, err := client.CmdToString(d.transportIP(host), env, cmd, spilt)
+	d.audit.record(host, auditOpCmd, cmd, start, err)
+	return out, err
 }
 
 func (d *SSHInfraDriver) IsFileExist(host net.IP, remoteFilePath string) (bool, error) {
@@ -234,7 +362,7 @@ func (d *SSHInfraDriver) IsFileExist(host net.IP, remoteFilePath string) (bool,
 	if client == nil {
 		return false, fmt.Errorf("ip(%s) is not in cluster", host.String())
 	}
-	return client.IsFileExist(host, remoteFilePath)
+	return client.IsFileExist(d.transportIP(host), remoteFilePath)
 }
 
 func (d *SSHInfraDriver) IsDirExist(host net.IP, remoteDirPath string) (bool, error) {
@@ -242,7 +370,7 @@ func (d *SSHInfraDriver) IsDirExist(host net.IP, remoteDirPath string) (bool, er
 	if client == nil {
 		return false, fmt.Errorf("ip(%s) is not in cluster", host.String())
 	}
-	return client.RemoteDirExist(host, remoteDirPath)
+	return client.RemoteDirExist(d.transportIP(host), remoteDirPath)
 }
 
 func (d *SSHInfraDriver) GetPlatform(host net.IP) (v1.Platform, error) {
@@ -250,7 +378,7 @@ func (d *SSHInfraDriver) GetPlatform(host net.IP) (v1.Platform, error) {
 	if client == nil {
 		return v1.Platform{}, fmt.Errorf("ip(%s) is not in cluster", host.String())
 	}
-	return client.GetPlatform(host)
+	return client.GetPlatform(d.transportIP(host))
 }
 
 func (d *SSHInfraDriver) Ping(host net.IP) error {
@@ -258,7 +386,7 @@ func (d *SSHInfraDriver) Ping(host net.IP) error {
 	if client == nil {
 		return fmt.Errorf("ip(%s) is not in cluster", host.String())
 	}
-	return client.Ping(host)
+	return client.Ping(d.transportIP(host))
 }
 
 func (d *SSHInfraDriver) SetHostName(host net.IP, hostName string) error {
@@ -357,9 +485,17 @@ func (d *SSHInfraDriver) GetClusterBasePath() string {
 
 func (d *SSHInfraDriver) Execute(hosts []net.IP, f func(host net.IP) error) error {
 	eg, _ := errgroup.WithContext(context.Background())
+	if d.sshConcurrency > 0 {
+		eg.SetLimit(d.sshConcurrency)
+	}
 	for _, ip := range hosts {
 		host := ip
 		eg.Go(func() error {
+			// do not start work on further hosts once the process
+			// context is canceled
+			if err := ssh.ProcessContext().Err(); err != nil {
+				return fmt.Errorf("on host [%s]: canceled: %v", host.String(), err)
+			}
 			err := f(host)
 			if err != nil {
 				return fmt.Errorf("on host [%s]: %v", host.String(), err)
@@ -16,7 +16,9 @@ package infradriver
 
 import (
 	"net"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/sealerio/sealer/common"
 	v1 "github.com/sealerio/sealer/types/api/v1"
@@ -92,14 +94,16 @@ func TestSSHInfraDriver_GetClusterInfo(t *testing.T) {
 	})
 
 	assert.Equal(t, map[string]string{
-		"HostIP":   "192.168.0.2",
-		"key1":     "value1",
-		"key2":     "value2, value3",
-		"etcd-dir": "/data/etcd",
+		"HostIP":    "192.168.0.2",
+		"HostRoles": "master",
+		"key1":      "value1",
+		"key2":      "value2, value3",
+		"etcd-dir":  "/data/etcd",
 	}, driver.GetHostEnv(net.IPv4(192, 168, 0, 2)))
 
 	assert.Equal(t, driver.GetHostEnv(net.IPv4(192, 168, 0, 3)), map[string]string{
 		"HostIP":            "192.168.0.3",
+		"HostRoles":         "node,db",
 		"key1":              "value1",
 		"key2":              "value2, value3",
 		"test_node_env_key": "test_node_env_value",
@@ -150,3 +154,129 @@ func TestCheckAllHostsSameFamily(t *testing.T) {
 		})
 	}
 }
+
+func TestExecuteConcurrencyLimit(t *testing.T) {
+	hosts := []net.IP{
+		net.IPv4(192, 168, 0, 2),
+		net.IPv4(192, 168, 0, 3),
+		net.IPv4(192, 168, 0, 4),
+		net.IPv4(192, 168, 0, 5),
+	}
+	driver := &SSHInfraDriver{sshConcurrency: 2}
+
+	var (
+		mux        sync.Mutex
+		inFlight   int
+		maxRunning int
+	)
+	err := driver.Execute(hosts, func(host net.IP) error {
+		mux.Lock()
+		inFlight++
+		if inFlight > maxRunning {
+			maxRunning = inFlight
+		}
+		mux.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mux.Lock()
+		inFlight--
+		mux.Unlock()
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.LessOrEqual(t, maxRunning, 2)
+}
+
+func TestInjectProxyEnv(t *testing.T) {
+	hosts := []net.IP{net.IPv4(192, 168, 0, 2), net.IPv4(192, 168, 0, 3)}
+	cluster := &v2.Cluster{
+		Spec: v2.ClusterSpec{
+			Proxy: v2.Proxy{
+				HTTPProxy:  "http://proxy.corp:3128",
+				HTTPSProxy: "http://proxy.corp:3128",
+				NoProxy:    "internal.corp",
+			},
+			Registry: v2.Registry{
+				LocalRegistry: &v2.LocalRegistry{
+					RegistryConfig: v2.RegistryConfig{Domain: "sea.hub"},
+				},
+			},
+		},
+	}
+
+	clusterEnv := map[string]string{"HTTP_PROXY": "http://keep-me:8080"}
+	injectProxyEnv(clusterEnv, cluster, hosts)
+
+	// an explicit env entry wins over the proxy field
+	assert.Equal(t, "http://keep-me:8080", clusterEnv["HTTP_PROXY"])
+	assert.Equal(t, "http://proxy.corp:3128", clusterEnv["HTTPS_PROXY"])
+	assert.Equal(t, clusterEnv["HTTPS_PROXY"], clusterEnv["https_proxy"])
+
+	noProxy := clusterEnv["NO_PROXY"]
+	for _, expected := range []string{"localhost", "127.0.0.1", "internal.corp", "192.168.0.2", "192.168.0.3", "sea.hub"} {
+		assert.Contains(t, noProxy, expected)
+	}
+}
+
+func TestInjectProxyEnvNoProxyConfigured(t *testing.T) {
+	clusterEnv := map[string]string{"key1": "value1"}
+	injectProxyEnv(clusterEnv, &v2.Cluster{}, []net.IP{net.IPv4(192, 168, 0, 2)})
+	assert.Equal(t, map[string]string{"key1": "value1"}, clusterEnv)
+}
+
+func TestTransportIP(t *testing.T) {
+	cluster := &v2.Cluster{
+		Spec: v2.ClusterSpec{
+			SSH: v1.SSH{User: "root", Passwd: "test123", Port: "22"},
+			Hosts: []v2.Host{
+				{
+					IPS:         []net.IP{net.IPv4(10, 0, 0, 2), net.IPv4(10, 0, 0, 3)},
+					ExternalIPS: []net.IP{net.IPv4(203, 0, 113, 2), net.IPv4(203, 0, 113, 3)},
+					Roles:       []string{"master"},
+				},
+				{
+					IPS:   []net.IP{net.IPv4(10, 0, 0, 4)},
+					Roles: []string{"node"},
+				},
+			},
+		},
+	}
+	cluster.APIVersion = "sealer.io/v2"
+	cluster.Kind = "Cluster"
+	cluster.Name = "my-cluster"
+
+	driver, err := NewInfraDriver(cluster)
+	assert.NoError(t, err)
+	d := driver.(*SSHInfraDriver)
+
+	// cluster identity stays the internal addresses
+	assert.Equal(t, []net.IP{net.IPv4(10, 0, 0, 2), net.IPv4(10, 0, 0, 3), net.IPv4(10, 0, 0, 4)}, d.GetHostIPList())
+
+	// hosts behind NAT are dialed over the external address
+	assert.Equal(t, net.IPv4(203, 0, 113, 2), d.transportIP(net.IPv4(10, 0, 0, 2)))
+	assert.Equal(t, net.IPv4(203, 0, 113, 3), d.transportIP(net.IPv4(10, 0, 0, 3)))
+	// hosts without one keep their own address
+	assert.Equal(t, net.IPv4(10, 0, 0, 4), d.transportIP(net.IPv4(10, 0, 0, 4)))
+}
+
+func TestTransportIPMismatchedExternalIPS(t *testing.T) {
+	cluster := &v2.Cluster{
+		Spec: v2.ClusterSpec{
+			SSH: v1.SSH{User: "root", Passwd: "test123", Port: "22"},
+			Hosts: []v2.Host{
+				{
+					IPS:         []net.IP{net.IPv4(10, 0, 0, 2), net.IPv4(10, 0, 0, 3)},
+					ExternalIPS: []net.IP{net.IPv4(203, 0, 113, 2)},
+					Roles:       []string{"master"},
+				},
+			},
+		},
+	}
+	cluster.APIVersion = "sealer.io/v2"
+	cluster.Kind = "Cluster"
+	cluster.Name = "my-cluster"
+
+	_, err := NewInfraDriver(cluster)
+	assert.Error(t, err)
+}
@@ -15,8 +15,13 @@
 package ipvs
 
 import (
+	"bytes"
 	"fmt"
+	"os"
 	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -27,8 +32,91 @@ import (
 
 const (
 	LvsCareCommand = "/usr/bin/lvscare"
+
+	// lvscareImageFile is the rootfs file a ClusterImage may ship to pin
+	// the lvscare image it was built and scanned with.
+	lvscareImageFile = "lvscare-image"
+
+	// lvscareTemplateSuffix is appended to the static pod name to locate a
+	// custom manifest template under rootfs/etc.
+	lvscareTemplateSuffix = ".yaml.tmpl"
 )
 
+// GetLvscareImage resolves the lvscare image reference for the generated
+// static pods. The LvscareImage cluster env wins, then an image the
+// ClusterImage records under etc/lvscare-image in its rootfs, and the
+// builtin default last. References without a registry host are served from
+// the given registry.
+func GetLvscareImage(rootfs string, clusterEnv map[string]string, registryURL string) string {
+	image := common.LvsCareRepoAndTag
+
+	if rootfs != "" {
+		if data, err := os.ReadFile(filepath.Clean(filepath.Join(rootfs, "etc", lvscareImageFile))); err == nil {
+			if v := strings.TrimSpace(string(data)); v != "" {
+				image = v
+			}
+		}
+	}
+
+	if v := clusterEnv[common.EnvLvscareImage]; v != "" {
+		image = v
+	}
+
+	if hasRegistryHost(image) {
+		return image
+	}
+	return path.Join(registryURL, image)
+}
+
+// hasRegistryHost reports whether the image reference already names a
+// registry, i.e. its first path segment looks like a host.
+func hasRegistryHost(image string) bool {
+	first := strings.Split(image, "/")[0]
+	return strings.ContainsAny(first, ".:") || first == "localhost"
+}
+
+// LvscareTemplateData is the data a custom lvscare static pod template is
+// rendered with.
+type LvscareTemplateData struct {
+	PodName         string
+	Image           string
+	VirtualEndpoint string
+	RealEndpoints   []string
+	HealthPath      string
+	HealthSchem     string
+}
+
+// LvsStaticPodYamlWithTemplate behaves like LvsStaticPodYaml, but lets the
+// ClusterImage override the generated manifest with a template shipped at
+// rootfs/etc/<podName>.yaml.tmpl, rendered with LvscareTemplateData.
+func LvsStaticPodYamlWithTemplate(rootfs, podName, virtualEndpoint string, realEndpoints []string, image,
+	healthPath, healthSchem string) (string, error) {
+	tmplPath := filepath.Join(rootfs, "etc", podName+lvscareTemplateSuffix)
+	data, err := os.ReadFile(filepath.Clean(tmplPath))
+	if err != nil {
+		return LvsStaticPodYaml(podName, virtualEndpoint, realEndpoints, image, healthPath, healthSchem)
+	}
+
+	t, err := template.New(podName).Parse(string(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse lvscare static pod template %s: %v", tmplPath, err)
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, LvscareTemplateData{
+		PodName:         podName,
+		Image:           image,
+		VirtualEndpoint: virtualEndpoint,
+		RealEndpoints:   realEndpoints,
+		HealthPath:      healthPath,
+		HealthSchem:     healthSchem,
+	}); err != nil {
+		return "", fmt.Errorf("failed to render lvscare static pod template %s: %v", tmplPath, err)
+	}
+
+	return buf.String(), nil
+}
+
 func GetCreateLvscareStaticPodCmd(content, fileName string) string {
 	return fmt.Sprintf("mkdir -p %s && echo \"%s\" > %s",
 		common.StaticPodDir,
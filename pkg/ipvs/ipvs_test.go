@@ -15,7 +15,11 @@
 package ipvs
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
+
+	"github.com/sealerio/sealer/common"
 )
 
 var want = []string{
@@ -200,3 +204,74 @@ func TestLvsStaticPodYaml(t *testing.T) {
 		})
 	}
 }
+
+func TestGetLvscareImage(t *testing.T) {
+	registryURL := "sea.hub:5000"
+
+	// builtin default, served from the local registry
+	got := GetLvscareImage("", nil, registryURL)
+	if want := "sea.hub:5000/" + common.LvsCareRepoAndTag; got != want {
+		t.Errorf("GetLvscareImage() = %v, want %v", got, want)
+	}
+
+	// the ClusterImage rootfs pins its own image
+	rootfs := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(rootfs, "etc"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(rootfs, "etc", lvscareImageFile), []byte("myorg/lvscare:v2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got = GetLvscareImage(rootfs, nil, registryURL)
+	if want := "sea.hub:5000/myorg/lvscare:v2"; got != want {
+		t.Errorf("GetLvscareImage() = %v, want %v", got, want)
+	}
+
+	// the cluster env wins over the rootfs file, and a reference naming a
+	// registry is used verbatim
+	env := map[string]string{common.EnvLvscareImage: "harbor.corp.com/infra/lvscare:v3"}
+	got = GetLvscareImage(rootfs, env, registryURL)
+	if want := "harbor.corp.com/infra/lvscare:v3"; got != want {
+		t.Errorf("GetLvscareImage() = %v, want %v", got, want)
+	}
+}
+
+func TestLvsStaticPodYamlWithTemplate(t *testing.T) {
+	rootfs := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(rootfs, "etc"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// no template shipped: fall back to the generated manifest
+	got, err := LvsStaticPodYamlWithTemplate(rootfs, "kube-lvscare", "10.107.2.1:6443",
+		[]string{"172.16.228.157:6443"}, "fdfadf", "/healthz", "https")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want[1] {
+		t.Errorf("LvsStaticPodYamlWithTemplate() = %v, want %v", got, want[1])
+	}
+
+	// a shipped template wins over the generated manifest
+	tmpl := "pod: {{ .PodName }} image: {{ .Image }} vs: {{ .VirtualEndpoint }}"
+	if err := os.WriteFile(filepath.Join(rootfs, "etc", "kube-lvscare"+lvscareTemplateSuffix), []byte(tmpl), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err = LvsStaticPodYamlWithTemplate(rootfs, "kube-lvscare", "10.107.2.1:6443",
+		[]string{"172.16.228.157:6443"}, "fdfadf", "/healthz", "https")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "pod: kube-lvscare image: fdfadf vs: 10.107.2.1:6443"; got != want {
+		t.Errorf("LvsStaticPodYamlWithTemplate() = %v, want %v", got, want)
+	}
+
+	// a broken template is an error, not a silent fallback
+	if err := os.WriteFile(filepath.Join(rootfs, "etc", "kube-lvscare"+lvscareTemplateSuffix), []byte("{{ .Missing"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = LvsStaticPodYamlWithTemplate(rootfs, "kube-lvscare", "10.107.2.1:6443",
+		[]string{"172.16.228.157:6443"}, "fdfadf", "/healthz", "https"); err == nil {
+		t.Errorf("LvsStaticPodYamlWithTemplate() expected error for broken template")
+	}
+}
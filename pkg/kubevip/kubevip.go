@@ -0,0 +1,200 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package kubevip generates the kube-vip static pod manifest sealer places
+// on the master nodes when the cluster serves its apiserver VIP through
+// kube-vip instead of the lvscare ipvs proxy. In ARP mode the VIP is
+// announced on the local network, in BGP mode it is advertised to the
+// configured peers; kube-vip can additionally serve LoadBalancer Services.
+package kubevip
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/sealerio/sealer/common"
+)
+
+const (
+	// StaticPodName is the name of the generated static pod and its
+	// manifest file below the static pod dir.
+	StaticPodName = "kube-vip"
+
+	// DefaultRepoAndTag is the kube-vip image served from the local
+	// registry unless the KubeVIPImage env overrides it.
+	DefaultRepoAndTag = "sealerio/kube-vip:v0.6.4"
+
+	ModeARP = "arp"
+	ModeBGP = "bgp"
+)
+
+// Config carries everything the kube-vip static pod manifest is generated
+// from; NewConfigFromEnv fills it from the cluster env.
+type Config struct {
+	VIP            string
+	Interface      string
+	Mode           string
+	Image          string
+	EnableServices bool
+	BGPRouterID    string
+	BGPAS          string
+	BGPPeers       string
+}
+
+// NewConfigFromEnv builds a kube-vip Config from the cluster env, with vip
+// as the address to serve and registryURL as the registry image references
+// without a host are pulled from.
+func NewConfigFromEnv(clusterEnv map[string]string, vip, registryURL string) (*Config, error) {
+	c := &Config{
+		VIP:            vip,
+		Interface:      clusterEnv[common.EnvKubeVIPInterface],
+		Mode:           ModeARP,
+		Image:          DefaultRepoAndTag,
+		EnableServices: clusterEnv[common.EnvKubeVIPEnableServices] == "true",
+		BGPRouterID:    clusterEnv[common.EnvKubeVIPBGPRouterID],
+		BGPAS:          clusterEnv[common.EnvKubeVIPBGPAS],
+		BGPPeers:       clusterEnv[common.EnvKubeVIPBGPPeers],
+	}
+
+	// the NIC the cluster network lives on is a sensible ARP default
+	if c.Interface == "" {
+		c.Interface = clusterEnv[common.EnvNodeInterface]
+	}
+
+	if mode := clusterEnv[common.EnvKubeVIPMode]; mode != "" {
+		if mode != ModeARP && mode != ModeBGP {
+			return nil, fmt.Errorf("invalid env %s(%s): expect %q or %q", common.EnvKubeVIPMode, mode, ModeARP, ModeBGP)
+		}
+		c.Mode = mode
+	}
+
+	if c.Mode == ModeBGP && c.BGPPeers == "" {
+		return nil, fmt.Errorf("env %s is required when %s is %q", common.EnvKubeVIPBGPPeers, common.EnvKubeVIPMode, ModeBGP)
+	}
+
+	if image := clusterEnv[common.EnvKubeVIPImage]; image != "" {
+		c.Image = image
+	}
+	if !hasRegistryHost(c.Image) {
+		c.Image = path.Join(registryURL, c.Image)
+	}
+
+	return c, nil
+}
+
+// hasRegistryHost reports whether the image reference already names a
+// registry, i.e. its first path segment looks like a host.
+func hasRegistryHost(image string) bool {
+	first := strings.Split(image, "/")[0]
+	return strings.ContainsAny(first, ".:") || first == "localhost"
+}
+
+// StaticPodYaml renders the kube-vip static pod manifest for one master.
+func StaticPodYaml(c *Config) (string, error) {
+	if c.VIP == "" || c.Image == "" {
+		return "", fmt.Errorf("invalid args to create kube-vip static pod")
+	}
+
+	env := []v1.EnvVar{
+		{Name: "vip_address", Value: c.VIP},
+		{Name: "port", Value: "6443"},
+		{Name: "cp_enable", Value: "true"},
+		{Name: "cp_namespace", Value: metav1.NamespaceSystem},
+		{Name: "svc_enable", Value: fmt.Sprintf("%t", c.EnableServices)},
+	}
+
+	switch c.Mode {
+	case ModeBGP:
+		env = append(env,
+			v1.EnvVar{Name: "bgp_enable", Value: "true"},
+			v1.EnvVar{Name: "bgp_routerid", Value: c.BGPRouterID},
+			v1.EnvVar{Name: "bgp_as", Value: c.BGPAS},
+			v1.EnvVar{Name: "bgp_peers", Value: c.BGPPeers},
+		)
+	default:
+		env = append(env, v1.EnvVar{Name: "vip_arp", Value: "true"})
+		if c.Interface != "" {
+			env = append(env, v1.EnvVar{Name: "vip_interface", Value: c.Interface})
+		}
+	}
+
+	hostPathType := v1.HostPathFileOrCreate
+	kubeconfig := "kubeconfig"
+	pod := v1.Pod{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Pod",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      StaticPodName,
+			Namespace: metav1.NamespaceSystem,
+		},
+		Spec: v1.PodSpec{
+			HostNetwork: true,
+			Containers: []v1.Container{
+				{
+					Name:            "main",
+					Image:           c.Image,
+					Args:            []string{"manager"},
+					Env:             env,
+					ImagePullPolicy: v1.PullIfNotPresent,
+					SecurityContext: &v1.SecurityContext{
+						Capabilities: &v1.Capabilities{
+							Add: []v1.Capability{"NET_ADMIN", "NET_RAW"},
+						},
+					},
+					VolumeMounts: []v1.VolumeMount{
+						{Name: kubeconfig, MountPath: "/etc/kubernetes/admin.conf"},
+					},
+				},
+			},
+			Volumes: []v1.Volume{
+				{Name: kubeconfig, VolumeSource: v1.VolumeSource{
+					HostPath: &v1.HostPathVolumeSource{
+						Path: "/etc/kubernetes/admin.conf",
+						Type: &hostPathType,
+					},
+				}},
+			},
+		},
+	}
+
+	yml, err := yaml.Marshal(pod)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode kube-vip static pod yaml: %s", err)
+	}
+
+	return string(yml), nil
+}
+
+// GetCreateStaticPodCmd returns the shell command writing the manifest
+// below the static pod dir of a master.
+func GetCreateStaticPodCmd(content string) string {
+	return fmt.Sprintf("mkdir -p %s && echo \"%s\" > %s",
+		common.StaticPodDir,
+		content,
+		path.Join(common.StaticPodDir, StaticPodName+".yaml"),
+	)
+}
+
+// GetRemoveStaticPodCmd returns the shell command removing the manifest
+// again when the cluster or a master is cleaned.
+func GetRemoveStaticPodCmd() string {
+	return fmt.Sprintf("rm -f %s", path.Join(common.StaticPodDir, StaticPodName+".yaml"))
+}
@@ -0,0 +1,96 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubevip
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sealerio/sealer/common"
+)
+
+func TestNewConfigFromEnv(t *testing.T) {
+	registryURL := "sea.hub:5000"
+
+	// defaults: ARP mode, builtin image from the local registry
+	c, err := NewConfigFromEnv(map[string]string{}, "192.168.0.100", registryURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Mode != ModeARP {
+		t.Errorf("Mode = %v, want %v", c.Mode, ModeARP)
+	}
+	if want := "sea.hub:5000/" + DefaultRepoAndTag; c.Image != want {
+		t.Errorf("Image = %v, want %v", c.Image, want)
+	}
+
+	// NodeInterface is the ARP interface fallback
+	c, err = NewConfigFromEnv(map[string]string{common.EnvNodeInterface: "eth1"}, "192.168.0.100", registryURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Interface != "eth1" {
+		t.Errorf("Interface = %v, want eth1", c.Interface)
+	}
+
+	// an image naming a registry is used verbatim
+	c, err = NewConfigFromEnv(map[string]string{common.EnvKubeVIPImage: "harbor.corp.com/infra/kube-vip:v1"}, "192.168.0.100", registryURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "harbor.corp.com/infra/kube-vip:v1"; c.Image != want {
+		t.Errorf("Image = %v, want %v", c.Image, want)
+	}
+
+	// bgp mode requires peers
+	if _, err = NewConfigFromEnv(map[string]string{common.EnvKubeVIPMode: ModeBGP}, "192.168.0.100", registryURL); err == nil {
+		t.Errorf("NewConfigFromEnv() expected error for bgp mode without peers")
+	}
+
+	// unknown mode is rejected
+	if _, err = NewConfigFromEnv(map[string]string{common.EnvKubeVIPMode: "ospf"}, "192.168.0.100", registryURL); err == nil {
+		t.Errorf("NewConfigFromEnv() expected error for unknown mode")
+	}
+}
+
+func TestStaticPodYaml(t *testing.T) {
+	arp, err := StaticPodYaml(&Config{VIP: "192.168.0.100", Interface: "eth0", Mode: ModeARP, Image: "img"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"name: kube-vip", "vip_arp", "vip_interface", "eth0", "192.168.0.100", "NET_ADMIN"} {
+		if !strings.Contains(arp, expected) {
+			t.Errorf("arp manifest missing %q:\n%s", expected, arp)
+		}
+	}
+
+	bgp, err := StaticPodYaml(&Config{VIP: "192.168.0.100", Mode: ModeBGP, Image: "img",
+		BGPRouterID: "192.168.0.2", BGPAS: "64512", BGPPeers: "192.168.0.254:64512"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"bgp_enable", "bgp_peers", "192.168.0.254:64512"} {
+		if !strings.Contains(bgp, expected) {
+			t.Errorf("bgp manifest missing %q:\n%s", expected, bgp)
+		}
+	}
+	if strings.Contains(bgp, "vip_arp") {
+		t.Errorf("bgp manifest should not enable arp:\n%s", bgp)
+	}
+
+	if _, err := StaticPodYaml(&Config{Image: "img"}); err == nil {
+		t.Errorf("StaticPodYaml() expected error without a VIP")
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"net"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// context holds the fields every log entry of the current operation gets
+// tagged with. Cluster and phase are process wide: one sealer invocation
+// works on one cluster and walks its phases sequentially.
+var (
+	contextMu      sync.RWMutex
+	currentCluster string
+	currentPhase   string
+)
+
+// SetCluster tags all subsequent log entries with the given cluster name.
+func SetCluster(name string) {
+	contextMu.Lock()
+	defer contextMu.Unlock()
+	currentCluster = name
+}
+
+// SetPhase tags all subsequent log entries with the given phase; pass ""
+// when the phase is over.
+func SetPhase(phase string) {
+	contextMu.Lock()
+	defer contextMu.Unlock()
+	currentPhase = phase
+}
+
+// WithHost returns an entry tagged with the host IP, for logs about one
+// remote machine. Cluster and phase are added by the context hook.
+func WithHost(host net.IP) *logrus.Entry {
+	return logrus.WithField("host", host.String())
+}
+
+// ContextHook stamps the current cluster and phase onto every entry, so
+// grepping the JSON log by cluster or phase works without every call site
+// threading those fields through.
+type ContextHook struct{}
+
+func (h *ContextHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *ContextHook) Fire(entry *logrus.Entry) error {
+	contextMu.RLock()
+	cluster, phase := currentCluster, currentPhase
+	contextMu.RUnlock()
+
+	if cluster != "" {
+		if _, ok := entry.Data["cluster"]; !ok {
+			entry.Data["cluster"] = cluster
+		}
+	}
+	if phase != "" {
+		if _, ok := entry.Data["phase"]; !ok {
+			entry.Data["phase"] = phase
+		}
+	}
+	return nil
+}
@@ -47,8 +47,9 @@ func NewFileHook(filePath string) (logrus.Hook, error) {
 		logrus.ErrorLevel: writer,
 		logrus.FatalLevel: writer,
 		logrus.PanicLevel: writer,
-	}, &logrus.TextFormatter{
-		DisableColors: true,
+	}, &logrus.JSONFormatter{
+		// structured logs on disk: one JSON object per line, so the
+		// rotated files are greppable and machine readable
 		CallerPrettyfier: func(frame *runtime.Frame) (function string, file string) {
 			return "", fmt.Sprintf("%s:%d", filepath.Base(frame.File), frame.Line)
 		},
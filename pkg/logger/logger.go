@@ -22,6 +22,9 @@ import (
 type LogOptions struct {
 	// sealer log file path, default log directory is `/var/lib/sealer/log`
 	OutputPath string
+	// Level is the log level name (trace/debug/info/warn/error); when set
+	// it wins over Verbose.
+	Level string
 	// Verbose: sealer log level,if it is ture will set debug log mode.
 	Verbose bool
 	// DisableColor if true will disable outputting colors.
@@ -33,14 +36,24 @@ type LogOptions struct {
 }
 
 func Init(options LogOptions) error {
-	if options.Verbose {
+	switch {
+	case options.Level != "":
+		level, err := logrus.ParseLevel(options.Level)
+		if err != nil {
+			return errors.Errorf("invalid log level %q: %v", options.Level, err)
+		}
+		logrus.SetLevel(level)
+	case options.Verbose:
 		logrus.SetLevel(logrus.DebugLevel)
-	} else {
+	default:
 		logrus.SetLevel(logrus.InfoLevel)
 	}
 
 	logrus.SetReportCaller(true)
 
+	// tag every entry with the cluster and phase being worked on
+	logrus.AddHook(&ContextHook{})
+
 	logrus.SetFormatter(&Formatter{
 		DisableColor: options.DisableColor,
 	})
@@ -0,0 +1,115 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metrics exposes operational metrics of long running sealer
+// operations through an optional local Prometheus endpoint, so platform
+// teams can watch fleet provisioning jobs. The endpoint only starts when
+// SEALER_METRICS_ADDR is set (e.g. ":9100"); without it every observation
+// is a cheap in-memory update and nothing listens anywhere.
+package metrics
+
+import (
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sirupsen/logrus"
+
+	"github.com/sealerio/sealer/common"
+)
+
+var (
+	registry = prometheus.NewRegistry()
+
+	phaseDuration = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "sealer_phase_duration_seconds",
+		Help: "Duration of the last run of each apply phase.",
+	}, []string{"cluster", "phase"})
+
+	remoteOpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "sealer_remote_op_duration_seconds",
+		Help:    "Duration of remote commands and file copies by operation and status.",
+		Buckets: prometheus.ExponentialBuckets(0.05, 4, 8),
+	}, []string{"op", "status"})
+
+	remoteOpFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "sealer_remote_op_failures_total",
+		Help: "Failed remote commands and file copies by host and operation.",
+	}, []string{"host", "op"})
+
+	copyBytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "sealer_copy_bytes_total",
+		Help: "Bytes copied to each host, for per-host copy throughput.",
+	}, []string{"host"})
+
+	serveOnce sync.Once
+)
+
+func init() {
+	registry.MustRegister(phaseDuration, remoteOpDuration, remoteOpFailures, copyBytes)
+}
+
+// ServeIfConfigured starts the metrics endpoint on the address of
+// SEALER_METRICS_ADDR. It is safe to call from several places, only the
+// first call starts the listener, and a misconfigured address is reported
+// without failing the operation being monitored.
+func ServeIfConfigured() {
+	addr := os.Getenv(common.EnvMetricsAddr)
+	if addr == "" {
+		return
+	}
+
+	serveOnce.Do(func() {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+		server := &http.Server{
+			Addr:              addr,
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		logrus.Infof("serving sealer metrics on %s/metrics", addr)
+		go func() {
+			if err := server.ListenAndServe(); err != nil {
+				logrus.Warnf("metrics endpoint on %s stopped: %v", addr, err)
+			}
+		}()
+	})
+}
+
+// ObservePhase records the wall time of one apply phase.
+func ObservePhase(cluster, phase string, start time.Time) {
+	phaseDuration.WithLabelValues(cluster, phase).Set(time.Since(start).Seconds())
+}
+
+// ObserveRemoteOp records the duration and outcome of one remote command or
+// file copy.
+func ObserveRemoteOp(host, op string, start time.Time, err error) {
+	status := "ok"
+	if err != nil {
+		status = "failed"
+		remoteOpFailures.WithLabelValues(host, op).Inc()
+	}
+	remoteOpDuration.WithLabelValues(op, status).Observe(time.Since(start).Seconds())
+}
+
+// AddCopiedBytes accounts the payload size of a finished copy to a host.
+func AddCopiedBytes(host string, bytes int64) {
+	if bytes > 0 {
+		copyBytes.WithLabelValues(host).Add(float64(bytes))
+	}
+}
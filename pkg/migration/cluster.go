@@ -0,0 +1,85 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package migration converts cluster state written by older sealer versions
+// into the current format: v1 Cluster specs become v2 Clusterfiles and
+// pre-0.9 work dir layouts are located so they can be taken over without
+// recreating the cluster.
+package migration
+
+import (
+	"fmt"
+
+	"github.com/sealerio/sealer/common"
+	"github.com/sealerio/sealer/types/api/constants"
+	v1 "github.com/sealerio/sealer/types/api/v1"
+	v2 "github.com/sealerio/sealer/types/api/v2"
+)
+
+// legacyClusterAPIVersions are the Cluster apiVersions written by older
+// sealer releases, all of them carry the v1 cluster spec.
+var legacyClusterAPIVersions = map[string]struct{}{
+	v1.GroupVersion.String():     {},
+	"sealer.aliyun.com/v1alpha1": {},
+	"zlink.aliyun.com/v1alpha1":  {},
+}
+
+// IsLegacyClusterAPIVersion reports whether the apiVersion belongs to a v1
+// cluster spec which needs conversion before it can be used.
+func IsLegacyClusterAPIVersion(apiVersion string) bool {
+	_, ok := legacyClusterAPIVersions[apiVersion]
+	return ok
+}
+
+// ConvertV1Cluster converts a v1 cluster spec into the v2 Cluster used by
+// the current sealer. Network settings and cert SANs move into the cluster
+// env, where the v2 rootfs templates expect them.
+func ConvertV1Cluster(old *v1.Cluster) (*v2.Cluster, error) {
+	if len(old.Spec.Masters.IPList) == 0 {
+		return nil, fmt.Errorf("cannot convert cluster %s: no master IP list found, "+
+			"clusters provisioned by count only are not supported", old.Name)
+	}
+
+	env := append([]string{}, old.Spec.Env...)
+	if old.Spec.Network.PodCIDR != "" {
+		env = append(env, fmt.Sprintf("PodCIDR=%s", old.Spec.Network.PodCIDR))
+	}
+	if old.Spec.Network.SvcCIDR != "" {
+		env = append(env, fmt.Sprintf("SvcCIDR=%s", old.Spec.Network.SvcCIDR))
+	}
+	for _, san := range old.Spec.CertSANS {
+		env = append(env, fmt.Sprintf("CertSANS=%s", san))
+	}
+
+	hosts := []v2.Host{
+		{IPS: old.Spec.Masters.IPList, Roles: []string{common.MASTER}},
+	}
+	if len(old.Spec.Nodes.IPList) > 0 {
+		hosts = append(hosts, v2.Host{IPS: old.Spec.Nodes.IPList, Roles: []string{common.NODE}})
+	}
+
+	cluster := &v2.Cluster{
+		Spec: v2.ClusterSpec{
+			Image: old.Spec.Image,
+			Env:   env,
+			SSH:   old.Spec.SSH,
+			Hosts: hosts,
+		},
+	}
+	cluster.APIVersion = v2.GroupVersion.String()
+	cluster.Kind = constants.ClusterKind
+	cluster.Name = old.Name
+
+	return cluster, nil
+}
@@ -0,0 +1,79 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migration
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sealerio/sealer/common"
+	v1 "github.com/sealerio/sealer/types/api/v1"
+)
+
+func TestIsLegacyClusterAPIVersion(t *testing.T) {
+	assert.True(t, IsLegacyClusterAPIVersion("sealer.io/v1"))
+	assert.True(t, IsLegacyClusterAPIVersion("sealer.aliyun.com/v1alpha1"))
+	assert.True(t, IsLegacyClusterAPIVersion("zlink.aliyun.com/v1alpha1"))
+	assert.False(t, IsLegacyClusterAPIVersion("sealer.io/v2"))
+	assert.False(t, IsLegacyClusterAPIVersion(""))
+}
+
+func TestConvertV1Cluster(t *testing.T) {
+	masters := []net.IP{net.IPv4(192, 168, 0, 2), net.IPv4(192, 168, 0, 3)}
+	nodes := []net.IP{net.IPv4(192, 168, 0, 4)}
+
+	old := &v1.Cluster{}
+	old.Name = "my-cluster"
+	old.Spec.Image = "docker.io/sealerio/kubernetes:v1.19.8"
+	old.Spec.Env = []string{"key=value"}
+	old.Spec.SSH = v1.SSH{User: common.ROOT, Passwd: "Sealer123", Port: "22"}
+	old.Spec.Network.PodCIDR = "100.64.0.0/10"
+	old.Spec.Network.SvcCIDR = "10.96.0.0/16"
+	old.Spec.CertSANS = []string{"sealer.cloud"}
+	old.Spec.Masters.IPList = masters
+	old.Spec.Nodes.IPList = nodes
+
+	cluster, err := ConvertV1Cluster(old)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "sealer.io/v2", cluster.APIVersion)
+	assert.Equal(t, "Cluster", cluster.Kind)
+	assert.Equal(t, "my-cluster", cluster.Name)
+	assert.Equal(t, old.Spec.Image, cluster.Spec.Image)
+	assert.Equal(t, old.Spec.SSH, cluster.Spec.SSH)
+	assert.Equal(t, []string{
+		"key=value",
+		"PodCIDR=100.64.0.0/10",
+		"SvcCIDR=10.96.0.0/16",
+		"CertSANS=sealer.cloud",
+	}, cluster.Spec.Env)
+
+	assert.Len(t, cluster.Spec.Hosts, 2)
+	assert.Equal(t, masters, cluster.Spec.Hosts[0].IPS)
+	assert.Equal(t, []string{common.MASTER}, cluster.Spec.Hosts[0].Roles)
+	assert.Equal(t, nodes, cluster.Spec.Hosts[1].IPS)
+	assert.Equal(t, []string{common.NODE}, cluster.Spec.Hosts[1].Roles)
+}
+
+func TestConvertV1ClusterWithoutMasterIPList(t *testing.T) {
+	old := &v1.Cluster{}
+	old.Name = "cloud-cluster"
+	old.Spec.Masters.Count = "3"
+
+	_, err := ConvertV1Cluster(old)
+	assert.Error(t, err)
+}
@@ -0,0 +1,69 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migration
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	osi "github.com/sealerio/sealer/utils/os"
+)
+
+// LegacyClusterfile is one Clusterfile found in the pre-0.9 work dir
+// layout, where each cluster kept its state under <workDir>/<name>/.
+type LegacyClusterfile struct {
+	ClusterName string
+	Path        string
+}
+
+// FindLegacyClusterfiles scans the sealer work dir for the per-cluster
+// subdirs used before 0.9 and returns every Clusterfile found there. The
+// current layout keeps the Clusterfile directly under the work dir, so
+// anything in a subdir is legacy state.
+func FindLegacyClusterfiles(workDir string) ([]LegacyClusterfile, error) {
+	entries, err := os.ReadDir(workDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read sealer work dir %s: %v", workDir, err)
+	}
+
+	var found []LegacyClusterfile
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		// the clusters dir holds the current cluster contexts, everything
+		// in there is already in the new layout.
+		if entry.Name() == "clusters" {
+			continue
+		}
+
+		path := filepath.Join(workDir, entry.Name(), "Clusterfile")
+		if !osi.IsFileExist(path) {
+			continue
+		}
+
+		found = append(found, LegacyClusterfile{
+			ClusterName: entry.Name(),
+			Path:        path,
+		})
+	}
+
+	return found, nil
+}
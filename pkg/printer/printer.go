@@ -0,0 +1,120 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package printer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/sealerio/sealer/common"
+)
+
+// Format is the wire format used to encode command results.
+type Format string
+
+const (
+	// FormatTable is the default human-readable output.
+	FormatTable Format = "table"
+	// FormatJSON encodes command results as indented JSON.
+	FormatJSON Format = "json"
+	// FormatYAML encodes command results as YAML.
+	FormatYAML Format = "yaml"
+)
+
+// SupportedFormats lists the values accepted by the global --output flag.
+var SupportedFormats = []Format{FormatTable, FormatJSON, FormatYAML}
+
+var defaultFormat = FormatTable
+
+// ParseFormat converts the raw flag value to a Format. An empty value
+// falls back to the human-readable table output.
+func ParseFormat(value string) (Format, error) {
+	switch Format(value) {
+	case "", FormatTable:
+		return FormatTable, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	case FormatYAML:
+		return FormatYAML, nil
+	default:
+		return "", fmt.Errorf("unsupported output format %q, the possible values can be %v", value, SupportedFormats)
+	}
+}
+
+// SetOutputFormat records the format chosen via the global --output flag,
+// making it available to every subcommand through Default().
+func SetOutputFormat(value string) error {
+	format, err := ParseFormat(value)
+	if err != nil {
+		return err
+	}
+	defaultFormat = format
+	return nil
+}
+
+// Printer encodes command results to a writer in the configured format.
+type Printer struct {
+	format Format
+	out    io.Writer
+}
+
+// New returns a Printer writing to out in the given format.
+func New(format Format, out io.Writer) *Printer {
+	return &Printer{format: format, out: out}
+}
+
+// Default returns a Printer honoring the global --output flag and
+// writing to standard output.
+func Default() *Printer {
+	return New(defaultFormat, common.StdOut)
+}
+
+// Format returns the wire format this Printer encodes with.
+func (p *Printer) Format() Format {
+	return p.format
+}
+
+// IsStructured reports whether the Printer emits machine-readable
+// output(json or yaml) instead of the human-readable table form.
+func (p *Printer) IsStructured() bool {
+	return p.format == FormatJSON || p.format == FormatYAML
+}
+
+// PrintObj encodes obj in the configured format. It is a no-op contract
+// violation to call it with FormatTable: callers are expected to render
+// tables themselves and only delegate structured output here.
+func (p *Printer) PrintObj(obj interface{}) error {
+	switch p.format {
+	case FormatJSON:
+		data, err := json.MarshalIndent(obj, "", "    ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(p.out, string(data))
+		return err
+	case FormatYAML:
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		_, err = p.out.Write(data)
+		return err
+	default:
+		return fmt.Errorf("refuse to print object with format %q: only json and yaml are supported", p.format)
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package printer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Format
+		wantErr bool
+	}{
+		{value: "", want: FormatTable},
+		{value: "table", want: FormatTable},
+		{value: "json", want: FormatJSON},
+		{value: "yaml", want: FormatYAML},
+		{value: "xml", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseFormat(tt.value)
+		if tt.wantErr {
+			assert.Error(t, err)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, got)
+	}
+}
+
+func TestPrintObj(t *testing.T) {
+	obj := struct {
+		Name string `json:"name"`
+	}{Name: "sealer"}
+
+	var jsonOut bytes.Buffer
+	assert.NoError(t, New(FormatJSON, &jsonOut).PrintObj(obj))
+	assert.Equal(t, "{\n    \"name\": \"sealer\"\n}\n", jsonOut.String())
+
+	var yamlOut bytes.Buffer
+	assert.NoError(t, New(FormatYAML, &yamlOut).PrintObj(obj))
+	assert.Equal(t, "name: sealer\n", yamlOut.String())
+
+	var tableOut bytes.Buffer
+	assert.Error(t, New(FormatTable, &tableOut).PrintObj(obj))
+}
@@ -0,0 +1,157 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package progress reports phased status of long-running cluster
+// operations. On a TTY each phase renders a per-host progress bar, on a
+// non-TTY it emits structured events through the logger so that wrapping
+// tools can follow the operation without parsing interleaved log lines.
+package progress
+
+import (
+	"os"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/term"
+
+	"github.com/sealerio/sealer/utils/progressbar"
+)
+
+// Phase identifies one stage of a long-running sealer operation.
+type Phase string
+
+const (
+	PhasePullImage        Phase = "pull-image"
+	PhaseDistributeRootfs Phase = "distribute-rootfs"
+	PhaseInitMaster0      Phase = "init-master0"
+	PhaseJoinMasters      Phase = "join-masters"
+	PhaseJoinNodes        Phase = "join-nodes"
+	PhaseLaunchApps       Phase = "launch-apps"
+)
+
+// Reporter receives phase and per-host progress notifications.
+type Reporter interface {
+	// PhaseStarted marks the beginning of a phase covering totalHosts
+	// hosts. A totalHosts of 0 is valid for phases without per-host
+	// granularity, such as pulling the sealer image.
+	PhaseStarted(phase Phase, totalHosts int)
+	// HostSucceeded marks one host as finished within the phase.
+	HostSucceeded(phase Phase, host string)
+	// HostFailed marks one host as failed within the phase.
+	HostFailed(phase Phase, host string, err error)
+	// PhaseFinished marks the end of a phase, err being nil on success.
+	PhaseFinished(phase Phase, err error)
+}
+
+var (
+	defaultReporter Reporter
+	defaultOnce     sync.Once
+)
+
+// SetDefault overrides the process-wide Reporter. It is meant for programs
+// embedding sealer as a library that want to receive progress callbacks and
+// must be called before the first operation starts.
+func SetDefault(r Reporter) {
+	if r == nil {
+		return
+	}
+	// consume the once so that a later Default() call does not replace the
+	// reporter installed here
+	defaultOnce.Do(func() {})
+	defaultReporter = r
+}
+
+// Default returns the process-wide Reporter, choosing progress bars on a
+// TTY and structured events otherwise.
+func Default() Reporter {
+	defaultOnce.Do(func() {
+		if term.IsTerminal(int(os.Stdout.Fd())) {
+			defaultReporter = &barReporter{bars: map[Phase]*progressbar.EasyProgressUtil{}}
+		} else {
+			defaultReporter = &eventReporter{}
+		}
+	})
+	return defaultReporter
+}
+
+// barReporter renders one progress bar per phase on a TTY.
+type barReporter struct {
+	mux  sync.Mutex
+	bars map[Phase]*progressbar.EasyProgressUtil
+}
+
+func (r *barReporter) PhaseStarted(phase Phase, totalHosts int) {
+	if totalHosts <= 0 {
+		logrus.Infof("phase %s started", phase)
+		return
+	}
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.bars[phase] = progressbar.NewEasyProgressUtil(totalHosts, string(phase))
+}
+
+func (r *barReporter) HostSucceeded(phase Phase, host string) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	if bar, ok := r.bars[phase]; ok {
+		bar.Increment()
+	}
+}
+
+func (r *barReporter) HostFailed(phase Phase, host string, err error) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	if bar, ok := r.bars[phase]; ok {
+		bar.Fail(err)
+	}
+}
+
+func (r *barReporter) PhaseFinished(phase Phase, err error) {
+	r.mux.Lock()
+	bar, ok := r.bars[phase]
+	delete(r.bars, phase)
+	r.mux.Unlock()
+	if ok {
+		_ = bar.Finish()
+	}
+	if err != nil {
+		logrus.Errorf("phase %s failed: %v", phase, err)
+		return
+	}
+	logrus.Infof("phase %s finished", phase)
+}
+
+// eventReporter emits one structured log line per event for non-TTY
+// consumers.
+type eventReporter struct{}
+
+func (r *eventReporter) PhaseStarted(phase Phase, totalHosts int) {
+	logrus.WithFields(logrus.Fields{"phase": phase, "total": totalHosts, "status": "started"}).Info("phase started")
+}
+
+func (r *eventReporter) HostSucceeded(phase Phase, host string) {
+	logrus.WithFields(logrus.Fields{"phase": phase, "host": host, "status": "succeeded"}).Info("host finished")
+}
+
+func (r *eventReporter) HostFailed(phase Phase, host string, err error) {
+	logrus.WithFields(logrus.Fields{"phase": phase, "host": host, "status": "failed", "error": err.Error()}).Warn("host failed")
+}
+
+func (r *eventReporter) PhaseFinished(phase Phase, err error) {
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"phase": phase, "status": "failed", "error": err.Error()}).Error("phase failed")
+		return
+	}
+	logrus.WithFields(logrus.Fields{"phase": phase, "status": "finished"}).Info("phase finished")
+}
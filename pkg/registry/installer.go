@@ -15,6 +15,7 @@
 package registry
 
 import (
+	"crypto/x509"
 	"fmt"
 	"net"
 	"path/filepath"
@@ -23,6 +24,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/sealerio/sealer/common"
+	"github.com/sealerio/sealer/pkg/clustercert"
 	"github.com/sealerio/sealer/pkg/clustercert/cert"
 	"github.com/sealerio/sealer/pkg/imagedistributor"
 	"github.com/sealerio/sealer/pkg/infradriver"
@@ -203,6 +205,30 @@ func (l *localInstaller) gen(certPath, certName string) error {
 		Usages:       nil,
 	}
 
+	// with a user supplied CA the registry serves a certificate issued from
+	// corporate PKI instead of a self-signed one
+	env := l.infraDriver.GetClusterEnv()
+	if customCAFile := env[common.EnvCustomCACert]; customCAFile != "" {
+		caCert, caKey, err := clustercert.LoadCustomCA(customCAFile, env[common.EnvCustomCAKey])
+		if err != nil {
+			return err
+		}
+		regCertConfig.CommonName = l.Domain
+		regCertConfig.Usages = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+		generator, err := cert.NewCommonCertificateGenerator(regCertConfig, caCert, caKey)
+		if err != nil {
+			return err
+		}
+		regCert, regKey, err := generator.Generate()
+		if err != nil {
+			return fmt.Errorf("unable to generate registry cert from custom CA: %v", err)
+		}
+		if err := cert.NewCertificateFileManger(certPath, certName).Write(regCert, regKey); err != nil {
+			return fmt.Errorf("unable to save registry cert: %v", err)
+		}
+		return nil
+	}
+
 	caGenerator := cert.NewAuthorityCertificateGenerator(regCertConfig)
 	caCert, caKey, err := caGenerator.Generate()
 	if err != nil {
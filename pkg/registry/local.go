@@ -21,7 +21,6 @@ import (
 	"fmt"
 	"net"
 	"os"
-	"path"
 	"path/filepath"
 	"sort"
 	"strconv"
@@ -180,8 +179,8 @@ func (c *localConfigurator) configureLvs(registryHosts, clientHosts []net.IP) er
 		realEndpoints = append(realEndpoints, ep)
 	}
 
-	//todo should make lvs image name as const value in sealer repo.
-	lvsImageURL := path.Join(net.JoinHostPort(c.Domain, strconv.Itoa(c.Port)), common.LvsCareRepoAndTag)
+	lvsImageURL := ipvs.GetLvscareImage(c.infraDriver.GetClusterRootfsPath(), c.infraDriver.GetClusterEnv(),
+		net.JoinHostPort(c.Domain, strconv.Itoa(c.Port)))
 
 	vip := GetRegistryVIP(c.infraDriver)
 
@@ -193,7 +192,7 @@ func (c *localConfigurator) configureLvs(registryHosts, clientHosts []net.IP) er
 		healthSchem = "http"
 	}
 
-	y, err := ipvs.LvsStaticPodYaml(common.RegLvsCareStaticPodName, vs, realEndpoints, lvsImageURL, healthPath, healthSchem)
+	y, err := ipvs.LvsStaticPodYamlWithTemplate(c.infraDriver.GetClusterRootfsPath(), common.RegLvsCareStaticPodName, vs, realEndpoints, lvsImageURL, healthPath, healthSchem)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,116 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/cmd/kubeadm/app/apis/kubeadm/v1beta3"
+
+	"github.com/sealerio/sealer/common"
+	"github.com/sealerio/sealer/pkg/runtime/kubernetes/kubeadm"
+	osutils "github.com/sealerio/sealer/utils/os"
+)
+
+const (
+	// auditPolicyPath is where copyStaticFiles already places the
+	// audit-policy.yml shipped in the image statics dir; a Clusterfile
+	// overrides the policy through a Config writing that statics file.
+	auditPolicyPath = "/etc/kubernetes/audit-policy.yml"
+	auditLogDir     = "/var/log/kubernetes/audit"
+	auditLogPath    = "/var/log/kubernetes/audit/audit.log"
+
+	defaultAuditLogMaxAge    = 30
+	defaultAuditLogMaxBackup = 10
+	defaultAuditLogMaxSize   = 100
+)
+
+// auditLogIntArg reads one of the audit log rotation envs, falling back to
+// the given compliance friendly default.
+func (k *Runtime) auditLogIntArg(env string, defaultValue int) (string, error) {
+	v, ok := k.infra.GetClusterEnv()[env]
+	if !ok || v == "" {
+		return strconv.Itoa(defaultValue), nil
+	}
+	if _, err := strconv.Atoi(v); err != nil {
+		return "", fmt.Errorf("invalid env %s(%s): %v", env, v, err)
+	}
+	return v, nil
+}
+
+// applyAuditToAPIServer wires the audit policy of the image into the
+// apiserver when the AuditLog env asks for it: the policy flag, a rotated
+// log file under /var/log/kubernetes/audit and the volumes both need. A
+// kubeadm.yml that already configures auditing wins, sealer then changes
+// nothing.
+func (k *Runtime) applyAuditToAPIServer(conf *kubeadm.KubeadmConfig) error {
+	if v := k.infra.GetClusterEnv()[common.EnvAuditLog]; v != "true" {
+		return nil
+	}
+
+	if _, ok := conf.ClusterConfiguration.APIServer.ExtraArgs["audit-policy-file"]; ok {
+		logrus.Debugf("kubeadm config already wires an audit policy, leaving it untouched")
+		return nil
+	}
+
+	if !osutils.IsFileExist(filepath.Join(k.getStaticFileDir(), AuditPolicyYml)) {
+		return fmt.Errorf("env %s is set but the image ships no statics/%s, supply one via the image or a Config", common.EnvAuditLog, AuditPolicyYml)
+	}
+
+	maxAge, err := k.auditLogIntArg(common.EnvAuditLogMaxAge, defaultAuditLogMaxAge)
+	if err != nil {
+		return err
+	}
+	maxBackup, err := k.auditLogIntArg(common.EnvAuditLogMaxBackup, defaultAuditLogMaxBackup)
+	if err != nil {
+		return err
+	}
+	maxSize, err := k.auditLogIntArg(common.EnvAuditLogMaxSize, defaultAuditLogMaxSize)
+	if err != nil {
+		return err
+	}
+
+	args := map[string]string{}
+	for key, value := range conf.ClusterConfiguration.APIServer.ExtraArgs {
+		args[key] = value
+	}
+	args["audit-policy-file"] = auditPolicyPath
+	args["audit-log-path"] = auditLogPath
+	args["audit-log-maxage"] = maxAge
+	args["audit-log-maxbackup"] = maxBackup
+	args["audit-log-maxsize"] = maxSize
+	conf.ClusterConfiguration.APIServer.ExtraArgs = args
+
+	conf.ClusterConfiguration.APIServer.ExtraVolumes = append(conf.ClusterConfiguration.APIServer.ExtraVolumes,
+		v1beta3.HostPathMount{
+			Name:      "audit-policy",
+			HostPath:  auditPolicyPath,
+			MountPath: auditPolicyPath,
+			ReadOnly:  true,
+			PathType:  corev1.HostPathFile,
+		},
+		v1beta3.HostPathMount{
+			Name:      "audit-log",
+			HostPath:  auditLogDir,
+			MountPath: auditLogDir,
+			PathType:  corev1.HostPathDirectoryOrCreate,
+		})
+
+	return nil
+}
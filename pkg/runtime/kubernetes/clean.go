@@ -18,16 +18,23 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"strconv"
 	"strings"
 
 	"github.com/sirupsen/logrus"
 	"golang.org/x/sync/errgroup"
+
+	containerruntime "github.com/sealerio/sealer/pkg/container-runtime"
+	"github.com/sealerio/sealer/utils/shellcommand"
 )
 
 func (k *Runtime) reset(mastersToDelete, workersToDelete []net.IP) error {
 	all := append(mastersToDelete, workersToDelete...)
 	remoteCleanCmd := []string{fmt.Sprintf(RemoteCleanK8sOnHost, vlogToStr(k.Config.Vlog)),
-		fmt.Sprintf(RemoteRemoveAPIServerEtcHost, k.getAPIServerDomain())}
+		fmt.Sprintf(RemoteRemoveAPIServerEtcHost, k.getAPIServerDomain()),
+		fmt.Sprintf(RemoteCleanSealerArtifacts, k.cleanRegistryCertsCmd()),
+		// drop every /etc/hosts alias sealer wrote, the registry domain included
+		shellcommand.CommandUnSetHostAlias()}
 
 	// do kubeadm reset
 	eg, _ := errgroup.WithContext(context.Background())
@@ -51,9 +58,58 @@ func (k *Runtime) reset(mastersToDelete, workersToDelete []net.IP) error {
 			return fmt.Errorf("failed to delete vip route %s: %v", node, err)
 		}
 	}
+
+	// audit the hosts so an incomplete cleanup surfaces now instead of
+	// biting the next install on the same machines
+	for _, node := range all {
+		k.auditCleanResult(node)
+	}
 	return nil
 }
 
+// cleanRegistryCertsCmd renders the shell fragment removing the registry
+// certificates sealer distributed under the container runtime certs.d dirs.
+func (k *Runtime) cleanRegistryCertsCmd() string {
+	regConfig := k.infra.GetClusterRegistry()
+	var endpoints []string
+	if regConfig.LocalRegistry != nil && regConfig.LocalRegistry.Domain != "" {
+		endpoints = append(endpoints, net.JoinHostPort(regConfig.LocalRegistry.Domain, strconv.Itoa(regConfig.LocalRegistry.Port)))
+	}
+	if regConfig.ExternalRegistry != nil && regConfig.ExternalRegistry.Domain != "" {
+		endpoints = append(endpoints, net.JoinHostPort(regConfig.ExternalRegistry.Domain, strconv.Itoa(regConfig.ExternalRegistry.Port)))
+	}
+
+	var cmd string
+	for _, endpoint := range endpoints {
+		cmd += fmt.Sprintf(" && rm -rf %s/%s %s/%s",
+			containerruntime.DefaultDockerCertsDir, endpoint,
+			containerruntime.DefaultContainerdCertsDir, endpoint)
+	}
+	return cmd
+}
+
+// auditCleanResult reports the artifacts a host still carries after cleanup.
+// It only warns: the node is already out of the cluster at this point.
+func (k *Runtime) auditCleanResult(host net.IP) {
+	var certsDirs string
+	regConfig := k.infra.GetClusterRegistry()
+	if regConfig.LocalRegistry != nil && regConfig.LocalRegistry.Domain != "" {
+		endpoint := net.JoinHostPort(regConfig.LocalRegistry.Domain, strconv.Itoa(regConfig.LocalRegistry.Port))
+		certsDirs = fmt.Sprintf("%s/%s %s/%s", containerruntime.DefaultDockerCertsDir, endpoint, containerruntime.DefaultContainerdCertsDir, endpoint)
+	}
+
+	out, err := k.infra.CmdToString(host, nil, fmt.Sprintf(RemoteCheckCleanResult, certsDirs), "\n")
+	if err != nil {
+		logrus.Debugf("failed to audit clean result on host %s: %v", host, err)
+		return
+	}
+	if leftovers := strings.Fields(out); len(leftovers) != 0 {
+		logrus.Warnf("host %s still has leftovers after cleanup: %s", host, strings.Join(leftovers, ", "))
+		return
+	}
+	logrus.Debugf("host %s is clean, it can be reused immediately", host)
+}
+
 func (k *Runtime) deleteMasters(mastersToDelete, remainMasters []net.IP) error {
 	var remainMaster0 *net.IP
 	if len(remainMasters) > 0 {
@@ -80,13 +136,16 @@ func (k *Runtime) deleteMasters(mastersToDelete, remainMasters []net.IP) error {
 
 func (k *Runtime) deleteMaster(master net.IP, remainMaster0 *net.IP) error {
 	remoteCleanCmd := []string{fmt.Sprintf(RemoteCleanK8sOnHost, vlogToStr(k.Config.Vlog)),
-		fmt.Sprintf(RemoteRemoveAPIServerEtcHost, k.getAPIServerDomain()), RemoteCleanCustomizeCRISocket}
+		fmt.Sprintf(RemoteRemoveAPIServerEtcHost, k.getAPIServerDomain()), RemoteCleanCustomizeCRISocket,
+		fmt.Sprintf(RemoteCleanSealerArtifacts, k.cleanRegistryCertsCmd()),
+		shellcommand.CommandUnSetHostAlias()}
 
 	//if the master to be removed is the execution machine, kubelet and ~./kube will not be removed and ApiServer host will be added.
 
 	if err := k.infra.CmdAsync(master, nil, remoteCleanCmd...); err != nil {
 		logrus.Warnf("failed to run remote cleanup cmd on master %s, ignore and continue remove it from cluster", master)
 	}
+	k.auditCleanResult(master)
 
 	// if remainMaster0 is nil, no need delete master from cluster
 	if remainMaster0 != nil {
@@ -127,13 +186,16 @@ func (k *Runtime) deleteNodes(nodesToDelete, remainMasters []net.IP) error {
 
 func (k *Runtime) deleteNode(node net.IP, remainMaster0 *net.IP) error {
 	remoteCleanCmd := []string{fmt.Sprintf(RemoteCleanK8sOnHost, vlogToStr(k.Config.Vlog)),
-		fmt.Sprintf(RemoteRemoveAPIServerEtcHost, k.getAPIServerDomain()), RemoteCleanCustomizeCRISocket, fmt.Sprintf(RemoteDelRoute, k.getAPIServerVIP(), node)}
+		fmt.Sprintf(RemoteRemoveAPIServerEtcHost, k.getAPIServerDomain()), RemoteCleanCustomizeCRISocket, fmt.Sprintf(RemoteDelRoute, k.getAPIServerVIP(), node),
+		fmt.Sprintf(RemoteCleanSealerArtifacts, k.cleanRegistryCertsCmd()),
+		shellcommand.CommandUnSetHostAlias()}
 
 	//if the master to be removed is the execution machine, kubelet and ~./kube will not be removed and ApiServer host will be added.
 
 	if err := k.infra.CmdAsync(node, nil, remoteCleanCmd...); err != nil {
 		logrus.Warnf("failed to run remote cleanup cmd on node %s, ignore and continue remove it from cluster", node)
 	}
+	k.auditCleanResult(node)
 
 	// if remainMaster0 is nil, no need delete master from cluster
 	if remainMaster0 != nil {
@@ -0,0 +1,246 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+	"sigs.k8s.io/yaml"
+
+	"github.com/sealerio/sealer/common"
+	"github.com/sealerio/sealer/pkg/infradriver"
+	"github.com/sealerio/sealer/pkg/runtime/kubernetes/kubeadm"
+	osutils "github.com/sealerio/sealer/utils/os"
+	"k8s.io/kubernetes/cmd/kubeadm/app/apis/kubeadm/v1beta3"
+)
+
+const (
+	// EncryptionConfigDir is mounted into kube-apiserver via an extra
+	// volume; the config itself stays outside the pki dir so key material
+	// handling and cert handling remain separate.
+	EncryptionConfigDir      = "/etc/kubernetes/encryption"
+	EncryptionConfigFileName = "encryption-config.yaml"
+)
+
+// minimal view of apiserver.config.k8s.io/v1 EncryptionConfiguration; the
+// upstream types are not vendored and only these fields are used.
+type encryptionConfiguration struct {
+	APIVersion string               `json:"apiVersion"`
+	Kind       string               `json:"kind"`
+	Resources  []encryptionResource `json:"resources"`
+}
+
+type encryptionResource struct {
+	Resources []string             `json:"resources"`
+	Providers []encryptionProvider `json:"providers"`
+}
+
+type encryptionProvider struct {
+	AESCBC   *aescbcProvider   `json:"aescbc,omitempty"`
+	KMS      *kmsProvider      `json:"kms,omitempty"`
+	Identity *identityProvider `json:"identity,omitempty"`
+}
+
+type identityProvider struct{}
+
+type aescbcProvider struct {
+	Keys []encryptionKey `json:"keys"`
+}
+
+type encryptionKey struct {
+	Name   string `json:"name"`
+	Secret string `json:"secret"`
+}
+
+type kmsProvider struct {
+	Name     string `json:"name"`
+	Endpoint string `json:"endpoint"`
+	Timeout  string `json:"timeout,omitempty"`
+}
+
+// localEncryptionConfigPath is where the rendered config lives under the
+// rootfs before it is copied onto the masters.
+func localEncryptionConfigPath(rootfs string) string {
+	return filepath.Join(rootfs, "encryption", EncryptionConfigFileName)
+}
+
+func (k *Runtime) secretEncryptionProvider() string {
+	return k.infra.GetClusterEnv()[common.EnvSecretEncryption]
+}
+
+// newEncryptionKey returns a fresh base64 encoded 32 byte aescbc key.
+func newEncryptionKey() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("failed to generate encryption key: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// generateEncryptionConfig renders the EncryptionConfiguration for the chosen
+// provider under the rootfs. An existing config is kept as is, so re-applies
+// and scale ups never rotate keys behind the operator's back.
+func (k *Runtime) generateEncryptionConfig(provider string) error {
+	configPath := localEncryptionConfigPath(k.infra.GetClusterRootfsPath())
+	if osutils.IsFileExist(configPath) {
+		return nil
+	}
+
+	var p encryptionProvider
+	switch provider {
+	case "aescbc":
+		secret, err := newEncryptionKey()
+		if err != nil {
+			return err
+		}
+		p.AESCBC = &aescbcProvider{Keys: []encryptionKey{{Name: "key-1", Secret: secret}}}
+	case "kms":
+		endpoint := k.infra.GetClusterEnv()[common.EnvKMSEndpoint]
+		if endpoint == "" {
+			return fmt.Errorf("env %s is required with %s=kms", common.EnvKMSEndpoint, common.EnvSecretEncryption)
+		}
+		p.KMS = &kmsProvider{Name: "sealer-kms", Endpoint: endpoint, Timeout: "3s"}
+	default:
+		return fmt.Errorf("unknown %s value %q, expect \"aescbc\" or \"kms\"", common.EnvSecretEncryption, provider)
+	}
+
+	config := encryptionConfiguration{
+		APIVersion: "apiserver.config.k8s.io/v1",
+		Kind:       "EncryptionConfiguration",
+		Resources: []encryptionResource{{
+			Resources: []string{"secrets"},
+			// identity last: everything not yet encrypted stays readable
+			Providers: []encryptionProvider{p, {Identity: &identityProvider{}}},
+		}},
+	}
+
+	content, err := yaml.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("failed to marshal encryption config: %v", err)
+	}
+	return osutils.NewCommonWriter(configPath).WriteFile(content)
+}
+
+// sendEncryptionConfig places the rendered config on the given masters; a
+// cluster without secret encryption has no local config and nothing is sent.
+func (k *Runtime) sendEncryptionConfig(masters []net.IP) error {
+	src := localEncryptionConfigPath(k.infra.GetClusterRootfsPath())
+	if !osutils.IsFileExist(src) {
+		return nil
+	}
+
+	return k.infra.Execute(masters, func(host net.IP) error {
+		if err := k.infra.Copy(host, src, filepath.Join(EncryptionConfigDir, EncryptionConfigFileName)); err != nil {
+			return fmt.Errorf("failed to copy encryption config to host %s: %v", host, err)
+		}
+		return nil
+	})
+}
+
+// applyEncryptionToAPIServer wires the config into kube-apiserver: the
+// encryption-provider-config flag plus an extra volume mounting the dir.
+func applyEncryptionToAPIServer(conf *kubeadm.KubeadmConfig) {
+	args := map[string]string{}
+	for key, value := range conf.ClusterConfiguration.APIServer.ExtraArgs {
+		args[key] = value
+	}
+	args["encryption-provider-config"] = filepath.Join(EncryptionConfigDir, EncryptionConfigFileName)
+	conf.ClusterConfiguration.APIServer.ExtraArgs = args
+
+	conf.ClusterConfiguration.APIServer.ExtraVolumes = append(conf.ClusterConfiguration.APIServer.ExtraVolumes, v1beta3.HostPathMount{
+		Name:      "encryption-config",
+		HostPath:  EncryptionConfigDir,
+		MountPath: EncryptionConfigDir,
+		ReadOnly:  true,
+	})
+}
+
+// restartAPIServerCmd bounces the static pod by moving its manifest away and
+// back, the only reliable restart mechanism kubelet offers for static pods.
+const restartAPIServerCmd = `mv /etc/kubernetes/manifests/kube-apiserver.yaml /tmp/kube-apiserver.yaml && sleep 5 && mv /tmp/kube-apiserver.yaml /etc/kubernetes/manifests/kube-apiserver.yaml && sleep 20`
+
+// RotateEncryptionKey adds a fresh aescbc key in front of the existing ones,
+// rolls it out to every master with an apiserver restart, then rewrites all
+// secrets so they are encrypted with the new key. Superseded keys are kept
+// last so old data stays readable; the next rotation drops them.
+func RotateEncryptionKey(infra infradriver.InfraDriver) error {
+	configPath := localEncryptionConfigPath(infra.GetClusterRootfsPath())
+	content, err := os.ReadFile(filepath.Clean(configPath))
+	if err != nil {
+		return fmt.Errorf("failed to read encryption config, was the cluster created with %s=aescbc: %v", common.EnvSecretEncryption, err)
+	}
+
+	var config encryptionConfiguration
+	if err := yaml.Unmarshal(content, &config); err != nil {
+		return fmt.Errorf("failed to parse encryption config %s: %v", configPath, err)
+	}
+
+	rotated := false
+	for i := range config.Resources {
+		for j := range config.Resources[i].Providers {
+			aescbc := config.Resources[i].Providers[j].AESCBC
+			if aescbc == nil || len(aescbc.Keys) == 0 {
+				continue
+			}
+			secret, err := newEncryptionKey()
+			if err != nil {
+				return err
+			}
+			// newest key first, only the current and the previous key are
+			// kept: everything older was already re-encrypted away
+			keys := []encryptionKey{{Name: fmt.Sprintf("key-%d", len(aescbc.Keys)+1), Secret: secret}, aescbc.Keys[0]}
+			aescbc.Keys = keys
+			rotated = true
+		}
+	}
+	if !rotated {
+		return fmt.Errorf("no aescbc provider in %s, kms keys are rotated by the KMS plugin", configPath)
+	}
+
+	updated, err := yaml.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("failed to marshal encryption config: %v", err)
+	}
+	if err := osutils.NewCommonWriter(configPath).WriteFile(updated); err != nil {
+		return err
+	}
+
+	masters := infra.GetHostIPListByRole(common.MASTER)
+	// restart one master at a time to keep the apiservers available
+	for _, master := range masters {
+		if err := infra.Copy(master, configPath, filepath.Join(EncryptionConfigDir, EncryptionConfigFileName)); err != nil {
+			return fmt.Errorf("failed to copy encryption config to host %s: %v", master, err)
+		}
+		logrus.Infof("restarting kube-apiserver on %s to pick up the new encryption key", master)
+		if err := infra.CmdAsync(master, nil, restartAPIServerCmd); err != nil {
+			return fmt.Errorf("failed to restart kube-apiserver on host %s: %v", master, err)
+		}
+	}
+
+	logrus.Info("rewriting all secrets with the new encryption key")
+	reEncryptCmd := "kubectl get secrets --all-namespaces -o json | kubectl replace -f -"
+	if err := infra.CmdAsync(masters[0], nil, reEncryptCmd); err != nil {
+		return fmt.Errorf("failed to re-encrypt existing secrets: %v", err)
+	}
+
+	logrus.Info("succeeded in rotating the secret encryption key")
+	return nil
+}
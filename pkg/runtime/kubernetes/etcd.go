@@ -0,0 +1,179 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/sealerio/sealer/common"
+	"github.com/sealerio/sealer/pkg/clustercert"
+)
+
+// Hosts carrying the etcd role run external etcd members as systemd services
+// instead of the stacked static pods kubeadm would place on the masters. The
+// etcd binary is expected at /usr/local/bin/etcd; an image may ship a
+// scripts/etcd.sh installing it, hosts with etcd preinstalled work without.
+const (
+	externalEtcdCertDir   = clustercert.KubeDefaultCertEtcdPath
+	externalEtcdDataDir   = "/var/lib/etcd"
+	externalEtcdUnitPath  = "/etc/systemd/system/etcd.service"
+	externalEtcdBinary    = "/usr/local/bin/etcd"
+	etcdInstallScriptName = "etcd.sh"
+
+	// waitEtcdReadyCmd blocks until the local member serves clients, so
+	// kubeadm init does not race the etcd bootstrap.
+	waitEtcdReadyCmd = `for i in $(seq 1 30); do (echo > /dev/tcp/127.0.0.1/2379) >/dev/null 2>&1 && exit 0; sleep 2; done; echo "etcd member did not start listening on 2379" >&2; exit 1`
+)
+
+func (k *Runtime) getEtcdHosts() []net.IP {
+	return k.infra.GetHostIPListByRole(common.ETCD)
+}
+
+// etcdMemberName returns the stable member name of the host at the given
+// Clusterfile position.
+func etcdMemberName(index int) string {
+	return fmt.Sprintf("etcd-%d", index)
+}
+
+// externalEtcdEndpoints lists the client URLs the apiservers are wired to.
+func externalEtcdEndpoints(etcdHosts []net.IP) []string {
+	var endpoints []string
+	for _, host := range etcdHosts {
+		endpoints = append(endpoints, "https://"+net.JoinHostPort(host.String(), "2379"))
+	}
+	return endpoints
+}
+
+// etcdUnitContent renders the systemd unit of one member, TLS on both the
+// client and peer listeners with the certs sealer generated.
+func etcdUnitContent(name string, host net.IP, initialCluster string) string {
+	clientURL := "https://" + net.JoinHostPort(host.String(), "2379")
+	localClientURL := "https://" + net.JoinHostPort("127.0.0.1", "2379")
+	peerURL := "https://" + net.JoinHostPort(host.String(), "2380")
+
+	return fmt.Sprintf(`[Unit]
+Description=etcd cluster member deployed by sealer
+After=network-online.target
+Wants=network-online.target
+
+[Service]
+ExecStart=%s --name %s \
+  --data-dir %s \
+  --listen-client-urls %s,%s \
+  --advertise-client-urls %s \
+  --listen-peer-urls %s \
+  --initial-advertise-peer-urls %s \
+  --initial-cluster %s \
+  --initial-cluster-state new \
+  --cert-file %s --key-file %s \
+  --peer-cert-file %s --peer-key-file %s \
+  --trusted-ca-file %s --peer-trusted-ca-file %s \
+  --client-cert-auth --peer-client-cert-auth
+Restart=always
+RestartSec=5
+LimitNOFILE=65536
+
+[Install]
+WantedBy=multi-user.target`,
+		externalEtcdBinary, name,
+		externalEtcdDataDir,
+		clientURL, localClientURL,
+		clientURL,
+		peerURL,
+		peerURL,
+		initialCluster,
+		filepath.Join(externalEtcdCertDir, "server.crt"), filepath.Join(externalEtcdCertDir, "server.key"),
+		filepath.Join(externalEtcdCertDir, "peer.crt"), filepath.Join(externalEtcdCertDir, "peer.key"),
+		filepath.Join(externalEtcdCertDir, "ca.crt"), filepath.Join(externalEtcdCertDir, "ca.crt"))
+}
+
+// installExternalEtcd brings up one etcd member per etcd role host: member
+// certs signed by the cluster etcd CA, the optional binary install script
+// from the rootfs, a systemd unit, then a wait until the member serves. It
+// must run before kubeadm init, the apiservers point at these endpoints.
+func (k *Runtime) installExternalEtcd(etcdHosts []net.IP) error {
+	// the members share one CA, sign serially before fanning out
+	for i, host := range etcdHosts {
+		memberCertDir := filepath.Join(k.getEtcdCertPath(), host.String())
+		if err := clustercert.GenerateEtcdMemberCerts(k.getEtcdCertPath(), memberCertDir, etcdMemberName(i), host); err != nil {
+			return fmt.Errorf("failed to generate etcd member certs for host %s: %v", host, err)
+		}
+	}
+
+	var members []string
+	for i, host := range etcdHosts {
+		members = append(members, fmt.Sprintf("%s=https://%s", etcdMemberName(i), net.JoinHostPort(host.String(), "2380")))
+	}
+	initialCluster := strings.Join(members, ",")
+
+	installScript := filepath.Join(k.infra.GetClusterRootfsPath(), "scripts", etcdInstallScriptName)
+	installCmd := fmt.Sprintf(`if [ -f %[1]s ]; then bash %[1]s; fi`, installScript)
+
+	eg, _ := errgroup.WithContext(context.Background())
+	for i := range etcdHosts {
+		index, host := i, etcdHosts[i]
+		eg.Go(func() error {
+			logrus.Infof("start to install external etcd member %s on %s", etcdMemberName(index), host)
+
+			memberCertDir := filepath.Join(k.getEtcdCertPath(), host.String())
+			certs := map[string]string{
+				filepath.Join(k.getEtcdCertPath(), "ca.crt"): filepath.Join(externalEtcdCertDir, "ca.crt"),
+				filepath.Join(memberCertDir, "server.crt"):   filepath.Join(externalEtcdCertDir, "server.crt"),
+				filepath.Join(memberCertDir, "server.key"):   filepath.Join(externalEtcdCertDir, "server.key"),
+				filepath.Join(memberCertDir, "peer.crt"):     filepath.Join(externalEtcdCertDir, "peer.crt"),
+				filepath.Join(memberCertDir, "peer.key"):     filepath.Join(externalEtcdCertDir, "peer.key"),
+			}
+			for src, dest := range certs {
+				if err := k.infra.Copy(host, src, dest); err != nil {
+					return fmt.Errorf("failed to copy etcd cert to host %s: %v", host, err)
+				}
+			}
+
+			unitCmd := fmt.Sprintf(`cat > %s <<'EOF'
+%s
+EOF
+systemctl daemon-reload && systemctl enable --now etcd`, externalEtcdUnitPath, etcdUnitContent(etcdMemberName(index), host, initialCluster))
+
+			if err := k.infra.CmdAsync(host, nil, installCmd, unitCmd, waitEtcdReadyCmd); err != nil {
+				return fmt.Errorf("failed to install etcd member on host %s: %v", host, err)
+			}
+
+			logrus.Infof("succeeded in installing etcd member on %s", host)
+			return nil
+		})
+	}
+
+	return eg.Wait()
+}
+
+// cleanExternalEtcd stops the members and removes their unit, data and certs.
+func (k *Runtime) cleanExternalEtcd(etcdHosts []net.IP) error {
+	cleanCmd := fmt.Sprintf(`systemctl disable --now etcd > /dev/null 2>&1; rm -f %s; rm -rf %s %s; systemctl daemon-reload`,
+		externalEtcdUnitPath, externalEtcdDataDir, externalEtcdCertDir)
+
+	return k.infra.Execute(etcdHosts, func(host net.IP) error {
+		if err := k.infra.CmdAsync(host, nil, cleanCmd); err != nil {
+			return fmt.Errorf("failed to clean etcd member on host %s: %v", host, err)
+		}
+		return nil
+	})
+}
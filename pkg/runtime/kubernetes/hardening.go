@@ -0,0 +1,266 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+	kubeletv1beta1 "k8s.io/kubelet/config/v1beta1"
+	"k8s.io/kubernetes/cmd/kubeadm/app/apis/kubeadm/v1beta3"
+
+	"github.com/sealerio/sealer/common"
+	"github.com/sealerio/sealer/pkg/infradriver"
+	"github.com/sealerio/sealer/pkg/runtime/kubernetes/kubeadm"
+	utilsnet "github.com/sealerio/sealer/utils/net"
+)
+
+// cisTLSCipherSuites is the modern cipher list the CIS benchmark accepts,
+// applied to the apiserver, the kubelets and stacked etcd alike.
+var cisTLSCipherSuites = []string{
+	"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256",
+	"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+	"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384",
+	"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+	"TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305",
+	"TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305",
+}
+
+const (
+	// cisMasterFilePermCmd tightens the file modes the benchmark checks on
+	// control plane hosts: manifests and kubeconfigs 600, private keys 600,
+	// certificates 644, everything owned by root.
+	cisMasterFilePermCmd = `chmod 600 /etc/kubernetes/manifests/*.yaml /etc/kubernetes/*.conf;
+find /etc/kubernetes/pki -name '*.key' -exec chmod 600 {} +;
+find /etc/kubernetes/pki -name '*.crt' -exec chmod 644 {} +;
+chown -R root:root /etc/kubernetes`
+	// cisNodeFilePermCmd does the same for the files every kubelet host has.
+	cisNodeFilePermCmd = `chmod 600 /var/lib/kubelet/config.yaml /etc/kubernetes/kubelet.conf;
+chown root:root /var/lib/kubelet/config.yaml /etc/kubernetes/kubelet.conf`
+)
+
+// hardeningProfile reads the HardeningProfile env, "" when no profile is
+// requested.
+func (k *Runtime) hardeningProfile() (string, error) {
+	profile := k.infra.GetClusterEnv()[common.EnvHardeningProfile]
+	switch profile {
+	case "", common.HardeningProfileCIS:
+		return profile, nil
+	default:
+		return "", fmt.Errorf("unknown env %s(%s), only %q is supported", common.EnvHardeningProfile, profile, common.HardeningProfileCIS)
+	}
+}
+
+// setArgIfAbsent copies args and adds key=value unless the kubeadm config of
+// the image or Clusterfile already sets the flag: explicit config wins over
+// the profile.
+func setArgIfAbsent(args map[string]string, key, value string) map[string]string {
+	copied := map[string]string{}
+	for k, v := range args {
+		copied[k] = v
+	}
+	if _, ok := copied[key]; !ok {
+		copied[key] = value
+	}
+	return copied
+}
+
+// applyHardeningProfile mutates the kubeadm config according to the
+// HardeningProfile env. The cis profile disables the kubelet read-only port
+// and anonymous auth, forces webhook authorization, pins strong TLS cipher
+// suites on the apiserver, the kubelets and stacked etcd, and turns off
+// profiling endpoints on the control plane components.
+func (k *Runtime) applyHardeningProfile(conf *kubeadm.KubeadmConfig) error {
+	profile, err := k.hardeningProfile()
+	if err != nil {
+		return err
+	}
+	if profile != common.HardeningProfileCIS {
+		return nil
+	}
+	logrus.Infof("applying the %s hardening profile to the kubeadm config", profile)
+
+	anonymous := false
+	conf.KubeletConfiguration.ReadOnlyPort = 0
+	conf.KubeletConfiguration.Authentication.Anonymous.Enabled = &anonymous
+	conf.KubeletConfiguration.Authorization.Mode = kubeletv1beta1.KubeletAuthorizationModeWebhook
+	if len(conf.KubeletConfiguration.TLSCipherSuites) == 0 {
+		conf.KubeletConfiguration.TLSCipherSuites = cisTLSCipherSuites
+	}
+
+	cipherSuites := strings.Join(cisTLSCipherSuites, ",")
+	apiServer := conf.ClusterConfiguration.APIServer.ExtraArgs
+	apiServer = setArgIfAbsent(apiServer, "profiling", "false")
+	apiServer = setArgIfAbsent(apiServer, "tls-cipher-suites", cipherSuites)
+	conf.ClusterConfiguration.APIServer.ExtraArgs = apiServer
+
+	conf.ClusterConfiguration.ControllerManager.ExtraArgs = setArgIfAbsent(conf.ClusterConfiguration.ControllerManager.ExtraArgs, "profiling", "false")
+	conf.ClusterConfiguration.Scheduler.ExtraArgs = setArgIfAbsent(conf.ClusterConfiguration.Scheduler.ExtraArgs, "profiling", "false")
+
+	// dedicated etcd role hosts run outside kubeadm, their systemd units keep
+	// their own TLS config; only stacked members take extra args here
+	if conf.ClusterConfiguration.Etcd.External == nil {
+		if conf.ClusterConfiguration.Etcd.Local == nil {
+			conf.ClusterConfiguration.Etcd.Local = &v1beta3.LocalEtcd{DataDir: "/var/lib/etcd"}
+		}
+		conf.ClusterConfiguration.Etcd.Local.ExtraArgs = setArgIfAbsent(conf.ClusterConfiguration.Etcd.Local.ExtraArgs, "cipher-suites", cipherSuites)
+	}
+
+	return nil
+}
+
+// applyCISFilePermissions tightens the file modes the CIS benchmark checks,
+// after kubeadm has written them. It is a no-op without the cis profile.
+func (k *Runtime) applyCISFilePermissions(masters, workers []net.IP) error {
+	profile, err := k.hardeningProfile()
+	if err != nil {
+		return err
+	}
+	if profile != common.HardeningProfileCIS {
+		return nil
+	}
+
+	eg, _ := errgroup.WithContext(context.Background())
+	for _, m := range masters {
+		master := m
+		eg.Go(func() error {
+			if err := k.infra.CmdAsync(master, nil, cisMasterFilePermCmd, cisNodeFilePermCmd); err != nil {
+				return fmt.Errorf("failed to tighten file permissions on master(%s): %v", master, err)
+			}
+			return nil
+		})
+	}
+	for _, w := range workers {
+		worker := w
+		eg.Go(func() error {
+			if err := k.infra.CmdAsync(worker, nil, cisNodeFilePermCmd); err != nil {
+				return fmt.Errorf("failed to tighten file permissions on worker(%s): %v", worker, err)
+			}
+			return nil
+		})
+	}
+	return eg.Wait()
+}
+
+// cisCheck is one remote CIS audit probe. The command prints its findings,
+// one per line; empty output means the control passes.
+type cisCheck struct {
+	name       string
+	cmd        string
+	masterOnly bool
+}
+
+// cisChecks covers the controls sealer can verify over ssh without kube API
+// access: flags in the static pod manifests, the kubelet config and the file
+// modes the cis profile tightens.
+var cisChecks = []cisCheck{
+	{
+		name:       "apiserver profiling disabled",
+		cmd:        `grep -q -- "--profiling=false" /etc/kubernetes/manifests/kube-apiserver.yaml 2>/dev/null || echo "kube-apiserver runs without --profiling=false"`,
+		masterOnly: true,
+	},
+	{
+		name:       "apiserver strong TLS cipher suites",
+		cmd:        `grep -q -- "--tls-cipher-suites=" /etc/kubernetes/manifests/kube-apiserver.yaml 2>/dev/null || echo "kube-apiserver runs without --tls-cipher-suites"`,
+		masterOnly: true,
+	},
+	{
+		name:       "controller-manager profiling disabled",
+		cmd:        `grep -q -- "--profiling=false" /etc/kubernetes/manifests/kube-controller-manager.yaml 2>/dev/null || echo "kube-controller-manager runs without --profiling=false"`,
+		masterOnly: true,
+	},
+	{
+		name:       "scheduler profiling disabled",
+		cmd:        `grep -q -- "--profiling=false" /etc/kubernetes/manifests/kube-scheduler.yaml 2>/dev/null || echo "kube-scheduler runs without --profiling=false"`,
+		masterOnly: true,
+	},
+	{
+		name:       "static pod manifest permissions are 600 or stricter",
+		cmd:        `find /etc/kubernetes/manifests -name '*.yaml' -perm /077 2>/dev/null`,
+		masterOnly: true,
+	},
+	{
+		name:       "pki private key permissions are 600 or stricter",
+		cmd:        `find /etc/kubernetes/pki -name '*.key' -perm /077 2>/dev/null`,
+		masterOnly: true,
+	},
+	{
+		name:       "kubeconfig file permissions are 600 or stricter",
+		cmd:        `find /etc/kubernetes -maxdepth 1 -name '*.conf' -perm /077 2>/dev/null`,
+		masterOnly: true,
+	},
+	{
+		name:       "/etc/kubernetes owned by root",
+		cmd:        `find /etc/kubernetes ! -user root 2>/dev/null`,
+		masterOnly: true,
+	},
+	{
+		name: "kubelet read-only port disabled",
+		cmd:  `awk '/^readOnlyPort:/ && $2 != 0 {print "kubelet read-only port enabled: "$2}' /var/lib/kubelet/config.yaml 2>/dev/null`,
+	},
+	{
+		name: "kubelet anonymous auth disabled",
+		cmd:  `grep -A1 "anonymous:" /var/lib/kubelet/config.yaml 2>/dev/null | grep -q "enabled: false" || echo "kubelet anonymous auth is not disabled"`,
+	},
+	{
+		name: "kubelet authorization mode is Webhook",
+		cmd:  `grep -A1 "^authorization:" /var/lib/kubelet/config.yaml 2>/dev/null | grep -q "mode: Webhook" || echo "kubelet authorization mode is not Webhook"`,
+	},
+	{
+		name: "kubelet config permissions are 600 or stricter",
+		cmd:  `find /var/lib/kubelet/config.yaml -perm /077 2>/dev/null`,
+	},
+}
+
+// CISResult is the outcome of one check on one host; empty Findings means
+// the control passes there.
+type CISResult struct {
+	Host     string
+	Name     string
+	Findings string
+}
+
+// AuditCIS runs the remote CIS checks against the current cluster,
+// master-only controls against the masters and the kubelet controls against
+// every kubernetes host, and returns one result per host and check.
+// Dedicated etcd role hosts run no kubelet and are not probed.
+func AuditCIS(infra infradriver.InfraDriver) ([]CISResult, error) {
+	masters := infra.GetHostIPListByRole(common.MASTER)
+	all := append(masters, utilsnet.RemoveIPs(infra.GetHostIPListByRole(common.NODE), masters)...)
+
+	var results []CISResult
+	for _, check := range cisChecks {
+		hosts := all
+		if check.masterOnly {
+			hosts = masters
+		}
+		for _, host := range hosts {
+			output, err := infra.CmdToString(host, nil, check.cmd, "\n")
+			if err != nil {
+				return nil, fmt.Errorf("failed to run check %q on host(%s): %v", check.name, host, err)
+			}
+			results = append(results, CISResult{
+				Host:     host.String(),
+				Name:     check.name,
+				Findings: strings.TrimSpace(output),
+			})
+		}
+	}
+	return results, nil
+}
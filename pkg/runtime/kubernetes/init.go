@@ -29,7 +29,6 @@ import (
 	"github.com/sealerio/sealer/pkg/clustercert"
 	"github.com/sealerio/sealer/pkg/runtime/kubernetes/kubeadm"
 	"github.com/sealerio/sealer/utils/shellcommand"
-	"github.com/sealerio/sealer/utils/yaml"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/sync/errgroup"
 )
@@ -56,7 +55,50 @@ func (k *Runtime) initKubeadmConfig(masters []net.IP) (kubeadm.KubeadmConfig, er
 		conf.InitConfiguration.NodeRegistration.CRISocket = output
 	}
 
-	bs, err := yaml.MarshalWithDelimiter(&conf.InitConfiguration,
+	// the advertise address also becomes the etcd peer URL of master0
+	if nodeIP, err := k.getNodeIPOverride(masters[0]); err != nil {
+		return kubeadm.KubeadmConfig{}, err
+	} else if nodeIP != nil {
+		conf.InitConfiguration.LocalAPIEndpoint.AdvertiseAddress = nodeIP.String()
+		applyNodeIPOverride(&conf.InitConfiguration.NodeRegistration, nodeIP)
+	}
+
+	// dedicated etcd role hosts serve the cluster store, kubeadm must not
+	// bring up stacked members on the masters
+	if etcdHosts := k.getEtcdHosts(); len(etcdHosts) > 0 {
+		conf.ClusterConfiguration.Etcd = v1beta3.Etcd{
+			External: &v1beta3.ExternalEtcd{
+				Endpoints: externalEtcdEndpoints(etcdHosts),
+				CAFile:    filepath.Join(clustercert.KubeDefaultCertEtcdPath, "ca.crt"),
+				CertFile:  filepath.Join(clustercert.KubeDefaultCertPath, "apiserver-etcd-client.crt"),
+				KeyFile:   filepath.Join(clustercert.KubeDefaultCertPath, "apiserver-etcd-client.key"),
+			},
+		}
+	}
+
+	if provider := k.secretEncryptionProvider(); provider != "" {
+		if err := k.generateEncryptionConfig(provider); err != nil {
+			return kubeadm.KubeadmConfig{}, err
+		}
+		if err := k.sendEncryptionConfig(masters[0:1]); err != nil {
+			return kubeadm.KubeadmConfig{}, err
+		}
+		applyEncryptionToAPIServer(&conf)
+	}
+
+	if err := k.applyAuditToAPIServer(&conf); err != nil {
+		return kubeadm.KubeadmConfig{}, err
+	}
+
+	if err := k.applyOIDCToAPIServer(&conf); err != nil {
+		return kubeadm.KubeadmConfig{}, err
+	}
+
+	if err := k.applyHardeningProfile(&conf); err != nil {
+		return kubeadm.KubeadmConfig{}, err
+	}
+
+	bs, err := kubeadm.MarshalWithConversion(&conf.InitConfiguration,
 		&conf.ClusterConfiguration,
 		&conf.KubeletConfiguration,
 		&conf.KubeProxyConfiguration,
@@ -90,6 +132,19 @@ func (k *Runtime) generateCert(kubeadmConf kubeadm.KubeadmConfig, master0 net.IP
 		return err
 	}
 
+	env := k.infra.GetClusterEnv()
+	caFile, caKeyFile := env[common.EnvCustomCACert], env[common.EnvCustomCAKey]
+	if (caFile == "") != (caKeyFile == "") {
+		return fmt.Errorf("env %s and %s must be set together", common.EnvCustomCACert, common.EnvCustomCAKey)
+	}
+	if caFile != "" {
+		// seed the user CA as kubernetes and etcd CA before generation, so
+		// every certificate below chains to corporate PKI
+		if err := clustercert.SeedCustomCA(caFile, caKeyFile, k.getPKIPath(), k.getEtcdCertPath()); err != nil {
+			return err
+		}
+	}
+
 	return clustercert.GenerateAllKubernetesCerts(
 		k.getPKIPath(),
 		k.getEtcdCertPath(),
@@ -21,10 +21,10 @@ import (
 	"github.com/sirupsen/logrus"
 	"k8s.io/kubernetes/cmd/kubeadm/app/apis/kubeadm/v1beta3"
 
+	"github.com/sealerio/sealer/pkg/progress"
 	"github.com/sealerio/sealer/pkg/runtime"
 	"github.com/sealerio/sealer/pkg/runtime/kubernetes/kubeadm"
 	"github.com/sealerio/sealer/utils/shellcommand"
-	"github.com/sealerio/sealer/utils/yaml"
 )
 
 func (k *Runtime) joinMasters(newMasters []net.IP, master0 net.IP, kubeadmConfig kubeadm.KubeadmConfig, token v1beta3.BootstrapTokenDiscovery, certKey string) error {
@@ -55,71 +55,99 @@ func (k *Runtime) joinMasters(newMasters []net.IP, master0 net.IP, kubeadmConfig
 		return err
 	}
 
+	if err := k.sendEncryptionConfig(newMasters); err != nil {
+		return err
+	}
+
+	reporter := progress.Default()
+	reporter.PhaseStarted(progress.PhaseJoinMasters, len(newMasters))
+
 	//set master0 as APIServerEndpoint when join master
 	vs := net.JoinHostPort(master0.String(), "6443")
 	for _, m := range newMasters {
 		logrus.Infof("start to join %s as master", m)
 
-		joinCmd, err := k.Command(JoinMaster, k.getNodeNameOverride(m))
-		if err != nil {
-			return fmt.Errorf("failed to get join master command: %v", err)
-		}
-
-		hostname, err := k.infra.GetHostName(m)
-		if err != nil {
+		if err := k.joinMaster(m, master0, vs, kubeadmConfig, token, certKey); err != nil {
+			reporter.HostFailed(progress.PhaseJoinMasters, m.String(), err)
+			reporter.PhaseFinished(progress.PhaseJoinMasters, err)
 			return err
 		}
 
-		if output, err := k.infra.CmdToString(m, nil, GetCustomizeCRISocket, ""); err == nil && output != "" {
-			kubeadmConfig.JoinConfiguration.NodeRegistration.CRISocket = output
-		}
+		reporter.HostSucceeded(progress.PhaseJoinMasters, m.String())
+		logrus.Infof("succeeded in joining %s as master", m)
+	}
+	reporter.PhaseFinished(progress.PhaseJoinMasters, nil)
+	return nil
+}
 
-		kubeadmConfig.JoinConfiguration.Discovery.BootstrapToken = &token
-		kubeadmConfig.JoinConfiguration.Discovery.BootstrapToken.APIServerEndpoint = vs
-		kubeadmConfig.JoinConfiguration.ControlPlane.LocalAPIEndpoint.AdvertiseAddress = m.String()
-		kubeadmConfig.JoinConfiguration.ControlPlane.LocalAPIEndpoint.BindPort = int32(6443)
-		kubeadmConfig.JoinConfiguration.ControlPlane.CertificateKey = certKey
-		str, err := yaml.MarshalWithDelimiter(kubeadmConfig.JoinConfiguration, kubeadmConfig.KubeletConfiguration)
-		if err != nil {
-			return err
-		}
-		cmd := fmt.Sprintf("mkdir -p /etc/kubernetes && echo \"%s\" > %s", str, KubeadmFileYml)
-		if err = k.infra.CmdAsync(m, nil, cmd); err != nil {
-			return fmt.Errorf("failed to set join kubeadm config on host(%s) with cmd(%s): %v", m, cmd, err)
-		}
+// joinMaster joins one new master against master0.
+func (k *Runtime) joinMaster(m, master0 net.IP, vs string, kubeadmConfig kubeadm.KubeadmConfig, token v1beta3.BootstrapTokenDiscovery, certKey string) error {
+	joinCmd, err := k.Command(JoinMaster, k.getNodeNameOverride(m))
+	if err != nil {
+		return fmt.Errorf("failed to get join master command: %v", err)
+	}
 
-		if err = k.infra.CmdAsync(m, nil, shellcommand.CommandSetHostAlias(k.getAPIServerDomain(), master0.String())); err != nil {
-			return fmt.Errorf("failed to config cluster hosts file cmd: %v", err)
-		}
+	hostname, err := k.infra.GetHostName(m)
+	if err != nil {
+		return err
+	}
 
-		certCMD := runtime.RemoteCertCmd(kubeadmConfig.GetCertSANS(), m, hostname, kubeadmConfig.GetSvcCIDR(), "")
-		if err = k.infra.CmdAsync(m, nil, certCMD); err != nil {
-			return fmt.Errorf("failed to exec command(%s) on master(%s): %v", certCMD, m, err)
-		}
+	if output, err := k.infra.CmdToString(m, nil, GetCustomizeCRISocket, ""); err == nil && output != "" {
+		kubeadmConfig.JoinConfiguration.NodeRegistration.CRISocket = output
+	}
 
-		if err = k.infra.CmdAsync(m, nil, joinCmd); err != nil {
-			return fmt.Errorf("failed to exec command(%s) on master(%s): %v", joinCmd, m, err)
-		}
+	kubeadmConfig.JoinConfiguration.Discovery.BootstrapToken = &token
+	kubeadmConfig.JoinConfiguration.Discovery.BootstrapToken.APIServerEndpoint = vs
+	advertiseAddress := m.String()
+	// the advertise address also becomes the etcd peer URL of this master
+	if nodeIP, err := k.getNodeIPOverride(m); err != nil {
+		return err
+	} else if nodeIP != nil {
+		advertiseAddress = nodeIP.String()
+		applyNodeIPOverride(&kubeadmConfig.JoinConfiguration.NodeRegistration, nodeIP)
+	}
+	kubeadmConfig.JoinConfiguration.ControlPlane.LocalAPIEndpoint.AdvertiseAddress = advertiseAddress
+	kubeadmConfig.JoinConfiguration.ControlPlane.LocalAPIEndpoint.BindPort = int32(6443)
+	kubeadmConfig.JoinConfiguration.ControlPlane.CertificateKey = certKey
+	str, err := kubeadm.MarshalWithConversion(kubeadmConfig.JoinConfiguration, kubeadmConfig.KubeletConfiguration)
+	if err != nil {
+		return err
+	}
+	cmd := fmt.Sprintf("mkdir -p /etc/kubernetes && echo \"%s\" > %s", str, KubeadmFileYml)
+	if err = k.infra.CmdAsync(m, nil, cmd); err != nil {
+		return fmt.Errorf("failed to set join kubeadm config on host(%s) with cmd(%s): %v", m, cmd, err)
+	}
 
-		if err = k.infra.CmdAsync(m, nil, shellcommand.CommandSetHostAlias(k.getAPIServerDomain(), m.String())); err != nil {
-			return fmt.Errorf("failed to config cluster hosts file cmd: %v", err)
-		}
+	if err = k.infra.CmdAsync(m, nil, shellcommand.CommandSetHostAlias(k.getAPIServerDomain(), master0.String())); err != nil {
+		return fmt.Errorf("failed to config cluster hosts file cmd: %v", err)
+	}
 
-		if err = k.infra.CmdAsync(m, nil, "rm -rf .kube/config && mkdir -p /root/.kube && cp /etc/kubernetes/admin.conf /root/.kube/config"); err != nil {
-			return err
-		}
+	certCMD := runtime.RemoteCertCmd(kubeadmConfig.GetCertSANS(), m, hostname, kubeadmConfig.GetSvcCIDR(), "")
+	if err = k.infra.CmdAsync(m, nil, certCMD); err != nil {
+		return fmt.Errorf("failed to exec command(%s) on master(%s): %v", certCMD, m, err)
+	}
 
-		// At beginning, we set APIServerDomain direct to master0 and then kubeadm start scheduler and kcm, then we reset
-		// the APIServerDomain to the master itself, but scheduler and kcm already load the domain info and will not reload.
-		// So, we need restart them after reset the APIServerDomain.
-		if err = k.infra.CmdAsync(m, nil, "mv /etc/kubernetes/manifests/kube-scheduler.yaml /tmp/ && mv /tmp/kube-scheduler.yaml /etc/kubernetes/manifests/"); err != nil {
-			return err
-		}
-		if err = k.infra.CmdAsync(m, nil, "mv /etc/kubernetes/manifests/kube-controller-manager.yaml /tmp/ && mv /tmp/kube-controller-manager.yaml /etc/kubernetes/manifests/"); err != nil {
-			return err
-		}
+	if err = k.infra.CmdAsync(m, nil, joinCmd); err != nil {
+		return fmt.Errorf("failed to exec command(%s) on master(%s): %v", joinCmd, m, err)
+	}
 
-		logrus.Infof("succeeded in joining %s as master", m)
+	if err = k.infra.CmdAsync(m, nil, shellcommand.CommandSetHostAlias(k.getAPIServerDomain(), m.String())); err != nil {
+		return fmt.Errorf("failed to config cluster hosts file cmd: %v", err)
+	}
+
+	if err = k.infra.CmdAsync(m, nil, "rm -rf .kube/config && mkdir -p /root/.kube && cp /etc/kubernetes/admin.conf /root/.kube/config"); err != nil {
+		return err
 	}
+
+	// At beginning, we set APIServerDomain direct to master0 and then kubeadm start scheduler and kcm, then we reset
+	// the APIServerDomain to the master itself, but scheduler and kcm already load the domain info and will not reload.
+	// So, we need restart them after reset the APIServerDomain.
+	if err = k.infra.CmdAsync(m, nil, "mv /etc/kubernetes/manifests/kube-scheduler.yaml /tmp/ && mv /tmp/kube-scheduler.yaml /etc/kubernetes/manifests/"); err != nil {
+		return err
+	}
+	if err = k.infra.CmdAsync(m, nil, "mv /etc/kubernetes/manifests/kube-controller-manager.yaml /tmp/ && mv /tmp/kube-controller-manager.yaml /etc/kubernetes/manifests/"); err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -18,14 +18,17 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"sync"
 
 	"github.com/sirupsen/logrus"
 	"golang.org/x/sync/errgroup"
 	"k8s.io/kubernetes/cmd/kubeadm/app/apis/kubeadm/v1beta3"
 
+	"github.com/sealerio/sealer/common"
+	"github.com/sealerio/sealer/pkg/checkpoint"
+	"github.com/sealerio/sealer/pkg/progress"
 	"github.com/sealerio/sealer/pkg/runtime/kubernetes/kubeadm"
 	utilsnet "github.com/sealerio/sealer/utils/net"
-	"github.com/sealerio/sealer/utils/yaml"
 )
 
 func (k *Runtime) joinNodes(newNodes, masters []net.IP, kubeadmConfig kubeadm.KubeadmConfig, token v1beta3.BootstrapTokenDiscovery) error {
@@ -46,49 +49,99 @@ func (k *Runtime) joinNodes(newNodes, masters []net.IP, kubeadmConfig kubeadm.Ku
 		return fmt.Errorf("failed to configure lvs rule for apiserver: %v", err)
 	}
 
+	reporter := progress.Default()
+	reporter.PhaseStarted(progress.PhaseJoinNodes, len(newNodes))
+
 	eg, _ := errgroup.WithContext(context.Background())
 
+	joinNode := func(node net.IP) error {
+		logrus.Infof("start to join %s as worker", node)
+		joinNodeCmd, err := k.Command(JoinNode, k.getNodeNameOverride(node))
+		if err != nil {
+			return err
+		}
+
+		myKubeadmConfig := kubeadmConfig
+
+		if output, err := k.infra.CmdToString(node, nil, GetCustomizeCRISocket, ""); err == nil && output != "" {
+			myKubeadmConfig.JoinConfiguration.NodeRegistration.CRISocket = output
+		}
+
+		if nodeIP, err := k.getNodeIPOverride(node); err != nil {
+			return err
+		} else if nodeIP != nil {
+			applyNodeIPOverride(&myKubeadmConfig.JoinConfiguration.NodeRegistration, nodeIP)
+		}
+		joinConfig, err := kubeadm.MarshalWithConversion(myKubeadmConfig.JoinConfiguration, myKubeadmConfig.KubeletConfiguration)
+		if err != nil {
+			return err
+		}
+		writeJoinConfigCmd := fmt.Sprintf("mkdir -p /etc/kubernetes && echo \"%s\" > %s", joinConfig, KubeadmFileYml)
+
+		err = k.checkMultiNetworkAddVIPRoute(node)
+		if err != nil {
+			return fmt.Errorf("failed to check multi network: %v", err)
+		}
+
+		if err = k.infra.CmdAsync(node, nil, writeJoinConfigCmd); err != nil {
+			return fmt.Errorf("failed to set join kubeadm config on host(%s) with cmd(%s): %v", node, writeJoinConfigCmd, err)
+		}
+
+		if err = k.infra.CmdAsync(node, nil, joinNodeCmd); err != nil {
+			return fmt.Errorf("failed to join node %s: %v", node, err)
+		}
+
+		logrus.Infof("succeeded in joining %s as worker", node)
+		return nil
+	}
+
+	var (
+		failedMux   sync.Mutex
+		failedHosts []string
+	)
+
 	for _, n := range newNodes {
 		node := n
 		eg.Go(func() error {
-			logrus.Infof("start to join %s as worker", node)
-			joinNodeCmd, err := k.Command(JoinNode, k.getNodeNameOverride(node))
-			if err != nil {
-				return err
-			}
-
-			myKubeadmConfig := kubeadmConfig
-
-			if output, err := k.infra.CmdToString(node, nil, GetCustomizeCRISocket, ""); err == nil && output != "" {
-				myKubeadmConfig.JoinConfiguration.NodeRegistration.CRISocket = output
-			}
-			joinConfig, err := yaml.MarshalWithDelimiter(myKubeadmConfig.JoinConfiguration, myKubeadmConfig.KubeletConfiguration)
-			if err != nil {
+			if err := joinNode(node); err != nil {
+				reporter.HostFailed(progress.PhaseJoinNodes, node.String(), err)
+				failedMux.Lock()
+				failedHosts = append(failedHosts, node.String())
+				failedMux.Unlock()
 				return err
 			}
-			writeJoinConfigCmd := fmt.Sprintf("mkdir -p /etc/kubernetes && echo \"%s\" > %s", joinConfig, KubeadmFileYml)
-
-			err = k.checkMultiNetworkAddVIPRoute(node)
-			if err != nil {
-				return fmt.Errorf("failed to check multi network: %v", err)
-			}
-
-			if err = k.infra.CmdAsync(node, nil, writeJoinConfigCmd); err != nil {
-				return fmt.Errorf("failed to set join kubeadm config on host(%s) with cmd(%s): %v", node, writeJoinConfigCmd, err)
-			}
-
-			if err = k.infra.CmdAsync(node, nil, joinNodeCmd); err != nil {
-				return fmt.Errorf("failed to join node %s: %v", node, err)
-			}
-
-			logrus.Infof("succeeded in joining %s as worker", node)
+			reporter.HostSucceeded(progress.PhaseJoinNodes, node.String())
 			return nil
 		})
 	}
-	return eg.Wait()
+
+	err := eg.Wait()
+	if err != nil && len(failedHosts) <= k.Config.MaxJoinFailures {
+		// tolerate the failed workers: report them, remember them for a
+		// later `sealer join --retry-failed` and keep the apply going.
+		logrus.Warnf("%d node(s) failed to join and will be skipped per the failure policy: %v", len(failedHosts), failedHosts)
+		if cp, cpErr := checkpoint.NewManager(k.infra.GetClusterName()); cpErr != nil {
+			logrus.Warnf("failed to load checkpoint to record failed join hosts: %v", cpErr)
+		} else {
+			for _, host := range failedHosts {
+				if cpErr := cp.RecordFailedJoinHost(host); cpErr != nil {
+					logrus.Warnf("failed to record failed join host %s: %v", host, cpErr)
+				}
+			}
+		}
+		err = nil
+	}
+	reporter.PhaseFinished(progress.PhaseJoinNodes, err)
+	return err
 }
 
 func (k *Runtime) checkMultiNetworkAddVIPRoute(node net.IP) error {
+	// with kube-vip the VIP is a real announced address, a local route
+	// through the node itself would shadow it
+	if k.vipMode() == common.VIPModeKubeVIP {
+		return nil
+	}
+
 	result, err := k.infra.CmdToString(node, nil, fmt.Sprintf(RemoteCheckRoute, node), "")
 	if err != nil {
 		return err
@@ -0,0 +1,94 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubeadm
+
+import (
+	"bytes"
+	"sort"
+
+	"sigs.k8s.io/yaml"
+)
+
+// extraArgsPaths lists where kubeadm configurations carry component
+// arguments. v1beta3 and older serialize them as maps, v1beta4 as
+// name/value lists.
+var extraArgsPaths = [][]string{
+	{"apiServer", "extraArgs"},
+	{"controllerManager", "extraArgs"},
+	{"scheduler", "extraArgs"},
+	{"etcd", "local", "extraArgs"},
+	{"nodeRegistration", "kubeletExtraArgs"},
+}
+
+// MarshalWithConversion renders the given configuration documents like
+// utils/yaml.MarshalWithDelimiter, but rewrites kubeadm documents whose
+// apiVersion was set to v1beta4 into the v1beta4 wire format, so the
+// internal v1beta3 representation can drive kubeadm of any supported
+// Kubernetes version.
+func MarshalWithConversion(configs ...interface{}) ([]byte, error) {
+	var cfgs [][]byte
+	for _, cfg := range configs {
+		data, err := yaml.Marshal(cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		doc := map[string]interface{}{}
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, err
+		}
+		if doc["apiVersion"] == KubeadmV1beta4 {
+			for _, path := range extraArgsPaths {
+				convertArgsMapToList(doc, path)
+			}
+			data, err = yaml.Marshal(doc)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		cfgs = append(cfgs, data)
+	}
+	return bytes.Join(cfgs, []byte("\n---\n")), nil
+}
+
+func convertArgsMapToList(doc map[string]interface{}, path []string) {
+	parent := doc
+	for _, field := range path[:len(path)-1] {
+		next, ok := parent[field].(map[string]interface{})
+		if !ok {
+			return
+		}
+		parent = next
+	}
+
+	field := path[len(path)-1]
+	args, ok := parent[field].(map[string]interface{})
+	if !ok || len(args) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(args))
+	for name := range args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	list := make([]interface{}, 0, len(args))
+	for _, name := range names {
+		list = append(list, map[string]interface{}{"name": name, "value": args[name]})
+	}
+	parent[field] = list
+}
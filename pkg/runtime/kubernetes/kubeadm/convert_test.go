@@ -0,0 +1,45 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubeadm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/kubernetes/cmd/kubeadm/app/apis/kubeadm/v1beta3"
+)
+
+func TestMarshalWithConversion(t *testing.T) {
+	clusterConfiguration := v1beta3.ClusterConfiguration{}
+	clusterConfiguration.Kind = "ClusterConfiguration"
+	clusterConfiguration.APIServer.ExtraArgs = map[string]string{
+		"audit-log-path": "/var/log/audit.log",
+		"etcd-servers":   "https://192.168.0.2:2379",
+	}
+
+	// v1beta3 documents keep the map form
+	clusterConfiguration.APIVersion = KubeadmV1beta3
+	out, err := MarshalWithConversion(clusterConfiguration)
+	assert.NoError(t, err)
+	assert.Contains(t, string(out), "audit-log-path: /var/log/audit.log")
+
+	// v1beta4 documents are rewritten to name/value lists
+	clusterConfiguration.APIVersion = KubeadmV1beta4
+	out, err = MarshalWithConversion(clusterConfiguration)
+	assert.NoError(t, err)
+	assert.Contains(t, string(out), "- name: audit-log-path")
+	assert.Contains(t, string(out), "value: /var/log/audit.log")
+	assert.NotContains(t, string(out), "audit-log-path: /var/log/audit.log")
+}
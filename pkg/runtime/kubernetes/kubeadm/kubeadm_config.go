@@ -59,11 +59,13 @@ const (
 	V1150 = "v1.15.0"
 	V1200 = "v1.20.0"
 	V1230 = "v1.23.0"
+	V1310 = "v1.31.0"
 
 	// kubeadm api version
 	KubeadmV1beta1 = "kubeadm.k8s.io/v1beta1"
 	KubeadmV1beta2 = "kubeadm.k8s.io/v1beta2"
 	KubeadmV1beta3 = "kubeadm.k8s.io/v1beta3"
+	KubeadmV1beta4 = "kubeadm.k8s.io/v1beta4"
 )
 
 // LoadFromClusterfile :Load KubeadmConfig from Clusterfile.
@@ -71,6 +73,7 @@ const (
 // If Kubeadm raw config in Clusterfile, just load it.
 func (k *KubeadmConfig) LoadFromClusterfile(kubeadmConfig KubeadmConfig) error {
 	k.APIServer.CertSANs = strUtils.RemoveDuplicate(append(k.APIServer.CertSANs, kubeadmConfig.APIServer.CertSANs...))
+	k.mergeComponentArgs(kubeadmConfig)
 
 	return mergo.Merge(k, kubeadmConfig)
 }
@@ -83,10 +86,71 @@ func (k *KubeadmConfig) Merge(kubeadmYamlPath string, decode func(arg string, ki
 		return fmt.Errorf("failed to found kubeadm config from %s: %v", kubeadmYamlPath, err)
 	}
 	k.APIServer.CertSANs = strUtils.RemoveDuplicate(append(k.APIServer.CertSANs, newConfig.APIServer.CertSANs...))
+	k.mergeComponentArgs(newConfig)
 
 	return mergo.Merge(k, newConfig)
 }
 
+// mergeComponentArgs deep-merges the component argument and feature
+// gate maps of newConfig into k, so tuning a single flag does not throw
+// away the remaining defaults. Values already set on k win; a shadowed
+// different default is reported.
+func (k *KubeadmConfig) mergeComponentArgs(newConfig KubeadmConfig) {
+	k.APIServer.ExtraArgs = mergeArgsMap("kube-apiserver", k.APIServer.ExtraArgs, newConfig.APIServer.ExtraArgs)
+	k.ControllerManager.ExtraArgs = mergeArgsMap("kube-controller-manager", k.ControllerManager.ExtraArgs, newConfig.ControllerManager.ExtraArgs)
+	k.Scheduler.ExtraArgs = mergeArgsMap("kube-scheduler", k.Scheduler.ExtraArgs, newConfig.Scheduler.ExtraArgs)
+	k.InitConfiguration.NodeRegistration.KubeletExtraArgs = mergeArgsMap("kubelet", k.InitConfiguration.NodeRegistration.KubeletExtraArgs, newConfig.InitConfiguration.NodeRegistration.KubeletExtraArgs)
+	k.JoinConfiguration.NodeRegistration.KubeletExtraArgs = mergeArgsMap("kubelet", k.JoinConfiguration.NodeRegistration.KubeletExtraArgs, newConfig.JoinConfiguration.NodeRegistration.KubeletExtraArgs)
+	if newConfig.Etcd.Local != nil {
+		if k.Etcd.Local == nil {
+			k.Etcd.Local = newConfig.Etcd.Local.DeepCopy()
+		} else {
+			k.Etcd.Local.ExtraArgs = mergeArgsMap("etcd", k.Etcd.Local.ExtraArgs, newConfig.Etcd.Local.ExtraArgs)
+		}
+	}
+	k.ClusterConfiguration.FeatureGates = mergeFeatureGates("kubeadm", k.ClusterConfiguration.FeatureGates, newConfig.ClusterConfiguration.FeatureGates)
+	k.KubeletConfiguration.FeatureGates = mergeFeatureGates("kubelet", k.KubeletConfiguration.FeatureGates, newConfig.KubeletConfiguration.FeatureGates)
+	k.KubeProxyConfiguration.FeatureGates = mergeFeatureGates("kube-proxy", k.KubeProxyConfiguration.FeatureGates, newConfig.KubeProxyConfiguration.FeatureGates)
+}
+
+func mergeArgsMap(component string, current, defaults map[string]string) map[string]string {
+	if len(defaults) == 0 {
+		return current
+	}
+	if current == nil {
+		current = map[string]string{}
+	}
+	for key, value := range defaults {
+		if existing, ok := current[key]; ok {
+			if existing != value {
+				logrus.Debugf("%s option %s: keeping %q over default %q", component, key, existing, value)
+			}
+			continue
+		}
+		current[key] = value
+	}
+	return current
+}
+
+func mergeFeatureGates(component string, current, defaults map[string]bool) map[string]bool {
+	if len(defaults) == 0 {
+		return current
+	}
+	if current == nil {
+		current = map[string]bool{}
+	}
+	for key, value := range defaults {
+		if existing, ok := current[key]; ok {
+			if existing != value {
+				logrus.Debugf("%s feature gate %s: keeping %t over default %t", component, key, existing, value)
+			}
+			continue
+		}
+		current[key] = value
+	}
+	return current
+}
+
 func (k *KubeadmConfig) setAPIVersion(apiVersion string) {
 	k.InitConfiguration.APIVersion = apiVersion
 	k.ClusterConfiguration.APIVersion = apiVersion
@@ -103,7 +167,15 @@ func (k *KubeadmConfig) setKubeadmAPIVersion() {
 	if err != nil {
 		logrus.Errorf("compare kubernetes version failed: %s", err)
 	}
+	greaterThanKV1310, err := kv.GreaterThan(V1310)
+	if err != nil {
+		logrus.Errorf("compare kubernetes version failed: %s", err)
+	}
 	switch {
+	case greaterThanKV1310:
+		// the v1beta4 wire format is produced at render time, see
+		// MarshalWithConversion
+		k.setAPIVersion(KubeadmV1beta4)
 	case greaterThanKV1150 && !greaterThanKV1230:
 		k.setAPIVersion(KubeadmV1beta2)
 	case greaterThanKV1230:
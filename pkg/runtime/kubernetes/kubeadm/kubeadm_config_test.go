@@ -223,3 +223,29 @@ func TestKubeadmConfig_LoadFromClusterfile(t *testing.T) {
 		})
 	}
 }
+
+func TestKubeadmConfig_MergeComponentArgs(t *testing.T) {
+	user := KubeadmConfig{}
+	user.APIServer.ExtraArgs = map[string]string{"max-requests-inflight": "800"}
+	user.KubeletConfiguration.FeatureGates = map[string]bool{"GracefulNodeShutdown": false}
+
+	defaults := KubeadmConfig{}
+	defaults.APIServer.ExtraArgs = map[string]string{
+		"max-requests-inflight": "400",
+		"audit-log-maxage":      "30",
+	}
+	defaults.KubeletConfiguration.FeatureGates = map[string]bool{"GracefulNodeShutdown": true}
+
+	user.mergeComponentArgs(defaults)
+
+	// user values win, missing defaults are filled in
+	if user.APIServer.ExtraArgs["max-requests-inflight"] != "800" {
+		t.Errorf("user extraArgs value was overwritten: %v", user.APIServer.ExtraArgs)
+	}
+	if user.APIServer.ExtraArgs["audit-log-maxage"] != "30" {
+		t.Errorf("default extraArgs value was not merged: %v", user.APIServer.ExtraArgs)
+	}
+	if user.KubeletConfiguration.FeatureGates["GracefulNodeShutdown"] {
+		t.Errorf("user feature gate was overwritten: %v", user.KubeletConfiguration.FeatureGates)
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/client-go/util/keyutil"
+
+	"github.com/sealerio/sealer/common"
+	"github.com/sealerio/sealer/pkg/clustercert/cert"
+	"github.com/sealerio/sealer/pkg/infradriver"
+)
+
+const (
+	// ExportUserAdmin exports the full cluster-admin credential of admin.conf.
+	ExportUserAdmin = "admin"
+	// ExportUserView mints a fresh read-only credential: a client certificate
+	// in the sealer:view group, bound to the built-in view ClusterRole.
+	ExportUserView = "view"
+
+	// viewGroup is the certificate organization RBAC binds to the view role.
+	viewGroup = "sealer:view"
+	// ensureViewBindingCmd idempotently binds the view group on master0.
+	ensureViewBindingCmd = `kubectl create clusterrolebinding sealer-view --clusterrole=view --group=` + viewGroup + ` --dry-run=client -o yaml | kubectl apply -f -`
+)
+
+// ExportKubeConfig builds a kubeconfig for the current cluster reachable from
+// outside: the admin.conf credential fetched from master0 or a freshly minted
+// read-only one, with the server endpoint rewritten to master0 instead of the
+// cluster internal apiserver domain. The result is merged into the kubeconfig
+// at mergePath under a context named after the cluster, which becomes the
+// current context. The context name is returned.
+func ExportKubeConfig(infra infradriver.InfraDriver, user, mergePath string) (string, error) {
+	masters := infra.GetHostIPListByRole(common.MASTER)
+	if len(masters) == 0 {
+		return "", fmt.Errorf("no master host found in cluster %s", infra.GetClusterName())
+	}
+	master0 := masters[0]
+	serverURL := fmt.Sprintf("https://%s", net.JoinHostPort(master0.String(), "6443"))
+	clusterName := infra.GetClusterName()
+
+	var (
+		authInfo *clientcmdapi.AuthInfo
+		caData   []byte
+		err      error
+	)
+	switch user {
+	case ExportUserAdmin:
+		authInfo, caData, err = fetchAdminCredential(infra, master0)
+	case ExportUserView:
+		authInfo, caData, err = mintViewCredential(infra, master0)
+	default:
+		return "", fmt.Errorf("unknown user %q, only %q and %q are supported", user, ExportUserAdmin, ExportUserView)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	contextName := fmt.Sprintf("%s-%s", clusterName, user)
+	merged := clientcmdapi.NewConfig()
+	if _, err := os.Stat(mergePath); err == nil {
+		merged, err = clientcmd.LoadFromFile(mergePath)
+		if err != nil {
+			return "", fmt.Errorf("failed to load kubeconfig %s: %v", mergePath, err)
+		}
+	}
+
+	merged.Clusters[clusterName] = &clientcmdapi.Cluster{
+		Server:                   serverURL,
+		CertificateAuthorityData: caData,
+	}
+	merged.AuthInfos[contextName] = authInfo
+	merged.Contexts[contextName] = &clientcmdapi.Context{
+		Cluster:  clusterName,
+		AuthInfo: contextName,
+	}
+	merged.CurrentContext = contextName
+
+	if err := os.MkdirAll(filepath.Dir(mergePath), 0700); err != nil {
+		return "", err
+	}
+	if err := clientcmd.WriteToFile(*merged, mergePath); err != nil {
+		return "", fmt.Errorf("failed to write kubeconfig %s: %v", mergePath, err)
+	}
+	return contextName, nil
+}
+
+// fetchAdminCredential pulls admin.conf from master0 and returns its client
+// credential and cluster CA.
+func fetchAdminCredential(infra infradriver.InfraDriver, master0 net.IP) (*clientcmdapi.AuthInfo, []byte, error) {
+	tmpFile, err := os.CreateTemp("", "sealer-admin-conf-*")
+	if err != nil {
+		return nil, nil, err
+	}
+	tmpPath := tmpFile.Name()
+	_ = tmpFile.Close()
+	defer func() {
+		_ = os.Remove(tmpPath)
+	}()
+
+	if err := infra.CopyR(master0, common.KubeAdminConf, tmpPath); err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch %s from master0(%s): %v", common.KubeAdminConf, master0, err)
+	}
+
+	adminConf, err := clientcmd.LoadFromFile(tmpPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse fetched admin.conf: %v", err)
+	}
+	context, ok := adminConf.Contexts[adminConf.CurrentContext]
+	if !ok {
+		return nil, nil, fmt.Errorf("fetched admin.conf has no current context")
+	}
+	authInfo, ok := adminConf.AuthInfos[context.AuthInfo]
+	if !ok {
+		return nil, nil, fmt.Errorf("fetched admin.conf has no user %s", context.AuthInfo)
+	}
+	cluster, ok := adminConf.Clusters[context.Cluster]
+	if !ok {
+		return nil, nil, fmt.Errorf("fetched admin.conf has no cluster %s", context.Cluster)
+	}
+	return authInfo, cluster.CertificateAuthorityData, nil
+}
+
+// mintViewCredential signs a short client certificate in the sealer:view
+// group with the cluster CA kept locally under rootfs/pki, and makes sure the
+// group is bound to the view ClusterRole.
+func mintViewCredential(infra infradriver.InfraDriver, master0 net.IP) (*clientcmdapi.AuthInfo, []byte, error) {
+	pkiPath := filepath.Join(infra.GetClusterRootfsPath(), "pki")
+	if _, err := os.Stat(cert.PathForKey(pkiPath, "ca")); err != nil {
+		return nil, nil, fmt.Errorf("cluster CA key not found under %s, the view user can only be minted on the machine that installed the cluster", pkiPath)
+	}
+
+	caCert, caKey, err := cert.NewCertificateFileManger(pkiPath, "ca").Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load cluster CA: %v", err)
+	}
+
+	generator, err := cert.NewCommonCertificateGenerator(cert.CertificateDescriptor{
+		CommonName:   "sealer-view",
+		Organization: []string{viewGroup},
+		Year:         1,
+		Usages:       []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, caCert, caKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	viewCert, viewKey, err := generator.Generate()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to mint view certificate: %v", err)
+	}
+
+	if err := infra.CmdAsync(master0, nil, ensureViewBindingCmd); err != nil {
+		return nil, nil, fmt.Errorf("failed to bind group %s to the view ClusterRole on master0(%s): %v", viewGroup, master0, err)
+	}
+
+	keyPEM, err := keyutil.MarshalPrivateKeyToPEM(viewKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &clientcmdapi.AuthInfo{
+		ClientCertificateData: cert.EncodeCertPEM(viewCert),
+		ClientKeyData:         keyPEM,
+	}, cert.EncodeCertPEM(caCert), nil
+}
@@ -0,0 +1,81 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/sealerio/sealer/common"
+	"github.com/sealerio/sealer/pkg/kubevip"
+	"github.com/sealerio/sealer/utils/shellcommand"
+)
+
+// vipMode returns how the apiserver VIP is served, lvscare unless the
+// VIPMode env picks kube-vip.
+func (k *Runtime) vipMode() string {
+	if mode, ok := k.infra.GetClusterEnv()[common.EnvVIPMode]; ok && mode != "" {
+		return mode
+	}
+	return common.VIPModeLvscare
+}
+
+// configureKubeVIP places the kube-vip static pod on every master and lets
+// the other hosts resolve the apiserver domain to the announced VIP. Unlike
+// lvscare there is no per-node proxy: the VIP is a real address served via
+// ARP or BGP by whichever master holds leadership.
+func (k *Runtime) configureKubeVIP(masterHosts, clientHosts []net.IP) error {
+	vip := k.getAPIServerVIP().String()
+
+	eg, _ := errgroup.WithContext(context.Background())
+
+	for i := range masterHosts {
+		master := masterHosts[i]
+		eg.Go(func() error {
+			// the host env wins over the cluster env, so a master with a
+			// different NIC naming can pick its own interface
+			config, err := kubevip.NewConfigFromEnv(k.infra.GetHostEnv(master), vip, k.Config.RegistryInfo.URL)
+			if err != nil {
+				return err
+			}
+
+			manifest, err := kubevip.StaticPodYaml(config)
+			if err != nil {
+				return err
+			}
+
+			if err := k.infra.CmdAsync(master, nil, kubevip.GetCreateStaticPodCmd(manifest),
+				shellcommand.CommandSetHostAlias(k.getAPIServerDomain(), vip)); err != nil {
+				return fmt.Errorf("failed to config kube-vip on master %s: %v", master, err)
+			}
+			return nil
+		})
+	}
+
+	for i := range clientHosts {
+		node := clientHosts[i]
+		eg.Go(func() error {
+			if err := k.infra.CmdAsync(node, nil, shellcommand.CommandSetHostAlias(k.getAPIServerDomain(), vip)); err != nil {
+				return fmt.Errorf("failed to config apiserver domain on node %s: %v", node, err)
+			}
+			return nil
+		})
+	}
+
+	return eg.Wait()
+}
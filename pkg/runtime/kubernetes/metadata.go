@@ -0,0 +1,102 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtimeClient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/sealerio/sealer/pkg/runtime"
+	"github.com/sealerio/sealer/version"
+)
+
+const (
+	// SealerSystemNamespace holds the metadata sealer records about the
+	// cluster it installed.
+	SealerSystemNamespace = "sealer-system"
+	// ClusterMetadataConfigMapName is the ConfigMap recording what was
+	// installed, from where and when; `sealer version --cluster` reads it.
+	ClusterMetadataConfigMapName = "sealer-cluster-metadata"
+)
+
+// clusterMetadata snapshots the install: image, sealer version, applied env,
+// host inventory with roles, and the timestamp.
+func (k *Runtime) clusterMetadata() (map[string]string, error) {
+	envBytes, err := json.Marshal(k.infra.GetClusterEnv())
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal cluster env: %v", err)
+	}
+
+	var hosts []string
+	for _, ip := range k.infra.GetHostIPList() {
+		roles := k.infra.GetRoleListByHostIP(ip.String())
+		hosts = append(hosts, fmt.Sprintf("%s %s", ip, strings.Join(roles, ",")))
+	}
+	sort.Strings(hosts)
+
+	return map[string]string{
+		"imageName":     k.infra.GetClusterImageName(),
+		"sealerVersion": version.Get().String(),
+		"installTime":   time.Now().Format(time.RFC3339),
+		"env":           string(envBytes),
+		"hosts":         strings.Join(hosts, "\n"),
+	}, nil
+}
+
+// dumpClusterMetadata records the install metadata in the sealer-system
+// namespace, next to the admin.conf secret in kube-system, so the facts of a
+// running cluster stay queryable without the machine that installed it.
+func (k *Runtime) dumpClusterMetadata(driver runtime.Driver) error {
+	data, err := k.clusterMetadata()
+	if err != nil {
+		return err
+	}
+
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: SealerSystemNamespace,
+		},
+	}
+	if err := driver.Create(context.Background(), namespace, &runtimeClient.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("unable to create namespace %s: %v", SealerSystemNamespace, err)
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ClusterMetadataConfigMapName,
+			Namespace: SealerSystemNamespace,
+		},
+		Data: data,
+	}
+	if err := driver.Create(context.Background(), configMap, &runtimeClient.CreateOptions{}); err != nil {
+		if !apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("unable to create configmap %s: %v", ClusterMetadataConfigMapName, err)
+		}
+		if err := driver.Update(context.Background(), configMap, &runtimeClient.UpdateOptions{}); err != nil {
+			return fmt.Errorf("unable to update configmap %s: %v", ClusterMetadataConfigMapName, err)
+		}
+	}
+
+	return nil
+}
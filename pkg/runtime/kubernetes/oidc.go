@@ -0,0 +1,91 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/sealerio/sealer/common"
+	"github.com/sealerio/sealer/pkg/runtime/kubernetes/kubeadm"
+)
+
+// oidcIssuerURL reads the OIDCIssuerURL env, "" when OIDC is not requested.
+func (k *Runtime) oidcIssuerURL() string {
+	return k.infra.GetClusterEnv()[common.EnvOIDCIssuerURL]
+}
+
+// applyOIDCToAPIServer wires the OIDC envs of the Clusterfile into the
+// apiserver so enterprise IdP tokens authenticate from cluster creation on.
+// A kubeadm.yml that already configures an OIDC issuer wins, sealer then
+// changes nothing.
+func (k *Runtime) applyOIDCToAPIServer(conf *kubeadm.KubeadmConfig) error {
+	issuer := k.oidcIssuerURL()
+	if issuer == "" {
+		return nil
+	}
+
+	if _, ok := conf.ClusterConfiguration.APIServer.ExtraArgs["oidc-issuer-url"]; ok {
+		logrus.Debugf("kubeadm config already wires an OIDC issuer, leaving it untouched")
+		return nil
+	}
+
+	if !strings.HasPrefix(issuer, "https://") {
+		return fmt.Errorf("env %s(%s) must be an https:// URL, the apiserver refuses plain http issuers", common.EnvOIDCIssuerURL, issuer)
+	}
+	env := k.infra.GetClusterEnv()
+	clientID := env[common.EnvOIDCClientID]
+	if clientID == "" {
+		return fmt.Errorf("env %s is required when %s is set", common.EnvOIDCClientID, common.EnvOIDCIssuerURL)
+	}
+
+	args := map[string]string{}
+	for key, value := range conf.ClusterConfiguration.APIServer.ExtraArgs {
+		args[key] = value
+	}
+	args["oidc-issuer-url"] = issuer
+	args["oidc-client-id"] = clientID
+	if claim := env[common.EnvOIDCUsernameClaim]; claim != "" {
+		args["oidc-username-claim"] = claim
+	}
+	if prefix := env[common.EnvOIDCUsernamePrefix]; prefix != "" {
+		args["oidc-username-prefix"] = prefix
+	}
+	if claim := env[common.EnvOIDCGroupsClaim]; claim != "" {
+		args["oidc-groups-claim"] = claim
+	}
+	conf.ClusterConfiguration.APIServer.ExtraArgs = args
+
+	return nil
+}
+
+// logOIDCUsageHint tells the operator how to hand out OIDC based kubeconfigs
+// once the cluster serves: the admin.conf sealer generates keeps its client
+// certificate, human users get a token credential against the configured IdP.
+func (k *Runtime) logOIDCUsageHint() {
+	issuer := k.oidcIssuerURL()
+	if issuer == "" {
+		return
+	}
+	clientID := k.infra.GetClusterEnv()[common.EnvOIDCClientID]
+	logrus.Infof(`the apiserver accepts OIDC tokens from %s. Add an IdP backed user to a kubeconfig with:
+  kubectl config set-credentials oidc-user \
+    --exec-api-version=client.authentication.k8s.io/v1beta1 \
+    --exec-command=kubectl \
+    --exec-arg=oidc-login,get-token,--oidc-issuer-url=%s,--oidc-client-id=%s
+and bind the user or its groups via RBAC; the generated admin.conf keeps its client certificate.`, issuer, issuer, clientID)
+}
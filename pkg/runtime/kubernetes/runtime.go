@@ -22,6 +22,8 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
@@ -33,6 +35,7 @@ import (
 	"github.com/sealerio/sealer/common"
 	containerruntime "github.com/sealerio/sealer/pkg/container-runtime"
 	"github.com/sealerio/sealer/pkg/infradriver"
+	"github.com/sealerio/sealer/pkg/progress"
 	"github.com/sealerio/sealer/pkg/registry"
 	"github.com/sealerio/sealer/pkg/runtime"
 	"github.com/sealerio/sealer/pkg/runtime/kubernetes/kubeadm"
@@ -47,6 +50,10 @@ type Config struct {
 	containerRuntimeInfo         containerruntime.Info
 	KubeadmConfigFromClusterFile kubeadm.KubeadmConfig
 	APIServerDomain              string
+	// MaxJoinFailures is the number of worker join failures tolerated
+	// before the whole apply is aborted. 0 keeps the historical
+	// fail-fast behavior.
+	MaxJoinFailures int
 }
 
 // Runtime struct is the runtime interface for kubernetes
@@ -71,12 +78,45 @@ func NewKubeadmRuntime(clusterFileKubeConfig kubeadm.KubeadmConfig, infra infrad
 		k.Config.VIP = common.DefaultVIPForIPv6
 	}
 
+	var userSetVIP bool
 	if ipv4, ok := infra.GetClusterEnv()[common.EnvIPvsVIPForIPv4]; ok {
 		k.Config.VIP = ipv4
+		userSetVIP = true
 	}
 
 	if ipv6, ok := infra.GetClusterEnv()[common.EnvIPvsVIPForIPv6]; ok {
 		k.Config.VIP = ipv6
+		userSetVIP = true
+	}
+
+	// basic networking parameters can be given as cluster env instead
+	// of a full kubeadm ClusterConfiguration; an explicit kubeadm
+	// config still wins
+	networking := &k.Config.KubeadmConfigFromClusterFile.ClusterConfiguration.Networking
+	if podCIDR, ok := infra.GetClusterEnv()[common.EnvPodCIDR]; ok && networking.PodSubnet == "" {
+		networking.PodSubnet = podCIDR
+	}
+	if svcCIDR, ok := infra.GetClusterEnv()[common.EnvSvcCIDR]; ok && networking.ServiceSubnet == "" {
+		networking.ServiceSubnet = svcCIDR
+	}
+	if dnsDomain, ok := infra.GetClusterEnv()[common.EnvDNSDomain]; ok && networking.DNSDomain == "" {
+		networking.DNSDomain = dnsDomain
+	}
+
+	// only a user chosen VIP is validated: the defaults predate the check
+	// and changing their behavior would break existing clusters
+	if userSetVIP {
+		if err := validateVIP(k.Config.VIP, infra.GetHostIPList(), networking.PodSubnet, networking.ServiceSubnet); err != nil {
+			return nil, err
+		}
+	}
+
+	if v, ok := infra.GetClusterEnv()[common.EnvMaxJoinFailures]; ok {
+		maxJoinFailures, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid env %s(%s): %v", common.EnvMaxJoinFailures, v, err)
+		}
+		k.Config.MaxJoinFailures = maxJoinFailures
 	}
 
 	if logrus.GetLevel() == logrus.DebugLevel {
@@ -86,6 +126,38 @@ func NewKubeadmRuntime(clusterFileKubeConfig kubeadm.KubeadmConfig, infra infrad
 	return k, nil
 }
 
+// validateVIP checks a user chosen ipvs VIP: it must parse as an address of
+// the cluster ip family and stay outside the pod and service CIDRs, where
+// CNI and kube-proxy routing would capture the lvscare virtual server.
+func validateVIP(vip string, hosts []net.IP, cidrs ...string) error {
+	ip := net.ParseIP(vip)
+	if ip == nil {
+		return fmt.Errorf("invalid ipvs VIP(%s): not an ip address", vip)
+	}
+
+	if len(hosts) > 0 && k8snet.IsIPv6(hosts[0]) != k8snet.IsIPv6(ip) {
+		return fmt.Errorf("invalid ipvs VIP(%s): ip family differs from the cluster hosts", vip)
+	}
+
+	for _, cidr := range cidrs {
+		if cidr == "" {
+			continue
+		}
+		// dual-stack subnets are given comma separated
+		for _, c := range strings.Split(cidr, ",") {
+			_, ipNet, err := net.ParseCIDR(strings.TrimSpace(c))
+			if err != nil {
+				continue
+			}
+			if ipNet.Contains(ip) {
+				return fmt.Errorf("invalid ipvs VIP(%s): inside CIDR %s, pick an address outside the pod and service networks", vip, strings.TrimSpace(c))
+			}
+		}
+	}
+
+	return nil
+}
+
 func (k *Runtime) Install() error {
 	masters := k.infra.GetHostIPListByRole(common.MASTER)
 	workers := k.infra.GetHostIPListByRole(common.NODE)
@@ -99,6 +171,13 @@ func (k *Runtime) Install() error {
 		return err
 	}
 
+	// external etcd must serve before kubeadm init points the apiservers at it
+	if etcdHosts := k.getEtcdHosts(); len(etcdHosts) > 0 {
+		if err = k.installExternalEtcd(etcdHosts); err != nil {
+			return err
+		}
+	}
+
 	if err = k.createKubeConfig(masters[0]); err != nil {
 		return err
 	}
@@ -107,10 +186,16 @@ func (k *Runtime) Install() error {
 		return err
 	}
 
+	reporter := progress.Default()
+	reporter.PhaseStarted(progress.PhaseInitMaster0, 1)
 	token, certKey, err := k.initMaster0(masters[0])
 	if err != nil {
+		reporter.HostFailed(progress.PhaseInitMaster0, masters[0].String(), err)
+		reporter.PhaseFinished(progress.PhaseInitMaster0, err)
 		return err
 	}
+	reporter.HostSucceeded(progress.PhaseInitMaster0, masters[0].String())
+	reporter.PhaseFinished(progress.PhaseInitMaster0, nil)
 
 	if err = k.joinMasters(masters[1:], masters[0], kubeadmConf, token, certKey); err != nil {
 		return err
@@ -120,6 +205,10 @@ func (k *Runtime) Install() error {
 		return err
 	}
 
+	if err = k.applyCISFilePermissions(masters, workers); err != nil {
+		return err
+	}
+
 	driver, err := k.GetCurrentRuntimeDriver()
 	if err != nil {
 		return err
@@ -129,6 +218,12 @@ func (k *Runtime) Install() error {
 		return err
 	}
 
+	if err := k.dumpClusterMetadata(driver); err != nil {
+		return err
+	}
+
+	k.logOIDCUsageHint()
+
 	logrus.Info("succeeded in creating a new cluster.")
 	return nil
 }
@@ -144,7 +239,14 @@ func (k *Runtime) Upgrade() error {
 func (k *Runtime) Reset() error {
 	masters := k.infra.GetHostIPListByRole(common.MASTER)
 	workers := k.infra.GetHostIPListByRole(common.NODE)
-	return k.reset(masters, workers)
+	if err := k.reset(masters, workers); err != nil {
+		return err
+	}
+
+	if etcdHosts := k.getEtcdHosts(); len(etcdHosts) > 0 {
+		return k.cleanExternalEtcd(etcdHosts)
+	}
+	return nil
 }
 
 func (k *Runtime) ScaleUp(newMasters, newWorkers []net.IP) error {
@@ -176,6 +278,10 @@ func (k *Runtime) ScaleUp(newMasters, newWorkers []net.IP) error {
 		return err
 	}
 
+	if err = k.applyCISFilePermissions(newMasters, newWorkers); err != nil {
+		return err
+	}
+
 	logrus.Info("cluster scale up succeeded!")
 	return nil
 }
@@ -18,7 +18,8 @@ import (
 	"context"
 	"fmt"
 	"net"
-	"path"
+	"os"
+	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
@@ -28,6 +29,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/kubernetes/cmd/kubeadm/app/apis/kubeadm/v1beta3"
 	runtimeClient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/sealerio/sealer/common"
@@ -70,11 +72,46 @@ rm -rf /var/lib/etcd/* && rm -rf /var/etcd/* && rm -rf /root/.kube/config
 	RemoteRemoveAPIServerEtcHost = "echo \"$(sed \"/%s/d\" /etc/hosts)\" > /etc/hosts"
 	KubeDeleteNode               = "kubectl delete node %s"
 
+	// RemoteCleanSealerArtifacts removes what sealer itself laid down on a host
+	// besides the kubernetes binaries: lvscare static pods, leftover ipvs
+	// rules and the registry certificates under the container runtime certs.d dirs.
+	RemoteCleanSealerArtifacts = `rm -f /etc/kubernetes/manifests/kube-lvscare.yaml /etc/kubernetes/manifests/reg-lvscare.yaml /etc/kubernetes/manifests/kube-vip.yaml && \
+if command -v ipvsadm > /dev/null 2>&1; then ipvsadm -C > /dev/null 2>&1 || true; fi%s
+`
+	// RemoteCheckCleanResult prints every well-known artifact that survived the
+	// cleanup, one path per line, so the caller can report what still needs
+	// manual scrubbing before the host is reused.
+	RemoteCheckCleanResult = `for f in /etc/kubernetes /etc/systemd/system/kubelet.service /etc/systemd/system/kubelet.service.d /usr/bin/kubelet /usr/bin/kubeadm /etc/cni /opt/cni %s; do if [ -e "$f" ]; then echo "$f"; fi; done`
+
+	// RemoteGetInterfaceIP prints the first global address of the given
+	// family on the given interface, used to resolve the NodeInterface env
+	// into a node ip on the host itself.
+	RemoteGetInterfaceIP = `ip -o %s addr show dev %s scope global | head -1 | awk '{print $4}' | cut -d/ -f1`
+
 	RemoteCheckRoute = "seautil route check --host %s"
 	RemoteAddRoute   = "seautil route add --host %s --gateway %s"
 	RemoteDelRoute   = "if command -v seautil > /dev/null 2>&1; then seautil route del --host %s --gateway %s; fi"
 )
 
+// KubeadmPatchesDirName is the directory under rootfs/etc carrying kubeadm
+// patch files (kubeadm's --patches mechanism), named like
+// "kube-apiserver+json.json" or "etcd0.yaml". An image ships them in the
+// rootfs; a Clusterfile adds or overrides them through a Config object
+// writing below etc/kubeadm-patches. The rootfs is distributed to every
+// host, so the directory is in place before kubeadm runs.
+const KubeadmPatchesDirName = "kubeadm-patches"
+
+// getKubeadmPatchesDir returns the kubeadm patches directory of this cluster,
+// or "" when neither the image nor the Clusterfile provides patch files.
+func (k *Runtime) getKubeadmPatchesDir() string {
+	dir := filepath.Join(k.infra.GetClusterRootfsPath(), "etc", KubeadmPatchesDirName)
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) == 0 {
+		return ""
+	}
+	return dir
+}
+
 // StaticFile :static file should not be template, will never be changed while initialization.
 type StaticFile struct {
 	DestinationDir string
@@ -139,6 +176,13 @@ func (k *Runtime) Command(name CommandType, nodeNameOverride string) (string, er
 	if nodeNameOverride != "" {
 		v = fmt.Sprintf("%s --node-name %s", v, nodeNameOverride)
 	}
+	// control-plane customizations beyond what kubeadm.yml can express, e.g.
+	// kube-apiserver extra volumes, are applied through kubeadm patch files
+	if name == InitMaster || name == JoinMaster {
+		if patchesDir := k.getKubeadmPatchesDir(); patchesDir != "" {
+			v = fmt.Sprintf("%s --patches %s", v, patchesDir)
+		}
+	}
 
 	if runtime.IsInContainer() {
 		return fmt.Sprintf("%s%s%s", v, vlogToStr(k.Config.Vlog), " --ignore-preflight-errors=all"), nil
@@ -158,6 +202,54 @@ func (k *Runtime) getNodeNameOverride(ip net.IP) string {
 	return ""
 }
 
+// getNodeIPOverride returns the address kubelet should register as node ip
+// and kubeadm should advertise on the given host, for multi-NIC servers
+// where the ssh address is not on the cluster network. The per-host NodeIP
+// env wins; otherwise NodeInterface names the NIC and the address is
+// resolved on the host. A nil return without error means no override.
+func (k *Runtime) getNodeIPOverride(host net.IP) (net.IP, error) {
+	hostEnv := k.infra.GetHostEnv(host)
+
+	if v, ok := hostEnv[common.EnvNodeIP]; ok && v != "" {
+		ip := net.ParseIP(v)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid env %s(%s) on host %s: not an ip address", common.EnvNodeIP, v, host)
+		}
+		return ip, nil
+	}
+
+	iface, ok := hostEnv[common.EnvNodeInterface]
+	if !ok || iface == "" {
+		return nil, nil
+	}
+
+	family := "-4"
+	if host.To4() == nil {
+		family = "-6"
+	}
+	output, err := k.infra.CmdToString(host, nil, fmt.Sprintf(RemoteGetInterfaceIP, family, iface), "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve interface %s on host %s: %v", iface, host, err)
+	}
+	ip := net.ParseIP(strings.TrimSpace(output))
+	if ip == nil {
+		return nil, fmt.Errorf("interface %s on host %s has no global address of the cluster ip family", iface, host)
+	}
+	return ip, nil
+}
+
+// applyNodeIPOverride renders the node ip into the kubelet extra args of
+// the given node registration. The extra args map is copied first, since
+// one kubeadm config is shared between the hosts of a join round.
+func applyNodeIPOverride(reg *v1beta3.NodeRegistrationOptions, nodeIP net.IP) {
+	args := map[string]string{}
+	for key, value := range reg.KubeletExtraArgs {
+		args[key] = value
+	}
+	args["node-ip"] = nodeIP.String()
+	reg.KubeletExtraArgs = args
+}
+
 func GetClientFromConfig(adminConfPath string) (runtimeClient.Client, error) {
 	adminConfig, err := clientcmd.BuildConfigFromFlags("", adminConfPath)
 	if nil != err {
@@ -187,7 +279,11 @@ func GetClientFromConfig(adminConfPath string) (runtimeClient.Client, error) {
 }
 
 func (k *Runtime) configureLvs(masterHosts, clientHosts []net.IP) error {
-	lvsImageURL := path.Join(k.Config.RegistryInfo.URL, common.LvsCareRepoAndTag)
+	if k.vipMode() == common.VIPModeKubeVIP {
+		return k.configureKubeVIP(masterHosts, clientHosts)
+	}
+
+	lvsImageURL := ipvs.GetLvscareImage(k.infra.GetClusterRootfsPath(), k.infra.GetClusterEnv(), k.Config.RegistryInfo.URL)
 
 	var rs []string
 	var realEndpoints []string
@@ -201,7 +297,7 @@ func (k *Runtime) configureLvs(masterHosts, clientHosts []net.IP) error {
 	}
 	vs := net.JoinHostPort(k.getAPIServerVIP().String(), "6443")
 	ipvsCmd := fmt.Sprintf("seautil ipvs --vs %s %s --health-path /healthz --health-schem https --run-once", vs, strings.Join(rs, " "))
-	y, err := ipvs.LvsStaticPodYaml(common.KubeLvsCareStaticPodName, vs, realEndpoints, lvsImageURL,
+	y, err := ipvs.LvsStaticPodYamlWithTemplate(k.infra.GetClusterRootfsPath(), common.KubeLvsCareStaticPodName, vs, realEndpoints, lvsImageURL,
 		"/healthz", "https")
 	if err != nil {
 		return err
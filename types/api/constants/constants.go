@@ -19,4 +19,6 @@ const (
 	PluginKind      = "Plugin"
 	ConfigKind      = "Config"
 	ApplicationKind = "Application"
+	SecretKind      = "Secret"
+	UserKind        = "User"
 )
@@ -0,0 +1,91 @@
+/*
+Copyright 2023 Alibaba Group.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SecretSpec defines the desired state of Secret
+type SecretSpec struct {
+	// Values are the entries of this secret. Each resolved value is exposed
+	// to Config/App rendering as env data under its Name.
+	Values []SecretValue `json:"values,omitempty"`
+}
+
+// SecretValue is one secret entry. Exactly one of Value, ValueFromEnv,
+// ValueFromFile or ValueFromVault must be set.
+type SecretValue struct {
+	// Name the resolved value is referenced by from Config data and
+	// application templates.
+	Name string `json:"name"`
+	// Value is a literal. It is encrypted in place before the Clusterfile
+	// is persisted, like the ssh credentials.
+	Value string `json:"value,omitempty"`
+	// Encrypted marks that Value already went through encryption.
+	Encrypted bool `json:"encrypted,omitempty"`
+	// ValueFromEnv reads the value from an env var on the machine running
+	// sealer at apply time.
+	ValueFromEnv string `json:"valueFromEnv,omitempty"`
+	// ValueFromFile reads the value from a local file at apply time.
+	ValueFromFile string `json:"valueFromFile,omitempty"`
+	// ValueFromVault fetches the value from a HashiCorp Vault KV store at
+	// apply time; nothing secret is ever written to the Clusterfile.
+	ValueFromVault *VaultValueRef `json:"valueFromVault,omitempty"`
+}
+
+// VaultValueRef points at one key of a Vault KV secret.
+type VaultValueRef struct {
+	// Address of the Vault server, e.g. https://vault.corp.com:8200.
+	Address string `json:"address"`
+	// Path of the secret below /v1/, e.g. secret/data/myapp.
+	Path string `json:"path"`
+	// Key inside the secret data.
+	Key string `json:"key"`
+	// TokenEnv names the env var holding the Vault token, default VAULT_TOKEN.
+	TokenEnv string `json:"tokenEnv,omitempty"`
+}
+
+// SecretStatus defines the observed state of Secret
+type SecretStatus struct{}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Secret is the Schema for the secrets API
+type Secret struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SecretSpec   `json:"spec,omitempty"`
+	Status SecretStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SecretList contains a list of Secret
+type SecretList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Secret `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Secret{}, &SecretList{})
+}
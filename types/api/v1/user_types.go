@@ -0,0 +1,74 @@
+/*
+Copyright 2023 Alibaba Group.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// User auth modes.
+const (
+	// UserAuthCert (the default) authenticates with a client certificate
+	// signed by the cluster CA; groups become certificate organizations.
+	UserAuthCert = "cert"
+	// UserAuthToken authenticates with a service account token; the account
+	// lives in kube-system under the user name.
+	UserAuthToken = "token"
+)
+
+// UserSpec defines the desired state of User
+type UserSpec struct {
+	// Groups the user belongs to, usable as RBAC subjects. Only meaningful
+	// with cert auth, where they become certificate organizations.
+	Groups []string `json:"groups,omitempty"`
+	// Auth mode, "cert" (default) or "token".
+	Auth string `json:"auth,omitempty"`
+	// ClusterRoles bound to the user right after install, e.g. view or edit.
+	ClusterRoles []string `json:"clusterRoles,omitempty"`
+}
+
+// UserStatus defines the observed state of User
+type UserStatus struct{}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// User declares an initial cluster user sealer creates at install time: a
+// credential, a kubeconfig on the machine running sealer and the RBAC
+// bindings, so day-one access needs no shared cluster-admin kubeconfig.
+type User struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   UserSpec   `json:"spec,omitempty"`
+	Status UserStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// UserList contains a list of User
+type UserList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []User `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&User{}, &UserList{})
+}
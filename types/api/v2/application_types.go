@@ -55,10 +55,32 @@ type ApplicationConfig struct {
 	// app Launch customization
 	Launch *Launch `json:"launch,omitempty"`
 
+	// ReadinessGates are evaluated after the app launch commands finished,
+	// the launch only succeeds when every gate passes within its timeout.
+	ReadinessGates []ReadinessGate `json:"readinessGates,omitempty"`
+
 	// app Delete customization
 	//Delete *Delete `json:"delete,omitempty"`
 }
 
+// ReadinessGate describes one readiness check of an app.
+// Exactly one of KubectlWait, RolloutStatus and HTTPGet must be set.
+type ReadinessGate struct {
+	// KubectlWait is a raw kubectl wait expression,
+	// e.g. "--for=condition=Available deployment/nginx -n default".
+	KubectlWait string `json:"kubectlWait,omitempty"`
+
+	// RolloutStatus is a resource watched via kubectl rollout status,
+	// e.g. "deployment/nginx -n default".
+	RolloutStatus string `json:"rolloutStatus,omitempty"`
+
+	// HTTPGet is a URL probed from master0 until it answers successfully.
+	HTTPGet string `json:"httpGet,omitempty"`
+
+	// TimeoutSeconds bounds the gate evaluation, default is 300 seconds.
+	TimeoutSeconds int `json:"timeoutSeconds,omitempty"`
+}
+
 type Strategy string
 
 const (
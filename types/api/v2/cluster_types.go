@@ -50,18 +50,57 @@ type ClusterSpec struct {
 	// Registry field contains configurations about local registry and remote registry.
 	Registry Registry `json:"registry,omitempty"`
 
+	// Certs distributes additional trust material to every node.
+	Certs Certs `json:"certs,omitempty"`
+
 	// DataRoot set sealer rootfs directory path.
 	// if not set, default value is "/var/lib/sealer/data"
 	DataRoot string `json:"dataRoot,omitempty"`
+
+	// Proxy propagates corporate proxy settings to every node: the values
+	// are exported into the remote shell env of all sealer commands and
+	// rendered into the docker/containerd systemd drop-ins.
+	Proxy Proxy `json:"proxy,omitempty"`
+}
+
+// Proxy holds the cluster wide http proxy configuration. NoProxy is
+// extended by sealer with the node IPs and the local registry domain so
+// in-cluster traffic never goes through the proxy.
+type Proxy struct {
+	HTTPProxy  string `json:"httpProxy,omitempty"`
+	HTTPSProxy string `json:"httpsProxy,omitempty"`
+	NoProxy    string `json:"noProxy,omitempty"`
 }
 
 type ContainerRuntimeConfig struct {
 	Type string `json:"type,omitempty"`
+	// Mirrors maps a registry domain to its mirror endpoints. For docker it
+	// is rendered into the registry-mirrors field of daemon.json (docker.io
+	// only), for containerd into a certs.d/<domain>/hosts.toml per domain.
+	Mirrors map[string][]string `json:"mirrors,omitempty"`
+	// InsecureRegistries lists registries that are served over plain HTTP
+	// or with certificates the nodes do not trust.
+	InsecureRegistries []string `json:"insecureRegistries,omitempty"`
+}
+
+// Certs holds user provided certificates that sealer installs on the nodes.
+type Certs struct {
+	// ExtraCAs are paths to PEM encoded CA certificates on the machine
+	// running sealer. Each one is installed into the system trust store of
+	// every node; a file named after a registry domain (for example
+	// harbor.corp.com.crt) is additionally placed into the container
+	// runtime certs.d directory for that domain.
+	ExtraCAs []string `json:"extraCAs,omitempty"`
 }
 
 type Host struct {
-	IPS   []net.IP `json:"ips,omitempty"`
-	Roles []string `json:"roles,omitempty"`
+	IPS []net.IP `json:"ips,omitempty"`
+	// ExternalIPS are the addresses sealer dials over ssh when the hosts sit
+	// behind NAT, aligned index by index with IPS. The IPS entries stay the
+	// internal addresses the hosts see themselves, which kubeadm, etcd and
+	// ipvs keep using. Leave it empty when the hosts are reachable directly.
+	ExternalIPS []net.IP `json:"externalIPs,omitempty"`
+	Roles       []string `json:"roles,omitempty"`
 	//overwrite SSH config
 	SSH v1.SSH `json:"ssh,omitempty"`
 	//overwrite env
@@ -206,6 +206,8 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		}
 	}
 	out.SSH = in.SSH
+	in.ContainerRuntime.DeepCopyInto(&out.ContainerRuntime)
+	in.Certs.DeepCopyInto(&out.Certs)
 	return
 }
 
@@ -235,6 +237,60 @@ func (in *ClusterStatus) DeepCopy() *ClusterStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Certs) DeepCopyInto(out *Certs) {
+	*out = *in
+	if in.ExtraCAs != nil {
+		in, out := &in.ExtraCAs, &out.ExtraCAs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Certs.
+func (in *Certs) DeepCopy() *Certs {
+	if in == nil {
+		return nil
+	}
+	out := new(Certs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerRuntimeConfig) DeepCopyInto(out *ContainerRuntimeConfig) {
+	*out = *in
+	if in.Mirrors != nil {
+		in, out := &in.Mirrors, &out.Mirrors
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val != nil {
+				outVal = make([]string, len(val))
+				copy(outVal, val)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.InsecureRegistries != nil {
+		in, out := &in.InsecureRegistries, &out.InsecureRegistries
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerRuntimeConfig.
+func (in *ContainerRuntimeConfig) DeepCopy() *ContainerRuntimeConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerRuntimeConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Host) DeepCopyInto(out *Host) {
 	*out = *in
@@ -243,6 +299,11 @@ func (in *Host) DeepCopyInto(out *Host) {
 		*out = make([]net.IP, len(*in))
 		copy(*out, *in)
 	}
+	if in.ExternalIPS != nil {
+		in, out := &in.ExternalIPS, &out.ExternalIPS
+		*out = make([]net.IP, len(*in))
+		copy(*out, *in)
+	}
 	if in.Roles != nil {
 		in, out := &in.Roles, &out.Roles
 		*out = make([]string, len(*in))
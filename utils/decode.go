@@ -17,6 +17,7 @@ package utils
 import (
 	"bufio"
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -201,18 +202,112 @@ func decodePluginListFunc(reader io.Reader) (interface{}, error) {
 }
 
 func decodeInitConfigurationFunc(reader io.Reader) (out interface{}, err error) {
-	switchVersion := func(version string) interface{} { return &v1beta3.InitConfiguration{} }
-	return decodeCRDFromReader(NewK8sYamlDecoder(reader), kubeadmConstants.InitConfigurationKind, switchVersion)
+	return decodeKubeadmCRDFromReader(NewK8sYamlDecoder(reader), kubeadmConstants.InitConfigurationKind, func() interface{} { return &v1beta3.InitConfiguration{} })
 }
 
 func decodeJoinConfigurationFunc(reader io.Reader) (out interface{}, err error) {
-	switchVersion := func(version string) interface{} { return &v1beta3.JoinConfiguration{} }
-	return decodeCRDFromReader(NewK8sYamlDecoder(reader), kubeadmConstants.JoinConfigurationKind, switchVersion)
+	return decodeKubeadmCRDFromReader(NewK8sYamlDecoder(reader), kubeadmConstants.JoinConfigurationKind, func() interface{} { return &v1beta3.JoinConfiguration{} })
 }
 
 func decodeClusterConfigurationFunc(reader io.Reader) (out interface{}, err error) {
-	switchVersion := func(version string) interface{} { return &v1beta3.ClusterConfiguration{} }
-	return decodeCRDFromReader(NewK8sYamlDecoder(reader), kubeadmConstants.ClusterConfigurationKind, switchVersion)
+	return decodeKubeadmCRDFromReader(NewK8sYamlDecoder(reader), kubeadmConstants.ClusterConfigurationKind, func() interface{} { return &v1beta3.ClusterConfiguration{} })
+}
+
+const kubeadmV1beta4 = "kubeadm.k8s.io/v1beta4"
+
+// kubeadmExtraArgsPaths lists where kubeadm configurations carry
+// component arguments, serialized as maps up to v1beta3 and as
+// name/value lists since v1beta4.
+var kubeadmExtraArgsPaths = [][]string{
+	{"apiServer", "extraArgs"},
+	{"controllerManager", "extraArgs"},
+	{"scheduler", "extraArgs"},
+	{"etcd", "local", "extraArgs"},
+	{"nodeRegistration", "kubeletExtraArgs"},
+}
+
+// decodeKubeadmCRDFromReader works like decodeCRDFromReader, but
+// accepts any supported kubeadm apiVersion and converts the document to
+// the v1beta3 shape the internal types use. The version required by the
+// target Kubernetes release is re-applied at render time.
+func decodeKubeadmCRDFromReader(decoder *yaml.YAMLToJSONDecoder, kind string, newObj func() interface{}) (interface{}, error) {
+	for {
+		ext := runtime.RawExtension{}
+		if err := decoder.Decode(&ext); err != nil {
+			return nil, err
+		}
+		ext.Raw = bytes.TrimSpace(ext.Raw)
+		if len(ext.Raw) == 0 || bytes.Equal(ext.Raw, []byte("null")) {
+			continue
+		}
+		metaType := metav1.TypeMeta{}
+		if err := yaml.Unmarshal(ext.Raw, &metaType); err != nil {
+			return nil, fmt.Errorf("failed to decode cluster: %v", err)
+		}
+		if metaType.Kind != kind {
+			continue
+		}
+
+		raw := ext.Raw
+		if metaType.APIVersion == kubeadmV1beta4 {
+			converted, err := convertKubeadmV1beta4Raw(raw)
+			if err != nil {
+				return nil, fmt.Errorf("failed to convert %s[%s]: %v", metaType.Kind, metaType.APIVersion, err)
+			}
+			raw = converted
+		}
+
+		in := newObj()
+		if err := yaml.Unmarshal(raw, in); err != nil {
+			return nil, fmt.Errorf("failed to decode %s[%s]: %v", metaType.Kind, metaType.APIVersion, err)
+		}
+		return in, nil
+	}
+}
+
+// convertKubeadmV1beta4Raw rewrites a v1beta4 kubeadm document to the
+// v1beta3 shape by turning the name/value argument lists back into
+// maps.
+func convertKubeadmV1beta4Raw(raw []byte) ([]byte, error) {
+	doc := map[string]interface{}{}
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	for _, path := range kubeadmExtraArgsPaths {
+		convertKubeadmArgsListToMap(doc, path)
+	}
+	return json.Marshal(doc)
+}
+
+func convertKubeadmArgsListToMap(doc map[string]interface{}, path []string) {
+	parent := doc
+	for _, field := range path[:len(path)-1] {
+		next, ok := parent[field].(map[string]interface{})
+		if !ok {
+			return
+		}
+		parent = next
+	}
+
+	field := path[len(path)-1]
+	list, ok := parent[field].([]interface{})
+	if !ok {
+		return
+	}
+
+	args := map[string]interface{}{}
+	for _, item := range list {
+		arg, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, ok := arg["name"].(string)
+		if !ok {
+			continue
+		}
+		args[name] = arg["value"]
+	}
+	parent[field] = args
 }
 
 func decodeKubeletConfigurationFunc(reader io.Reader) (out interface{}, err error) {
@@ -22,13 +22,20 @@ import (
 	"fmt"
 )
 
-const aesKey = "ZU9WbzRMVXRQZ2pzTGowR2hNWUpIZjRkWld4aWVRWko="
+// legacyAesKey is the key historically compiled into sealer. It is
+// only kept to decrypt credentials written by older versions; new
+// ciphertext always uses the per-installation key from the key file.
+const legacyAesKey = "ZU9WbzRMVXRQZ2pzTGowR2hNWUpIZjRkWld4aWVRWko="
 
 func AesEncrypt(origData []byte) (string, error) {
-	key, err := base64.StdEncoding.DecodeString(aesKey)
+	key, err := loadOrCreateSecretKey()
 	if err != nil {
-		return "", fmt.Errorf("failed to decode key base64: %v", err)
+		return "", fmt.Errorf("failed to load local aes key: %v", err)
 	}
+	return aesEncryptWithKey(origData, key)
+}
+
+func aesEncryptWithKey(origData, key []byte) (string, error) {
 	block, err := aes.NewCipher(key)
 	if err != nil {
 		return "", err
@@ -42,11 +49,22 @@ func AesEncrypt(origData []byte) (string, error) {
 }
 
 func AesDecrypt(ciphertext []byte) (string, error) {
-	key, err := base64.StdEncoding.DecodeString(aesKey)
+	if key := loadSecretKey(); key != nil {
+		plaintext, err := aesDecryptWithKey(ciphertext, key)
+		if err == nil {
+			return plaintext, nil
+		}
+	}
+	// fall back to the key compiled into older sealer versions
+	key, err := base64.StdEncoding.DecodeString(legacyAesKey)
 	if err != nil {
 		return "", fmt.Errorf("failed to decode key base64: %v", err)
 	}
-	ciphertext, err = base64.StdEncoding.DecodeString(string(ciphertext))
+	return aesDecryptWithKey(ciphertext, key)
+}
+
+func aesDecryptWithKey(ciphertext, key []byte) (string, error) {
+	ciphertext, err := base64.StdEncoding.DecodeString(string(ciphertext))
 	if err != nil {
 		return "", fmt.Errorf("failed to decode key base64: %v", err)
 	}
@@ -63,6 +81,9 @@ func AesDecrypt(ciphertext []byte) (string, error) {
 	mode := cipher.NewCBCDecrypter(block, key[:blockSize])
 	mode.CryptBlocks(plaintext, ciphertext)
 	plaintext = pkcs7UnPadding(plaintext)
+	if plaintext == nil {
+		return "", fmt.Errorf("invalid padding, ciphertext encrypted with a different key")
+	}
 	return string(plaintext), nil
 }
 
@@ -74,6 +95,12 @@ func pkcs7Padding(origData []byte, blockSize int) []byte {
 
 func pkcs7UnPadding(origData []byte) []byte {
 	length := len(origData)
+	if length == 0 {
+		return nil
+	}
 	unPadding := int(origData[length-1])
+	if unPadding < 1 || unPadding > length {
+		return nil
+	}
 	return origData[:(length - unPadding)]
 }
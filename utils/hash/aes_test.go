@@ -0,0 +1,66 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hash
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAesEncryptDecryptRoundTrip(t *testing.T) {
+	originalAesKeyFile := aesKeyFile
+	aesKeyFile = filepath.Join(t.TempDir(), "credential.key")
+	defer func() {
+		aesKeyFile = originalAesKeyFile
+	}()
+
+	ciphertext, err := AesEncrypt([]byte("Sealer123"))
+	assert.NoError(t, err)
+	assert.NotEqual(t, "Sealer123", ciphertext)
+
+	// the key file was generated on first use
+	_, err = os.Stat(aesKeyFile)
+	assert.NoError(t, err)
+
+	plaintext, err := AesDecrypt([]byte(ciphertext))
+	assert.NoError(t, err)
+	assert.Equal(t, "Sealer123", plaintext)
+}
+
+func TestAesDecryptLegacyKeyFallback(t *testing.T) {
+	originalAesKeyFile := aesKeyFile
+	aesKeyFile = filepath.Join(t.TempDir(), "credential.key")
+	defer func() {
+		aesKeyFile = originalAesKeyFile
+	}()
+
+	// ciphertext produced by older sealer versions with the built-in key
+	legacyKey, err := base64.StdEncoding.DecodeString(legacyAesKey)
+	assert.NoError(t, err)
+	ciphertext, err := aesEncryptWithKey([]byte("Sealer123"), legacyKey)
+	assert.NoError(t, err)
+
+	// generate a fresh local key, then decryption still falls back
+	_, err = loadOrCreateSecretKey()
+	assert.NoError(t, err)
+
+	plaintext, err := AesDecrypt([]byte(ciphertext))
+	assert.NoError(t, err)
+	assert.Equal(t, "Sealer123", plaintext)
+}
@@ -0,0 +1,81 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hash
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/mitchellh/go-homedir"
+)
+
+// aesKeyFile holds the per-installation AES key used to encrypt
+// credentials. It is generated on first use and kept out of the
+// Clusterfile, so persisted credentials cannot be decrypted without
+// access to this machine (or wherever the key file is copied to).
+var aesKeyFile = defaultAesKeyFile()
+
+func defaultAesKeyFile() string {
+	home, err := homedir.Dir()
+	if err != nil {
+		home = "/root"
+	}
+	return filepath.Join(home, ".sealer", "credential.key")
+}
+
+// loadOrCreateSecretKey returns the local AES key, generating and
+// persisting a fresh random one if none exists yet.
+func loadOrCreateSecretKey() ([]byte, error) {
+	data, err := os.ReadFile(filepath.Clean(aesKeyFile))
+	if err == nil {
+		key, err := base64.StdEncoding.DecodeString(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode key file %s: %v", aesKeyFile, err)
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read key file %s: %v", aesKeyFile, err)
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(aesKeyFile), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create key dir: %v", err)
+	}
+	if err := os.WriteFile(aesKeyFile, []byte(base64.StdEncoding.EncodeToString(key)), 0600); err != nil {
+		return nil, fmt.Errorf("failed to save key file %s: %v", aesKeyFile, err)
+	}
+	return key, nil
+}
+
+// loadSecretKey returns the local AES key or nil when none has been
+// generated yet.
+func loadSecretKey() []byte {
+	data, err := os.ReadFile(filepath.Clean(aesKeyFile))
+	if err != nil {
+		return nil
+	}
+	key, err := base64.StdEncoding.DecodeString(string(data))
+	if err != nil {
+		return nil
+	}
+	return key
+}
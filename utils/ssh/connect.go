@@ -30,7 +30,9 @@ import (
 	"github.com/sealerio/sealer/utils/hash"
 
 	"github.com/pkg/sftp"
+	"github.com/sirupsen/logrus"
 	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
 )
 
 const DefaultSSHPort = "22"
@@ -44,7 +46,34 @@ func (s *SSH) connect(host net.IP) (*ssh.Client, error) {
 		s.Password = passwd
 		s.Encrypted = false
 	}
-	auth := s.sshAuthMethod(s.Password, s.PkFile, s.PkPassword)
+	var (
+		addr         = host.String()
+		user         = s.User
+		port         = s.Port
+		pkFile       = s.PkFile
+		proxyCommand string
+	)
+	// per-host overrides from the user's openssh config, if enabled
+	if override := lookupSSHConfigOverride(addr); override != nil {
+		if override.HostName != "" {
+			addr = override.HostName
+		}
+		if override.User != "" {
+			user = override.User
+		}
+		if override.Port != "" {
+			port = override.Port
+		}
+		if override.IdentityFile != "" {
+			pkFile = override.IdentityFile
+		}
+		proxyCommand = override.ProxyCommand
+	}
+	if port == "" {
+		port = DefaultSSHPort
+	}
+
+	auth := s.sshAuthMethod(s.Password, pkFile, s.PkPassword)
 	config := ssh.Config{
 		Ciphers: []string{"aes128-ctr", "aes192-ctr", "aes256-ctr", "aes128-gcm@openssh.com", "arcfour256", "arcfour128", "aes128-cbc", "3des-cbc", "aes192-cbc", "aes256-cbc"},
 	}
@@ -53,18 +82,18 @@ func (s *SSH) connect(host net.IP) (*ssh.Client, error) {
 		s.Timeout = &DefaultTimeout
 	}
 	clientConfig := &ssh.ClientConfig{
-		User:    s.User,
-		Auth:    auth,
-		Timeout: *s.Timeout,
-		Config:  config,
-		HostKeyCallback: func(hostname string, remote net.Addr, key ssh.PublicKey) error {
-			return nil
-		},
-	}
-	if s.Port == "" {
-		s.Port = DefaultSSHPort
-	}
-	return ssh.Dial("tcp", net.JoinHostPort(host.String(), s.Port), clientConfig)
+		User:            user,
+		Auth:            auth,
+		Timeout:         *s.Timeout,
+		Config:          config,
+		HostKeyCallback: hostKeyCallback(),
+	}
+
+	endpoint := net.JoinHostPort(addr, port)
+	if proxyCommand != "" {
+		return dialThroughProxy(expandSSHTokens(proxyCommand, addr, port, user), endpoint, clientConfig)
+	}
+	return ssh.Dial("tcp", endpoint, clientConfig)
 }
 
 func (s *SSH) Connect(host net.IP) (*ssh.Client, *ssh.Session, error) {
@@ -95,6 +124,9 @@ func (s *SSH) Connect(host net.IP) (*ssh.Client, *ssh.Session, error) {
 }
 
 func (s *SSH) sshAuthMethod(password, pkFile, pkPasswd string) (auth []ssh.AuthMethod) {
+	if am := sshAgentMethod(); am != nil {
+		auth = append(auth, am)
+	}
 	if fileExist(pkFile) {
 		am, err := s.sshPrivateKeyMethod(pkFile, pkPasswd)
 		if err == nil {
@@ -107,6 +139,21 @@ func (s *SSH) sshAuthMethod(password, pkFile, pkPasswd string) (auth []ssh.AuthM
 	return auth
 }
 
+// sshAgentMethod authenticates with the keys held by the ssh-agent
+// reachable through SSH_AUTH_SOCK, if any.
+func sshAgentMethod() ssh.AuthMethod {
+	sock := os.Getenv("SSH_AUTH_SOCK")
+	if sock == "" {
+		return nil
+	}
+	conn, err := net.Dial("unix", sock)
+	if err != nil {
+		logrus.Warnf("failed to connect ssh agent on %s: %v", sock, err)
+		return nil
+	}
+	return ssh.PublicKeysCallback(agent.NewClient(conn).Signers)
+}
+
 // Authentication with a private key,private key has password and no password to verify in this
 func (s *SSH) sshPrivateKeyMethod(pkFile, pkPassword string) (am ssh.AuthMethod, err error) {
 	pkData, err := os.ReadFile(filepath.Clean(pkFile))
@@ -127,6 +174,30 @@ func (s *SSH) sshPrivateKeyMethod(pkFile, pkPassword string) (am ssh.AuthMethod,
 			return nil, err
 		}
 	}
+	// honor an OpenSSH certificate sitting next to the private key
+	// (the conventional <key>-cert.pub naming), so certificate
+	// authorities can be used instead of raw public keys.
+	certFile := pkFile + "-cert.pub"
+	if fileExist(certFile) {
+		certData, err := os.ReadFile(filepath.Clean(certFile))
+		if err != nil {
+			return nil, err
+		}
+		pubKey, _, _, _, err := ssh.ParseAuthorizedKey(certData)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse ssh certificate %s: %v", certFile, err)
+		}
+		cert, ok := pubKey.(*ssh.Certificate)
+		if !ok {
+			return nil, fmt.Errorf("%s is not an ssh certificate", certFile)
+		}
+		certSigner, err := ssh.NewCertSigner(cert, pk)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build certificate signer from %s: %v", certFile, err)
+		}
+		return ssh.PublicKeys(certSigner), nil
+	}
+
 	return ssh.PublicKeys(pk), nil
 }
 
@@ -0,0 +1,46 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh
+
+import (
+	"context"
+	"sync"
+)
+
+var (
+	processCtx   = context.Background()
+	processCtxMu sync.RWMutex
+)
+
+// SetProcessContext installs the context governing all remote operations
+// of this process. The CLI wires it to Ctrl-C and the global --timeout
+// flag; once the context is done, in-flight ssh sessions are closed so
+// remote processes are not orphaned and no new commands are started.
+func SetProcessContext(ctx context.Context) {
+	if ctx == nil {
+		return
+	}
+	processCtxMu.Lock()
+	defer processCtxMu.Unlock()
+	processCtx = ctx
+}
+
+// ProcessContext returns the context governing remote operations,
+// context.Background() unless SetProcessContext was called.
+func ProcessContext() context.Context {
+	processCtxMu.RLock()
+	defer processCtxMu.RUnlock()
+	return processCtx
+}
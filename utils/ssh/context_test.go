@@ -0,0 +1,49 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunCancelable(t *testing.T) {
+	defer SetProcessContext(context.Background())
+
+	// normal completion
+	err := runCancelable("test", func() error { return nil }, nil)
+	assert.NoError(t, err)
+
+	// cancellation aborts a hung command and reports the context error
+	ctx, cancel := context.WithCancel(context.Background())
+	SetProcessContext(ctx)
+
+	aborted := make(chan struct{})
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+	err = runCancelable("test", func() error {
+		<-aborted
+		return nil
+	}, func() {
+		close(aborted)
+	})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "canceled")
+}
@@ -0,0 +1,137 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ssh"
+)
+
+// Host key checking modes, set through SEALER_SSH_STRICT_HOST_KEY_CHECKING:
+//
+//	no         accept any host key and record it (historical behavior, default)
+//	accept-new accept and record keys of unknown hosts, reject changed keys
+//	yes        only accept hosts whose key is already recorded
+const (
+	hostKeyCheckingNo        = "no"
+	hostKeyCheckingAcceptNew = "accept-new"
+	hostKeyCheckingYes       = "yes"
+)
+
+// knownHostsFile is the sealer-managed known_hosts database.
+var knownHostsFile = "/var/lib/sealer/ssh/known_hosts"
+
+var knownHostsMux sync.Mutex
+
+func hostKeyCheckingMode() string {
+	switch mode := os.Getenv("SEALER_SSH_STRICT_HOST_KEY_CHECKING"); mode {
+	case "", hostKeyCheckingNo:
+		return hostKeyCheckingNo
+	case hostKeyCheckingAcceptNew, hostKeyCheckingYes:
+		return mode
+	default:
+		logrus.Warnf("invalid SEALER_SSH_STRICT_HOST_KEY_CHECKING(%s), falling back to %q", mode, hostKeyCheckingNo)
+		return hostKeyCheckingNo
+	}
+}
+
+// hostKeyCallback returns the ssh.HostKeyCallback honoring the configured
+// host key checking mode against the sealer-managed known_hosts file.
+func hostKeyCallback() ssh.HostKeyCallback {
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		mode := hostKeyCheckingMode()
+
+		knownHostsMux.Lock()
+		defer knownHostsMux.Unlock()
+
+		host := normalizeKnownHost(hostname)
+		recorded, err := lookupKnownHostKey(host)
+		if err != nil {
+			return err
+		}
+
+		current := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(key)))
+		if recorded != "" {
+			if recorded == current {
+				return nil
+			}
+			if mode == hostKeyCheckingNo {
+				logrus.Warnf("host key of %s changed, accepting anyway since host key checking is disabled", host)
+				return nil
+			}
+			return fmt.Errorf("host key of %s changed, remove the entry from %s if this is expected", host, knownHostsFile)
+		}
+
+		if mode == hostKeyCheckingYes {
+			return fmt.Errorf("unknown host %s: no key recorded in %s", host, knownHostsFile)
+		}
+		return recordKnownHostKey(host, current)
+	}
+}
+
+// normalizeKnownHost strips the port so the record survives custom sshd
+// ports.
+func normalizeKnownHost(hostname string) string {
+	if host, _, err := net.SplitHostPort(hostname); err == nil {
+		return host
+	}
+	return hostname
+}
+
+func lookupKnownHostKey(host string) (string, error) {
+	data, err := os.ReadFile(filepath.Clean(knownHostsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read known_hosts file %s: %v", knownHostsFile, err)
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 || fields[0] != host {
+			continue
+		}
+		return strings.Join(fields[1:3], " "), nil
+	}
+	return "", nil
+}
+
+func recordKnownHostKey(host, key string) error {
+	if err := os.MkdirAll(filepath.Dir(knownHostsFile), 0700); err != nil {
+		return fmt.Errorf("failed to create known_hosts dir: %v", err)
+	}
+	f, err := os.OpenFile(filepath.Clean(knownHostsFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open known_hosts file %s: %v", knownHostsFile, err)
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			logrus.Errorf("failed to close known_hosts file: %v", err)
+		}
+	}()
+
+	if _, err := fmt.Fprintf(f, "%s %s\n", host, key); err != nil {
+		return fmt.Errorf("failed to record host key of %s: %v", host, err)
+	}
+	return nil
+}
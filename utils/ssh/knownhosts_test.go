@@ -0,0 +1,50 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKnownHostsRecordAndLookup(t *testing.T) {
+	originalKnownHostsFile := knownHostsFile
+	knownHostsFile = filepath.Join(t.TempDir(), "known_hosts")
+	defer func() {
+		knownHostsFile = originalKnownHostsFile
+	}()
+
+	key, err := lookupKnownHostKey("192.168.0.2")
+	assert.NoError(t, err)
+	assert.Empty(t, key)
+
+	assert.NoError(t, recordKnownHostKey("192.168.0.2", "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIJx"))
+
+	key, err = lookupKnownHostKey("192.168.0.2")
+	assert.NoError(t, err)
+	assert.Equal(t, "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIJx", key)
+
+	// other hosts stay unknown
+	key, err = lookupKnownHostKey("192.168.0.3")
+	assert.NoError(t, err)
+	assert.Empty(t, key)
+}
+
+func TestNormalizeKnownHost(t *testing.T) {
+	assert.Equal(t, "192.168.0.2", normalizeKnownHost("192.168.0.2:22"))
+	assert.Equal(t, "192.168.0.2", normalizeKnownHost("192.168.0.2"))
+}
@@ -0,0 +1,141 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// Retry and timeout knobs for remote command execution. They are read
+// from environment variables so that CI and large fleets can tune them
+// without touching the Clusterfile:
+//
+//	SEALER_SSH_RETRY_TIMES    how often to attempt a connection (default 3)
+//	SEALER_SSH_RETRY_INTERVAL base backoff between attempts (default 1s, doubled per attempt)
+//	SEALER_SSH_CMD_TIMEOUT    per-command execution timeout (default 0: no timeout)
+const (
+	defaultConnectRetryTimes    = 3
+	defaultConnectRetryInterval = time.Second
+)
+
+func connectRetryTimes() int {
+	if v := os.Getenv("SEALER_SSH_RETRY_TIMES"); v != "" {
+		if times, err := strconv.Atoi(v); err == nil && times > 0 {
+			return times
+		}
+		logrus.Warnf("invalid SEALER_SSH_RETRY_TIMES(%s), using default %d", v, defaultConnectRetryTimes)
+	}
+	return defaultConnectRetryTimes
+}
+
+func connectRetryInterval() time.Duration {
+	if v := os.Getenv("SEALER_SSH_RETRY_INTERVAL"); v != "" {
+		if interval, err := time.ParseDuration(v); err == nil && interval > 0 {
+			return interval
+		}
+		logrus.Warnf("invalid SEALER_SSH_RETRY_INTERVAL(%s), using default %v", v, defaultConnectRetryInterval)
+	}
+	return defaultConnectRetryInterval
+}
+
+func cmdTimeout() time.Duration {
+	if v := os.Getenv("SEALER_SSH_CMD_TIMEOUT"); v != "" {
+		if timeout, err := time.ParseDuration(v); err == nil && timeout > 0 {
+			return timeout
+		}
+		logrus.Warnf("invalid SEALER_SSH_CMD_TIMEOUT(%s), command timeout disabled", v)
+	}
+	return 0
+}
+
+// connectWithRetry establishes an ssh session, retrying transient
+// connection failures with exponential backoff. Only the connection is
+// retried: commands are never re-executed, since they may not be
+// idempotent.
+func (s *SSH) connectWithRetry(host net.IP) (*ssh.Client, *ssh.Session, error) {
+	var (
+		times    = connectRetryTimes()
+		interval = connectRetryInterval()
+		err      error
+	)
+	for i := 0; i < times; i++ {
+		var (
+			client  *ssh.Client
+			session *ssh.Session
+		)
+		client, session, err = s.Connect(host)
+		if err == nil {
+			return client, session, nil
+		}
+		if i == times-1 {
+			break
+		}
+		backoff := interval * time.Duration(1<<i)
+		logrus.Debugf("failed to connect %s(attempt %d/%d), retrying in %v: %v", host, i+1, times, backoff, err)
+		time.Sleep(backoff)
+	}
+	return nil, nil, fmt.Errorf("failed to connect %s after %d attempts: %v", host, times, err)
+}
+
+// runCancelable runs f, aborting once the configured command timeout
+// elapses or the process context is canceled (Ctrl-C, global --timeout).
+// On abort the given abort func is called to tear down the in-flight
+// session so the remote process is not orphaned, then f is awaited so its
+// resources are released before returning.
+func runCancelable(desc string, f func() error, abort func()) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- f()
+	}()
+
+	var timeoutCh <-chan time.Time
+	if timeout := cmdTimeout(); timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		timeoutCh = timer.C
+
+		select {
+		case err := <-done:
+			return err
+		case <-timeoutCh:
+			if abort != nil {
+				abort()
+			}
+			<-done
+			return fmt.Errorf("%s timed out after %v", desc, timeout)
+		case <-ProcessContext().Done():
+		}
+	} else {
+		select {
+		case err := <-done:
+			return err
+		case <-ProcessContext().Done():
+		}
+	}
+
+	if abort != nil {
+		abort()
+	}
+	<-done
+	return fmt.Errorf("%s canceled: %v", desc, ProcessContext().Err())
+}
@@ -204,6 +204,11 @@ func (s *SSH) copyLocalFileToRemote(host net.IP, sftpClient *sftp.Client, localP
 	var (
 		srcMd5, dstMd5 string
 	)
+	// stop between files once the process context is canceled, large
+	// rootfs distributions should not keep copying after Ctrl-C
+	if err := ProcessContext().Err(); err != nil {
+		return fmt.Errorf("copy to host(%s) canceled: %v", host, err)
+	}
 	srcMd5 = localMd5Sum(localPath)
 	if exist, err := s.IsFileExist(host, remotePath); err != nil {
 		return err
@@ -71,17 +71,24 @@ func (s *SSH) CmdAsync(host net.IP, hostEnv map[string]string, cmds ...string) e
 				return fmt.Errorf("failed to start command %s: %v", cmd, err)
 			}
 
-			ReadPipe(stdout, stderr, s.AlsoToStdout)
-
-			err = c.Wait()
-			if err != nil {
-				return fmt.Errorf("failed to execute command(%s) on host(%s): error(%v)", cmd, host, err)
-			}
-			return nil
+			return runCancelable(fmt.Sprintf("command(%s) on host(%s)", cmd, host), func() error {
+				ReadPipe(stdout, stderr, s.AlsoToStdout)
+
+				if err := c.Wait(); err != nil {
+					return fmt.Errorf("failed to execute command(%s) on host(%s): error(%v)", cmd, host, err)
+				}
+				return nil
+			}, func() {
+				// kill the local process instead of leaving it running
+				// after the command is abandoned
+				if c.Process != nil {
+					_ = c.Process.Kill()
+				}
+			})
 		}
 	} else {
 		execFunc = func(cmd string) error {
-			client, session, err := s.Connect(host)
+			client, session, err := s.connectWithRetry(host)
 			if err != nil {
 				return fmt.Errorf("failed to create ssh session for %s: %v", host, err)
 			}
@@ -100,14 +107,19 @@ func (s *SSH) CmdAsync(host net.IP, hostEnv map[string]string, cmds ...string) e
 				return fmt.Errorf("failed to start command %s on %s: %v", cmd, host, err)
 			}
 
-			ReadPipe(stdout, stderr, s.AlsoToStdout)
-
-			err = session.Wait()
-			if err != nil {
-				return fmt.Errorf("failed to execute command(%s) on host(%s): error(%v)", cmd, host, err)
-			}
-
-			return nil
+			return runCancelable(fmt.Sprintf("command(%s) on host(%s)", cmd, host), func() error {
+				ReadPipe(stdout, stderr, s.AlsoToStdout)
+
+				if err := session.Wait(); err != nil {
+					return fmt.Errorf("failed to execute command(%s) on host(%s): error(%v)", cmd, host, err)
+				}
+				return nil
+			}, func() {
+				// closing the session tears down the remote process
+				// instead of orphaning it
+				_ = session.Close()
+				_ = client.Close()
+			})
 		}
 	}
 
@@ -115,6 +127,9 @@ func (s *SSH) CmdAsync(host net.IP, hostEnv map[string]string, cmds ...string) e
 		if cmd == "" {
 			continue
 		}
+		if err := ProcessContext().Err(); err != nil {
+			return fmt.Errorf("command on host(%s) canceled: %v", host, err)
+		}
 		if s.User != common.ROOT {
 			cmd = fmt.Sprintf("sudo -E /bin/bash <<EOF\n%s\nEOF", cmd)
 		}
@@ -148,13 +163,17 @@ func (s *SSH) Cmd(host net.IP, hostEnv map[string]string, cmd string) ([]byte, e
 		localCmd := exec.Command("/bin/bash", "-c", cmd)
 		localCmd.Stdout = &stdoutContent
 		localCmd.Stderr = &stderrContent
-		if err := localCmd.Run(); err != nil {
+		if err := runCancelable(fmt.Sprintf("command(%s) on host(%s)", cmd, host), localCmd.Run, func() {
+			if localCmd.Process != nil {
+				_ = localCmd.Process.Kill()
+			}
+		}); err != nil {
 			return stdoutContent.Bytes(), fmt.Errorf("failed to execute command(%s) on host(%s): error(%v)", cmd, host, stderrContent.String())
 		}
 		return stdoutContent.Bytes(), nil
 	}
 
-	client, session, err := s.Connect(host)
+	client, session, err := s.connectWithRetry(host)
 	if err != nil {
 		return nil, fmt.Errorf("[ssh][%s] failed to create ssh session: %s", host, err)
 	}
@@ -163,7 +182,12 @@ func (s *SSH) Cmd(host net.IP, hostEnv map[string]string, cmd string) ([]byte, e
 
 	session.Stdout = &stdoutContent
 	session.Stderr = &stderrContent
-	if err := session.Run(cmd); err != nil {
+	if err := runCancelable(fmt.Sprintf("command(%s) on host(%s)", cmd, host), func() error {
+		return session.Run(cmd)
+	}, func() {
+		_ = session.Close()
+		_ = client.Close()
+	}); err != nil {
 		return stdoutContent.Bytes(), fmt.Errorf("[ssh][%s]failed to run command[%s]: %s", host, cmd, stderrContent.String())
 	}
 
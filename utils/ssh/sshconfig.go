@@ -0,0 +1,222 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/sealerio/sealer/common"
+)
+
+// Per-host transport overrides read from the user's OpenSSH
+// configuration. They are opt-in through SEALER_SSH_CONFIG: set it to
+// "true" to read the default ~/.ssh/config, or to a path pointing at an
+// alternative config file. Only the options that map onto sealer's
+// transport are honored: HostName, User, Port, IdentityFile and
+// ProxyCommand.
+type sshConfigOverride struct {
+	HostName     string
+	User         string
+	Port         string
+	IdentityFile string
+	ProxyCommand string
+}
+
+func sshConfigFile() string {
+	switch v := os.Getenv("SEALER_SSH_CONFIG"); v {
+	case "":
+		return ""
+	case "true":
+		return filepath.Join(common.GetHomeDir(), ".ssh", "config")
+	default:
+		return v
+	}
+}
+
+// lookupSSHConfigOverride returns the ssh config options applying to
+// host, or nil if the config is disabled, unreadable or has no matching
+// Host block. Like OpenSSH, the first obtained value for an option wins.
+func lookupSSHConfigOverride(host string) *sshConfigOverride {
+	configFile := sshConfigFile()
+	if configFile == "" {
+		return nil
+	}
+	data, err := os.ReadFile(filepath.Clean(configFile))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logrus.Warnf("failed to read ssh config %s: %v", configFile, err)
+		}
+		return nil
+	}
+	return parseSSHConfigOverride(string(data), host)
+}
+
+func parseSSHConfigOverride(config, host string) *sshConfigOverride {
+	var (
+		override sshConfigOverride
+		matched  bool
+		inBlock  bool
+	)
+	for _, line := range strings.Split(config, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, ok := splitSSHConfigLine(line)
+		if !ok {
+			continue
+		}
+
+		if strings.EqualFold(key, "Host") {
+			inBlock = matchSSHHostPatterns(strings.Fields(value), host)
+			matched = matched || inBlock
+			continue
+		}
+		if !inBlock {
+			continue
+		}
+
+		switch strings.ToLower(key) {
+		case "hostname":
+			setIfEmpty(&override.HostName, value)
+		case "user":
+			setIfEmpty(&override.User, value)
+		case "port":
+			setIfEmpty(&override.Port, value)
+		case "identityfile":
+			setIfEmpty(&override.IdentityFile, expandSSHHomeDir(value))
+		case "proxycommand":
+			setIfEmpty(&override.ProxyCommand, value)
+		}
+	}
+	if !matched {
+		return nil
+	}
+	return &override
+}
+
+func splitSSHConfigLine(line string) (key, value string, ok bool) {
+	// both "Key value" and "Key=value" forms are valid in ssh_config
+	if i := strings.IndexAny(line, " \t="); i > 0 {
+		return line[:i], strings.Trim(strings.TrimSpace(line[i+1:]), `"`), true
+	}
+	return "", "", false
+}
+
+func matchSSHHostPatterns(patterns []string, host string) bool {
+	matched := false
+	for _, pattern := range patterns {
+		negated := strings.HasPrefix(pattern, "!")
+		pattern = strings.TrimPrefix(pattern, "!")
+		ok, err := path.Match(pattern, host)
+		if err != nil || !ok {
+			continue
+		}
+		if negated {
+			return false
+		}
+		matched = true
+	}
+	return matched
+}
+
+func setIfEmpty(dst *string, value string) {
+	if *dst == "" {
+		*dst = value
+	}
+}
+
+func expandSSHHomeDir(p string) string {
+	if strings.HasPrefix(p, "~/") {
+		return filepath.Join(common.GetHomeDir(), p[2:])
+	}
+	return p
+}
+
+// expandSSHTokens substitutes the ssh_config percent tokens used by
+// ProxyCommand: %h(host), %p(port), %r(user) and %%.
+func expandSSHTokens(s, host, port, user string) string {
+	replacer := strings.NewReplacer("%%", "%", "%h", host, "%p", port, "%r", user)
+	return replacer.Replace(s)
+}
+
+// dialThroughProxy establishes the ssh connection over the stdio of a
+// ProxyCommand, mirroring how the openssh client tunnels through jump
+// hosts.
+func dialThroughProxy(proxyCommand, addr string, config *ssh.ClientConfig) (*ssh.Client, error) {
+	cmd := exec.Command("/bin/sh", "-c", proxyCommand) // #nosec
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start proxy command %q: %v", proxyCommand, err)
+	}
+
+	conn := &proxyCommandConn{cmd: cmd, stdin: stdin, stdout: stdout}
+	clientConn, chans, reqs, err := ssh.NewClientConn(conn, addr, config)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return ssh.NewClient(clientConn, chans, reqs), nil
+}
+
+// proxyCommandConn adapts the pipes of a running ProxyCommand to the
+// net.Conn expected by the ssh client.
+type proxyCommandConn struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout io.ReadCloser
+}
+
+func (c *proxyCommandConn) Read(b []byte) (int, error)  { return c.stdout.Read(b) }
+func (c *proxyCommandConn) Write(b []byte) (int, error) { return c.stdin.Write(b) }
+
+func (c *proxyCommandConn) Close() error {
+	_ = c.stdin.Close()
+	if c.cmd.Process != nil {
+		_ = c.cmd.Process.Kill()
+	}
+	return c.cmd.Wait()
+}
+
+func (c *proxyCommandConn) LocalAddr() net.Addr                { return proxyCommandAddr{} }
+func (c *proxyCommandConn) RemoteAddr() net.Addr               { return proxyCommandAddr{} }
+func (c *proxyCommandConn) SetDeadline(t time.Time) error      { return nil }
+func (c *proxyCommandConn) SetReadDeadline(t time.Time) error  { return nil }
+func (c *proxyCommandConn) SetWriteDeadline(t time.Time) error { return nil }
+
+type proxyCommandAddr struct{}
+
+func (proxyCommandAddr) Network() string { return "proxy-command" }
+func (proxyCommandAddr) String() string  { return "proxy-command" }
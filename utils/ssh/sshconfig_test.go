@@ -0,0 +1,73 @@
+// Copyright © 2023 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testSSHConfig = `
+# jump through the bastion for the whole private range
+Host 192.168.0.*
+    User ops
+    Port 2222
+    IdentityFile /etc/sealer/id_rsa
+    ProxyCommand ssh -W %h:%p bastion
+
+Host 192.168.0.9
+    HostName 10.0.0.9
+
+Host *
+    User fallback
+`
+
+func TestParseSSHConfigOverride(t *testing.T) {
+	override := parseSSHConfigOverride(testSSHConfig, "192.168.0.2")
+	if assert.NotNil(t, override) {
+		assert.Equal(t, "ops", override.User)
+		assert.Equal(t, "2222", override.Port)
+		assert.Equal(t, "/etc/sealer/id_rsa", override.IdentityFile)
+		assert.Equal(t, "ssh -W %h:%p bastion", override.ProxyCommand)
+		assert.Empty(t, override.HostName)
+	}
+
+	// first obtained value wins, later blocks only fill the gaps
+	override = parseSSHConfigOverride(testSSHConfig, "192.168.0.9")
+	if assert.NotNil(t, override) {
+		assert.Equal(t, "10.0.0.9", override.HostName)
+		assert.Equal(t, "ops", override.User)
+	}
+
+	override = parseSSHConfigOverride(testSSHConfig, "172.16.0.2")
+	if assert.NotNil(t, override) {
+		assert.Equal(t, "fallback", override.User)
+		assert.Empty(t, override.Port)
+	}
+}
+
+func TestMatchSSHHostPatterns(t *testing.T) {
+	assert.True(t, matchSSHHostPatterns([]string{"192.168.0.*"}, "192.168.0.2"))
+	assert.False(t, matchSSHHostPatterns([]string{"192.168.0.*"}, "172.16.0.2"))
+	assert.False(t, matchSSHHostPatterns([]string{"!192.168.0.2", "192.168.0.*"}, "192.168.0.2"))
+	assert.True(t, matchSSHHostPatterns([]string{"!192.168.0.2", "192.168.0.*"}, "192.168.0.3"))
+}
+
+func TestExpandSSHTokens(t *testing.T) {
+	assert.Equal(t, "ssh -W 192.168.0.2:22 bastion",
+		expandSSHTokens("ssh -W %h:%p bastion", "192.168.0.2", "22", "root"))
+	assert.Equal(t, "100%", expandSSHTokens("100%%", "h", "p", "r"))
+}